@@ -0,0 +1,58 @@
+package path
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Audit actions recorded by LogAudit
+const (
+	AuditSetPath        = "set-path"
+	AuditApplyPathExt   = "apply-pathext"
+	AuditCreateJunction = "create-junction"
+	AuditRemoveJunction = "remove-junction"
+	AuditBackupCreate   = "backup-create"
+	AuditBackupDelete   = "backup-delete"
+	AuditBackupRestore  = "backup-restore"
+)
+
+// GetAuditLogPath returns the append-only log of changes made by WinPath
+func GetAuditLogPath() string {
+	return filepath.Join(getConfigDir(), "audit.log")
+}
+
+// LogAudit appends a timestamped line describing a change to the audit log
+// Logging is best effort: a change that succeeded is never reported as failed
+// because its audit line could not be written
+func LogAudit(action, detail string) {
+	if err := os.MkdirAll(getConfigDir(), 0755); err != nil {
+		return
+	}
+	f, err := os.OpenFile(GetAuditLogPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	detail = strings.NewReplacer("\r", " ", "\n", " ").Replace(detail)
+	fmt.Fprintf(f, "%s  %-15s  %s\n", time.Now().Format("2006-01-02 15:04:05"), action, detail)
+}
+
+// ReadAuditLog returns the lines of the audit log, oldest first
+// A missing log means nothing has been changed yet and is not an error
+func ReadAuditLog() ([]string, error) {
+	data, err := os.ReadFile(GetAuditLogPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if text == "" {
+		return nil, nil
+	}
+	return strings.Split(text, "\n"), nil
+}
@@ -0,0 +1,87 @@
+package path
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLogAudit_Appends(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+
+	if lines, err := ReadAuditLog(); err != nil || lines != nil {
+		t.Fatalf("Expected an empty log before any change, got %v, %v", lines, err)
+	}
+
+	LogAudit(AuditBackupCreate, "path_1.json")
+	LogAudit(AuditSetPath, "User PATH\nwith a newline")
+	lines, err := ReadAuditLog()
+	if err != nil {
+		t.Fatalf("ReadAuditLog error: %v", err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d: %v", len(lines), lines)
+	}
+	if !strings.Contains(lines[0], AuditBackupCreate) || !strings.HasSuffix(lines[0], "path_1.json") {
+		t.Errorf("Unexpected first line: %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "User PATH with a newline") {
+		t.Errorf("Expected newlines in the detail to be flattened, got %q", lines[1])
+	}
+}
+
+func TestSetPath_LogsAudit(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+	withMockRunner(t, nil, func() {
+		if err := SetPath(`C:\Tools;C:\Bin`, "System"); err != nil {
+			t.Fatalf("SetPath error: %v", err)
+		}
+	})
+	lines, _ := ReadAuditLog()
+	if len(lines) != 1 || !strings.Contains(lines[0], AuditSetPath) || !strings.HasSuffix(lines[0], "System PATH, 2 entries, 15 chars") {
+		t.Errorf("Expected one set-path line, got %v", lines)
+	}
+}
+
+func TestSetPath_FailureNotAudited(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetError("SetEnvironmentVariable", os.ErrPermission)
+	}, func() {
+		if err := SetPath(`C:\Tools`, "User"); err == nil {
+			t.Fatal("Expected SetPath to fail")
+		}
+	})
+	if lines, _ := ReadAuditLog(); len(lines) != 0 {
+		t.Errorf("A failed write should not be logged, got %v", lines)
+	}
+}
+
+func TestBackupLifecycle_LogsAudit(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\Tools`)
+	}, func() {
+		info, err := CreateLabeledBackup("before audit")
+		if err != nil {
+			t.Fatalf("CreateLabeledBackup error: %v", err)
+		}
+		if err := RestoreBackup(info.Filename, false); err != nil {
+			t.Fatalf("RestoreBackup error: %v", err)
+		}
+		if err := DeleteBackup(info.Filename); err != nil {
+			t.Fatalf("DeleteBackup error: %v", err)
+		}
+	})
+
+	log := func() string { lines, _ := ReadAuditLog(); return strings.Join(lines, "\n") }()
+	for _, action := range []string{AuditBackupCreate, AuditBackupRestore, AuditBackupDelete} {
+		if !strings.Contains(log, action) {
+			t.Errorf("Expected %s in the audit log:\n%s", action, log)
+		}
+	}
+}
@@ -29,10 +29,11 @@ func getConfigDir() string {
 
 // Backup represents a saved PATH backup
 type Backup struct {
-	Timestamp  time.Time `json:"timestamp"`
-	Hostname   string    `json:"hostname"`
-	Suffix     string    `json:"suffix"`
-	SystemPath struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Hostname    string    `json:"hostname"`
+	Suffix      string    `json:"suffix"`
+	Description string    `json:"description,omitempty"`
+	SystemPath  struct {
 		Raw     string   `json:"raw"`
 		Entries []string `json:"entries"`
 	} `json:"systemPath"`
@@ -48,23 +49,48 @@ type BackupInfo struct {
 	Timestamp     time.Time
 	Suffix        string
 	FormattedDate string
+	Description   string
 }
 
 // Config stores application configuration
 type Config struct {
-	JunctionFolder string   `json:"junctionFolder"`
-	MaxBackups     int      `json:"maxBackups"`
-	AutoBackup     bool     `json:"autoBackup"`
-	HotPaths       []string `json:"hotPaths"`
+	JunctionFolder              string   `json:"junctionFolder"`
+	MaxBackups                  int      `json:"maxBackups"`
+	AutoBackup                  bool     `json:"autoBackup"`
+	HotPaths                    []string `json:"hotPaths"`
+	RollbackSeconds             int      `json:"rollbackSeconds"`
+	AutoAnalyzeOnStart          bool     `json:"autoAnalyzeOnStart"`
+	RewritePathOnJunction       bool     `json:"rewritePathOnJunction"`
+	JunctionMinSavings          int      `json:"junctionMinSavings"`          // Chars a junction must save to be suggested
+	JunctionMinPathLength       int      `json:"junctionMinPathLength"`       // Shorter entries are never suggested
+	AdvisoryMode                bool     `json:"advisoryMode"`                // Analyze and report only; every apply path is disabled
+	MaintenanceReminder         string   `json:"maintenanceReminder"`         // Shown on confirms that write System PATH
+	CanonicalizePaths           bool     `json:"canonicalizePaths"`           // Rewrite entries to their on-disk casing and long names
+	LastViewerScope             string   `json:"lastViewerScope"`             // "User" or "System"; empty means User
+	LastOptimizerScope          string   `json:"lastOptimizerScope"`          // "both", "system" or "user"; empty means both
+	Theme                       string   `json:"theme"`                       // "default", "mono" or "highcontrast"; empty means default
+	ProtectedEntries            []string `json:"protectedEntries"`            // Entries whose removal or rewrite needs an explicit override
+	BackupCoalesceWindowSeconds int      `json:"backupCoalesceWindowSeconds"` // Pre-change backups this close together are merged; 0 disables
+	ExtraSubstitutionVars       []string `json:"extraSubstitutionVars"`       // Variables tried alongside SubstitutionPriority, e.g. TOOLS_HOME
+	ShellTimeoutSeconds         int      `json:"shellTimeoutSeconds"`         // A PowerShell call running longer is killed; 0 means 60
+	OptimizerPreset             string   `json:"optimizerPreset"`             // "default", "conservative", "existing" or "aggressive"; empty means default
+	DetectShadowedTools         bool     `json:"detectShadowedTools"`         // Scan PATH directories for commands provided more than once
+	JunctionNameMaxLen          int      `json:"junctionNameMaxLen"`          // Longest name suggested for a new junction; 0 means 12
+	JunctionExcludePrefixes     []string `json:"junctionExcludePrefixes"`     // Entries in these folders are never suggested for a junction
+	PowerShellExe               string   `json:"powerShellExe"`               // Shell to run commands with; empty prefers pwsh and falls back to powershell.exe
 }
 
 // DefaultConfig returns default configuration
 func DefaultConfig() Config {
 	return Config{
-		JunctionFolder: `C:\l`,
-		MaxBackups:     10,
-		AutoBackup:     true,
-		HotPaths:       []string{},
+		JunctionFolder:        `C:\l`,
+		MaxBackups:            10,
+		AutoBackup:            true,
+		HotPaths:              []string{},
+		RollbackSeconds:       15,
+		JunctionMinSavings:    21,
+		JunctionMinPathLength: 30,
+		JunctionNameMaxLen:    12,
 	}
 }
 
@@ -78,6 +104,21 @@ func GetConfigPath() string {
 	return filepath.Join(getConfigDir(), "config.json")
 }
 
+// openFolderCommand returns the PowerShell command that shows dir in Explorer
+func openFolderCommand(dir string) string {
+	return fmt.Sprintf(`Start-Process explorer.exe -ArgumentList '"%s"'`, escapePSString(dir))
+}
+
+// OpenConfigDir shows the folder holding config.json and the backups in Explorer and returns it
+func OpenConfigDir() (string, error) {
+	dir := getConfigDir()
+	if err := EnsureBackupDir(); err != nil {
+		return dir, err
+	}
+	_, err := RunPowerShell(openFolderCommand(dir))
+	return dir, err
+}
+
 // EnsureBackupDir creates the backup directory if it doesn't exist
 func EnsureBackupDir() error {
 	dir := GetBackupDir()
@@ -109,15 +150,88 @@ func SaveConfig(config Config) error {
 }
 
 // CreateBackup creates a new backup with the given suffix
+// A pre-change backup ("pre-..." suffix) taken within BackupCoalesceWindowSeconds of
+// the previous pre-change backup reuses it, so that backup keeps the PATH as it was
+// before the first of several quick applies
 func CreateBackup(suffix string) (*BackupInfo, error) {
+	if recent := coalescableBackup(suffix, LoadConfig().BackupCoalesceWindowSeconds); recent != nil {
+		return recent, nil
+	}
+	return createBackup(suffix, "")
+}
+
+// isCoalescableSuffix reports whether a backup suffix marks a pre-change backup
+// Restores always keep their own safety backup so they can be undone
+func isCoalescableSuffix(suffix string) bool {
+	return strings.HasPrefix(suffix, "pre-") && suffix != "pre-restore"
+}
+
+// coalescableBackup returns the most recent backup if a new pre-change backup
+// should reuse it, or nil if a new one has to be written
+func coalescableBackup(suffix string, windowSeconds int) *BackupInfo {
+	if windowSeconds <= 0 || !isCoalescableSuffix(suffix) {
+		return nil
+	}
+	backups := ListBackups()
+	if len(backups) == 0 || !isCoalescableSuffix(backups[0].Suffix) {
+		return nil
+	}
+	// Filename timestamps are local wall-clock time parsed without a zone, so compare
+	// them in the same form rather than as instants
+	cutoff := time.Now().Add(-time.Duration(windowSeconds) * time.Second).Format(backupTimestampLayout)
+	if backups[0].Timestamp.Format(backupTimestampLayout) < cutoff {
+		return nil
+	}
+	return &backups[0]
+}
+
+// CreateLabeledBackup creates a backup described by a user-entered label
+// The label is kept as the description; a filename-safe version becomes the suffix
+func CreateLabeledBackup(description string) (*BackupInfo, error) {
+	description = strings.TrimSpace(description)
+	suffix := SanitizeBackupLabel(description)
+	if suffix == "" {
+		suffix = "manual"
+	}
+	return createBackup(suffix, description)
+}
+
+// maxBackupLabelLen keeps labeled backup filenames reasonably short
+const maxBackupLabelLen = 40
+
+// SanitizeBackupLabel turns a free-form description into a filename-safe suffix:
+// lowercase letters, digits, dashes and underscores, with runs of anything else
+// collapsed into a single dash
+func SanitizeBackupLabel(label string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(label) {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_':
+			b.WriteRune(r)
+			dash = false
+		case b.Len() > 0 && !dash:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	suffix := strings.TrimRight(b.String(), "-")
+	if len(suffix) > maxBackupLabelLen {
+		suffix = strings.TrimRight(suffix[:maxBackupLabelLen], "-")
+	}
+	return suffix
+}
+
+func createBackup(suffix, description string) (*BackupInfo, error) {
 	if err := EnsureBackupDir(); err != nil {
 		return nil, err
 	}
 
 	backup := Backup{
-		Timestamp: time.Now(),
-		Hostname:  GetHostname(),
-		Suffix:    suffix,
+		Timestamp:   time.Now(),
+		Hostname:    GetHostname(),
+		Suffix:      suffix,
+		Description: description,
 	}
 
 	// Get current paths
@@ -131,7 +245,7 @@ func CreateBackup(suffix string) (*BackupInfo, error) {
 
 	// Generate filename
 	filename := fmt.Sprintf("path_%s_%s.json",
-		backup.Timestamp.Format("20060102_150405"),
+		backup.Timestamp.Format(backupTimestampLayout),
 		suffix)
 
 	filepath := filepath.Join(GetBackupDir(), filename)
@@ -145,6 +259,7 @@ func CreateBackup(suffix string) (*BackupInfo, error) {
 	if err := os.WriteFile(filepath, data, 0644); err != nil {
 		return nil, err
 	}
+	LogAudit(AuditBackupCreate, filename)
 
 	// Enforce backup limit
 	EnforceBackupLimit()
@@ -154,6 +269,7 @@ func CreateBackup(suffix string) (*BackupInfo, error) {
 		Timestamp:     backup.Timestamp,
 		Suffix:        suffix,
 		FormattedDate: backup.Timestamp.Format("2006-01-02 15:04:05"),
+		Description:   description,
 	}, nil
 }
 
@@ -171,28 +287,10 @@ func ListBackups() []BackupInfo {
 			continue
 		}
 
-		// Parse filename: path_YYYYMMDD_HHMMSS_suffix.json
-		name := strings.TrimSuffix(entry.Name(), ".json")
-		parts := strings.Split(name, "_")
-		if len(parts) < 4 || parts[0] != "path" {
-			continue
+		if info, ok := parseBackupFilename(entry.Name()); ok {
+			info.Description = readBackupDescription(filepath.Join(dir, entry.Name()))
+			backups = append(backups, info)
 		}
-
-		// Parse timestamp
-		dateStr := parts[1] + "_" + parts[2]
-		timestamp, err := time.Parse("20060102_150405", dateStr)
-		if err != nil {
-			continue
-		}
-
-		suffix := strings.Join(parts[3:], "_")
-
-		backups = append(backups, BackupInfo{
-			Filename:      entry.Name(),
-			Timestamp:     timestamp,
-			Suffix:        suffix,
-			FormattedDate: timestamp.Format("2006-01-02 15:04:05"),
-		})
 	}
 
 	// Sort by timestamp descending
@@ -203,6 +301,48 @@ func ListBackups() []BackupInfo {
 	return backups
 }
 
+// backupTimestampLayout is the timestamp part of a backup filename
+const backupTimestampLayout = "20060102_150405"
+
+// parseBackupFilename parses path_YYYYMMDD_HHMMSS_suffix.json
+// The timestamp sits at a fixed position, so the suffix may contain underscores
+func parseBackupFilename(filename string) (BackupInfo, bool) {
+	const prefix = "path_"
+	name, ok := strings.CutSuffix(filename, ".json")
+	if !ok || !strings.HasPrefix(name, prefix) {
+		return BackupInfo{}, false
+	}
+	rest := name[len(prefix):]
+	if len(rest) < len(backupTimestampLayout)+2 || rest[len(backupTimestampLayout)] != '_' {
+		return BackupInfo{}, false
+	}
+
+	timestamp, err := time.Parse(backupTimestampLayout, rest[:len(backupTimestampLayout)])
+	if err != nil {
+		return BackupInfo{}, false
+	}
+
+	return BackupInfo{
+		Filename:      filename,
+		Timestamp:     timestamp,
+		Suffix:        rest[len(backupTimestampLayout)+1:],
+		FormattedDate: timestamp.Format("2006-01-02 15:04:05"),
+	}, true
+}
+
+// readBackupDescription returns the description stored in a backup file, if any
+func readBackupDescription(file string) string {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	var header struct {
+		Description string `json:"description"`
+	}
+	_ = json.Unmarshal(data, &header) // Older or damaged backups simply have no description
+	return header.Description
+}
+
 // LoadBackup loads a backup from disk
 func LoadBackup(filename string) (*Backup, error) {
 	filepath := filepath.Join(GetBackupDir(), filename)
@@ -219,10 +359,75 @@ func LoadBackup(filename string) (*Backup, error) {
 	return &backup, nil
 }
 
+// ImportBackup copies a backup file from elsewhere, such as one sent by a teammate,
+// into the backup directory as path_<timestamp>_imported.json
+// The file must decode as a Backup with a timestamp and at least one PATH
+func ImportBackup(srcPath string) (*BackupInfo, error) {
+	data, err := os.ReadFile(srcPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var backup Backup
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return nil, fmt.Errorf("%s is not a valid backup file: %w", filepath.Base(srcPath), err)
+	}
+	if backup.Timestamp.IsZero() {
+		return nil, fmt.Errorf("%s is not a valid backup file: missing timestamp", filepath.Base(srcPath))
+	}
+	if backup.SystemPath.Raw == "" && len(backup.SystemPath.Entries) == 0 &&
+		backup.UserPath.Raw == "" && len(backup.UserPath.Entries) == 0 {
+		return nil, fmt.Errorf("%s is not a valid backup file: no PATH data", filepath.Base(srcPath))
+	}
+
+	// Fill in whichever of Raw or Entries is missing so restore and preview both work
+	if backup.SystemPath.Raw == "" {
+		backup.SystemPath.Raw = JoinPath(backup.SystemPath.Entries)
+	} else if len(backup.SystemPath.Entries) == 0 {
+		backup.SystemPath.Entries = ParsePath(backup.SystemPath.Raw)
+	}
+	if backup.UserPath.Raw == "" {
+		backup.UserPath.Raw = JoinPath(backup.UserPath.Entries)
+	} else if len(backup.UserPath.Entries) == 0 {
+		backup.UserPath.Entries = ParsePath(backup.UserPath.Raw)
+	}
+	backup.Suffix = "imported"
+
+	if err := EnsureBackupDir(); err != nil {
+		return nil, err
+	}
+	filename := fmt.Sprintf("path_%s_%s.json", backup.Timestamp.Format(backupTimestampLayout), backup.Suffix)
+	dest := filepath.Join(GetBackupDir(), filename)
+	if _, err := os.Stat(dest); err == nil {
+		return nil, fmt.Errorf("backup %s already exists", filename)
+	}
+
+	out, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	// The backup limit is not enforced here: an old imported backup would be the first to go
+	if err := os.WriteFile(dest, out, 0644); err != nil {
+		return nil, err
+	}
+
+	return &BackupInfo{
+		Filename:      filename,
+		Timestamp:     backup.Timestamp,
+		Suffix:        backup.Suffix,
+		FormattedDate: backup.Timestamp.Format("2006-01-02 15:04:05"),
+		Description:   backup.Description,
+	}, nil
+}
+
 // DeleteBackup deletes a backup file
 func DeleteBackup(filename string) error {
 	filepath := filepath.Join(GetBackupDir(), filename)
-	return os.Remove(filepath)
+	if err := os.Remove(filepath); err != nil {
+		return err
+	}
+	LogAudit(AuditBackupDelete, filename)
+	return nil
 }
 
 // EnforceBackupLimit removes old backups to stay under the limit
@@ -251,10 +456,12 @@ func RestoreBackup(filename string, isAdmin bool) error {
 	_, _ = CreateBackup("pre-restore") // Best effort, don't fail restore
 
 	// Restore user PATH
+	var scopes []string
 	if backup.UserPath.Raw != "" {
 		if err := SetPath(backup.UserPath.Raw, "User"); err != nil {
 			return fmt.Errorf("failed to restore user PATH: %w", err)
 		}
+		scopes = append(scopes, "User")
 	}
 
 	// Restore system PATH if admin
@@ -262,8 +469,10 @@ func RestoreBackup(filename string, isAdmin bool) error {
 		if err := SetPath(backup.SystemPath.Raw, "System"); err != nil {
 			return fmt.Errorf("failed to restore system PATH: %w", err)
 		}
+		scopes = append(scopes, "System")
 	}
 
+	LogAudit(AuditBackupRestore, fmt.Sprintf("%s (%s)", filename, strings.Join(scopes, " and ")))
 	BroadcastEnvChange()
 	return nil
 }
@@ -2,6 +2,7 @@ package path
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -183,6 +184,140 @@ func TestListBackups_Sorted(t *testing.T) {
 	}
 }
 
+func TestParseBackupFilename(t *testing.T) {
+	tests := []struct {
+		filename string
+		suffix   string
+		date     string
+	}{
+		{"path_20250115_100000_manual.json", "manual", "2025-01-15 10:00:00"},
+		{"path_20250115_100000_before_vs_install.json", "before_vs_install", "2025-01-15 10:00:00"},
+		{"path_20241231_235959_pre-junction-rewrite.json", "pre-junction-rewrite", "2024-12-31 23:59:59"},
+	}
+	for _, tt := range tests {
+		info, ok := parseBackupFilename(tt.filename)
+		if !ok {
+			t.Errorf("%s: expected to parse", tt.filename)
+			continue
+		}
+		if info.Suffix != tt.suffix {
+			t.Errorf("%s: suffix = %q, want %q", tt.filename, info.Suffix, tt.suffix)
+		}
+		if info.FormattedDate != tt.date {
+			t.Errorf("%s: date = %q, want %q", tt.filename, info.FormattedDate, tt.date)
+		}
+	}
+}
+
+func TestParseBackupFilename_Invalid(t *testing.T) {
+	invalid := []string{
+		"invalid.json",
+		"path_20250115_100000.json",
+		"path_20250115_100000_.json",
+		"path_2025011_1000000_manual.json",
+		"path_20250115-100000_manual.json",
+		"path_20251315_100000_manual.json",
+		"path_20250115_100000_manual.txt",
+		"backup_20250115_100000_manual.json",
+	}
+	for _, name := range invalid {
+		if _, ok := parseBackupFilename(name); ok {
+			t.Errorf("%s: expected parse to fail", name)
+		}
+	}
+}
+
+func TestListBackups_SuffixWithUnderscores(t *testing.T) {
+	EnsureBackupDir()
+
+	name := "path_20250115_100000_before_vs_install.json"
+	file := filepath.Join(GetBackupDir(), name)
+	os.WriteFile(file, []byte("{}"), 0644)
+	defer os.Remove(file)
+
+	for _, b := range ListBackups() {
+		if b.Filename == name {
+			if b.Suffix != "before_vs_install" {
+				t.Errorf("Suffix = %q, want before_vs_install", b.Suffix)
+			}
+			if !b.Timestamp.Equal(time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)) {
+				t.Errorf("Timestamp = %v", b.Timestamp)
+			}
+			return
+		}
+	}
+	t.Errorf("%s not listed", name)
+}
+
+func TestSanitizeBackupLabel(t *testing.T) {
+	tests := []struct {
+		label string
+		want  string
+	}{
+		{"Before VS install", "before-vs-install"},
+		{"  pre upgrade!!  ", "pre-upgrade"},
+		{`C:\Tools / "v2" <final>`, "c-tools-v2-final"},
+		{"keep_under_scores", "keep_under_scores"},
+		{"Ünïcode café", "n-code-caf"},
+		{"!!!", ""},
+		{"", ""},
+		{strings.Repeat("abc ", 20), "abc-abc-abc-abc-abc-abc-abc-abc-abc-abc"},
+	}
+	for _, tt := range tests {
+		if got := SanitizeBackupLabel(tt.label); got != tt.want {
+			t.Errorf("SanitizeBackupLabel(%q) = %q, want %q", tt.label, got, tt.want)
+		}
+	}
+}
+
+func TestCreateLabeledBackup(t *testing.T) {
+	info, err := CreateLabeledBackup("  Before VS install  ")
+	if err != nil {
+		t.Fatalf("CreateLabeledBackup error: %v", err)
+	}
+	defer DeleteBackup(info.Filename)
+
+	if info.Suffix != "before-vs-install" {
+		t.Errorf("Suffix = %q", info.Suffix)
+	}
+	if !strings.HasSuffix(info.Filename, "_before-vs-install.json") {
+		t.Errorf("Filename = %q", info.Filename)
+	}
+
+	backup, err := LoadBackup(info.Filename)
+	if err != nil {
+		t.Fatalf("LoadBackup error: %v", err)
+	}
+	if backup.Description != "Before VS install" {
+		t.Errorf("Description = %q", backup.Description)
+	}
+
+	found := false
+	for _, b := range ListBackups() {
+		if b.Filename == info.Filename {
+			found = true
+			if b.Description != "Before VS install" {
+				t.Errorf("Listed description = %q", b.Description)
+			}
+		}
+	}
+	if !found {
+		t.Error("Labeled backup not listed")
+	}
+}
+
+func TestCreateLabeledBackup_EmptyLabel(t *testing.T) {
+	info, err := CreateLabeledBackup("???")
+	if err != nil {
+		t.Fatalf("CreateLabeledBackup error: %v", err)
+	}
+	defer DeleteBackup(info.Filename)
+
+	if info.Suffix != "manual" {
+		t.Errorf("Expected fallback suffix 'manual', got %q", info.Suffix)
+	}
+}
+
 func TestListBackups_SkipsInvalid(t *testing.T) {
 	EnsureBackupDir()
 
@@ -330,3 +465,187 @@ func TestGetConfigPath(t *testing.T) {
 		t.Errorf("Config path should end with config.json, got: %s", configPath)
 	}
 }
+
+func TestOpenConfigDir_CommandUsesConfigDir(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	dir := filepath.Join(t.TempDir(), "Tom's config")
+	SetConfigDir(dir)
+
+	withMockRunner(t, nil, func() {
+		before := len(getMockRunner(t).Calls)
+		opened, err := OpenConfigDir()
+		if err != nil {
+			t.Fatalf("OpenConfigDir failed: %v", err)
+		}
+		if opened != dir || filepath.Dir(GetConfigPath()) != dir {
+			t.Errorf("Opened %s, want the config dir %s", opened, dir)
+		}
+		if _, err := os.Stat(GetBackupDir()); err != nil {
+			t.Errorf("Expected the folder created before opening it: %v", err)
+		}
+
+		calls := getMockRunner(t).Calls[before:]
+		want := "Start-Process explorer.exe -ArgumentList '\"" + strings.ReplaceAll(dir, "'", "''") + "\"'"
+		if len(calls) != 1 || calls[0] != want {
+			t.Errorf("Expected %q, got %v", want, calls)
+		}
+	})
+}
+
+func TestImportBackup_Valid(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "teammate.json")
+	data := `{
+  "timestamp": "2025-03-04T09:08:07Z",
+  "hostname": "TEAMMATE-PC",
+  "suffix": "manual",
+  "description": "Known good",
+  "systemPath": {"raw": "C:\\Windows;C:\\Windows\\System32"},
+  "userPath": {"entries": ["C:\\Tools"]}
+}`
+	if err := os.WriteFile(src, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := ImportBackup(src)
+	if err != nil {
+		t.Fatalf("ImportBackup error: %v", err)
+	}
+	defer DeleteBackup(info.Filename)
+
+	if info.Filename != "path_20250304_090807_imported.json" {
+		t.Errorf("Filename = %q", info.Filename)
+	}
+	if info.Description != "Known good" {
+		t.Errorf("Description = %q", info.Description)
+	}
+
+	backup, err := LoadBackup(info.Filename)
+	if err != nil {
+		t.Fatalf("LoadBackup error: %v", err)
+	}
+	if backup.Hostname != "TEAMMATE-PC" {
+		t.Errorf("Hostname = %q", backup.Hostname)
+	}
+	if len(backup.SystemPath.Entries) != 2 {
+		t.Errorf("Expected system entries parsed from raw, got %v", backup.SystemPath.Entries)
+	}
+	if backup.UserPath.Raw != `C:\Tools` {
+		t.Errorf("Expected user raw joined from entries, got %q", backup.UserPath.Raw)
+	}
+
+	if _, err := ImportBackup(src); err == nil {
+		t.Error("Expected importing the same backup twice to fail")
+	}
+}
+
+func TestImportBackup_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"malformed.json", `{"timestamp": `, "not a valid backup file"},
+		{"notimestamp.json", `{"userPath": {"raw": "C:\\Tools"}}`, "missing timestamp"},
+		{"nopath.json", `{"timestamp": "2025-03-04T09:08:07Z"}`, "no PATH data"},
+	}
+	before := len(ListBackups())
+	for _, tt := range tests {
+		src := filepath.Join(dir, tt.name)
+		if err := os.WriteFile(src, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := ImportBackup(src)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+	if _, err := ImportBackup(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Expected error for a missing file")
+	}
+	if after := len(ListBackups()); after != before {
+		t.Errorf("Expected no backups added, got %d -> %d", before, after)
+	}
+}
+
+func TestCreateBackup_CoalescesWithinWindow(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+	config := DefaultConfig()
+	config.BackupCoalesceWindowSeconds = 60
+	_ = SaveConfig(config)
+
+	first, err := CreateBackup("pre-optimize")
+	if err != nil {
+		t.Fatalf("CreateBackup failed: %v", err)
+	}
+	second, err := CreateBackup("pre-edit")
+	if err != nil {
+		t.Fatalf("CreateBackup failed: %v", err)
+	}
+
+	if second.Filename != first.Filename {
+		t.Errorf("Expected the second apply to reuse %s, got %s", first.Filename, second.Filename)
+	}
+	if backups := ListBackups(); len(backups) != 1 {
+		t.Errorf("Expected a single backup within the window, got %d", len(backups))
+	}
+}
+
+func TestCreateBackup_OutsideWindowCreatesNew(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+	config := DefaultConfig()
+	config.BackupCoalesceWindowSeconds = 60
+	_ = SaveConfig(config)
+
+	first, err := CreateBackup("pre-optimize")
+	if err != nil {
+		t.Fatalf("CreateBackup failed: %v", err)
+	}
+	// Age the first backup past the window
+	old := fmt.Sprintf("path_%s_pre-optimize.json", time.Now().Add(-5*time.Minute).Format(backupTimestampLayout))
+	if err := os.Rename(filepath.Join(GetBackupDir(), first.Filename), filepath.Join(GetBackupDir(), old)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := CreateBackup("pre-edit"); err != nil {
+		t.Fatalf("CreateBackup failed: %v", err)
+	}
+	if backups := ListBackups(); len(backups) != 2 {
+		t.Errorf("Expected two backups outside the window, got %d", len(backups))
+	}
+}
+
+func TestCreateBackup_NoCoalesceByDefault(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+
+	if _, err := CreateBackup("pre-optimize"); err != nil {
+		t.Fatalf("CreateBackup failed: %v", err)
+	}
+	if _, err := CreateBackup("pre-edit"); err != nil {
+		t.Fatalf("CreateBackup failed: %v", err)
+	}
+	if backups := ListBackups(); len(backups) != 2 {
+		t.Errorf("Expected coalescing to be off by default, got %d backups", len(backups))
+	}
+}
+
+func TestCreateBackup_RestoreNeverCoalesces(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+	config := DefaultConfig()
+	config.BackupCoalesceWindowSeconds = 60
+	_ = SaveConfig(config)
+
+	if _, err := CreateBackup("pre-optimize"); err != nil {
+		t.Fatalf("CreateBackup failed: %v", err)
+	}
+	if _, err := CreateBackup("pre-restore"); err != nil {
+		t.Fatalf("CreateBackup failed: %v", err)
+	}
+	if backups := ListBackups(); len(backups) != 2 {
+		t.Errorf("Expected the pre-restore backup to be kept separately, got %d backups", len(backups))
+	}
+}
@@ -0,0 +1,53 @@
+package path
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// CaseConflict records two PATH entries that differ only in case but live under a
+// case-sensitive directory, so they may name different folders and both are kept
+type CaseConflict struct {
+	Entry     string `json:"entry"`     // Entry seen first
+	Variant   string `json:"variant"`   // Later entry differing only in case
+	Directory string `json:"directory"` // Case-sensitive directory the names differ under
+}
+
+// IsCaseSensitiveDir reports whether the per-directory case sensitivity flag is set on dir
+// Any error is treated as not case sensitive, which is the Windows default
+func IsCaseSensitiveDir(dir string) bool {
+	if dir == "" || strings.Contains(dir, "%") {
+		return false
+	}
+	out, err := RunPowerShell(fmt.Sprintf("fsutil.exe file queryCaseSensitiveInfo '%s'", escapePSString(dir)))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(out), "is enabled")
+}
+
+// caseVariantDir returns the directory holding the first path component where a and b
+// differ only in case, or "" if they do not differ that way
+func caseVariantDir(a, b string) string {
+	pa := strings.Split(cleanEntry(a), `\`)
+	pb := strings.Split(cleanEntry(b), `\`)
+	if len(pa) != len(pb) {
+		return ""
+	}
+	for i := range pa {
+		if pa[i] == pb[i] {
+			continue
+		}
+		if i == 0 || !strings.EqualFold(pa[i], pb[i]) {
+			return ""
+		}
+		return strings.Join(pa[:i], `\`) + `\`
+	}
+	return ""
+}
+
+// cleanEntry trims trailing separators and uses backslashes, keeping the original case
+func cleanEntry(entry string) string {
+	return strings.TrimRight(strings.ReplaceAll(filepath.Clean(entry), "/", `\`), `\`)
+}
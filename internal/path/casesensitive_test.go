@@ -0,0 +1,98 @@
+package path
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsCaseSensitiveDir(t *testing.T) {
+	withMockRunner(t, func(m *MockShellRunner) {
+		m.Responses["queryCaseSensitiveInfo"] = `Case sensitive attribute on directory C:\proj is enabled.`
+	}, func() {
+		if !IsCaseSensitiveDir(`C:\proj`) {
+			t.Error("Expected C:\\proj to be case sensitive")
+		}
+	})
+
+	withMockRunner(t, func(m *MockShellRunner) {
+		m.Responses["queryCaseSensitiveInfo"] = `Case sensitive attribute on directory C:\proj is disabled.`
+	}, func() {
+		if IsCaseSensitiveDir(`C:\proj`) {
+			t.Error("Expected C:\\proj not to be case sensitive")
+		}
+	})
+
+	if IsCaseSensitiveDir(`%USERPROFILE%\bin`) {
+		t.Error("Entries with variables should never be reported as case sensitive")
+	}
+}
+
+func TestCaseVariantDir(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{`C:\proj\Bin`, `C:\proj\bin`, `C:\proj\`},
+		{`C:\Proj\bin`, `C:\proj\bin`, `C:\`},
+		{`C:\proj\bin\`, `C:\proj\Bin`, `C:\proj\`},
+		{`C:\proj\bin`, `C:\proj\bin`, ""},
+		{`C:\proj\bin`, `C:\other\bin`, ""},
+		{`c:\proj`, `C:\proj`, ""},
+	}
+	for _, tt := range tests {
+		if got := caseVariantDir(tt.a, tt.b); got != tt.want {
+			t.Errorf("caseVariantDir(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestOptimize_CaseVariantsKeptInCaseSensitiveDir(t *testing.T) {
+	opts := OptimizeOptions{RemoveDuplicates: true}
+	withMockRunner(t, func(m *MockShellRunner) {
+		m.Responses["queryCaseSensitiveInfo"] = `Case sensitive attribute on directory C:\proj is enabled.`
+	}, func() {
+		result := Optimize(`C:\proj\Bin;C:\proj\bin;C:\proj\bin;C:\proj\Bin`, opts)
+
+		if len(result.Optimized.Entries) != 2 {
+			t.Fatalf("Expected both case variants kept, got %v", result.Optimized.Entries)
+		}
+		if result.Metrics.DuplicatesRemoved != 2 {
+			t.Errorf("Expected exact repeats still removed, got %d", result.Metrics.DuplicatesRemoved)
+		}
+		if len(result.CaseConflicts) != 1 {
+			t.Fatalf("Expected 1 case conflict, got %d", len(result.CaseConflicts))
+		}
+		c := result.CaseConflicts[0]
+		if c.Entry != `C:\proj\Bin` || c.Variant != `C:\proj\bin` || c.Directory != `C:\proj\` {
+			t.Errorf("Unexpected conflict: %+v", c)
+		}
+	})
+}
+
+func TestOptimize_CaseVariantsDedupedByDefault(t *testing.T) {
+	opts := OptimizeOptions{RemoveDuplicates: true}
+	withMockRunner(t, func(m *MockShellRunner) {
+		m.Responses["queryCaseSensitiveInfo"] = `Case sensitive attribute on directory C:\proj is disabled.`
+	}, func() {
+		mock := DefaultRunner.(*MockShellRunner)
+		before := len(mock.Calls)
+
+		result := Optimize(`C:\proj\Bin;C:\proj\bin;C:\proj\BIN`, opts)
+
+		if len(result.Optimized.Entries) != 1 {
+			t.Errorf("Expected case variants deduped, got %v", result.Optimized.Entries)
+		}
+		if len(result.CaseConflicts) != 0 {
+			t.Errorf("Expected no case conflicts, got %d", len(result.CaseConflicts))
+		}
+		queries := 0
+		for _, c := range mock.Calls[before:] {
+			if strings.Contains(c, "queryCaseSensitiveInfo") {
+				queries++
+			}
+		}
+		if queries != 1 {
+			t.Errorf("Expected the directory flag to be queried once, got %d", queries)
+		}
+	})
+}
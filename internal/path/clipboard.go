@@ -0,0 +1,54 @@
+package path
+
+import "github.com/atotto/clipboard"
+
+// ClipboardWriter interface for writing to the system clipboard
+// This allows mocking in tests and headless environments
+type ClipboardWriter interface {
+	WriteAll(text string) error
+}
+
+// RealClipboard writes to the actual system clipboard
+type RealClipboard struct{}
+
+// WriteAll copies text to the system clipboard
+func (c *RealClipboard) WriteAll(text string) error {
+	return clipboard.WriteAll(text)
+}
+
+// DefaultClipboard is the package-level clipboard writer
+// Tests can replace this with a mock
+var DefaultClipboard ClipboardWriter = &RealClipboard{}
+
+// CopyToClipboard copies text to the Windows clipboard
+func CopyToClipboard(text string) error {
+	return DefaultClipboard.WriteAll(text)
+}
+
+// MockClipboard for testing
+type MockClipboard struct {
+	Writes []string
+	Err    error
+}
+
+// NewMockClipboard creates a new mock clipboard
+func NewMockClipboard() *MockClipboard {
+	return &MockClipboard{Writes: []string{}}
+}
+
+// WriteAll records the text, or returns Err when set
+func (m *MockClipboard) WriteAll(text string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Writes = append(m.Writes, text)
+	return nil
+}
+
+// Last returns the most recently written text
+func (m *MockClipboard) Last() string {
+	if len(m.Writes) == 0 {
+		return ""
+	}
+	return m.Writes[len(m.Writes)-1]
+}
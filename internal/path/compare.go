@@ -0,0 +1,71 @@
+package path
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// PathComparison is the result of comparing an imported PATH against a baseline
+type PathComparison struct {
+	Baseline      []string       `json:"baseline"`
+	Imported      []string       `json:"imported"`
+	Diff          PathDiff       `json:"diff"`
+	Optimized     OptimizeResult `json:"optimized"`
+	NestedEntries []NestedEntry  `json:"nestedEntries,omitempty"`
+}
+
+// ImportPathFromFile reads PATH entries from a text file, such as the output of
+// `echo %PATH%` or `$env:Path` from another machine
+// Entries may be separated by semicolons or newlines, and a leading PATH= is ignored
+func ImportPathFromFile(file string) ([]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	var entries []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) >= 5 && strings.EqualFold(line[:5], "PATH=") {
+			line = line[5:]
+		}
+		entries = append(entries, ParsePath(line)...)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no PATH entries found in %s", file)
+	}
+	return entries, nil
+}
+
+// LivePathEntries returns the System PATH followed by the User PATH of this machine
+// Without PowerShell it returns the PATH this process inherited instead
+func LivePathEntries() []string {
+	sysPath, err := GetPathRaw("System")
+	if errors.Is(err, ErrPowerShellNotFound) {
+		return ParsePath(os.Getenv("PATH"))
+	}
+	usrPath, _ := GetPathRaw("User")
+	return append(ParsePath(sysPath), ParsePath(usrPath)...)
+}
+
+// ComparePaths diffs an imported PATH against a baseline and suggests optimizations
+// for the imported one. Only checks that don't depend on the local machine are used,
+// so nothing is looked up on disk and the registry is never touched
+func ComparePaths(baseline, imported []string) PathComparison {
+	opts := DefaultOptions()
+	opts.RemoveDeadPaths = false
+	opts.ShortenPaths = false
+	opts.SubstituteVars = false
+	opts.Offline = true
+
+	return PathComparison{
+		Baseline:      baseline,
+		Imported:      imported,
+		Diff:          DiffPaths(baseline, imported),
+		Optimized:     Optimize(JoinPath(imported), opts),
+		NestedEntries: FindNestedEntries(imported),
+	}
+}
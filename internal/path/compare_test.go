@@ -0,0 +1,115 @@
+package path
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writePathFile(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "path.txt")
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", file, err)
+	}
+	return file
+}
+
+func TestImportPathFromFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"semicolons", `C:\Windows;C:\Tools;`},
+		{"newlines", "C:\\Windows\r\nC:\\Tools\r\n"},
+		{"prefix", "PATH=C:\\Windows;C:\\Tools\n"},
+		{"bom", "\ufeffC:\\Windows;C:\\Tools"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := ImportPathFromFile(writePathFile(t, tt.content))
+			if err != nil {
+				t.Fatalf("ImportPathFromFile error: %v", err)
+			}
+			if !reflect.DeepEqual(entries, []string{`C:\Windows`, `C:\Tools`}) {
+				t.Errorf("Unexpected entries: %q", entries)
+			}
+		})
+	}
+}
+
+func TestImportPathFromFile_Errors(t *testing.T) {
+	if _, err := ImportPathFromFile(writePathFile(t, " \n;;\n")); err == nil {
+		t.Error("Expected error for file without entries")
+	}
+	if _, err := ImportPathFromFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+func TestComparePaths_TwoFiles(t *testing.T) {
+	mock := getMockRunner(t)
+	before := len(mock.Calls)
+
+	baseline, err := ImportPathFromFile(writePathFile(t, `C:\Windows;C:\Tools;C:\Old`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := ImportPathFromFile(writePathFile(t, `C:\Windows;C:\Tools;C:\App;C:\App\bin;C:\tools\`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := ComparePaths(baseline, imported)
+
+	if !reflect.DeepEqual(c.Diff.Added, []string{`C:\App`, `C:\App\bin`}) {
+		t.Errorf("Added = %q", c.Diff.Added)
+	}
+	if !reflect.DeepEqual(c.Diff.Removed, []string{`C:\Old`}) {
+		t.Errorf("Removed = %q", c.Diff.Removed)
+	}
+	if c.Optimized.Metrics.DuplicatesRemoved != 1 {
+		t.Errorf("Expected the duplicate C:\\tools\\ to be suggested for removal, got %d", c.Optimized.Metrics.DuplicatesRemoved)
+	}
+	if len(c.NestedEntries) != 1 {
+		t.Errorf("Expected 1 nested entry, got %+v", c.NestedEntries)
+	}
+
+	for _, call := range mock.Calls[before:] {
+		if strings.Contains(call, "Registry") || strings.Contains(call, "SetEnvironmentVariable") {
+			t.Errorf("Comparing files should not touch the registry: %s", call)
+		}
+	}
+}
+
+func TestComparePaths_IgnoresLocalMachine(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+	config := LoadConfig()
+	config.HotPaths = []string{`C:\App`}
+	if err := SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetResponse("queryCaseSensitiveInfo", "Case sensitive attribute on directory C:\\ is enabled.")
+	}, func() {
+		mock := getMockRunner(t)
+		before := len(mock.Calls)
+
+		c := ComparePaths([]string{`C:\Windows`}, []string{`C:\Windows`, `C:\Tools`, `C:\tools`, `C:\App`})
+		if c.Optimized.Metrics.DuplicatesRemoved != 1 || len(c.Optimized.CaseConflicts) != 0 {
+			t.Errorf("Expected the case variant treated as a duplicate, got %+v", c.Optimized.Metrics)
+		}
+		for _, change := range c.Optimized.Changes {
+			if change.Type == "reordered" {
+				t.Errorf("Local hot paths should not reorder another machine's PATH: %+v", change)
+			}
+		}
+		if n := len(mock.Calls) - before; n != 0 {
+			t.Errorf("Expected no shell calls, got %d: %v", n, mock.Calls[before:])
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package path
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Section headers of the diagnostics report, in the order they are written
+const (
+	DiagSectionAdmin      = "Admin"
+	DiagSectionPathLength = "PATH Lengths"
+	DiagSectionPathExt    = "PATHEXT"
+	DiagSectionConfig     = "Config"
+	DiagSectionBackups    = "Backups"
+	DiagSectionShell      = "PowerShell"
+)
+
+// powerShellVersionCommand prints the running PowerShell version
+const powerShellVersionCommand = `$PSVersionTable.PSVersion.ToString()`
+
+// BuildDiagnosticsReport gathers the environment state useful in a bug report
+// The user's home folder and account name are replaced with placeholders
+func BuildDiagnosticsReport() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "WinPath diagnostics, %s\n", time.Now().Format("2006-01-02 15:04:05"))
+
+	section := func(name string) { fmt.Fprintf(&b, "\n== %s ==\n", name) }
+
+	section(DiagSectionAdmin)
+	fmt.Fprintf(&b, "Running as administrator: %v\n", IsAdmin())
+
+	section(DiagSectionPathLength)
+	for _, scope := range []string{"System", "User"} {
+		raw, err := GetPathRaw(scope)
+		if err != nil {
+			fmt.Fprintf(&b, "%s: error: %v\n", scope, err)
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %d chars, %d entries\n", scope, len(raw), len(ParsePath(raw)))
+	}
+
+	section(DiagSectionPathExt)
+	pathExt := AnalyzePathExt()
+	fmt.Fprintf(&b, "Current: %s\n", strings.Join(pathExt.Current, ";"))
+	fmt.Fprintf(&b, "User override: %v\n", pathExt.HasUserPathExt)
+	for _, issue := range pathExt.Issues {
+		fmt.Fprintf(&b, "Issue: %s\n", issue.Message)
+	}
+
+	section(DiagSectionConfig)
+	fmt.Fprintf(&b, "File: %s\n", GetConfigPath())
+	if data, err := json.MarshalIndent(LoadConfig(), "", "  "); err != nil {
+		fmt.Fprintf(&b, "error: %v\n", err)
+	} else {
+		b.Write(data)
+		b.WriteString("\n")
+	}
+
+	section(DiagSectionBackups)
+	fmt.Fprintf(&b, "Folder: %s\n", GetBackupDir())
+	fmt.Fprintf(&b, "Count: %d\n", len(ListBackups()))
+
+	section(DiagSectionShell)
+	fmt.Fprintf(&b, "Executable: %s\n", PowerShellExe)
+	if version, err := RunPowerShell(powerShellVersionCommand); err != nil {
+		fmt.Fprintf(&b, "Available: false (%v)\n", err)
+	} else {
+		fmt.Fprintf(&b, "Available: true, version %s\n", strings.TrimSpace(version))
+	}
+
+	return redactDiagnostics(b.String())
+}
+
+// SaveDiagnosticsReport writes the diagnostics report to the export directory and returns its path
+func SaveDiagnosticsReport() (string, error) {
+	report := BuildDiagnosticsReport()
+	return writeExportFile(fmt.Sprintf("diagnostics_%s.txt", exportTimestamp()), []byte(report))
+}
+
+// redactDiagnostics replaces the home folder and account name, in any case, with placeholders
+func redactDiagnostics(report string) string {
+	if home, err := os.UserHomeDir(); err == nil && len(home) > 3 {
+		report = replaceFold(report, home, "%USERPROFILE%")
+	}
+	for _, key := range []string{"USERNAME", "USER"} {
+		if name := os.Getenv(key); len(name) > 1 {
+			report = replaceFold(report, name, "<user>")
+		}
+	}
+	return report
+}
+
+// replaceFold replaces every case-insensitive occurrence of old in s
+func replaceFold(s, old, replacement string) string {
+	re := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(old))
+	return re.ReplaceAllLiteralString(s, replacement)
+}
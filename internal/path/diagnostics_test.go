@@ -0,0 +1,58 @@
+package path
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveDiagnosticsReport_IncludesEverySection(t *testing.T) {
+	home := filepath.Join(t.TempDir(), "Jordan")
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("USERNAME", "")
+	t.Setenv("USER", "")
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(filepath.Join(home, ".winpath"))
+
+	withMockRunner(t, func(m *MockShellRunner) {
+		m.SetResponse("PSVersionTable", "5.1.19041.4291")
+	}, func() {
+		saved, err := SaveDiagnosticsReport()
+		if err != nil {
+			t.Fatalf("SaveDiagnosticsReport failed: %v", err)
+		}
+		if filepath.Dir(saved) != GetExportDir() {
+			t.Errorf("Expected the report in %s, got %s", GetExportDir(), saved)
+		}
+		data, err := os.ReadFile(saved)
+		if err != nil {
+			t.Fatalf("Reading the report failed: %v", err)
+		}
+		report := string(data)
+
+		for _, header := range []string{DiagSectionAdmin, DiagSectionPathLength, DiagSectionPathExt, DiagSectionConfig, DiagSectionBackups, DiagSectionShell} {
+			if !strings.Contains(report, "== "+header+" ==") {
+				t.Errorf("Expected section %q in report:\n%s", header, report)
+			}
+		}
+		if !strings.Contains(report, "Executable: "+PowerShellExe) || !strings.Contains(report, "version 5.1.19041.4291") {
+			t.Errorf("Expected the PowerShell executable and version, got:\n%s", report)
+		}
+		if strings.Contains(report, home) || !strings.Contains(report, `%USERPROFILE%`) {
+			t.Errorf("Expected the home folder redacted, got:\n%s", report)
+		}
+	})
+}
+
+func TestBuildDiagnosticsReport_PowerShellUnavailable(t *testing.T) {
+	withMockRunner(t, func(m *MockShellRunner) {
+		m.SetError("PSVersionTable", os.ErrNotExist)
+	}, func() {
+		report := BuildDiagnosticsReport()
+		if !strings.Contains(report, "Available: false") {
+			t.Errorf("Expected PowerShell reported unavailable, got:\n%s", report)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package path
+
+// PathDiff lists the entries added and removed between two PATH entry lists
+type PathDiff struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// Empty reports whether the two lists contain the same entries
+func (d PathDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// DiffPaths compares two PATH entry lists, ignoring case, trailing slashes and order
+func DiffPaths(before, after []string) PathDiff {
+	diff := PathDiff{Added: []string{}, Removed: []string{}}
+
+	inBefore := make(map[string]bool, len(before))
+	for _, e := range before {
+		inBefore[NormalizePath(e)] = true
+	}
+	inAfter := make(map[string]bool, len(after))
+	for _, e := range after {
+		inAfter[NormalizePath(e)] = true
+	}
+
+	seen := make(map[string]bool)
+	for _, e := range after {
+		n := NormalizePath(e)
+		if !inBefore[n] && !seen[n] {
+			diff.Added = append(diff.Added, e)
+			seen[n] = true
+		}
+	}
+	for _, e := range before {
+		n := NormalizePath(e)
+		if !inAfter[n] && !seen[n] {
+			diff.Removed = append(diff.Removed, e)
+			seen[n] = true
+		}
+	}
+	return diff
+}
+
+// DiffBackups compares the System and User PATH of two backups, from older to newer
+func DiffBackups(older, newer *Backup) (system, user PathDiff) {
+	system = DiffPaths(older.SystemPath.Entries, newer.SystemPath.Entries)
+	user = DiffPaths(older.UserPath.Entries, newer.UserPath.Entries)
+	return system, user
+}
@@ -0,0 +1,44 @@
+package path
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffPaths(t *testing.T) {
+	before := []string{`C:\Windows`, `C:\Old\bin`, `C:\Tools`}
+	after := []string{`c:\windows\`, `C:\Tools`, `C:\New\bin`, `C:\New\bin`}
+
+	diff := DiffPaths(before, after)
+
+	if !reflect.DeepEqual(diff.Added, []string{`C:\New\bin`}) {
+		t.Errorf("Added = %v", diff.Added)
+	}
+	if !reflect.DeepEqual(diff.Removed, []string{`C:\Old\bin`}) {
+		t.Errorf("Removed = %v", diff.Removed)
+	}
+}
+
+func TestDiffPaths_Identical(t *testing.T) {
+	diff := DiffPaths([]string{`C:\A`, `C:\B`}, []string{`C:\B`, `C:\A`})
+	if !diff.Empty() {
+		t.Errorf("Reordered lists should not differ, got %+v", diff)
+	}
+}
+
+func TestDiffBackups(t *testing.T) {
+	older := &Backup{}
+	older.SystemPath.Entries = []string{`C:\Windows`}
+	older.UserPath.Entries = []string{`C:\Users\Test\bin`}
+	newer := &Backup{}
+	newer.SystemPath.Entries = []string{`C:\Windows`, `C:\Go\bin`}
+
+	system, user := DiffBackups(older, newer)
+
+	if !reflect.DeepEqual(system.Added, []string{`C:\Go\bin`}) || len(system.Removed) != 0 {
+		t.Errorf("System diff = %+v", system)
+	}
+	if !reflect.DeepEqual(user.Removed, []string{`C:\Users\Test\bin`}) || len(user.Added) != 0 {
+		t.Errorf("User diff = %+v", user)
+	}
+}
@@ -2,6 +2,7 @@ package path
 
 import (
 	"os"
+	"sort"
 	"strings"
 )
 
@@ -28,8 +29,15 @@ var SubstitutionPriority = []string{
 	"PNPM_HOME",
 }
 
-// SubstituteEnvVars replaces path prefixes with environment variables where beneficial
+// SubstituteEnvVars replaces path prefixes with environment variables where beneficial,
+// also considering the configured ExtraSubstitutionVars
 func SubstituteEnvVars(path string) (string, bool) {
+	return SubstituteEnvVarsWith(path, LoadConfig().ExtraSubstitutionVars)
+}
+
+// SubstituteEnvVarsWith is SubstituteEnvVars with extra candidate variables
+// merged into SubstitutionPriority
+func SubstituteEnvVarsWith(path string, extra []string) (string, bool) {
 	if path == "" || strings.Contains(path, "%") {
 		return path, false
 	}
@@ -47,12 +55,8 @@ func SubstituteEnvVars(path string) (string, bool) {
 		saved     int
 	}
 
-	for _, varName := range SubstitutionPriority {
-		value, ok := envVars[varName]
-		if !ok || value == "" {
-			continue
-		}
-
+	for _, varName := range substitutionCandidates(envVars, extra) {
+		value := envVars[varName]
 		valueLower := strings.ToLower(value)
 		if strings.HasPrefix(pathLower, valueLower) {
 			// Make sure we're at a path boundary
@@ -78,6 +82,26 @@ func SubstituteEnvVars(path string) (string, bool) {
 	return path, false
 }
 
+// substitutionCandidates merges extra into SubstitutionPriority, dropping
+// unset variables and duplicates, ordered by longest expanded value first
+func substitutionCandidates(envVars map[string]string, extra []string) []string {
+	seen := make(map[string]bool)
+	var candidates []string
+	for _, name := range append(append([]string{}, SubstitutionPriority...), extra...) {
+		name = strings.Trim(strings.TrimSpace(name), "%")
+		key := strings.ToLower(name)
+		if name == "" || seen[key] || envVars[name] == "" {
+			continue
+		}
+		seen[key] = true
+		candidates = append(candidates, name)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return len(envVars[candidates[i]]) > len(envVars[candidates[j]])
+	})
+	return candidates
+}
+
 // expandShortUserPath expands 8.3 short names in the user profile portion of a path
 // E.g., C:\Users\JEREMY~1\AppData -> C:\Users\Jeremy\AppData
 func expandShortUserPath(path string) string {
@@ -165,13 +189,115 @@ func ExpandEnvVars(path string) string {
 	return result
 }
 
+// UnresolvedVars returns the names of %VAR% references left in an already-expanded path
+func UnresolvedVars(path string) []string {
+	var names []string
+	rest := path
+	for {
+		start := strings.Index(rest, "%")
+		if start == -1 {
+			break
+		}
+		end := strings.Index(rest[start+1:], "%")
+		if end == -1 {
+			break
+		}
+		name := rest[start+1 : start+1+end]
+		if name != "" && !strings.ContainsAny(name, `\/`) {
+			names = append(names, name)
+			rest = rest[start+end+2:]
+		} else {
+			rest = rest[start+1:]
+		}
+	}
+	return names
+}
+
+// FindVarsReferencing returns the sorted names of environment variables whose value
+// contains dir as one of its ;-separated entries
+func FindVarsReferencing(dir string) []string {
+	target := NormalizePath(ExpandEnvVars(dir))
+	var names []string
+	for name, value := range GetAllEnvVars() {
+		for _, entry := range ParsePath(value) {
+			if NormalizePath(ExpandEnvVars(entry)) == target {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MisdirectedVar is a non-PATH variable whose value looks like PATH entries,
+// usually left behind by an installer that appended to the wrong variable
+type MisdirectedVar struct {
+	Name    string   `json:"name"`
+	Entries []string `json:"entries"`
+}
+
+// pathListVars are variables that legitimately hold a ;-separated list of folders
+var pathListVars = map[string]bool{
+	"path": true, "psmodulepath": true, "pathext": true,
+	"classpath": true, "pythonpath": true, "node_path": true,
+	"include": true, "lib": true, "libpath": true,
+}
+
+// LooksLikePathList reports whether a variable holds a ;-separated list of folders,
+// either because it is a known list variable or because its value has two or more
+// entries that all look like paths. Nothing is checked on disk
+func LooksLikePathList(name, value string) bool {
+	if pathListVars[strings.ToLower(name)] {
+		return true
+	}
+	entries := ParsePath(value)
+	if len(entries) < 2 {
+		return false
+	}
+	for _, entry := range entries {
+		if !strings.ContainsAny(entry, `\/`) {
+			return false
+		}
+	}
+	return true
+}
+
+// FindMisdirectedPathVars reports variables other than PATH whose value is a
+// ;-separated list of two or more directories that all exist, sorted by name
+func FindMisdirectedPathVars(vars map[string]string) []MisdirectedVar {
+	result := make([]MisdirectedVar, 0)
+	for name, value := range vars {
+		if pathListVars[strings.ToLower(name)] || !strings.Contains(value, ";") {
+			continue
+		}
+		entries := ParsePath(value)
+		if len(entries) < 2 {
+			continue
+		}
+		allDirs := true
+		for _, entry := range entries {
+			info, err := os.Stat(ExpandEnvVars(entry))
+			if err != nil || !info.IsDir() {
+				allDirs = false
+				break
+			}
+		}
+		if allDirs {
+			result = append(result, MisdirectedVar{Name: name, Entries: entries})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
 // GetEnvVariable gets a specific environment variable value
 func GetEnvVariable(name, scope string) (string, error) {
 	var command string
 	if scope == "System" {
-		command = `[Microsoft.Win32.Registry]::LocalMachine.OpenSubKey('SYSTEM\CurrentControlSet\Control\Session Manager\Environment')?.GetValue('` + name + `', '', [Microsoft.Win32.RegistryValueOptions]::DoNotExpandEnvironmentNames)`
+		command = `[Microsoft.Win32.Registry]::LocalMachine.OpenSubKey('SYSTEM\CurrentControlSet\Control\Session Manager\Environment')?.GetValue('` + escapePSString(name) + `', '', [Microsoft.Win32.RegistryValueOptions]::DoNotExpandEnvironmentNames)`
 	} else {
-		command = `[Microsoft.Win32.Registry]::CurrentUser.OpenSubKey('Environment')?.GetValue('` + name + `', '', [Microsoft.Win32.RegistryValueOptions]::DoNotExpandEnvironmentNames)`
+		command = `[Microsoft.Win32.Registry]::CurrentUser.OpenSubKey('Environment')?.GetValue('` + escapePSString(name) + `', '', [Microsoft.Win32.RegistryValueOptions]::DoNotExpandEnvironmentNames)`
 	}
 	return RunPowerShell(command)
 }
@@ -182,8 +308,7 @@ func SetEnvVariable(name, value, scope string) error {
 	if scope == "System" {
 		target = "Machine"
 	}
-	escaped := strings.ReplaceAll(value, "'", "''")
-	command := `[Environment]::SetEnvironmentVariable('` + name + `', '` + escaped + `', '` + target + `')`
+	command := `[Environment]::SetEnvironmentVariable('` + escapePSString(name) + `', '` + escapePSString(value) + `', '` + target + `')`
 	_, err := RunPowerShell(command)
 	if err == nil {
 		BroadcastEnvChange()
@@ -2,6 +2,7 @@ package path
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -220,6 +221,76 @@ func TestSubstituteEnvVars_ExactMatch(t *testing.T) {
 	}
 }
 
+func TestSubstituteEnvVars_ExtraVars(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+	toolsHome := filepath.Join(t.TempDir(), "Custom Tool Home")
+	t.Setenv("WINPATH_TOOLS_HOME", toolsHome)
+	input := toolsHome + `\bin`
+
+	if _, changed := SubstituteEnvVars(input); changed {
+		t.Fatal("Expected no substitution before the variable is configured")
+	}
+
+	config := LoadConfig()
+	config.ExtraSubstitutionVars = []string{"%WINPATH_TOOLS_HOME%"}
+	if err := SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	result, changed := SubstituteEnvVars(input)
+	if !changed || result != `%WINPATH_TOOLS_HOME%\bin` {
+		t.Errorf("Expected the extra variable to be substituted, got %q (changed=%v)", result, changed)
+	}
+}
+
+func TestOptimize_SubstitutesDetectedCustomVars(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+	toolHome := filepath.Join(t.TempDir(), "My Tool Home")
+	t.Setenv("MY_TOOL_HOME", toolHome)
+
+	opts := DefaultOptions()
+	opts.RemoveDeadPaths = false
+	opts.ShortenPaths = false
+
+	// Not used anywhere in the PATH, so not a candidate
+	result := Optimize(toolHome+`\bin`, opts)
+	if result.Metrics.VarsSubstituted != 0 {
+		t.Fatalf("Expected no substitution for an unused custom variable, got %v", result.Optimized.Entries)
+	}
+
+	result = Optimize(`%MY_TOOL_HOME%\lib;`+toolHome+`\bin`, opts)
+	want := `%MY_TOOL_HOME%\bin`
+	if len(result.Optimized.Entries) != 2 || result.Optimized.Entries[1] != want {
+		t.Fatalf("Expected %s, got %v", want, result.Optimized.Entries)
+	}
+	found := false
+	for _, c := range result.Changes {
+		if c.Type == "variable" && c.Original == toolHome+`\bin` && c.New == want {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected a variable change for the literal entry, got %+v", result.Changes)
+	}
+
+	opts.Offline = true
+	if result := Optimize(`%MY_TOOL_HOME%\lib;`+toolHome+`\bin`, opts); result.Metrics.VarsSubstituted != 0 {
+		t.Error("An offline PATH should not be matched against local custom variables")
+	}
+}
+
+func TestSubstituteEnvVarsWith_LongerValueWins(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "tools")
+	t.Setenv("WINPATH_TOOLS", base)
+	t.Setenv("WINPATH_TOOLS_GO", base+`\go`)
+
+	result, changed := SubstituteEnvVarsWith(base+`\go\bin`, []string{"WINPATH_TOOLS", "WINPATH_TOOLS_GO"})
+	if !changed || result != `%WINPATH_TOOLS_GO%\bin` {
+		t.Errorf("Expected the variable with the longer value to win, got %q", result)
+	}
+}
+
 func TestExpandEnvVars_NestedNotSupported(t *testing.T) {
 	// Windows doesn't support nested expansion like %VAR1%VAR2%%
 	input := `%SystemRoot%%USERPROFILE%`
@@ -333,3 +404,112 @@ func TestSetEnvVariable_System(t *testing.T) {
 		t.Logf("SetEnvVariable error (expected without admin): %v", err)
 	}
 }
+
+func TestUnresolvedVars(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{`C:\Windows\System32`, nil},
+		{`%UNSET_TOOLS%\bin`, []string{"UNSET_TOOLS"}},
+		{`C:\%A%\%B%`, []string{"A", "B"}},
+		{`C:\100%\done`, nil},
+		{`C:\50%\x\%REAL%`, []string{"REAL"}},
+	}
+
+	for _, tt := range tests {
+		got := UnresolvedVars(tt.input)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("UnresolvedVars(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFindVarsReferencing(t *testing.T) {
+	t.Setenv("WINPATH_TEST_TOOLS", `C:\Tools\Bin`)
+	t.Setenv("WINPATH_TEST_LIST", `C:\Other;c:\tools\bin\`)
+	t.Setenv("WINPATH_TEST_NESTED", `%WINPATH_TEST_TOOLS%`)
+	t.Setenv("WINPATH_TEST_PARENT", `C:\Tools`)
+
+	got := FindVarsReferencing(`C:\Tools\Bin`)
+
+	want := []string{"WINPATH_TEST_LIST", "WINPATH_TEST_NESTED", "WINPATH_TEST_TOOLS"}
+	var found []string
+	for _, name := range got {
+		if strings.HasPrefix(name, "WINPATH_TEST_") {
+			found = append(found, name)
+		}
+	}
+	if strings.Join(found, ",") != strings.Join(want, ",") {
+		t.Errorf("FindVarsReferencing = %v, want %v", found, want)
+	}
+}
+
+func TestLooksLikePathList(t *testing.T) {
+	tests := []struct {
+		name, value string
+		want        bool
+	}{
+		{"Path", `C:\Windows`, true},
+		{"PSModulePath", "", true},
+		{"TOOLS", `C:\Tools\bin;D:\Other`, true},
+		{"TOOLS", `C:\Tools\bin`, false},
+		{"COMPILER_FLAGS", "-O2;-g", false},
+		{"USERNAME", "dev", false},
+	}
+	for _, tt := range tests {
+		if got := LooksLikePathList(tt.name, tt.value); got != tt.want {
+			t.Errorf("LooksLikePathList(%q, %q) = %v, want %v", tt.name, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestFindMisdirectedPathVars(t *testing.T) {
+	a, b := t.TempDir(), t.TempDir()
+	file := filepath.Join(a, "file.txt")
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	vars := map[string]string{
+		"PATH":         a + ";" + b,
+		"PSModulePath": a + ";" + b,
+		"TOOLS_HOME":   a + ";" + b + ";",
+		"SINGLE":       a,
+		"MISSING":      a + `;C:\does\not\exist\anywhere`,
+		"WITH_FILE":    a + ";" + file,
+		"NOT_A_PATH":   "foo;bar",
+	}
+
+	result := FindMisdirectedPathVars(vars)
+	if len(result) != 1 {
+		t.Fatalf("Expected 1 misdirected variable, got %+v", result)
+	}
+	if result[0].Name != "TOOLS_HOME" {
+		t.Errorf("Expected TOOLS_HOME, got %s", result[0].Name)
+	}
+	if len(result[0].Entries) != 2 || result[0].Entries[0] != a || result[0].Entries[1] != b {
+		t.Errorf("Unexpected entries: %v", result[0].Entries)
+	}
+}
+
+func TestAnalyzeAll_MisdirectedVars(t *testing.T) {
+	a, b := t.TempDir(), t.TempDir()
+	t.Setenv("WINPATH_TEST_MISDIRECTED", a+";"+b)
+
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetResponse("LocalMachine.OpenSubKey", `C:\Windows`)
+		mock.SetResponse("CurrentUser.OpenSubKey", ``)
+	}, func() {
+		result, err := AnalyzeAll(DefaultOptions())
+		if err != nil {
+			t.Fatalf("AnalyzeAll failed: %v", err)
+		}
+		for _, v := range result.MisdirectedVars {
+			if v.Name == "WINPATH_TEST_MISDIRECTED" {
+				return
+			}
+		}
+		t.Errorf("Expected WINPATH_TEST_MISDIRECTED to be reported, got %+v", result.MisdirectedVars)
+	})
+}
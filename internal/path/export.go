@@ -0,0 +1,200 @@
+package path
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// GetExportDir returns the directory where exported files are written
+func GetExportDir() string {
+	return filepath.Join(getConfigDir(), "exports")
+}
+
+// writeExportFile writes data to a new file in the export directory and returns its path
+func writeExportFile(filename string, data []byte) (string, error) {
+	dir := GetExportDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	fullPath := filepath.Join(dir, filename)
+	if err := os.WriteFile(fullPath, data, 0644); err != nil {
+		return "", err
+	}
+	return fullPath, nil
+}
+
+// exportTimestamp returns a filename-safe timestamp for export files
+func exportTimestamp() string {
+	return time.Now().Format("20060102_150405")
+}
+
+// ExportAnalysisJSON serializes an analysis result to indented JSON
+func ExportAnalysisJSON(r AnalysisResult) ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// SaveAnalysisExport writes the analysis as JSON to the export directory
+func SaveAnalysisExport(r AnalysisResult) (string, error) {
+	data, err := ExportAnalysisJSON(r)
+	if err != nil {
+		return "", err
+	}
+	return writeExportFile(fmt.Sprintf("analysis_%s.json", exportTimestamp()), data)
+}
+
+// ExportEntries writes PATH entries one per line, in order
+func ExportEntries(entries []string, w io.Writer) error {
+	for _, e := range entries {
+		if _, err := io.WriteString(w, e+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveEntriesExport writes entries as a text file in the export directory and returns its path
+// A "#" header names the scope (and whether the entries are optimized) and the export time
+func SaveEntriesExport(entries []string, scope string, optimized bool) (string, error) {
+	label, name := scope+" PATH", strings.ToLower(scope)
+	if optimized {
+		label += " (optimized)"
+		name += "_optimized"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s, %d entries\n# Exported by WinPath on %s\n", label, len(entries), time.Now().Format("2006-01-02 15:04:05"))
+	if err := ExportEntries(entries, &b); err != nil {
+		return "", err
+	}
+	return writeExportFile(fmt.Sprintf("path_%s_%s.txt", name, exportTimestamp()), []byte(b.String()))
+}
+
+// ImportEntries reads PATH entries one per line, the format written by ExportEntries
+// Blank lines and lines starting with "#" are skipped, and every other line must be a
+// plausible Windows directory
+func ImportEntries(r io.Reader) ([]string, error) {
+	var entries []string
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if n == 1 {
+			line = strings.TrimSpace(strings.TrimPrefix(line, "\ufeff"))
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := ValidateEntry(line); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		entries = append(entries, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ImportEntriesFile reads a newline-delimited list of PATH entries from file
+func ImportEntriesFile(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	entries, err := ImportEntries(f)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no PATH entries found in %s", file)
+	}
+	return entries, nil
+}
+
+// ValidateEntry reports why entry is not a plausible PATH directory, or nil if it is
+// Absolute drive paths, UNC paths and paths starting with a %VARIABLE% are accepted
+func ValidateEntry(entry string) error {
+	if strings.Contains(entry, ";") {
+		return fmt.Errorf("%q contains ';'", entry)
+	}
+	if suggestion := MalformedSuggestion(entry); suggestion != "" {
+		return fmt.Errorf("%q uses forward slashes, did you mean %s?", entry, suggestion)
+	}
+	if i := strings.IndexAny(entry, `<>"|?*`); i >= 0 {
+		return fmt.Errorf("%q contains the invalid character %q", entry, entry[i])
+	}
+	switch {
+	case hasDrivePrefix(entry) && len(entry) > 2 && entry[2] == '\\':
+	case strings.HasPrefix(entry, `\\`) && len(entry) > 2:
+	case strings.HasPrefix(entry, "%") && strings.Count(entry, "%") >= 2:
+	default:
+		return fmt.Errorf("%q is not an absolute path", entry)
+	}
+	return nil
+}
+
+// Registry keys holding the System and User environment in .reg syntax
+const (
+	regSystemEnvKey = `HKEY_LOCAL_MACHINE\SYSTEM\CurrentControlSet\Control\Session Manager\Environment`
+	regUserEnvKey   = `HKEY_CURRENT_USER\Environment`
+)
+
+// ExportBackupReg renders a backup as .reg content that restores PATH when imported
+// scope is "System", "User" or "both"; Path is written as REG_EXPAND_SZ so variables keep working
+func ExportBackupReg(b *Backup, scope string) (string, error) {
+	var keys []string
+	switch strings.ToLower(scope) {
+	case "system":
+		keys = append(keys, regKeyBlock(regSystemEnvKey, b.SystemPath.Raw, b.SystemPath.Entries))
+	case "user":
+		keys = append(keys, regKeyBlock(regUserEnvKey, b.UserPath.Raw, b.UserPath.Entries))
+	case "both":
+		keys = append(keys,
+			regKeyBlock(regSystemEnvKey, b.SystemPath.Raw, b.SystemPath.Entries),
+			regKeyBlock(regUserEnvKey, b.UserPath.Raw, b.UserPath.Entries))
+	default:
+		return "", fmt.Errorf("invalid scope %q (want System, User, or both)", scope)
+	}
+	return "Windows Registry Editor Version 5.00\r\n\r\n" + strings.Join(keys, "\r\n"), nil
+}
+
+// regKeyBlock renders one key with its Path value
+func regKeyBlock(key, raw string, entries []string) string {
+	if raw == "" {
+		raw = JoinPath(entries)
+	}
+	return "[" + key + "]\r\n\"Path\"=hex(2):" + regExpandSzHex(raw) + "\r\n"
+}
+
+// regExpandSzHex encodes s as the comma-separated UTF-16LE bytes regedit expects,
+// including the terminating null
+func regExpandSzHex(s string) string {
+	units := append(utf16.Encode([]rune(s)), 0)
+	parts := make([]string, 0, len(units)*2)
+	for _, u := range units {
+		parts = append(parts, fmt.Sprintf("%02x", byte(u)), fmt.Sprintf("%02x", byte(u>>8)))
+	}
+	return strings.Join(parts, ",")
+}
+
+// SaveBackupReg writes a backup as a .reg file to the export directory
+// The file is UTF-16LE with a byte order mark, the encoding regedit writes itself
+func SaveBackupReg(b *Backup, scope string) (string, error) {
+	content, err := ExportBackupReg(b, scope)
+	if err != nil {
+		return "", err
+	}
+	units := utf16.Encode([]rune(content))
+	data := make([]byte, 0, 2+len(units)*2)
+	data = append(data, 0xff, 0xfe)
+	for _, u := range units {
+		data = append(data, byte(u), byte(u>>8))
+	}
+	return writeExportFile(fmt.Sprintf("backup_%s.reg", b.Timestamp.Format(backupTimestampLayout)), data)
+}
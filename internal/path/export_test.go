@@ -0,0 +1,263 @@
+package path
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func sampleAnalysis() AnalysisResult {
+	r := AnalysisResult{}
+	r.System.Original = PathInfo{Raw: `C:\Windows;C:\Windows`, Entries: []string{`C:\Windows`, `C:\Windows`}, Length: 21, Count: 2}
+	r.System.Optimized = PathInfo{Raw: `C:\Windows`, Entries: []string{`C:\Windows`}, Length: 10, Count: 1}
+	r.System.Changes = []PathChange{{Type: "duplicate", Original: `C:\Windows`}}
+	r.System.Metrics = OptimizeMetrics{DuplicatesRemoved: 1, TotalSaved: 0, PercentageSaved: 52.4}
+	r.User.Original = PathInfo{Raw: `C:\Users\Test\AppData\Local\bin`, Entries: []string{`C:\Users\Test\AppData\Local\bin`}, Length: 31, Count: 1}
+	r.User.Optimized = PathInfo{Raw: `%LOCALAPPDATA%\bin`, Entries: []string{`%LOCALAPPDATA%\bin`}, Length: 18, Count: 1}
+	r.User.Changes = []PathChange{{Type: "variable", Original: `C:\Users\Test\AppData\Local\bin`, New: `%LOCALAPPDATA%\bin`, Saved: 13}}
+	r.User.Metrics = OptimizeMetrics{VarsSubstituted: 1, TotalSaved: 13, PercentageSaved: 41.9}
+	r.CustomVariables = []CustomPathVar{{Name: "MY_TOOLS", FoundIn: "User"}}
+	return r
+}
+
+func TestExportAnalysisJSON_RoundTrip(t *testing.T) {
+	original := sampleAnalysis()
+
+	data, err := ExportAnalysisJSON(original)
+	if err != nil {
+		t.Fatalf("ExportAnalysisJSON error: %v", err)
+	}
+
+	var decoded AnalysisResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if !reflect.DeepEqual(original, decoded) {
+		t.Errorf("Round trip mismatch:\noriginal: %+v\ndecoded:  %+v", original, decoded)
+	}
+}
+
+func TestExportAnalysisJSON_FieldNames(t *testing.T) {
+	data, err := ExportAnalysisJSON(sampleAnalysis())
+	if err != nil {
+		t.Fatalf("ExportAnalysisJSON error: %v", err)
+	}
+
+	out := string(data)
+	for _, key := range []string{
+		`"system"`, `"user"`, `"customVariables"`,
+		`"original"`, `"optimized"`, `"changes"`, `"metrics"`,
+		`"raw"`, `"entries"`, `"length"`, `"count"`,
+		`"duplicatesRemoved"`, `"percentageSaved"`, `"foundIn"`,
+	} {
+		if !strings.Contains(out, key) {
+			t.Errorf("Expected key %s in JSON output", key)
+		}
+	}
+}
+
+func TestSaveAnalysisExport(t *testing.T) {
+	file, err := SaveAnalysisExport(sampleAnalysis())
+	if err != nil {
+		t.Fatalf("SaveAnalysisExport error: %v", err)
+	}
+	defer os.Remove(file)
+
+	if filepath.Dir(file) != GetExportDir() {
+		t.Errorf("Expected file in %s, got %s", GetExportDir(), file)
+	}
+	if !strings.HasPrefix(filepath.Base(file), "analysis_") {
+		t.Errorf("Unexpected filename: %s", filepath.Base(file))
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("ReadFile error: %v", err)
+	}
+	var decoded AnalysisResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Errorf("Exported file is not valid JSON: %v", err)
+	}
+}
+
+func TestExportEntries_OnePerLineInOrder(t *testing.T) {
+	entries := []string{`C:\Windows\System32`, `%USERPROFILE%\bin`, `C:\Program Files\Git\cmd`}
+
+	var b strings.Builder
+	if err := ExportEntries(entries, &b); err != nil {
+		t.Fatalf("ExportEntries error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != len(entries) {
+		t.Fatalf("Expected %d lines, got %d:\n%s", len(entries), len(lines), b.String())
+	}
+	for i, e := range entries {
+		if lines[i] != e {
+			t.Errorf("Line %d: expected %q, got %q", i, e, lines[i])
+		}
+	}
+}
+
+func TestExportEntries_Empty(t *testing.T) {
+	var b strings.Builder
+	if err := ExportEntries(nil, &b); err != nil || b.Len() != 0 {
+		t.Errorf("Expected nothing written for no entries, got %q, %v", b.String(), err)
+	}
+}
+
+func TestSaveEntriesExport(t *testing.T) {
+	entries := []string{`C:\Tools`, `C:\Windows`}
+	file, err := SaveEntriesExport(entries, "User", true)
+	if err != nil {
+		t.Fatalf("SaveEntriesExport error: %v", err)
+	}
+	defer os.Remove(file)
+
+	if filepath.Dir(file) != GetExportDir() {
+		t.Errorf("Expected file in %s, got %s", GetExportDir(), file)
+	}
+	if !strings.HasPrefix(filepath.Base(file), "path_user_optimized_") || filepath.Ext(file) != ".txt" {
+		t.Errorf("Unexpected filename: %s", filepath.Base(file))
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("ReadFile error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 2 header and 2 entry lines, got:\n%s", data)
+	}
+	if lines[0] != "# User PATH (optimized), 2 entries" || !strings.HasPrefix(lines[1], "# Exported by WinPath on ") {
+		t.Errorf("Unexpected header:\n%s\n%s", lines[0], lines[1])
+	}
+	if lines[2] != entries[0] || lines[3] != entries[1] {
+		t.Errorf("Expected entries after the header, got %q", lines[2:])
+	}
+}
+
+func sampleBackup() *Backup {
+	b := &Backup{}
+	b.SystemPath.Raw = `%SystemRoot%\system32;C:\Windows`
+	b.SystemPath.Entries = ParsePath(b.SystemPath.Raw)
+	b.UserPath.Entries = []string{`C:\Tools`}
+	return b
+}
+
+func TestExportBackupReg_Both(t *testing.T) {
+	content, err := ExportBackupReg(sampleBackup(), "both")
+	if err != nil {
+		t.Fatalf("ExportBackupReg error: %v", err)
+	}
+
+	if !strings.HasPrefix(content, "Windows Registry Editor Version 5.00\r\n\r\n") {
+		t.Errorf("Missing .reg header: %q", content[:min(len(content), 40)])
+	}
+	for _, key := range []string{
+		`[HKEY_LOCAL_MACHINE\SYSTEM\CurrentControlSet\Control\Session Manager\Environment]`,
+		`[HKEY_CURRENT_USER\Environment]`,
+	} {
+		if !strings.Contains(content, key+"\r\n\"Path\"=hex(2):") {
+			t.Errorf("Expected key %s with a REG_EXPAND_SZ Path value", key)
+		}
+	}
+}
+
+func TestExportBackupReg_Scope(t *testing.T) {
+	content, err := ExportBackupReg(sampleBackup(), "User")
+	if err != nil {
+		t.Fatalf("ExportBackupReg error: %v", err)
+	}
+	if strings.Contains(content, "HKEY_LOCAL_MACHINE") {
+		t.Error("User export should not include the System key")
+	}
+	// C:\Tools as UTF-16LE followed by the null terminator
+	if !strings.Contains(content, `"Path"=hex(2):43,00,3a,00,5c,00,54,00,6f,00,6f,00,6c,00,73,00,00,00`) {
+		t.Errorf("Unexpected User value: %q", content)
+	}
+
+	if _, err := ExportBackupReg(sampleBackup(), "machine"); err == nil {
+		t.Error("Expected error for invalid scope")
+	}
+}
+
+func TestSaveBackupReg(t *testing.T) {
+	file, err := SaveBackupReg(sampleBackup(), "both")
+	if err != nil {
+		t.Fatalf("SaveBackupReg error: %v", err)
+	}
+	defer os.Remove(file)
+
+	if filepath.Ext(file) != ".reg" || filepath.Dir(file) != GetExportDir() {
+		t.Errorf("Unexpected file location: %s", file)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("ReadFile error: %v", err)
+	}
+	if len(data) < 2 || data[0] != 0xff || data[1] != 0xfe {
+		t.Error("Expected a UTF-16LE byte order mark")
+	}
+}
+
+func TestImportEntries_SkipsBlanksAndComments(t *testing.T) {
+	input := "\ufeff# User PATH, 3 entries\n\nC:\\Tools\r\n   \n  # indented comment\n  %USERPROFILE%\\bin  \n\\\\server\\share\\bin\n"
+	entries, err := ImportEntries(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ImportEntries error: %v", err)
+	}
+	expected := []string{`C:\Tools`, `%USERPROFILE%\bin`, `\\server\share\bin`}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected %v, got %v", expected, entries)
+	}
+}
+
+func TestImportEntries_RoundTripsExport(t *testing.T) {
+	entries := []string{`C:\Windows\System32`, `%LOCALAPPDATA%\Programs\bin`, `D:\Go\bin`}
+	var b strings.Builder
+	if err := ExportEntries(entries, &b); err != nil {
+		t.Fatalf("ExportEntries error: %v", err)
+	}
+	got, err := ImportEntries(strings.NewReader("# header\n" + b.String()))
+	if err != nil {
+		t.Fatalf("ImportEntries error: %v", err)
+	}
+	if !reflect.DeepEqual(got, entries) {
+		t.Errorf("Expected %v, got %v", entries, got)
+	}
+}
+
+func TestImportEntries_RejectsImplausiblePaths(t *testing.T) {
+	for _, line := range []string{"bin", `C:Tools`, "C:/Tools", "/mnt/c/Tools", `C:\a;C:\b`, `C:\Tools\*`, "/usr/bin"} {
+		_, err := ImportEntries(strings.NewReader("C:\\Windows\n" + line + "\n"))
+		if err == nil {
+			t.Errorf("Expected %q to be rejected", line)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), "line 2:") {
+			t.Errorf("Expected the error to name line 2, got %v", err)
+		}
+	}
+}
+
+func TestImportEntriesFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "entries.txt")
+	os.WriteFile(file, []byte("C:\\Tools\nC:\\Go\\bin\n"), 0644)
+	entries, err := ImportEntriesFile(file)
+	if err != nil || len(entries) != 2 {
+		t.Errorf("Expected 2 entries, got %v, %v", entries, err)
+	}
+
+	empty := filepath.Join(dir, "empty.txt")
+	os.WriteFile(empty, []byte("# nothing here\n\n"), 0644)
+	if _, err := ImportEntriesFile(empty); err == nil {
+		t.Error("Expected an error for a file with only comments")
+	}
+	if _, err := ImportEntriesFile(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
@@ -116,7 +116,10 @@ func TestIntegration_BackupConfig(t *testing.T) {
 func TestIntegration_FullAnalysis(t *testing.T) {
 	opts := DefaultOptions()
 	opts.RemoveDeadPaths = false
-	result := AnalyzeAll(opts)
+	result, err := AnalyzeAll(opts)
+	if err != nil {
+		t.Fatalf("AnalyzeAll failed: %v", err)
+	}
 
 	t.Logf("System: %d entries, User: %d entries",
 		result.System.Original.Count, result.User.Original.Count)
@@ -277,7 +280,7 @@ func BenchmarkIntegration_AnalyzeAll(b *testing.B) {
 	opts.RemoveDeadPaths = false
 
 	for i := 0; i < b.N; i++ {
-		AnalyzeAll(opts)
+		_, _ = AnalyzeAll(opts)
 	}
 }
 
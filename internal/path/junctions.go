@@ -13,6 +13,15 @@ type Junction struct {
 	Name   string
 	Path   string
 	Target string
+	Broken bool // Target no longer exists
+}
+
+// JunctionEquivalent is a pair of PATH entries that resolve to the same directory
+// because one of them goes through a junction
+type JunctionEquivalent struct {
+	Entry      string `json:"entry"`      // Entry that goes through the junction
+	Equivalent string `json:"equivalent"` // Entry it duplicates
+	Junction   string `json:"junction"`   // Name of the junction involved
 }
 
 // JunctionSuggestion represents a suggested junction
@@ -36,6 +45,20 @@ func SetJunctionFolder(folder string) error {
 	return SaveConfig(config)
 }
 
+// ValidateJunctionFolder checks that folder is an absolute local path that can hold junctions
+func ValidateJunctionFolder(folder string) error {
+	switch {
+	case folder == "":
+		return fmt.Errorf("junction folder cannot be empty")
+	case len(folder) < 3 || folder[1] != ':' || folder[2] != '\\' ||
+		!(folder[0] >= 'A' && folder[0] <= 'Z' || folder[0] >= 'a' && folder[0] <= 'z'):
+		return fmt.Errorf("junction folder must be an absolute path like C:\\l")
+	case strings.ContainsAny(folder[2:], `;%<>"|?*:`):
+		return fmt.Errorf("junction folder contains invalid characters: %s", folder)
+	}
+	return nil
+}
+
 // EnsureJunctionFolder creates the junction folder if it doesn't exist
 func EnsureJunctionFolder() error {
 	folder := GetJunctionFolder()
@@ -56,7 +79,7 @@ func ListJunctions() []Junction {
 				"$($_.Name)|$target"
 			}
 		}
-	`, strings.ReplaceAll(folder, "'", "''"))
+	`, escapePSString(folder))
 
 	result, err := RunPowerShell(command)
 	if err != nil || result == "" {
@@ -76,6 +99,7 @@ func ListJunctions() []Junction {
 				Name:   parts[0],
 				Path:   filepath.Join(folder, parts[0]),
 				Target: parts[1],
+				Broken: isBrokenTarget(parts[1]),
 			})
 		}
 	}
@@ -83,8 +107,31 @@ func ListJunctions() []Junction {
 	return junctions
 }
 
+// isBrokenTarget reports whether a junction target no longer exists
+func isBrokenTarget(target string) bool {
+	if target == "" {
+		return true
+	}
+	_, err := os.Stat(ExpandEnvVars(target))
+	return err != nil
+}
+
 // CreateJunction creates a new junction
 func CreateJunction(name, target string) error {
+	if err := ValidateJunctionName(name); err != nil {
+		return err
+	}
+	if target == "" {
+		return fmt.Errorf("junction target cannot be empty")
+	}
+
+	// mklink resolves relative targets against the process directory, so pin them down first
+	absTarget, err := filepath.Abs(ExpandEnvVars(target))
+	if err != nil {
+		return fmt.Errorf("cannot resolve target %s: %w", target, err)
+	}
+	target = absTarget
+
 	folder := GetJunctionFolder()
 	if err := EnsureJunctionFolder(); err != nil {
 		return err
@@ -103,9 +150,198 @@ func CreateJunction(name, target string) error {
 	}
 
 	// Create junction using mklink /J (requires appropriate permissions)
-	command := fmt.Sprintf(`cmd /c mklink /J "%s" "%s"`, junctionPath, target)
-	_, err := RunPowerShell(command)
-	return err
+	// Paths go in as single-quoted literals so PowerShell leaves $ and ` alone
+	command := fmt.Sprintf(`$j = '%s'; $t = '%s'; cmd /c mklink /J "$j" "$t"`, escapePSString(junctionPath), escapePSString(target))
+	if _, err := RunPowerShell(command); err != nil {
+		return err
+	}
+	LogAudit(AuditCreateJunction, junctionPath+" -> "+target)
+	return nil
+}
+
+// RewritePathEntry points PATH entries at old (or inside it) to new instead
+// It backs up both scopes before writing and fails if no entry matches
+func RewritePathEntry(old, new, scope string) error {
+	raw, err := GetPathRaw(scope)
+	if err != nil {
+		return err
+	}
+	entries, count := rewriteEntries(ParsePath(raw), old, new)
+	if count == 0 {
+		return fmt.Errorf("%s not found in %s PATH", old, scope)
+	}
+
+	if _, err := CreateBackup("pre-junction-rewrite"); err != nil {
+		return fmt.Errorf("backup failed, PATH unchanged: %w", err)
+	}
+	if err := SetPath(JoinPath(entries), scope); err != nil {
+		return err
+	}
+	BroadcastEnvChange()
+	return nil
+}
+
+// rewriteEntries replaces the old prefix with new in matching entries and returns how many changed
+func rewriteEntries(entries []string, old, new string) ([]string, int) {
+	oldNorm := NormalizePath(old)
+	oldBase := strings.TrimRight(old, "\\/")
+	newBase := strings.TrimRight(new, "\\/")
+	result := make([]string, len(entries))
+	count := 0
+	for i, e := range entries {
+		result[i] = e
+		if NormalizePath(e) == oldNorm {
+			result[i] = newBase
+			count++
+			continue
+		}
+		// Entries inside old keep their relative part, e.g. old\bin -> new\bin
+		if len(e) > len(oldBase) && strings.EqualFold(e[:len(oldBase)], oldBase) && (e[len(oldBase)] == '\\' || e[len(oldBase)] == '/') {
+			result[i] = newBase + e[len(oldBase):]
+			count++
+		}
+	}
+	return result, count
+}
+
+// ProjectJunctionLengths returns the combined System and User PATH length after pointing
+// entries at the first n suggested junctions, for every n from 0 to len(suggestions)
+// Suggestions are applied in order, so an entry already rewritten by an earlier one is
+// not counted again by a later, overlapping one
+func ProjectJunctionLengths(sysPath, usrPath string, suggestions []JunctionSuggestion) []int {
+	sysEntries := ParsePath(sysPath)
+	usrEntries := ParsePath(usrPath)
+	lengths := make([]int, 0, len(suggestions)+1)
+	lengths = append(lengths, len(JoinPath(sysEntries))+len(JoinPath(usrEntries)))
+	for _, s := range suggestions {
+		sysEntries, _ = rewriteEntries(sysEntries, s.OriginalPath, s.JunctionPath)
+		usrEntries, _ = rewriteEntries(usrEntries, s.OriginalPath, s.JunctionPath)
+		lengths = append(lengths, len(JoinPath(sysEntries))+len(JoinPath(usrEntries)))
+	}
+	return lengths
+}
+
+// EntryRewrite is a PATH entry before and after pointing it at a suggested junction
+// After equals Before when no suggestion covers the entry
+type EntryRewrite struct {
+	Before string
+	After  string
+}
+
+// JunctionPreview is what both PATHs would look like with every suggestion applied
+type JunctionPreview struct {
+	System     []EntryRewrite
+	User       []EntryRewrite
+	SavedChars int // Combined System and User PATH length saved
+}
+
+// PreviewJunctionRewrites points entries at every suggested junction, in order, as
+// ProjectJunctionLengths does, and returns each entry before and after
+func PreviewJunctionRewrites(sysPath, usrPath string, suggestions []JunctionSuggestion) JunctionPreview {
+	sysEntries := ParsePath(sysPath)
+	usrEntries := ParsePath(usrPath)
+	sysAfter, usrAfter := sysEntries, usrEntries
+	for _, s := range suggestions {
+		sysAfter, _ = rewriteEntries(sysAfter, s.OriginalPath, s.JunctionPath)
+		usrAfter, _ = rewriteEntries(usrAfter, s.OriginalPath, s.JunctionPath)
+	}
+	before := len(JoinPath(sysEntries)) + len(JoinPath(usrEntries))
+	after := len(JoinPath(sysAfter)) + len(JoinPath(usrAfter))
+	return JunctionPreview{
+		System:     pairRewrites(sysEntries, sysAfter),
+		User:       pairRewrites(usrEntries, usrAfter),
+		SavedChars: before - after,
+	}
+}
+
+// pairRewrites zips entries with their rewritten form; rewriteEntries keeps the order and count
+func pairRewrites(before, after []string) []EntryRewrite {
+	pairs := make([]EntryRewrite, len(before))
+	for i := range before {
+		pairs[i] = EntryRewrite{Before: before[i], After: after[i]}
+	}
+	return pairs
+}
+
+// ScopesContainingEntry returns the scopes ("System", "User") whose PATH has entry or a path inside it
+func ScopesContainingEntry(entry string) []string {
+	var scopes []string
+	for _, scope := range []string{"System", "User"} {
+		raw, err := GetPathRaw(scope)
+		if err != nil {
+			continue
+		}
+		if _, count := rewriteEntries(ParsePath(raw), entry, entry); count > 0 {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// RenameJunction recreates a junction under a new name pointing at the same target,
+// points PATH entries that go through the old one at the new one, then removes the old one
+// If a PATH can't be rewritten the old junction is kept so those entries still resolve
+func RenameJunction(oldName, newName string) error {
+	if err := ValidateJunctionName(newName); err != nil {
+		return err
+	}
+	if strings.EqualFold(oldName, newName) {
+		return fmt.Errorf("junction is already named %s", oldName)
+	}
+
+	var target string
+	found := false
+	for _, j := range ListJunctions() {
+		if strings.EqualFold(j.Name, newName) {
+			return fmt.Errorf("junction %s already exists", newName)
+		}
+		if strings.EqualFold(j.Name, oldName) {
+			target = j.Target
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("junction %s not found", oldName)
+	}
+
+	newPath := filepath.Join(GetJunctionFolder(), newName)
+	if _, err := os.Stat(newPath); err == nil {
+		return fmt.Errorf("junction %s already exists", newName)
+	}
+
+	command := fmt.Sprintf(`$j = '%s'; $t = '%s'; cmd /c mklink /J "$j" "$t"`, escapePSString(newPath), escapePSString(target))
+	if _, err := RunPowerShell(command); err != nil {
+		return fmt.Errorf("failed to create %s: %w", newName, err)
+	}
+	LogAudit(AuditCreateJunction, newPath+" -> "+target+" (renamed from "+oldName+")")
+
+	oldPath := filepath.Join(GetJunctionFolder(), oldName)
+	for _, scope := range ScopesContainingEntry(oldPath) {
+		if err := RewritePathEntry(oldPath, newPath, scope); err != nil {
+			return fmt.Errorf("created %s but kept %s, %s PATH still uses it: %w", newName, oldName, scope, err)
+		}
+	}
+	return RemoveJunction(oldName)
+}
+
+// maxJunctionNameLen keeps junction names short enough to be worth creating
+const maxJunctionNameLen = 12
+
+// ValidateJunctionName checks that name is usable as a junction folder name
+func ValidateJunctionName(name string) error {
+	if name == "" {
+		return fmt.Errorf("junction name cannot be empty")
+	}
+	if len(name) > maxJunctionNameLen {
+		return fmt.Errorf("junction name %q is too long (max %d characters)", name, maxJunctionNameLen)
+	}
+	if i := strings.IndexAny(name, `/\:*?"<>|`); i != -1 {
+		return fmt.Errorf("junction name %q contains invalid character %q", name, name[i])
+	}
+	if strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") {
+		return fmt.Errorf("junction name %q cannot end with a dot or space", name)
+	}
+	return nil
 }
 
 // RemoveJunction removes a junction
@@ -114,32 +350,55 @@ func RemoveJunction(name string) error {
 	junctionPath := filepath.Join(folder, name)
 
 	// Use rmdir to remove junction without deleting target contents
-	command := fmt.Sprintf(`cmd /c rmdir "%s"`, junctionPath)
-	_, err := RunPowerShell(command)
-	return err
+	command := fmt.Sprintf(`$j = '%s'; cmd /c rmdir "$j"`, escapePSString(junctionPath))
+	if _, err := RunPowerShell(command); err != nil {
+		return err
+	}
+	LogAudit(AuditRemoveJunction, junctionPath)
+	return nil
 }
 
 // SuggestJunctionCandidates analyzes PATH and suggests junction candidates
 func SuggestJunctionCandidates() []JunctionSuggestion {
+	return SuggestJunctionCandidatesWithProgress(nil)
+}
+
+// SuggestJunctionCandidatesWithProgress is SuggestJunctionCandidates with a
+// callback invoked once per PATH entry examined
+func SuggestJunctionCandidatesWithProgress(progress ProgressFunc) []JunctionSuggestion {
 	sysPath, _ := GetPathRaw("System")
 	usrPath, _ := GetPathRaw("User")
+	return SuggestJunctionCandidatesFrom(sysPath, usrPath, progress)
+}
 
+// SuggestJunctionCandidatesFrom suggests junctions for raw PATH values the caller has already read
+func SuggestJunctionCandidatesFrom(sysPath, usrPath string, progress ProgressFunc) []JunctionSuggestion {
 	allPaths := append(ParsePath(sysPath), ParsePath(usrPath)...)
-	folder := GetJunctionFolder()
+	config := LoadConfig()
+	folder := config.JunctionFolder
+	minSavings, minLength := junctionThresholds(config)
+	maxNameLen := junctionNameMaxLen(config)
 
 	suggestions := make([]JunctionSuggestion, 0)
 	seen := make(map[string]bool)
 	usedNames := make(map[string]int) // Track how many times each name is used
 
 	// First, get existing junctions to avoid conflicts
+	if progress != nil {
+		progress(0, len(allPaths), "Listing existing junctions...")
+	}
 	existingJunctions := ListJunctions()
 	for _, j := range existingJunctions {
 		usedNames[strings.ToLower(j.Name)] = 1
 	}
 
-	for _, p := range allPaths {
+	for i, p := range allPaths {
+		if progress != nil {
+			progress(i+1, len(allPaths), p)
+		}
+
 		// Skip paths with variables or already short paths
-		if strings.Contains(p, "%") || len(p) < 30 {
+		if strings.Contains(p, "%") || len(p) < minLength {
 			continue
 		}
 
@@ -148,6 +407,11 @@ func SuggestJunctionCandidates() []JunctionSuggestion {
 			continue
 		}
 
+		// Skip folders the user keeps literal on purpose
+		if JunctionExcluded(p, config.JunctionExcludePrefixes) {
+			continue
+		}
+
 		// Skip duplicates
 		normalized := NormalizePath(p)
 		if seen[normalized] {
@@ -156,7 +420,7 @@ func SuggestJunctionCandidates() []JunctionSuggestion {
 		seen[normalized] = true
 
 		// Generate suggested name from path
-		shortName := generateJunctionName(p, usedNames)
+		shortName := generateJunctionName(p, usedNames, maxNameLen)
 		if shortName == "" {
 			continue
 		}
@@ -165,7 +429,7 @@ func SuggestJunctionCandidates() []JunctionSuggestion {
 		savedChars := len(p) - len(junctionPath)
 
 		// Only suggest if it saves significant chars
-		if savedChars > 20 {
+		if savedChars >= minSavings {
 			suggestions = append(suggestions, JunctionSuggestion{
 				OriginalPath:  p,
 				SuggestedName: shortName,
@@ -184,7 +448,50 @@ func SuggestJunctionCandidates() []JunctionSuggestion {
 	return suggestions
 }
 
-// generateJunctionName creates a unique short name for a junction
+// JunctionExcluded reports whether p is one of the excluded folders or inside one, ignoring case
+func JunctionExcluded(p string, prefixes []string) bool {
+	norm := NormalizePath(p)
+	for _, prefix := range prefixes {
+		if strings.TrimSpace(prefix) == "" {
+			continue
+		}
+		excluded := NormalizePath(prefix)
+		if norm == excluded {
+			return true
+		}
+		if len(norm) > len(excluded) && strings.HasPrefix(norm, excluded) && (norm[len(excluded)] == '\\' || norm[len(excluded)] == '/') {
+			return true
+		}
+	}
+	return false
+}
+
+// junctionThresholds returns the configured suggestion thresholds, falling back to
+// the defaults for configs that predate them or hold invalid values
+func junctionThresholds(config Config) (minSavings, minLength int) {
+	defaults := DefaultConfig()
+	minSavings, minLength = config.JunctionMinSavings, config.JunctionMinPathLength
+	if minSavings <= 0 {
+		minSavings = defaults.JunctionMinSavings
+	}
+	if minLength <= 0 {
+		minLength = defaults.JunctionMinPathLength
+	}
+	return minSavings, minLength
+}
+
+// minJunctionNameLen is the shortest configurable name limit that still leaves room for a numeric suffix
+const minJunctionNameLen = 4
+
+// junctionNameMaxLen returns the configured junction name limit, falling back to
+// the default for configs that predate it and raising values too short to stay unique
+func junctionNameMaxLen(config Config) int {
+	if config.JunctionNameMaxLen <= 0 {
+		return DefaultConfig().JunctionNameMaxLen
+	}
+	return max(config.JunctionNameMaxLen, minJunctionNameLen)
+}
+
 // cleanNameChars removes invalid characters from a name, keeping only alphanumeric, dash, underscore
 func cleanNameChars(name string, keepDashUnderscore bool) string {
 	return strings.Map(func(r rune) rune {
@@ -207,7 +514,7 @@ func truncateName(name string, maxLen int) string {
 }
 
 // tryUniqueWithParent attempts to create a unique name using parent folder prefix
-func tryUniqueWithParent(path, cleanName string, usedNames map[string]int) string {
+func tryUniqueWithParent(path, cleanName string, usedNames map[string]int, maxLen int) string {
 	parent := filepath.Base(filepath.Dir(path))
 	if parent == "" || parent == "." || parent == "\\" {
 		return ""
@@ -218,7 +525,7 @@ func tryUniqueWithParent(path, cleanName string, usedNames map[string]int) strin
 		return ""
 	}
 
-	uniqueName := truncateName(parentClean+"-"+cleanName, 12)
+	uniqueName := truncateName(parentClean+"-"+cleanName, maxLen)
 	if usedNames[strings.ToLower(uniqueName)] == 0 {
 		return uniqueName
 	}
@@ -226,10 +533,10 @@ func tryUniqueWithParent(path, cleanName string, usedNames map[string]int) strin
 }
 
 // tryUniqueWithNumber attempts to create a unique name with numeric suffix
-func tryUniqueWithNumber(cleanName string, usedNames map[string]int) string {
+func tryUniqueWithNumber(cleanName string, usedNames map[string]int, maxLen int) string {
 	for i := 2; i <= 99; i++ {
 		suffix := fmt.Sprintf("%d", i)
-		baseLen := 10 - len(suffix)
+		baseLen := max(maxLen-2-len(suffix), 0)
 		if baseLen > len(cleanName) {
 			baseLen = len(cleanName)
 		}
@@ -241,7 +548,8 @@ func tryUniqueWithNumber(cleanName string, usedNames map[string]int) string {
 	return ""
 }
 
-func generateJunctionName(path string, usedNames map[string]int) string {
+// generateJunctionName creates a unique name of at most maxLen characters for a junction
+func generateJunctionName(path string, usedNames map[string]int, maxLen int) string {
 	baseName := filepath.Base(path)
 	if baseName == "" || baseName == "." || baseName == "\\" {
 		return ""
@@ -251,7 +559,8 @@ func generateJunctionName(path string, usedNames map[string]int) string {
 	if cleanName == "" {
 		cleanName = "dir"
 	}
-	cleanName = truncateName(cleanName, 8)
+	// Two thirds of the limit (8 of the default 12) leaves room for a parent prefix
+	cleanName = truncateName(cleanName, maxLen*2/3)
 
 	// Check if name is already used
 	if usedNames[strings.ToLower(cleanName)] == 0 {
@@ -259,12 +568,86 @@ func generateJunctionName(path string, usedNames map[string]int) string {
 	}
 
 	// Try with parent folder prefix
-	if uniqueName := tryUniqueWithParent(path, cleanName, usedNames); uniqueName != "" {
+	if uniqueName := tryUniqueWithParent(path, cleanName, usedNames, maxLen); uniqueName != "" {
 		return uniqueName
 	}
 
 	// Try with numeric suffix
-	return tryUniqueWithNumber(cleanName, usedNames)
+	return tryUniqueWithNumber(cleanName, usedNames, maxLen)
+}
+
+// ResolveJunctionPath rewrites a path inside a junction to the junction's target
+// Returns the path unchanged and nil when it doesn't go through any of the junctions
+func ResolveJunctionPath(p string, junctions []Junction) (string, *Junction) {
+	lower := strings.ToLower(p)
+	for i := range junctions {
+		j := &junctions[i]
+		if j.Path == "" || j.Target == "" {
+			continue
+		}
+		prefix := strings.TrimRight(j.Path, "\\/")
+		lowerPrefix := strings.ToLower(prefix)
+		if lower == lowerPrefix {
+			return j.Target, j
+		}
+		if strings.HasPrefix(lower, lowerPrefix) && (lower[len(lowerPrefix)] == '\\' || lower[len(lowerPrefix)] == '/') {
+			return strings.TrimRight(j.Target, "\\/") + p[len(prefix):], j
+		}
+	}
+	return p, nil
+}
+
+// usesJunctionFolder reports whether any entry is inside the junction folder
+func usesJunctionFolder(entries []string) bool {
+	folder := strings.ToLower(strings.TrimRight(GetJunctionFolder(), "\\/"))
+	for _, e := range entries {
+		lower := strings.ToLower(ExpandEnvVars(e))
+		if strings.HasPrefix(lower, folder) && len(lower) > len(folder) && (lower[len(folder)] == '\\' || lower[len(folder)] == '/') {
+			return true
+		}
+	}
+	return false
+}
+
+// FindJunctionEquivalents finds PATH entries that point at the same directory
+// once junctions are resolved, e.g. C:\l\la\bin and C:\Program Files\LongApp\bin
+// Plain duplicates (same text) are left to the regular duplicate check
+func FindJunctionEquivalents(entries []string, junctions []Junction) []JunctionEquivalent {
+	type seenEntry struct {
+		entry    string
+		junction *Junction
+	}
+
+	seen := make(map[string]seenEntry)
+	result := make([]JunctionEquivalent, 0)
+
+	for _, entry := range entries {
+		resolved, junction := ResolveJunctionPath(entry, junctions)
+		key := NormalizePath(resolved)
+
+		prev, ok := seen[key]
+		if !ok {
+			seen[key] = seenEntry{entry: entry, junction: junction}
+			continue
+		}
+		if junction == nil && prev.junction == nil {
+			continue
+		}
+		if NormalizePath(entry) == NormalizePath(prev.entry) {
+			continue
+		}
+
+		eq := JunctionEquivalent{Entry: entry, Equivalent: prev.entry}
+		if junction != nil {
+			eq.Junction = junction.Name
+		} else {
+			eq.Entry, eq.Equivalent = prev.entry, entry
+			eq.Junction = prev.junction.Name
+		}
+		result = append(result, eq)
+	}
+
+	return result
 }
 
 // CalculateJunctionSavings calculates total chars saved if all suggested junctions were applied
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -66,7 +68,7 @@ func TestJunctionSuggestionStruct(t *testing.T) {
 
 func TestGenerateJunctionName_Simple(t *testing.T) {
 	usedNames := make(map[string]int)
-	name := generateJunctionName(`C:\Program Files\Git\bin`, usedNames)
+	name := generateJunctionName(`C:\Program Files\Git\bin`, usedNames, 12)
 
 	if name == "" {
 		t.Error("Expected non-empty name")
@@ -79,10 +81,10 @@ func TestGenerateJunctionName_Simple(t *testing.T) {
 func TestGenerateJunctionName_Collision(t *testing.T) {
 	usedNames := make(map[string]int)
 
-	name1 := generateJunctionName(`C:\Program Files\Git\bin`, usedNames)
+	name1 := generateJunctionName(`C:\Program Files\Git\bin`, usedNames, 12)
 	usedNames[name1] = 1
 
-	name2 := generateJunctionName(`C:\Program Files\Other\bin`, usedNames)
+	name2 := generateJunctionName(`C:\Program Files\Other\bin`, usedNames, 12)
 
 	if name1 == name2 {
 		t.Error("Names should be different")
@@ -93,7 +95,7 @@ func TestGenerateJunctionName_MultipleCollisions(t *testing.T) {
 	usedNames := make(map[string]int)
 
 	for i := 0; i < 5; i++ {
-		name := generateJunctionName(`C:\Program Files\App`+string(rune('A'+i))+`\bin`, usedNames)
+		name := generateJunctionName(`C:\Program Files\App`+string(rune('A'+i))+`\bin`, usedNames, 12)
 		usedNames[name] = 1
 	}
 
@@ -105,7 +107,7 @@ func TestGenerateJunctionName_MultipleCollisions(t *testing.T) {
 func TestGenerateJunctionName_LongPath(t *testing.T) {
 	usedNames := make(map[string]int)
 	longPath := `C:\Program Files\Microsoft Visual Studio\2022\Enterprise\Common7\IDE\Extensions\Microsoft`
-	name := generateJunctionName(longPath, usedNames)
+	name := generateJunctionName(longPath, usedNames, 12)
 
 	if len(name) > 12 {
 		t.Errorf("Name should be max 12 chars: %s (%d)", name, len(name))
@@ -166,6 +168,39 @@ func TestListJunctions_EmptyFolder(t *testing.T) {
 	}
 }
 
+func TestListJunctions_Broken(t *testing.T) {
+	folder := t.TempDir()
+	live := t.TempDir()
+	removed := filepath.Join(t.TempDir(), "removed")
+	if err := os.Mkdir(removed, 0755); err != nil {
+		t.Fatalf("Failed to create target: %v", err)
+	}
+	if err := os.Remove(removed); err != nil {
+		t.Fatalf("Failed to delete target: %v", err)
+	}
+
+	original := GetJunctionFolder()
+	SetJunctionFolder(folder)
+	defer SetJunctionFolder(original)
+
+	withMockRunner(t, func(mock *MockShellRunner) {
+		listing := "live|" + live + "\ngone|" + removed
+		mock.SetResponse("Get-ChildItem", listing)
+		mock.SetResponse("Test-Path", listing)
+	}, func() {
+		junctions := ListJunctions()
+		if len(junctions) != 2 {
+			t.Fatalf("Expected 2 junctions, got %d", len(junctions))
+		}
+		if junctions[0].Broken {
+			t.Error("Junction with existing target should not be broken")
+		}
+		if !junctions[1].Broken {
+			t.Error("Junction with deleted target should be broken")
+		}
+	})
+}
+
 func TestCreateJunction_InvalidName(t *testing.T) {
 	err := CreateJunction("", `C:\Windows`)
 	if err == nil {
@@ -188,29 +223,37 @@ func TestRemoveJunction_NotFound(t *testing.T) {
 }
 
 func TestValidateJunctionName(t *testing.T) {
-	validNames := []string{"test", "git", "vscode", "app123"}
-	invalidNames := []string{"", "test/path", "test\\path", "test:name"}
+	validNames := []string{"test", "git", "vscode", "app123", "prog-python3"}
+	invalidNames := []string{
+		"", "test/path", "test\\path", "test:name", "a*b", "what?", `q"t`, "<x>", "a|b",
+		"averyverylongname", "trailing.", "trailing ",
+	}
 
 	for _, name := range validNames {
-		if len(name) == 0 || len(name) > 12 {
-			t.Errorf("Expected valid: %s", name)
+		if err := ValidateJunctionName(name); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", name, err)
 		}
 	}
 
 	for _, name := range invalidNames {
-		if name != "" && !containsInvalidChars(name) {
-			t.Logf("Name %s may be valid", name)
+		if err := ValidateJunctionName(name); err == nil {
+			t.Errorf("Expected %q to be invalid", name)
 		}
 	}
 }
 
-func containsInvalidChars(name string) bool {
-	for _, c := range name {
-		if c == '/' || c == '\\' || c == ':' || c == '*' || c == '?' || c == '"' || c == '<' || c == '>' || c == '|' {
-			return true
+func TestCreateJunction_InvalidNameSkipsShell(t *testing.T) {
+	mock, cleanup := SetDefaultTestRunner()
+	defer cleanup()
+
+	if err := CreateJunction("bad:name", `C:\Windows`); err == nil {
+		t.Error("Expected error for invalid junction name")
+	}
+	for _, call := range mock.Calls {
+		if strings.Contains(call, "mklink") {
+			t.Error("mklink should not run for an invalid name")
 		}
 	}
-	return false
 }
 
 func TestGetBackupDir_NotEmpty(t *testing.T) {
@@ -236,7 +279,7 @@ func BenchmarkGenerateJunctionName(b *testing.B) {
 	path := `C:\Program Files\Microsoft Visual Studio\2022\Enterprise\bin`
 
 	for i := 0; i < b.N; i++ {
-		generateJunctionName(path, usedNames)
+		generateJunctionName(path, usedNames, 12)
 	}
 }
 
@@ -306,13 +349,13 @@ func TestTryUniqueWithParent(t *testing.T) {
 	usedNames["test"] = 1
 
 	// Test with valid parent
-	result := tryUniqueWithParent(`C:\Parent\test`, "test", usedNames)
+	result := tryUniqueWithParent(`C:\Parent\test`, "test", usedNames, 12)
 	if result == "" {
 		t.Log("tryUniqueWithParent returned empty (parent may be invalid)")
 	}
 
 	// Test with root path (no parent)
-	result = tryUniqueWithParent(`C:\test`, "test", usedNames)
+	result = tryUniqueWithParent(`C:\test`, "test", usedNames, 12)
 	t.Logf("Root path result: %s", result)
 }
 
@@ -320,7 +363,7 @@ func TestTryUniqueWithNumber(t *testing.T) {
 	usedNames := make(map[string]int)
 	usedNames["test"] = 1
 
-	result := tryUniqueWithNumber("test", usedNames)
+	result := tryUniqueWithNumber("test", usedNames, 12)
 	if result == "" {
 		t.Error("tryUniqueWithNumber should return a unique name")
 	}
@@ -332,7 +375,7 @@ func TestTryUniqueWithNumber(t *testing.T) {
 	for i := 2; i <= 99; i++ {
 		usedNames[fmt.Sprintf("test%d", i)] = 1
 	}
-	result = tryUniqueWithNumber("test", usedNames)
+	result = tryUniqueWithNumber("test", usedNames, 12)
 	if result != "" {
 		t.Error("Should return empty when all numbers exhausted")
 	}
@@ -389,6 +432,175 @@ func TestSuggestJunctionCandidates_Analysis(t *testing.T) {
 	}
 }
 
+func TestJunctionThresholds_Defaults(t *testing.T) {
+	minSavings, minLength := junctionThresholds(DefaultConfig())
+	if minSavings != 21 || minLength != 30 {
+		t.Errorf("Defaults changed: minSavings=%d minLength=%d, want 21 and 30", minSavings, minLength)
+	}
+
+	minSavings, minLength = junctionThresholds(Config{})
+	if minSavings != 21 || minLength != 30 {
+		t.Errorf("Zero values should fall back to defaults, got %d and %d", minSavings, minLength)
+	}
+}
+
+func TestSuggestJunctionCandidates_Thresholds(t *testing.T) {
+	original := LoadConfig()
+	defer func() { _ = SaveConfig(original) }()
+
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetResponse("LocalMachine.OpenSubKey", `C:\Program Files\Some Vendor\Application Suite\bin;C:\Program Files\Tools\bin`)
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\Apps\Editor\resources\app\bin`)
+	}, func() {
+		config := DefaultConfig()
+		_ = SaveConfig(config)
+		defaults := SuggestJunctionCandidates()
+		if len(defaults) != 1 {
+			t.Fatalf("Expected 1 suggestion with default thresholds, got %+v", defaults)
+		}
+
+		config.JunctionMinSavings = 5
+		config.JunctionMinPathLength = 10
+		_ = SaveConfig(config)
+		lowered := SuggestJunctionCandidates()
+		if len(lowered) <= len(defaults) {
+			t.Errorf("Lower thresholds should produce more suggestions: %d vs %d", len(lowered), len(defaults))
+		}
+	})
+}
+
+func TestJunctionNameMaxLen_Fallbacks(t *testing.T) {
+	if n := junctionNameMaxLen(Config{}); n != 12 {
+		t.Errorf("Zero value should fall back to 12, got %d", n)
+	}
+	if n := junctionNameMaxLen(Config{JunctionNameMaxLen: 2}); n != minJunctionNameLen {
+		t.Errorf("Too short a limit should be raised to %d, got %d", minJunctionNameLen, n)
+	}
+	if n := junctionNameMaxLen(Config{JunctionNameMaxLen: 24}); n != 24 {
+		t.Errorf("Expected the configured limit, got %d", n)
+	}
+}
+
+func TestGenerateJunctionName_ConfigurableLimit(t *testing.T) {
+	paths := []string{
+		`C:\Program Files\Microsoft SQL Server\Client SDK\ODBC\Tools\Binn`,
+		`C:\Program Files\Microsoft SQL Server\150\Tools\Binn`,
+		`C:\Program Files (x86)\Microsoft SQL Server\150\Tools\Binn`,
+		`C:\Program Files\Azure Data Studio\Tools\Binn`,
+		`D:\Tools\Binn`,
+	}
+	for _, limit := range []int{6, 12, 24} {
+		usedNames := make(map[string]int)
+		for _, p := range paths {
+			name := generateJunctionName(p, usedNames, limit)
+			if name == "" {
+				t.Fatalf("limit %d: no name for %s", limit, p)
+			}
+			if len(name) > limit {
+				t.Errorf("limit %d: %s is %d chars", limit, name, len(name))
+			}
+			if usedNames[name] != 0 {
+				t.Errorf("limit %d: %s was handed out twice", limit, name)
+			}
+			usedNames[name]++
+		}
+	}
+
+	long := `C:\Users\dev\AppData\Local\Programs\PowerToysRunPlugins`
+	if name := generateJunctionName(long, map[string]int{}, 24); len(name) != 16 {
+		t.Errorf("Expected a 16 char base name with a 24 char limit, got %s", name)
+	}
+	if name := generateJunctionName(long, map[string]int{}, 12); len(name) != 8 {
+		t.Errorf("Expected the default 8 char base name, got %s", name)
+	}
+}
+
+func TestSuggestJunctionCandidates_NameMaxLen(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetResponse("LocalMachine.OpenSubKey", `C:\Program Files\Some Vendor\ApplicationSuite`)
+		mock.SetResponse("CurrentUser.OpenSubKey", "")
+	}, func() {
+		config := DefaultConfig()
+		config.JunctionNameMaxLen = 20
+		_ = SaveConfig(config)
+		suggestions := SuggestJunctionCandidates()
+		if len(suggestions) != 1 || len(suggestions[0].SuggestedName) != 13 {
+			t.Errorf("Expected a name sized to the configured limit, got %+v", suggestions)
+		}
+	})
+}
+
+func TestJunctionExcluded(t *testing.T) {
+	prefixes := []string{`C:\Windows\`, "", `d:\tools`}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{`C:\Windows`, true},
+		{`c:\windows\System32\WindowsPowerShell\v1.0`, true},
+		{`D:\Tools\bin`, true},
+		{`C:\WindowsApps\bin`, false},
+		{`D:\Toolshed\bin`, false},
+		{`C:\Program Files\Git\cmd`, false},
+	}
+	for _, tt := range tests {
+		if got := JunctionExcluded(tt.path, prefixes); got != tt.want {
+			t.Errorf("JunctionExcluded(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestJunctionCandidates_ExcludePrefixes(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetResponse("LocalMachine.OpenSubKey", `C:\Windows\System32\WindowsPowerShell\v1.0;C:\Program Files\Some Vendor\Application Suite\bin`)
+		mock.SetResponse("CurrentUser.OpenSubKey", "")
+	}, func() {
+		config := DefaultConfig()
+		config.JunctionMinSavings = 5
+		_ = SaveConfig(config)
+		if n := len(SuggestJunctionCandidates()); n != 2 {
+			t.Fatalf("Expected both long entries suggested without exclusions, got %d", n)
+		}
+
+		config.JunctionExcludePrefixes = []string{`c:\windows`}
+		_ = SaveConfig(config)
+		suggestions := SuggestJunctionCandidates()
+		if len(suggestions) != 1 || JunctionExcluded(suggestions[0].OriginalPath, config.JunctionExcludePrefixes) {
+			t.Errorf("Expected only the entry outside C:\\Windows, got %+v", suggestions)
+		}
+	})
+}
+
+func TestSuggestJunctionCandidatesWithProgress(t *testing.T) {
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetResponse("LocalMachine.OpenSubKey", `C:\Program Files\Some Vendor\Application Suite\bin;C:\Windows`)
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\Apps\Editor\resources\app\bin`)
+	}, func() {
+		var items []string
+		lastCurrent, lastTotal := -1, 0
+		SuggestJunctionCandidatesWithProgress(func(current, total int, item string) {
+			items = append(items, item)
+			lastCurrent, lastTotal = current, total
+		})
+
+		if len(items) == 0 {
+			t.Fatal("Progress callback was never invoked")
+		}
+		if lastCurrent != 3 || lastTotal != 3 {
+			t.Errorf("Final progress = %d/%d, want 3/3", lastCurrent, lastTotal)
+		}
+		if items[len(items)-1] != `C:\Apps\Editor\resources\app\bin` {
+			t.Errorf("Last reported item = %q, want the last PATH entry", items[len(items)-1])
+		}
+	})
+}
+
 func TestGenerateJunctionName_SpecialChars(t *testing.T) {
 	usedNames := make(map[string]int)
 
@@ -400,7 +612,7 @@ func TestGenerateJunctionName_SpecialChars(t *testing.T) {
 	}
 
 	for _, p := range paths {
-		name := generateJunctionName(p, usedNames)
+		name := generateJunctionName(p, usedNames, 12)
 		usedNames[name] = 1
 
 		if name == "" {
@@ -416,13 +628,474 @@ func TestGenerateJunctionName_EmptyBaseName(t *testing.T) {
 	usedNames := make(map[string]int)
 
 	// Test edge cases
-	result := generateJunctionName(`C:\`, usedNames)
+	result := generateJunctionName(`C:\`, usedNames, 12)
 	if result != "" {
 		t.Logf("Result for root: %s", result)
 	}
 
-	result = generateJunctionName("", usedNames)
+	result = generateJunctionName("", usedNames, 12)
 	if result != "" {
 		t.Error("Empty path should return empty name")
 	}
 }
+
+func TestResolveJunctionPath(t *testing.T) {
+	junctions := []Junction{
+		{Name: "la", Path: `C:\l\la`, Target: `C:\Program Files\LongApp`},
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+		matched  bool
+	}{
+		{`C:\l\la\bin`, `C:\Program Files\LongApp\bin`, true},
+		{`c:\L\LA`, `C:\Program Files\LongApp`, true},
+		{`C:\l\lab\bin`, `C:\l\lab\bin`, false},
+		{`C:\Other\bin`, `C:\Other\bin`, false},
+	}
+	for _, tt := range tests {
+		got, j := ResolveJunctionPath(tt.input, junctions)
+		if got != tt.expected {
+			t.Errorf("ResolveJunctionPath(%s) = %s, want %s", tt.input, got, tt.expected)
+		}
+		if (j != nil) != tt.matched {
+			t.Errorf("ResolveJunctionPath(%s) matched = %v, want %v", tt.input, j != nil, tt.matched)
+		}
+	}
+}
+
+func TestFindJunctionEquivalents(t *testing.T) {
+	junctions := []Junction{
+		{Name: "la", Path: `C:\l\la`, Target: `C:\Program Files\LongApp`},
+	}
+	entries := []string{
+		`C:\Windows`,
+		`C:\Program Files\LongApp\bin`,
+		`C:\l\la\bin`,
+		`C:\Windows`,
+	}
+
+	result := FindJunctionEquivalents(entries, junctions)
+	if len(result) != 1 {
+		t.Fatalf("Expected 1 equivalent pair, got %d: %+v", len(result), result)
+	}
+	eq := result[0]
+	if eq.Entry != `C:\l\la\bin` || eq.Equivalent != `C:\Program Files\LongApp\bin` || eq.Junction != "la" {
+		t.Errorf("Unexpected pair: %+v", eq)
+	}
+}
+
+func TestFindJunctionEquivalents_JunctionFirst(t *testing.T) {
+	junctions := []Junction{
+		{Name: "la", Path: `C:\l\la`, Target: `C:\Program Files\LongApp`},
+	}
+	entries := []string{`C:\l\la\bin`, `C:\Program Files\LongApp\bin`}
+
+	result := FindJunctionEquivalents(entries, junctions)
+	if len(result) != 1 {
+		t.Fatalf("Expected 1 equivalent pair, got %d", len(result))
+	}
+	if result[0].Entry != `C:\l\la\bin` {
+		t.Errorf("Entry should be the junction form, got %s", result[0].Entry)
+	}
+}
+
+func TestFindJunctionEquivalents_None(t *testing.T) {
+	result := FindJunctionEquivalents([]string{`C:\a`, `C:\b`}, nil)
+	if len(result) != 0 {
+		t.Errorf("Expected no equivalents, got %+v", result)
+	}
+}
+
+func TestRewriteEntries(t *testing.T) {
+	entries := []string{`C:\Windows`, `C:\Program Files\Long App\bin`, `c:\program files\long app\`, `C:\Program Files\Long Application`}
+
+	result, count := rewriteEntries(entries, `C:\Program Files\Long App`, `C:\l\app`)
+	expected := []string{`C:\Windows`, `C:\l\app\bin`, `C:\l\app`, `C:\Program Files\Long Application`}
+	if count != 2 {
+		t.Errorf("Expected 2 rewritten entries, got %d", count)
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("Entry %d: expected %s, got %s", i, expected[i], result[i])
+		}
+	}
+}
+
+func TestRewritePathEntry(t *testing.T) {
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\Windows;C:\Program Files\Long App\bin`)
+	}, func() {
+		mock := DefaultRunner.(*MockShellRunner)
+		before := len(mock.Calls)
+
+		if err := RewritePathEntry(`C:\Program Files\Long App\bin`, `C:\l\app`, "User"); err != nil {
+			t.Fatalf("RewritePathEntry error: %v", err)
+		}
+
+		var setCall string
+		for _, call := range mock.Calls[before:] {
+			if strings.Contains(call, "SetEnvironmentVariable('Path'") {
+				setCall = call
+			}
+		}
+		if !strings.Contains(setCall, `'C:\Windows;C:\l\app', 'User'`) {
+			t.Errorf("Expected SetPath with junction path, got %q", setCall)
+		}
+		if len(ListBackups()) == 0 {
+			t.Error("Expected a backup before rewriting")
+		}
+
+		if err := RewritePathEntry(`C:\Not\On\Path`, `C:\l\x`, "User"); err == nil {
+			t.Error("Expected error when entry is not on PATH")
+		}
+	})
+}
+
+func TestRenameJunction_InvalidName(t *testing.T) {
+	mock := getMockRunner(t)
+	before := len(mock.Calls)
+
+	if err := RenameJunction("old", "bad:name"); err == nil {
+		t.Error("Expected error for invalid new name")
+	}
+	if len(mock.Calls) != before {
+		t.Error("Invalid name should not reach the shell")
+	}
+}
+
+func TestRenameJunction_Collision(t *testing.T) {
+	folder := t.TempDir()
+	original := GetJunctionFolder()
+	SetJunctionFolder(folder)
+	defer SetJunctionFolder(original)
+
+	withMockRunner(t, func(mock *MockShellRunner) {
+		listing := "old|C:\\Tools\ntaken|C:\\Other"
+		mock.SetResponse("Get-ChildItem", listing)
+		mock.SetResponse("Test-Path", listing)
+	}, func() {
+		mock := getMockRunner(t)
+		before := len(mock.Calls)
+
+		err := RenameJunction("old", "Taken")
+		if err == nil || !strings.Contains(err.Error(), "already exists") {
+			t.Errorf("Expected collision error, got %v", err)
+		}
+		for _, call := range mock.Calls[before:] {
+			if strings.Contains(call, "mklink") || strings.Contains(call, "rmdir") {
+				t.Errorf("Collision should not touch junctions: %s", call)
+			}
+		}
+	})
+}
+
+func TestRenameJunction_NotFound(t *testing.T) {
+	folder := t.TempDir()
+	original := GetJunctionFolder()
+	SetJunctionFolder(folder)
+	defer SetJunctionFolder(original)
+
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetResponse("Get-ChildItem", "")
+		mock.SetResponse("Test-Path", "")
+	}, func() {
+		if err := RenameJunction("missing", "new"); err == nil {
+			t.Error("Expected error for unknown junction")
+		}
+	})
+}
+
+func TestRenameJunction_Success(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+	folder := t.TempDir()
+	original := GetJunctionFolder()
+	SetJunctionFolder(folder)
+	defer SetJunctionFolder(original)
+
+	withMockRunner(t, func(mock *MockShellRunner) {
+		listing := "old|C:\\Tools"
+		mock.SetResponse("Get-ChildItem", listing)
+		mock.SetResponse("Test-Path", listing)
+	}, func() {
+		mock := getMockRunner(t)
+		before := len(mock.Calls)
+
+		if err := RenameJunction("old", "new"); err != nil {
+			t.Fatalf("RenameJunction error: %v", err)
+		}
+
+		var created, removed bool
+		for _, call := range mock.Calls[before:] {
+			if strings.Contains(call, "mklink /J") && strings.Contains(call, filepath.Join(folder, "new")) && strings.Contains(call, `C:\Tools`) {
+				created = true
+			}
+			if strings.Contains(call, "rmdir") && strings.Contains(call, filepath.Join(folder, "old")) {
+				removed = true
+			}
+		}
+		if !created || !removed {
+			t.Errorf("Expected new junction created and old removed (created=%v removed=%v)", created, removed)
+		}
+
+		lines, _ := ReadAuditLog()
+		log := strings.Join(lines, "\n")
+		if !strings.Contains(log, AuditCreateJunction) || !strings.Contains(log, "renamed from old") || !strings.Contains(log, AuditRemoveJunction) {
+			t.Errorf("Expected the rename in the audit log, got:\n%s", log)
+		}
+	})
+}
+
+func TestRenameJunction_RewritesPathEntries(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+	folder := t.TempDir()
+	original := GetJunctionFolder()
+	SetJunctionFolder(folder)
+	defer SetJunctionFolder(original)
+
+	oldBin := filepath.Join(folder, "old", "bin")
+	withMockRunner(t, func(mock *MockShellRunner) {
+		listing := "old|C:\\Tools"
+		mock.SetResponse("Get-ChildItem", listing)
+		mock.SetResponse("Test-Path", listing)
+		mock.SetResponse("LocalMachine.OpenSubKey", `C:\Windows`)
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\Go\bin;`+oldBin)
+	}, func() {
+		mock := getMockRunner(t)
+		before := len(mock.Calls)
+
+		if err := RenameJunction("old", "new"); err != nil {
+			t.Fatalf("RenameJunction error: %v", err)
+		}
+		newBin := filepath.Join(folder, "new", "bin")
+		rewritten := -1
+		removed := -1
+		for i, call := range mock.Calls[before:] {
+			if strings.Contains(call, "SetEnvironmentVariable('Path'") && strings.Contains(call, newBin) && strings.Contains(call, "'User'") {
+				rewritten = i
+			}
+			if strings.Contains(call, "rmdir") {
+				removed = i
+			}
+		}
+		if rewritten < 0 || removed < rewritten {
+			t.Errorf("Expected the User PATH rewritten to %s before the old junction is removed", newBin)
+		}
+	})
+}
+
+func TestRenameJunction_KeepsOldWhenRewriteFails(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+	folder := t.TempDir()
+	original := GetJunctionFolder()
+	SetJunctionFolder(folder)
+	defer SetJunctionFolder(original)
+
+	withMockRunner(t, func(mock *MockShellRunner) {
+		listing := "old|C:\\Tools"
+		mock.SetResponse("Get-ChildItem", listing)
+		mock.SetResponse("Test-Path", listing)
+		mock.SetResponse("LocalMachine.OpenSubKey", filepath.Join(folder, "old"))
+		mock.SetError("SetEnvironmentVariable", fmt.Errorf("access denied"))
+	}, func() {
+		mock := getMockRunner(t)
+		before := len(mock.Calls)
+
+		if err := RenameJunction("old", "new"); err == nil || !strings.Contains(err.Error(), "System PATH still uses it") {
+			t.Errorf("Expected an error naming the System PATH, got %v", err)
+		}
+		for _, call := range mock.Calls[before:] {
+			if strings.Contains(call, "rmdir") {
+				t.Error("The old junction should be kept while PATH still uses it")
+			}
+		}
+	})
+}
+
+func TestProjectJunctionLengths(t *testing.T) {
+	sysPath := `C:\Windows;C:\Program Files\Vendor Suite\bin;C:\Program Files\Vendor Suite\tools`
+	usrPath := `C:\Users\Test\AppData\Local\Programs\Editor\bin`
+	suggestions := []JunctionSuggestion{
+		{OriginalPath: `C:\Program Files\Vendor Suite`, JunctionPath: `C:\l\vs`},
+		{OriginalPath: `C:\Users\Test\AppData\Local\Programs\Editor\bin`, JunctionPath: `C:\l\ed`},
+		{OriginalPath: `C:\Program Files\Vendor Suite\bin`, JunctionPath: `C:\l\vsb`},
+	}
+
+	lengths := ProjectJunctionLengths(sysPath, usrPath, suggestions)
+
+	want := []int{
+		len(sysPath) + len(usrPath),
+		len(`C:\Windows;C:\l\vs\bin;C:\l\vs\tools`) + len(usrPath),
+		len(`C:\Windows;C:\l\vs\bin;C:\l\vs\tools`) + len(`C:\l\ed`),
+		// The third suggestion's entry was already rewritten by the first
+		len(`C:\Windows;C:\l\vs\bin;C:\l\vs\tools`) + len(`C:\l\ed`),
+	}
+	if !reflect.DeepEqual(lengths, want) {
+		t.Errorf("lengths = %v, want %v", lengths, want)
+	}
+	for i := 1; i < len(lengths); i++ {
+		if lengths[i] > lengths[i-1] {
+			t.Errorf("Projected length grew at step %d: %v", i, lengths)
+		}
+	}
+}
+
+func TestProjectJunctionLengths_NoSuggestions(t *testing.T) {
+	lengths := ProjectJunctionLengths(`C:\Windows`, `C:\Tools`, nil)
+	if !reflect.DeepEqual(lengths, []int{len(`C:\Windows`) + len(`C:\Tools`)}) {
+		t.Errorf("lengths = %v", lengths)
+	}
+}
+
+func TestPreviewJunctionRewrites_SavingsMatchSuggestions(t *testing.T) {
+	sysPath := `C:\Windows;C:\Program Files\Vendor Suite\bin;C:\Program Files\Other Vendor\tools`
+	usrPath := `C:\Users\Test\AppData\Local\Programs\Editor\bin;C:\Tools`
+	var suggestions []JunctionSuggestion
+	for _, s := range []struct{ original, junction string }{
+		{`C:\Program Files\Vendor Suite\bin`, `C:\l\vs`},
+		{`C:\Program Files\Other Vendor\tools`, `C:\l\ov`},
+		{`C:\Users\Test\AppData\Local\Programs\Editor\bin`, `C:\l\ed`},
+	} {
+		suggestions = append(suggestions, JunctionSuggestion{
+			OriginalPath: s.original,
+			JunctionPath: s.junction,
+			SavedChars:   len(s.original) - len(s.junction),
+		})
+	}
+
+	preview := PreviewJunctionRewrites(sysPath, usrPath, suggestions)
+	if want := CalculateJunctionSavings(suggestions); preview.SavedChars != want {
+		t.Errorf("SavedChars = %d, want the sum of the suggestions %d", preview.SavedChars, want)
+	}
+
+	wantSys := []EntryRewrite{
+		{`C:\Windows`, `C:\Windows`},
+		{`C:\Program Files\Vendor Suite\bin`, `C:\l\vs`},
+		{`C:\Program Files\Other Vendor\tools`, `C:\l\ov`},
+	}
+	if !reflect.DeepEqual(preview.System, wantSys) {
+		t.Errorf("System = %v, want %v", preview.System, wantSys)
+	}
+	if len(preview.User) != 2 || preview.User[0].After != `C:\l\ed` || preview.User[1].After != `C:\Tools` {
+		t.Errorf("User = %v", preview.User)
+	}
+}
+
+func TestPreviewJunctionRewrites_OverlappingCountedOnce(t *testing.T) {
+	sysPath := `C:\Program Files\Vendor Suite\bin`
+	suggestions := []JunctionSuggestion{
+		{OriginalPath: `C:\Program Files\Vendor Suite`, JunctionPath: `C:\l\vs`},
+		{OriginalPath: `C:\Program Files\Vendor Suite\bin`, JunctionPath: `C:\l\vsb`},
+	}
+	preview := PreviewJunctionRewrites(sysPath, "", suggestions)
+	lengths := ProjectJunctionLengths(sysPath, "", suggestions)
+	if want := lengths[0] - lengths[len(lengths)-1]; preview.SavedChars != want {
+		t.Errorf("SavedChars = %d, want the projected %d", preview.SavedChars, want)
+	}
+	if preview.System[0].After != `C:\l\vs\bin` {
+		t.Errorf("Expected the first suggestion to win, got %s", preview.System[0].After)
+	}
+}
+
+func TestValidateJunctionFolder(t *testing.T) {
+	valid := []string{`C:\l`, `d:\Tools\links`, `C:\`}
+	for _, folder := range valid {
+		if err := ValidateJunctionFolder(folder); err != nil {
+			t.Errorf("ValidateJunctionFolder(%q) = %v, want nil", folder, err)
+		}
+	}
+
+	invalid := []string{"", `l`, `\l`, `C:l`, `\\server\share\l`, `%USERPROFILE%\l`, `C:\l;D:\m`, `C:\a|b`, `C:\a:b`, `1:\l`}
+	for _, folder := range invalid {
+		if err := ValidateJunctionFolder(folder); err == nil {
+			t.Errorf("ValidateJunctionFolder(%q) = nil, want error", folder)
+		}
+	}
+}
+
+func TestCreateJunction_ResolvesRelativeTarget(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+
+	base := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(base, "tools", "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetJunctionFolder(filepath.Join(base, "links")); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(wd) }()
+	if err := os.Chdir(base); err != nil {
+		t.Fatal(err)
+	}
+
+	mock := getMockRunner(t)
+	before := len(mock.Calls)
+	if err := CreateJunction("tb", filepath.Join("tools", "bin")); err != nil {
+		t.Fatalf("CreateJunction error: %v", err)
+	}
+
+	want := fmt.Sprintf(`$t = '%s'`, filepath.Join(base, "tools", "bin"))
+	found := false
+	for _, call := range mock.Calls[before:] {
+		if strings.Contains(call, "mklink") {
+			found = true
+			if !strings.Contains(call, want) {
+				t.Errorf("Expected mklink target %s, got: %s", want, call)
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected mklink to run")
+	}
+}
+
+func TestJunctionCommands_QuotePathsLiterally(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+
+	base := t.TempDir()
+	target := filepath.Join(base, "cash$dir", "it's")
+	if err := os.MkdirAll(target, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetJunctionFolder(filepath.Join(base, "links")); err != nil {
+		t.Fatal(err)
+	}
+
+	mock := getMockRunner(t)
+	before := len(mock.Calls)
+	if err := CreateJunction("c$h", target); err != nil {
+		t.Fatalf("CreateJunction error: %v", err)
+	}
+	if err := RemoveJunction("c$h"); err != nil {
+		t.Fatalf("RemoveJunction error: %v", err)
+	}
+
+	junctionPath := filepath.Join(base, "links", "c$h")
+	want := map[string]string{
+		"mklink": fmt.Sprintf(`$j = '%s'; $t = '%s'; cmd /c mklink /J "$j" "$t"`, junctionPath, strings.ReplaceAll(target, "'", "''")),
+		"rmdir":  fmt.Sprintf(`$j = '%s'; cmd /c rmdir "$j"`, junctionPath),
+	}
+	for _, call := range mock.Calls[before:] {
+		for verb, cmd := range want {
+			if strings.Contains(call, verb) {
+				if call != cmd {
+					t.Errorf("Expected %s command %s, got: %s", verb, cmd, call)
+				}
+				delete(want, verb)
+			}
+		}
+	}
+	for verb := range want {
+		t.Errorf("Expected %s to run", verb)
+	}
+}
@@ -3,7 +3,9 @@ package path
 import (
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 )
 
 // OptimizeOptions configures optimization behavior
@@ -13,53 +15,148 @@ type OptimizeOptions struct {
 	ShortenPaths     bool
 	SubstituteVars   bool
 	ReorderPaths     bool
-	Scope            string
+	// CanonicalizePaths rewrites surviving entries to their on-disk form
+	// (real casing, 8.3 names expanded) before shortening
+	CanonicalizePaths bool
+	// ConcurrentExistenceChecks stats every entry on a bounded worker pool before
+	// the main loop, so slow network drives don't serialize dead-path detection
+	ConcurrentExistenceChecks bool
+	// FixSlashes rewrites drive paths written with forward slashes (C:/Tools) to
+	// backslashes; without it they are only flagged as malformed
+	FixSlashes bool
+	// DetectShadowedTools lists commands found in more than one PATH directory during
+	// AnalyzeAll. It reads every directory on the PATH, so it is off by default
+	DetectShadowedTools bool
+	// Offline is set when the PATH comes from another machine: nothing on this machine is
+	// consulted, so case variants are treated as duplicates without probing the local
+	// disk, and the local config's hot paths and extra variables are ignored
+	Offline bool
+	Scope   string
 }
 
 // DefaultOptions returns sensible default optimization options
 func DefaultOptions() OptimizeOptions {
+	return OptimizeOptions{
+		RemoveDuplicates:          true,
+		RemoveDeadPaths:           true,
+		ShortenPaths:              true,
+		SubstituteVars:            true,
+		ReorderPaths:              false,
+		ConcurrentExistenceChecks: true,
+		FixSlashes:                true,
+		Scope:                     "User",
+	}
+}
+
+// RecommendedOptions returns the conservative set used by auto-fix: duplicates and
+// dead paths are removed, but nothing is shortened, substituted or rewritten
+func RecommendedOptions() OptimizeOptions {
+	return OptimizeOptions{
+		RemoveDuplicates:          true,
+		RemoveDeadPaths:           true,
+		ConcurrentExistenceChecks: true,
+		Scope:                     "User",
+	}
+}
+
+// ConservativeOptions only removes duplicates; dead entries are kept and nothing is rewritten
+func ConservativeOptions() OptimizeOptions {
 	return OptimizeOptions{
 		RemoveDuplicates: true,
-		RemoveDeadPaths:  true,
-		ShortenPaths:     true,
-		SubstituteVars:   true,
-		ReorderPaths:     false,
 		Scope:            "User",
 	}
 }
 
+// ExistingOnlyOptions keeps only entries that exist on disk, once each, without
+// rewriting any of them. It is the same set auto-fix uses
+func ExistingOnlyOptions() OptimizeOptions {
+	return RecommendedOptions()
+}
+
+// AggressiveOptions turns on every optimization, including reordering and
+// canonicalizing entries to their on-disk form
+func AggressiveOptions() OptimizeOptions {
+	opts := DefaultOptions()
+	opts.ReorderPaths = true
+	opts.CanonicalizePaths = true
+	return opts
+}
+
+// OptimizePreset is a named set of optimizer options describing an intent
+type OptimizePreset struct {
+	Name        string
+	Description string
+	Options     func() OptimizeOptions
+}
+
+// OptimizePresets lists the presets offered by the optimizer, the default first
+var OptimizePresets = []OptimizePreset{
+	{"default", "Dedupe, remove dead paths, shorten and substitute variables", DefaultOptions},
+	{"conservative", "Remove duplicates only", ConservativeOptions},
+	{"existing", "Keep only existing entries, once each, nothing rewritten", ExistingOnlyOptions},
+	{"aggressive", "Everything, including reordering and canonical casing", AggressiveOptions},
+}
+
+// PresetOptions returns the options of the named preset, or DefaultOptions for an unknown name
+func PresetOptions(name string) OptimizeOptions {
+	for _, p := range OptimizePresets {
+		if strings.EqualFold(p.Name, name) {
+			return p.Options()
+		}
+	}
+	return DefaultOptions()
+}
+
 // PathChange represents a single change made during optimization
 type PathChange struct {
-	Type     string // duplicate, dead, shortened, variable, reordered
-	Original string
-	New      string
-	Saved    int
+	Type     string `json:"type"` // duplicate, dead, malformed, canonical, shortened, variable, reordered
+	Original string `json:"original"`
+	New      string `json:"new,omitempty"`
+	Saved    int    `json:"saved"`
+	// MatchedWith is the earlier entry a duplicate was matched against
+	MatchedWith string `json:"matchedWith,omitempty"`
+	// Reason is why a dead entry failed the existence check
+	Reason string `json:"reason,omitempty"`
 }
 
 // PathInfo contains path metadata
 type PathInfo struct {
-	Raw     string
-	Entries []string
-	Length  int
-	Count   int
+	Raw     string   `json:"raw"`
+	Entries []string `json:"entries"`
+	Length  int      `json:"length"`
+	Count   int      `json:"count"`
 }
 
 // OptimizeMetrics contains optimization statistics
 type OptimizeMetrics struct {
-	DuplicatesRemoved int
-	DeadPathsRemoved  int
-	PathsShortened    int
-	VarsSubstituted   int
-	TotalSaved        int
-	PercentageSaved   float64
+	DuplicatesRemoved int     `json:"duplicatesRemoved"`
+	DeadPathsRemoved  int     `json:"deadPathsRemoved"`
+	PathsShortened    int     `json:"pathsShortened"`
+	VarsSubstituted   int     `json:"varsSubstituted"`
+	TotalSaved        int     `json:"totalSaved"`
+	PercentageSaved   float64 `json:"percentageSaved"`
+	// CheckedEntries counts entries tested for existence during dead path removal;
+	// UncheckedEntries counts variable-based entries that were skipped
+	CheckedEntries   int `json:"checkedEntries"`
+	UncheckedEntries int `json:"uncheckedEntries"`
+	// EstimatedLookupsAvoided roughly counts the file probes a command lookup no longer
+	// makes: one per PATHEXT extension for every removed entry, and for every position
+	// a hot path moved forward. It is a heuristic, not a measurement
+	EstimatedLookupsAvoided int `json:"estimatedLookupsAvoided"`
 }
 
 // OptimizeResult contains the results of path optimization
 type OptimizeResult struct {
-	Original  PathInfo
-	Optimized PathInfo
-	Changes   []PathChange
-	Metrics   OptimizeMetrics
+	Original  PathInfo        `json:"original"`
+	Optimized PathInfo        `json:"optimized"`
+	Changes   []PathChange    `json:"changes"`
+	Metrics   OptimizeMetrics `json:"metrics"`
+	// CaseConflicts lists case variants kept because their directory is case sensitive
+	CaseConflicts []CaseConflict `json:"caseConflicts,omitempty"`
+	// StoredAsString is set when PATH is REG_SZ; the next write converts it to REG_EXPAND_SZ
+	StoredAsString bool `json:"storedAsString,omitempty"`
+	// ProtectedViolations lists protected entries this result would remove or rewrite
+	ProtectedViolations []ProtectedViolation `json:"protectedViolations,omitempty"`
 }
 
 // NormalizePath normalizes a path for comparison
@@ -72,12 +169,66 @@ func NormalizePath(p string) string {
 
 // PathExists checks if a path exists on disk
 func PathExists(path string) bool {
+	return statPath(path) == nil
+}
+
+// statPath returns why path can't be found on disk, or nil if it exists
+func statPath(path string) error {
 	// Don't check paths with unexpanded variables
 	if strings.Contains(path, "%") {
-		return true
+		return nil
 	}
 	_, err := os.Stat(path)
-	return err == nil
+	return err
+}
+
+// existenceWorkers bounds how many existence checks run at once
+const existenceWorkers = 8
+
+// CheckPathsExist runs PathExists for each distinct path on up to workers goroutines
+// and returns the results keyed by path
+func CheckPathsExist(paths []string, workers int) map[string]bool {
+	results := make(map[string]bool, len(paths))
+	for p, err := range statPaths(paths, workers) {
+		results[p] = err == nil
+	}
+	return results
+}
+
+// statPaths runs statPath for each distinct path on up to workers goroutines,
+// keeping the error so a dead path's reason doesn't need a second, serial check
+func statPaths(paths []string, workers int) map[string]error {
+	results := make(map[string]error, len(paths))
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for range min(workers, len(paths)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range jobs {
+				err := statPath(p)
+				mu.Lock()
+				results[p] = err
+				mu.Unlock()
+			}
+		}()
+	}
+
+	queued := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		if !queued[p] {
+			queued[p] = true
+			jobs <- p
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return results
 }
 
 // Optimize optimizes a PATH string
@@ -88,17 +239,26 @@ func Optimize(pathStr string, opts OptimizeOptions) OptimizeResult {
 // OptimizeWithProgress optimizes a PATH string with progress reporting
 // entryProcessor handles optimization of a single PATH entry
 type entryProcessor struct {
-	opts   OptimizeOptions
-	result *OptimizeResult
-	seen   map[string]bool
+	opts          OptimizeOptions
+	result        *OptimizeResult
+	seen          map[string]string // normalized -> first entry
+	kept          map[string]bool   // case-preserving keys of kept case variants
+	caseSensitive map[string]bool   // directory -> case sensitivity flag
+	canonical     map[string]string // entry -> on-disk form, when canonicalizing
+	statErrs      map[string]error  // entry -> pre-resolved statPath result, when checked concurrently
+	shortLookup   []string          // Long paths to resolve in one batch the first time one is shortened
+	shortNames    map[string]string // lower-case long path -> 8.3 form from that batch
+	extraVars     []string          // configured ExtraSubstitutionVars
 }
 
 // newEntryProcessor creates a new entry processor
 func newEntryProcessor(opts OptimizeOptions, result *OptimizeResult) *entryProcessor {
 	return &entryProcessor{
-		opts:   opts,
-		result: result,
-		seen:   make(map[string]bool),
+		opts:          opts,
+		result:        result,
+		seen:          make(map[string]string),
+		kept:          make(map[string]bool),
+		caseSensitive: make(map[string]bool),
 	}
 }
 
@@ -107,16 +267,43 @@ func (p *entryProcessor) isDuplicate(entry, normalized string) bool {
 	if !p.opts.RemoveDuplicates {
 		return false
 	}
-	if p.seen[normalized] {
-		p.result.Changes = append(p.result.Changes, PathChange{
-			Type:     "duplicate",
-			Original: entry,
-		})
-		p.result.Metrics.DuplicatesRemoved++
-		return true
+	first, seen := p.seen[normalized]
+	if !seen {
+		p.seen[normalized] = entry
+		return false
+	}
+	if !p.kept[cleanEntry(entry)] {
+		if dir := caseVariantDir(first, entry); dir != "" && p.isCaseSensitive(dir) {
+			// The names can refer to different folders, so keep both and flag it
+			p.kept[cleanEntry(entry)] = true
+			p.result.CaseConflicts = append(p.result.CaseConflicts, CaseConflict{
+				Entry:     first,
+				Variant:   entry,
+				Directory: dir,
+			})
+			return false
+		}
 	}
-	p.seen[normalized] = true
-	return false
+	p.result.Changes = append(p.result.Changes, PathChange{
+		Type:        "duplicate",
+		Original:    entry,
+		MatchedWith: first,
+	})
+	p.result.Metrics.DuplicatesRemoved++
+	return true
+}
+
+// isCaseSensitive returns the cached case sensitivity flag for dir
+func (p *entryProcessor) isCaseSensitive(dir string) bool {
+	if p.opts.Offline {
+		return false
+	}
+	sensitive, ok := p.caseSensitive[dir]
+	if !ok {
+		sensitive = IsCaseSensitiveDir(dir)
+		p.caseSensitive[dir] = sensitive
+	}
+	return sensitive
 }
 
 // isDeadPath checks if entry is a dead path
@@ -125,26 +312,38 @@ func (p *entryProcessor) isDeadPath(entry string) bool {
 		return false
 	}
 	if strings.Contains(entry, "%") {
+		p.result.Metrics.UncheckedEntries++
 		return false
 	}
-	if PathExists(entry) {
+	p.result.Metrics.CheckedEntries++
+	err := p.statPath(entry)
+	if err == nil {
 		return false
 	}
 	p.result.Changes = append(p.result.Changes, PathChange{
 		Type:     "dead",
 		Original: entry,
+		Reason:   err.Error(),
 	})
 	p.result.Metrics.DeadPathsRemoved++
 	return true
 }
 
+// statPath consults the pre-resolved stat results, falling back to a direct check
+func (p *entryProcessor) statPath(entry string) error {
+	if err, ok := p.statErrs[entry]; ok {
+		return err
+	}
+	return statPath(entry)
+}
+
 // tryShorten attempts to shorten the path using 8.3 names
 func (p *entryProcessor) tryShorten(current string) string {
 	if !p.opts.ShortenPaths || strings.Contains(current, "%") {
 		return current
 	}
-	short, shortened := ToShortPath(current)
-	if !shortened || len(short) >= len(current) {
+	short := p.shortPath(current)
+	if len(short) >= len(current) {
 		return current
 	}
 	saved := len(current) - len(short)
@@ -159,12 +358,28 @@ func (p *entryProcessor) tryShorten(current string) string {
 	return short
 }
 
+// shortPath returns the 8.3 form of path from the batched lookup, falling back to a
+// lookup of its own for paths the batch didn't cover
+// The batch runs on first use, so a PATH with nothing left to shorten costs no shell call
+func (p *entryProcessor) shortPath(path string) string {
+	if p.shortNames == nil && len(p.shortLookup) > 0 {
+		p.shortNames = make(map[string]string, len(p.shortLookup))
+		for i, short := range ToShortPathBatch(p.shortLookup) {
+			p.shortNames[strings.ToLower(p.shortLookup[i])] = short
+		}
+	}
+	if short, ok := p.shortNames[strings.ToLower(path)]; ok {
+		return short
+	}
+	return shortPathOf(path)
+}
+
 // trySubstituteVars attempts to substitute environment variables
 func (p *entryProcessor) trySubstituteVars(current string) string {
 	if !p.opts.SubstituteVars || strings.Contains(current, "%") {
 		return current
 	}
-	subst, substituted := SubstituteEnvVars(current)
+	subst, substituted := SubstituteEnvVarsWith(current, p.extraVars)
 	if !substituted || len(subst) >= len(current) {
 		return current
 	}
@@ -185,7 +400,7 @@ func (p *entryProcessor) tryShortenSuffix(current string) string {
 	if !p.opts.ShortenPaths {
 		return current
 	}
-	shortSuffix, shortened := ShortenSuffix(current)
+	shortSuffix, shortened := shortenSuffixWith(current, p.shortPath)
 	if !shortened || len(shortSuffix) >= len(current) {
 		return current
 	}
@@ -201,6 +416,72 @@ func (p *entryProcessor) tryShortenSuffix(current string) string {
 	return shortSuffix
 }
 
+// tryCanonicalize returns the on-disk form of entry when canonicalization is enabled
+func (p *entryProcessor) tryCanonicalize(entry string) string {
+	canonical, ok := p.canonical[entry]
+	if !ok || canonical == entry {
+		return entry
+	}
+	p.result.Changes = append(p.result.Changes, PathChange{
+		Type:     "canonical",
+		Original: entry,
+		New:      canonical,
+		Saved:    len(entry) - len(canonical),
+	})
+	return canonical
+}
+
+// IsWSLPath reports whether entry is a WSL-style /mnt/<drive>/... path
+func IsWSLPath(entry string) bool {
+	lower := strings.ToLower(entry)
+	if !strings.HasPrefix(lower, "/mnt/") || len(lower) < 6 {
+		return false
+	}
+	drive := lower[5]
+	return drive >= 'a' && drive <= 'z' && (len(lower) == 6 || lower[6] == '/')
+}
+
+// MalformedSuggestion returns the Windows form of a drive path written with forward
+// slashes or of a WSL /mnt/<drive>/ path, or "" if the entry looks fine
+func MalformedSuggestion(entry string) string {
+	if IsWSLPath(entry) {
+		rest := strings.TrimPrefix(entry[6:], "/")
+		return strings.ToUpper(entry[5:6]) + `:\` + strings.ReplaceAll(rest, "/", `\`)
+	}
+	if hasDrivePrefix(entry) && strings.Contains(entry, "/") {
+		return strings.ReplaceAll(entry, "/", `\`)
+	}
+	return ""
+}
+
+// hasDrivePrefix reports whether entry starts with a drive letter such as C:
+func hasDrivePrefix(entry string) bool {
+	if len(entry) < 2 || entry[1] != ':' {
+		return false
+	}
+	drive := entry[0] | 0x20
+	return drive >= 'a' && drive <= 'z'
+}
+
+// checkMalformed flags entries using forward slashes or WSL paths, keeping the raw
+// entry as Original. Drive paths are rewritten when FixSlashes is set; WSL paths are
+// only flagged, since converting them is a guess
+func (p *entryProcessor) checkMalformed(entry string) string {
+	suggestion := MalformedSuggestion(entry)
+	if suggestion == "" {
+		return entry
+	}
+	p.result.Changes = append(p.result.Changes, PathChange{
+		Type:     "malformed",
+		Original: entry,
+		New:      suggestion,
+	})
+	if p.opts.FixSlashes && !IsWSLPath(entry) {
+		return suggestion
+	}
+	return entry
+}
+
 // processEntry processes a single entry and returns the optimized version or empty if skipped
 func (p *entryProcessor) processEntry(entry string) (string, bool) {
 	normalized := NormalizePath(entry)
@@ -208,11 +489,16 @@ func (p *entryProcessor) processEntry(entry string) (string, bool) {
 	if p.isDuplicate(entry, normalized) {
 		return "", false
 	}
-	if p.isDeadPath(entry) {
+	current := p.checkMalformed(entry)
+	if current == entry && MalformedSuggestion(entry) != "" {
+		// Left as written for the user to fix; it can't be checked or shortened
+		return entry, true
+	}
+	if p.isDeadPath(current) {
 		return "", false
 	}
 
-	current := entry
+	current = p.tryCanonicalize(current)
 	current = p.tryShorten(current)
 
 	beforeSubst := current
@@ -233,7 +519,38 @@ func OptimizeWithProgress(pathStr string, opts OptimizeOptions, startIdx, total
 	result.Original.Length = len(pathStr)
 	result.Original.Count = len(entries)
 
+	var config Config
+	if !opts.Offline {
+		config = LoadConfig()
+	}
 	processor := newEntryProcessor(opts, &result)
+	processor.extraVars = config.ExtraSubstitutionVars
+	if !opts.Offline {
+		// Custom variables this PATH already uses are known to expand in it, so literal
+		// entries under their values can use them too
+		processor.extraVars = append(append([]string(nil), config.ExtraSubstitutionVars...), customVarNames(pathStr)...)
+	}
+	if opts.CanonicalizePaths {
+		// One batched lookup for all entries instead of one shell call each
+		canonical := CanonicalPaths(entries)
+		processor.canonical = make(map[string]string, len(entries))
+		for i, entry := range entries {
+			processor.canonical[entry] = canonical[i]
+		}
+	}
+	if opts.ShortenPaths {
+		// One batched lookup instead of a shell call per entry
+		processor.shortLookup = make([]string, len(entries))
+		for i, entry := range entries {
+			processor.shortLookup[i] = entry
+			if canonical, ok := processor.canonical[entry]; ok {
+				processor.shortLookup[i] = canonical
+			}
+		}
+	}
+	if opts.RemoveDeadPaths && opts.ConcurrentExistenceChecks {
+		processor.statErrs = statPaths(entries, existenceWorkers)
+	}
 	optimized := make([]string, 0, len(entries))
 
 	for i, entry := range entries {
@@ -247,16 +564,23 @@ func OptimizeWithProgress(pathStr string, opts OptimizeOptions, startIdx, total
 	}
 
 	// Apply hot paths prioritization
-	config := LoadConfig()
+	skipped := 0
 	if len(config.HotPaths) > 0 {
-		optimized = applyHotPaths(optimized, config.HotPaths)
+		reordered := applyHotPaths(optimized, config.HotPaths)
+		result.Changes = append(result.Changes, reorderChanges(optimized, reordered, config.HotPaths)...)
+		skipped = positionsGained(optimized, reordered, config.HotPaths)
+		optimized = reordered
 	}
+	removed := result.Metrics.DuplicatesRemoved + result.Metrics.DeadPathsRemoved
+	result.Metrics.EstimatedLookupsAvoided = (removed + skipped) * pathExtCount()
 
 	result.Optimized.Entries = optimized
 	result.Optimized.Raw = JoinPath(optimized)
 	result.Optimized.Length = len(result.Optimized.Raw)
 	result.Optimized.Count = len(optimized)
 
+	result.ProtectedViolations = FindProtectedViolations(result, config.ProtectedEntries)
+
 	if result.Original.Length > 0 {
 		result.Metrics.PercentageSaved = float64(result.Original.Length-result.Optimized.Length) / float64(result.Original.Length) * 100
 	}
@@ -264,7 +588,63 @@ func OptimizeWithProgress(pathStr string, opts OptimizeOptions, startIdx, total
 	return result
 }
 
-// applyHotPaths moves hot paths to the front of the list
+// RejectShortenings rebuilds result with the long form kept for every shortened entry
+// whose original path is in rejected, as if those 8.3 conversions had not been made
+func RejectShortenings(result OptimizeResult, rejected map[string]bool) OptimizeResult {
+	revert := make(map[string]string)
+	changes := make([]PathChange, 0, len(result.Changes))
+	for _, c := range result.Changes {
+		if c.Type == "shortened" && rejected[c.Original] {
+			revert[c.New] = c.Original
+			result.Metrics.PathsShortened--
+			result.Metrics.TotalSaved -= c.Saved
+			continue
+		}
+		changes = append(changes, c)
+	}
+	if len(revert) == 0 {
+		return result
+	}
+	for i, c := range changes {
+		if long, ok := revert[c.Original]; ok && c.Type == "reordered" {
+			changes[i].Original = long
+		}
+	}
+	result.Changes = changes
+
+	entries := make([]string, len(result.Optimized.Entries))
+	for i, e := range result.Optimized.Entries {
+		if long, ok := revert[e]; ok {
+			e = long
+		}
+		entries[i] = e
+	}
+	result.Optimized.Entries = entries
+	result.Optimized.Raw = JoinPath(entries)
+	result.Optimized.Length = len(result.Optimized.Raw)
+
+	var violations []ProtectedViolation
+	for _, v := range result.ProtectedViolations {
+		if _, ok := revert[v.New]; !ok || v.Action != "modified" {
+			violations = append(violations, v)
+		}
+	}
+	result.ProtectedViolations = violations
+
+	if result.Original.Length > 0 {
+		result.Metrics.PercentageSaved = float64(result.Original.Length-result.Optimized.Length) / float64(result.Original.Length) * 100
+	}
+	return result
+}
+
+// PreviewHotPaths returns the entries of a PATH string with hot paths moved to the front
+// It applies the same ordering the optimizer uses, without touching anything else
+func PreviewHotPaths(pathStr string, hotPaths []string) []string {
+	return applyHotPaths(ParsePath(pathStr), hotPaths)
+}
+
+// applyHotPaths moves hot paths to the front of the list. Everything else keeps
+// its relative order, so with no hot paths present the list is returned as is
 func applyHotPaths(entries []string, hotPaths []string) []string {
 	if len(hotPaths) == 0 {
 		return entries
@@ -283,7 +663,9 @@ func applyHotPaths(entries []string, hotPaths []string) []string {
 
 	for _, entry := range entries {
 		normalized := NormalizePath(entry)
-		if idx, ok := hotPathsSet[normalized]; ok {
+		if idx, ok := hotPathsSet[normalized]; ok && !hotFound[idx] {
+			// Only the first copy moves; later copies (kept when duplicates are
+			// not removed) stay where they were instead of being dropped
 			hot[idx] = entry
 			hotFound[idx] = true
 		} else {
@@ -303,31 +685,110 @@ func applyHotPaths(entries []string, hotPaths []string) []string {
 	return result
 }
 
+// reorderChanges records the hot paths that applyHotPaths moved forward
+func reorderChanges(before, after, hotPaths []string) []PathChange {
+	hot := make(map[string]bool, len(hotPaths))
+	for _, hp := range hotPaths {
+		hot[NormalizePath(hp)] = true
+	}
+
+	var changes []PathChange
+	for i, entry := range after {
+		if entry != before[i] && hot[NormalizePath(entry)] {
+			changes = append(changes, PathChange{Type: "reordered", Original: entry})
+		}
+	}
+	return changes
+}
+
+// positionsGained sums how many places each hot path moved forward between before and after
+func positionsGained(before, after, hotPaths []string) int {
+	hot := make(map[string]bool, len(hotPaths))
+	for _, hp := range hotPaths {
+		hot[NormalizePath(hp)] = true
+	}
+	index := make(map[string]int, len(before))
+	for i, entry := range before {
+		index[entry] = i
+	}
+	gained := 0
+	for i, entry := range after {
+		if hot[NormalizePath(entry)] && index[entry] > i {
+			gained += index[entry] - i
+		}
+	}
+	return gained
+}
+
+// pathExtCount returns how many extensions a lookup tries in each PATH directory
+func pathExtCount() int {
+	return len(processPathExt())
+}
+
+// IsReorderOnly reports whether changes only reorder entries without removing or rewriting any
+func IsReorderOnly(changes []PathChange) bool {
+	if len(changes) == 0 {
+		return false
+	}
+	for _, c := range changes {
+		if c.Type != "reordered" {
+			return false
+		}
+	}
+	return true
+}
+
 // AnalyzeAll analyzes both System and User PATH
 type AnalysisResult struct {
-	System          OptimizeResult
-	User            OptimizeResult
-	CustomVariables []CustomPathVar
+	System              OptimizeResult       `json:"system"`
+	User                OptimizeResult       `json:"user"`
+	CustomVariables     []CustomPathVar      `json:"customVariables"`
+	JunctionEquivalents []JunctionEquivalent `json:"junctionEquivalents,omitempty"`
+	NestedEntries       []NestedEntry        `json:"nestedEntries,omitempty"`
+	UserVarsInSystem    []UserVarEntry       `json:"userVarsInSystem,omitempty"`
+	MisdirectedVars     []MisdirectedVar     `json:"misdirectedVars,omitempty"`
+	ShadowedTools       []ShadowInfo         `json:"shadowedTools,omitempty"`
+}
+
+// UserVarEntry is a System PATH entry that relies on a per-user variable
+type UserVarEntry struct {
+	Entry    string `json:"entry"`
+	Variable string `json:"variable"`
+}
+
+// NestedEntry is a PATH entry inside another PATH entry, e.g. C:\app and C:\app\bin
+type NestedEntry struct {
+	Parent string `json:"parent"`
+	Child  string `json:"child"`
 }
 
 type CustomPathVar struct {
-	Name    string
-	FoundIn string
-	Value   string
+	Name    string `json:"name"`
+	FoundIn string `json:"foundIn"`
+	Value   string `json:"value,omitempty"`
 }
 
-func AnalyzeAll(opts OptimizeOptions) AnalysisResult {
+// AnalyzeAll reads both PATH values and analyzes them, failing if either can't be read
+func AnalyzeAll(opts OptimizeOptions) (AnalysisResult, error) {
 	return AnalyzeAllWithProgress(opts, nil)
 }
 
 // ProgressFunc is a callback for reporting progress
 type ProgressFunc func(current, total int, item string)
 
-func AnalyzeAllWithProgress(opts OptimizeOptions, progress ProgressFunc) AnalysisResult {
-	result := AnalysisResult{}
+// AnalyzeAllWithProgress is AnalyzeAll with progress reporting
+func AnalyzeAllWithProgress(opts OptimizeOptions, progress ProgressFunc) (AnalysisResult, error) {
+	sysPath, usrPath, err := GetPathsRaw()
+	if err != nil {
+		return AnalysisResult{}, err
+	}
+	return AnalyzeAllFrom(sysPath, usrPath, opts, progress), nil
+}
 
-	sysPath, _ := GetPathRaw("System")
-	usrPath, _ := GetPathRaw("User")
+// AnalyzeAllFrom analyzes raw System and User PATH values the caller has already
+// read, so a run that needs them elsewhere doesn't query the registry twice
+func AnalyzeAllFrom(sysPath, usrPath string, opts OptimizeOptions, progress ProgressFunc) AnalysisResult {
+	result := AnalysisResult{}
 
 	sysEntries := ParsePath(sysPath)
 	usrEntries := ParsePath(usrPath)
@@ -340,6 +801,7 @@ func AnalyzeAllWithProgress(opts OptimizeOptions, progress ProgressFunc) Analysi
 	usrOpts := opts
 	usrOpts.Scope = "User"
 	result.User = OptimizeWithProgress(usrPath, usrOpts, len(sysEntries), totalEntries, progress)
+	result.System.StoredAsString, result.User.StoredAsString = PathStoredAsString()
 
 	// Detect custom path variables
 	if progress != nil {
@@ -347,9 +809,98 @@ func AnalyzeAllWithProgress(opts OptimizeOptions, progress ProgressFunc) Analysi
 	}
 	result.CustomVariables = DetectCustomPathVars(sysPath, usrPath)
 
+	// Detect entries that duplicate each other through a junction
+	if progress != nil {
+		progress(totalEntries, totalEntries, "Checking junction equivalents...")
+	}
+	allEntries := append(append([]string{}, sysEntries...), usrEntries...)
+	result.JunctionEquivalents = []JunctionEquivalent{}
+	if usesJunctionFolder(allEntries) {
+		// Equivalents need an entry through a junction, so skip listing them otherwise
+		result.JunctionEquivalents = FindJunctionEquivalents(allEntries, ListJunctions())
+	}
+	result.NestedEntries = FindNestedEntries(allEntries)
+	result.UserVarsInSystem = FindUserVarsInSystem(sysEntries)
+	result.MisdirectedVars = FindMisdirectedPathVars(GetAllEnvVars())
+	if opts.DetectShadowedTools {
+		if progress != nil {
+			progress(totalEntries, totalEntries, "Scanning for shadowed tools...")
+		}
+		result.ShadowedTools = FindShadowedTools(allEntries, processPathExt())
+	}
+
+	return result
+}
+
+// FindNestedEntries reports entries that sit inside another entry on the PATH
+// Only the closest parent is reported for each child; exact duplicates are ignored
+func FindNestedEntries(entries []string) []NestedEntry {
+	normalized := make([]string, len(entries))
+	for i, e := range entries {
+		normalized[i] = NormalizePath(e)
+	}
+
+	result := make([]NestedEntry, 0)
+	reported := make(map[string]bool)
+	for i, child := range normalized {
+		if reported[child] {
+			continue
+		}
+		parent := -1
+		for j, candidate := range normalized {
+			if i == j || len(candidate) >= len(child) {
+				continue
+			}
+			if !strings.HasPrefix(child, candidate) {
+				continue
+			}
+			if sep := child[len(candidate)]; sep != '\\' && sep != '/' {
+				continue
+			}
+			if parent == -1 || len(candidate) > len(normalized[parent]) {
+				parent = j
+			}
+		}
+		if parent != -1 {
+			reported[child] = true
+			result = append(result, NestedEntry{Parent: entries[parent], Child: entries[i]})
+		}
+	}
 	return result
 }
 
+// perUserVars are variables whose value differs per user, so they don't belong in System PATH
+var perUserVars = map[string]bool{
+	"userprofile": true, "appdata": true, "localappdata": true,
+	"homepath": true, "username": true, "onedrive": true,
+	"temp": true, "tmp": true,
+}
+
+// FindUserVarsInSystem reports System PATH entries that reference per-user variables
+// such as %USERPROFILE%, which resolve against whichever account reads the PATH
+func FindUserVarsInSystem(sysEntries []string) []UserVarEntry {
+	result := make([]UserVarEntry, 0)
+	for _, entry := range sysEntries {
+		for _, name := range UnresolvedVars(entry) {
+			if perUserVars[strings.ToLower(name)] {
+				result = append(result, UserVarEntry{Entry: entry, Variable: name})
+				break
+			}
+		}
+	}
+	return result
+}
+
+// customVarNames returns the names of the custom variables used in a PATH string, sorted
+func customVarNames(pathStr string) []string {
+	var names []string
+	for _, v := range DetectCustomPathVars(pathStr, "") {
+		names = append(names, v.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // DetectCustomPathVars finds custom PATH-like variables in the PATH strings
 func DetectCustomPathVars(sysPath, usrPath string) []CustomPathVar {
 	systemVars := map[string]bool{
@@ -1,7 +1,9 @@
 package path
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -193,6 +195,26 @@ func TestOptimize_Metrics(t *testing.T) {
 	}
 }
 
+func TestOptimize_CheckedAndUncheckedEntries(t *testing.T) {
+	opts := DefaultOptions()
+	opts.ShortenPaths = false
+	opts.SubstituteVars = false
+
+	dir := t.TempDir()
+	input := dir + `;%SystemRoot%\System32;` + dir + `;%JAVA_HOME%\bin;C:\Does\Not\Exist`
+	result := Optimize(input, opts)
+
+	if result.Metrics.CheckedEntries != 2 {
+		t.Errorf("CheckedEntries = %d, want 2", result.Metrics.CheckedEntries)
+	}
+	if result.Metrics.UncheckedEntries != 2 {
+		t.Errorf("UncheckedEntries = %d, want 2", result.Metrics.UncheckedEntries)
+	}
+	if result.Metrics.DeadPathsRemoved != 1 {
+		t.Errorf("DeadPathsRemoved = %d, want 1", result.Metrics.DeadPathsRemoved)
+	}
+}
+
 func TestOptimize_PercentageSaved(t *testing.T) {
 	opts := DefaultOptions()
 	opts.RemoveDeadPaths = false
@@ -207,6 +229,33 @@ func TestOptimize_PercentageSaved(t *testing.T) {
 	}
 }
 
+func TestOptimize_EstimatedLookupsAvoided(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+	t.Setenv("PATHEXT", ".COM;.EXE;.BAT;.CMD")
+	opts := ConservativeOptions()
+
+	result := Optimize(`C:\Test;C:\Other`, opts)
+	if result.Metrics.EstimatedLookupsAvoided != 0 {
+		t.Errorf("Expected no estimate when nothing is removed, got %d", result.Metrics.EstimatedLookupsAvoided)
+	}
+
+	result = Optimize(`C:\Test;C:\Test;C:\Other;C:\test`, opts)
+	if result.Metrics.EstimatedLookupsAvoided != 2*4 {
+		t.Errorf("Expected 2 removed entries x 4 extensions, got %d", result.Metrics.EstimatedLookupsAvoided)
+	}
+
+	config := LoadConfig()
+	config.HotPaths = []string{`C:\Hot`}
+	if err := SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	result = Optimize(`C:\A;C:\B;C:\Hot`, opts)
+	if result.Metrics.EstimatedLookupsAvoided != 2*4 {
+		t.Errorf("Expected a hot path moved 2 places x 4 extensions, got %d", result.Metrics.EstimatedLookupsAvoided)
+	}
+}
+
 func TestOptimize_ZeroOriginalLength(t *testing.T) {
 	opts := DefaultOptions()
 	result := Optimize("", opts)
@@ -269,6 +318,180 @@ func TestApplyHotPaths_CaseInsensitive(t *testing.T) {
 	}
 }
 
+func TestApplyHotPaths_KeepsDuplicateCopies(t *testing.T) {
+	entries := []string{`C:\First`, `C:\Git`, `C:\Second`, `c:\git`}
+	result := applyHotPaths(entries, []string{`C:\Git`})
+
+	expected := []string{`C:\Git`, `C:\First`, `C:\Second`, `c:\git`}
+	if strings.Join(result, ";") != strings.Join(expected, ";") {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestOptimize_StableOrderWithoutRewrites(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+	config := LoadConfig()
+	config.HotPaths = []string{`C:\Not\In\Path`}
+	if err := SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+
+	var dirs []string
+	for range 4 {
+		dirs = append(dirs, t.TempDir())
+	}
+	dead := filepath.Join(t.TempDir(), "gone")
+	input := []string{dirs[2], dirs[0], dead, dirs[3], dirs[0], dirs[1], dirs[2]}
+
+	opts := RecommendedOptions()
+	result := Optimize(JoinPath(input), opts)
+
+	expected := []string{dirs[2], dirs[0], dirs[3], dirs[1]}
+	if strings.Join(result.Optimized.Entries, ";") != strings.Join(expected, ";") {
+		t.Errorf("Expected the first copies in their original order:\nwant %v\ngot  %v", expected, result.Optimized.Entries)
+	}
+}
+
+func TestPreviewHotPaths(t *testing.T) {
+	pathStr := `C:\Windows;C:\Tools;C:\Python;C:\Git`
+	hotPaths := []string{`C:\Git`, `c:\python\`, `C:\Missing`}
+
+	result := PreviewHotPaths(pathStr, hotPaths)
+	expected := []string{`C:\Git`, `C:\Python`, `C:\Windows`, `C:\Tools`}
+
+	if len(result) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d: %v", len(expected), len(result), result)
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("Entry %d: expected %s, got %s", i, expected[i], result[i])
+		}
+	}
+}
+
+func TestReorderChanges(t *testing.T) {
+	before := []string{`C:\Windows`, `C:\Git`, `C:\Tools`}
+	after := applyHotPaths(before, []string{`C:\Git`, `C:\Windows`})
+
+	changes := reorderChanges(before, after, []string{`C:\Git`, `C:\Windows`})
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 reordered changes, got %d: %+v", len(changes), changes)
+	}
+	for _, c := range changes {
+		if c.Type != "reordered" {
+			t.Errorf("Expected reordered type, got %s", c.Type)
+		}
+	}
+
+	// Hot path already at the front is not a change
+	if changes := reorderChanges(before, before, []string{`C:\Windows`}); len(changes) != 0 {
+		t.Errorf("Expected no changes when order is unchanged, got %+v", changes)
+	}
+}
+
+func TestIsReorderOnly(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes []PathChange
+		want    bool
+	}{
+		{"none", nil, false},
+		{"reorder", []PathChange{{Type: "reordered"}}, true},
+		{"mixed", []PathChange{{Type: "reordered"}, {Type: "dead"}}, false},
+		{"shortened", []PathChange{{Type: "shortened"}}, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsReorderOnly(tt.changes); got != tt.want {
+			t.Errorf("%s: IsReorderOnly = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFindNestedEntries(t *testing.T) {
+	entries := []string{
+		`C:\App`,
+		`C:\Windows`,
+		`C:\App\bin`,
+		`C:\Application\bin`,
+		`C:\App\bin\tools\`,
+		`c:\app\BIN`,
+	}
+
+	result := FindNestedEntries(entries)
+
+	expected := []NestedEntry{
+		{Parent: `C:\App`, Child: `C:\App\bin`},
+		{Parent: `C:\App\bin`, Child: `C:\App\bin\tools\`},
+	}
+	if len(result) != len(expected) {
+		t.Fatalf("Expected %d relationships, got %d: %+v", len(expected), len(result), result)
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("Relationship %d: expected %+v, got %+v", i, expected[i], result[i])
+		}
+	}
+}
+
+func TestFindNestedEntries_None(t *testing.T) {
+	result := FindNestedEntries([]string{`C:\Windows`, `C:\Tools`, `C:\Windows`})
+	if len(result) != 0 {
+		t.Errorf("Expected no relationships, got %+v", result)
+	}
+}
+
+func TestFindUserVarsInSystem(t *testing.T) {
+	result := FindUserVarsInSystem([]string{`C:\Windows`, `%USERPROFILE%\bin`, `%SystemRoot%\System32`, `%LocalAppData%\Tools`})
+
+	expected := []UserVarEntry{
+		{Entry: `%USERPROFILE%\bin`, Variable: "USERPROFILE"},
+		{Entry: `%LocalAppData%\Tools`, Variable: "LocalAppData"},
+	}
+	if len(result) != len(expected) {
+		t.Fatalf("Expected %d findings, got %+v", len(expected), result)
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("Finding %d: expected %+v, got %+v", i, expected[i], result[i])
+		}
+	}
+}
+
+func TestAnalyzeAll_UserVarsInSystem(t *testing.T) {
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetResponse("LocalMachine.OpenSubKey", `C:\Windows;%USERPROFILE%\bin`)
+		mock.SetResponse("CurrentUser.OpenSubKey", `%USERPROFILE%\tools`)
+	}, func() {
+		result, err := AnalyzeAll(DefaultOptions())
+		if err != nil {
+			t.Fatalf("AnalyzeAll failed: %v", err)
+		}
+		if len(result.UserVarsInSystem) != 1 {
+			t.Fatalf("Expected 1 finding, got %+v", result.UserVarsInSystem)
+		}
+		if result.UserVarsInSystem[0].Entry != `%USERPROFILE%\bin` {
+			t.Errorf("Unexpected entry: %s", result.UserVarsInSystem[0].Entry)
+		}
+	})
+}
+
+func TestAnalyzeAll_UserVarsInUserScope(t *testing.T) {
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetResponse("LocalMachine.OpenSubKey", `C:\Windows`)
+		mock.SetResponse("CurrentUser.OpenSubKey", `%USERPROFILE%\bin;%APPDATA%\npm`)
+	}, func() {
+		result, err := AnalyzeAll(DefaultOptions())
+		if err != nil {
+			t.Fatalf("AnalyzeAll failed: %v", err)
+		}
+		if len(result.UserVarsInSystem) != 0 {
+			t.Errorf("User scope variables should not be flagged, got %+v", result.UserVarsInSystem)
+		}
+	})
+}
+
 func TestDetectCustomPathVars(t *testing.T) {
 	sysPath := `%SystemRoot%;%CUSTOM_VAR%\bin`
 	usrPath := `%USERPROFILE%;%MY_TOOL_HOME%\bin`
@@ -334,7 +557,10 @@ func TestAnalyzeAll(t *testing.T) {
 	opts := DefaultOptions()
 	opts.RemoveDeadPaths = false
 
-	result := AnalyzeAll(opts)
+	result, err := AnalyzeAll(opts)
+	if err != nil {
+		t.Fatalf("AnalyzeAll failed: %v", err)
+	}
 	t.Logf("System: %d, User: %d", result.System.Original.Count, result.User.Original.Count)
 }
 
@@ -347,7 +573,10 @@ func TestAnalyzeAllWithProgress(t *testing.T) {
 		progressCalled = true
 	}
 
-	result := AnalyzeAllWithProgress(opts, progress)
+	result, err := AnalyzeAllWithProgress(opts, progress)
+	if err != nil {
+		t.Fatalf("AnalyzeAll failed: %v", err)
+	}
 	t.Logf("Analysis: System=%d, User=%d, progress=%v",
 		result.System.Original.Count, result.User.Original.Count, progressCalled)
 }
@@ -385,6 +614,153 @@ func TestOptimizeWithProgress_NilCallback(t *testing.T) {
 	}
 }
 
+func TestRecommendedOptions(t *testing.T) {
+	opts := RecommendedOptions()
+	if !opts.RemoveDuplicates || !opts.RemoveDeadPaths {
+		t.Error("Recommended options should remove duplicates and dead paths")
+	}
+	if opts.ShortenPaths || opts.SubstituteVars || opts.ReorderPaths || opts.CanonicalizePaths {
+		t.Errorf("Recommended options should not rewrite entries: %+v", opts)
+	}
+}
+
+func TestOptimizePresets(t *testing.T) {
+	tests := []struct {
+		name string
+		opts OptimizeOptions
+		want OptimizeOptions
+	}{
+		{"conservative", ConservativeOptions(), OptimizeOptions{RemoveDuplicates: true, Scope: "User"}},
+		{"existing", ExistingOnlyOptions(), OptimizeOptions{RemoveDuplicates: true, RemoveDeadPaths: true, ConcurrentExistenceChecks: true, Scope: "User"}},
+		{"aggressive", AggressiveOptions(), OptimizeOptions{
+			RemoveDuplicates: true, RemoveDeadPaths: true, ShortenPaths: true, SubstituteVars: true, ReorderPaths: true,
+			CanonicalizePaths: true, ConcurrentExistenceChecks: true, FixSlashes: true, Scope: "User",
+		}},
+	}
+	for _, tt := range tests {
+		if tt.opts != tt.want {
+			t.Errorf("%s: expected %+v, got %+v", tt.name, tt.want, tt.opts)
+		}
+		if got := PresetOptions(tt.name); got != tt.want {
+			t.Errorf("PresetOptions(%q): expected %+v, got %+v", tt.name, tt.want, got)
+		}
+	}
+	if PresetOptions("") != DefaultOptions() || PresetOptions("bogus") != DefaultOptions() {
+		t.Error("Unknown presets should fall back to the default options")
+	}
+	if OptimizePresets[0].Name != "default" {
+		t.Errorf("Expected the default preset first, got %s", OptimizePresets[0].Name)
+	}
+}
+
+func TestMalformedSuggestion(t *testing.T) {
+	tests := []struct {
+		entry string
+		want  string
+	}{
+		{"/mnt/c/tools", `C:\tools`},
+		{"/mnt/d", `D:\`},
+		{"C:/Program Files", `C:\Program Files`},
+		{`C:\Program Files\Git\cmd`, ""},
+		{"/mnt/cdrom/bin", ""},
+	}
+	for _, tt := range tests {
+		if got := MalformedSuggestion(tt.entry); got != tt.want {
+			t.Errorf("MalformedSuggestion(%q) = %q, want %q", tt.entry, got, tt.want)
+		}
+	}
+}
+
+func TestOptimize_MalformedEntries(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+	opts := DefaultOptions()
+	opts.RemoveDeadPaths = false
+	opts.ShortenPaths = false
+	opts.SubstituteVars = false
+
+	result := Optimize(`/mnt/c/tools;C:/Program Files;C:\Windows`, opts)
+
+	var malformed []PathChange
+	for _, c := range result.Changes {
+		if c.Type == "malformed" {
+			malformed = append(malformed, c)
+		}
+	}
+	if len(malformed) != 2 {
+		t.Fatalf("Expected 2 malformed entries, got %+v", result.Changes)
+	}
+	if malformed[0].Original != "/mnt/c/tools" || malformed[0].New != `C:\tools` {
+		t.Errorf("Unexpected WSL finding: %+v", malformed[0])
+	}
+	if malformed[1].Original != "C:/Program Files" || malformed[1].New != `C:\Program Files` {
+		t.Errorf("Unexpected forward-slash finding: %+v", malformed[1])
+	}
+
+	want := []string{"/mnt/c/tools", `C:\Program Files`, `C:\Windows`}
+	if strings.Join(result.Optimized.Entries, ";") != strings.Join(want, ";") {
+		t.Errorf("Expected the WSL path kept and the drive path fixed, got %v", result.Optimized.Entries)
+	}
+
+	opts.FixSlashes = false
+	result = Optimize(`C:/Program Files`, opts)
+	if result.Optimized.Raw != "C:/Program Files" || len(result.Changes) != 1 {
+		t.Errorf("Without FixSlashes the entry should only be flagged, got %q %+v", result.Optimized.Raw, result.Changes)
+	}
+}
+
+func TestOptimize_DuplicateRecordsMatch(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+	opts := DefaultOptions()
+	opts.RemoveDeadPaths = false
+	opts.ShortenPaths = false
+	opts.SubstituteVars = false
+
+	result := Optimize(`C:\Tools;C:\Other;c:\tools\;C:\Other`, opts)
+	var matches []string
+	for _, c := range result.Changes {
+		if c.Type == "duplicate" {
+			matches = append(matches, c.Original+" => "+c.MatchedWith)
+		}
+	}
+	want := []string{`c:\tools\ => C:\Tools`, `C:\Other => C:\Other`}
+	if strings.Join(matches, "|") != strings.Join(want, "|") {
+		t.Errorf("Expected duplicates matched to their first entries, got %v", matches)
+	}
+}
+
+func TestOptimize_DeadPathRecordsReason(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+	missing := filepath.Join(t.TempDir(), "missing")
+	opts := DefaultOptions()
+	opts.ShortenPaths = false
+	opts.SubstituteVars = false
+
+	result := Optimize(missing, opts)
+	if len(result.Changes) != 1 || result.Changes[0].Type != "dead" {
+		t.Fatalf("Expected one dead path, got %+v", result.Changes)
+	}
+	if result.Changes[0].Reason == "" {
+		t.Error("Expected the existence check error to be recorded")
+	}
+}
+
+func TestEntryProcessor_DeadPathReasonFromConcurrentCheck(t *testing.T) {
+	result := &OptimizeResult{}
+	p := newEntryProcessor(OptimizeOptions{RemoveDeadPaths: true}, result)
+	// The reason comes from the worker pool's result, not a second stat on the caller
+	p.statErrs = map[string]error{`\\server\share\bin`: fmt.Errorf("network path unreachable")}
+
+	if !p.isDeadPath(`\\server\share\bin`) {
+		t.Fatal("Expected the entry to be dead")
+	}
+	if result.Changes[0].Reason != "network path unreachable" {
+		t.Errorf("Expected the pooled stat error as the reason, got %q", result.Changes[0].Reason)
+	}
+}
+
 func BenchmarkOptimize(b *testing.B) {
 	paths := make([]string, 50)
 	for i := 0; i < 50; i++ {
@@ -416,6 +792,73 @@ func BenchmarkPathExists(b *testing.B) {
 	}
 }
 
+// syntheticExistencePaths returns n paths, every other one existing
+func syntheticExistencePaths(tb testing.TB, n int) []string {
+	dir := tb.TempDir()
+	paths := make([]string, n)
+	for i := range paths {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("entry%02d", i))
+		if i%2 == 0 {
+			if err := os.Mkdir(paths[i], 0755); err != nil {
+				tb.Fatal(err)
+			}
+		}
+	}
+	return paths
+}
+
+func BenchmarkPathExists_Sequential(b *testing.B) {
+	paths := syntheticExistencePaths(b, 50)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, p := range paths {
+			PathExists(p)
+		}
+	}
+}
+
+func BenchmarkPathExists_Concurrent(b *testing.B) {
+	paths := syntheticExistencePaths(b, 50)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CheckPathsExist(paths, existenceWorkers)
+	}
+}
+
+func TestCheckPathsExist_MatchesSequential(t *testing.T) {
+	paths := syntheticExistencePaths(t, 50)
+	paths = append(paths, paths[0], `%MISSING%\bin`)
+
+	results := CheckPathsExist(paths, existenceWorkers)
+	if len(results) != 51 {
+		t.Errorf("Expected one result per distinct path, got %d", len(results))
+	}
+	for _, p := range paths {
+		if results[p] != PathExists(p) {
+			t.Errorf("%s: concurrent=%v sequential=%v", p, results[p], PathExists(p))
+		}
+	}
+}
+
+func TestOptimize_ConcurrentExistenceChecksMatchSequential(t *testing.T) {
+	input := strings.Join(syntheticExistencePaths(t, 50), ";")
+
+	concurrent := DefaultOptions()
+	concurrent.ShortenPaths = false
+	concurrent.SubstituteVars = false
+	sequential := concurrent
+	sequential.ConcurrentExistenceChecks = false
+
+	got := Optimize(input, concurrent)
+	want := Optimize(input, sequential)
+	if got.Optimized.Raw != want.Optimized.Raw {
+		t.Errorf("Optimized PATH differs:\nconcurrent: %s\nsequential: %s", got.Optimized.Raw, want.Optimized.Raw)
+	}
+	if got.Metrics.DeadPathsRemoved != 25 || want.Metrics.DeadPathsRemoved != 25 {
+		t.Errorf("Expected 25 dead paths both ways, got %d and %d", got.Metrics.DeadPathsRemoved, want.Metrics.DeadPathsRemoved)
+	}
+}
+
 func BenchmarkApplyHotPaths(b *testing.B) {
 	entries := make([]string, 50)
 	for i := 0; i < 50; i++ {
@@ -585,3 +1028,156 @@ func TestNewEntryProcessor(t *testing.T) {
 		t.Error("seen map should be initialized")
 	}
 }
+
+func TestOptimize_CanonicalizePaths(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir()) // no hot paths from other tests
+
+	opts := OptimizeOptions{RemoveDuplicates: true, CanonicalizePaths: true}
+	withMockRunner(t, func(m *MockShellRunner) {
+		m.Responses = map[string]string{
+			"foreach ($p in $paths)": `C:\Program Files\git\CMD`,
+			"GetFileSystemInfos":     `C:\Program Files\Git\cmd|C:\Windows`,
+		}
+	}, func() {
+		result := Optimize(`c:\PROGRA~1\git\CMD;C:\Windows`, opts)
+
+		want := `C:\Program Files\Git\cmd;C:\Windows`
+		if result.Optimized.Raw != want {
+			t.Errorf("Optimized = %q, want %q", result.Optimized.Raw, want)
+		}
+		if len(result.Changes) != 1 || result.Changes[0].Type != "canonical" {
+			t.Errorf("Expected a single canonical change, got %+v", result.Changes)
+		} else if result.Changes[0].Original != `c:\PROGRA~1\git\CMD` {
+			t.Errorf("Unexpected change original %q", result.Changes[0].Original)
+		}
+	})
+}
+
+func TestOptimize_CanonicalizePathsDisabled(t *testing.T) {
+	opts := OptimizeOptions{RemoveDuplicates: true}
+	withMockRunner(t, func(m *MockShellRunner) {
+		m.Responses = map[string]string{
+			"foreach ($p in $paths)": `C:\Program Files\git\CMD`,
+			"GetFileSystemInfos":     `C:\Program Files\Git\cmd`,
+		}
+	}, func() {
+		result := Optimize(`c:\PROGRA~1\git\CMD`, opts)
+		if result.Optimized.Raw != `c:\PROGRA~1\git\CMD` {
+			t.Errorf("Entry should be untouched when the option is off, got %q", result.Optimized.Raw)
+		}
+	})
+}
+
+func TestAnalyzeAll_ShellCallsPerRun(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+	withMockRunner(t, func(m *MockShellRunner) {
+		m.SetResponse("LocalMachine.OpenSubKey", `C:\Windows`)
+		m.SetResponse("CurrentUser.OpenSubKey", `C:\Tools;C:\Tools`)
+	}, func() {
+		mock := getMockRunner(t)
+		before := len(mock.Calls)
+		_, _ = AnalyzeAll(DefaultOptions())
+
+		// One read per PATH scope and one value-kind query for both; no junction
+		// listing when no entry goes through the junction folder
+		calls := mock.Calls[before:]
+		if len(calls) != 3 {
+			t.Errorf("Expected 3 shell calls for a full analysis, got %d: %v", len(calls), calls)
+		}
+		for _, pattern := range []string{"LocalMachine.OpenSubKey", "CurrentUser.OpenSubKey", "GetValueKind"} {
+			if n := countMockCalls(calls, pattern); n != 1 {
+				t.Errorf("Expected %s once, got %d", pattern, n)
+			}
+		}
+
+		SetJunctionFolder(`C:\l`)
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\l\go\bin`)
+		before = len(mock.Calls)
+		_, _ = AnalyzeAll(DefaultOptions())
+		if n := countMockCalls(mock.Calls[before:], "ReparsePoint"); n != 1 {
+			t.Errorf("Expected junctions to be listed when an entry uses the junction folder, got %d", n)
+		}
+	})
+}
+
+func TestAnalyzeAll_ReadErrorIsReturned(t *testing.T) {
+	withMockRunner(t, func(m *MockShellRunner) {
+		m.SetError("LocalMachine.OpenSubKey", fmt.Errorf("operation timed out"))
+		m.SetResponse("CurrentUser.OpenSubKey", `C:\Tools`)
+	}, func() {
+		mock := getMockRunner(t)
+		before := len(mock.Calls)
+		result, err := AnalyzeAll(DefaultOptions())
+		if err == nil || !strings.Contains(err.Error(), "System PATH") {
+			t.Fatalf("Expected the System read error, got %v", err)
+		}
+		if result.User.Original.Raw != "" || result.System.Original.Raw != "" {
+			t.Error("Expected no analysis when a PATH can't be read")
+		}
+		if n := countMockCalls(mock.Calls[before:], "CurrentUser.OpenSubKey"); n != 0 {
+			t.Errorf("Expected the User read to be skipped after the System read failed, got %d", n)
+		}
+	})
+}
+
+func TestAnalyzeAll_DetectsPathStoredAsString(t *testing.T) {
+	withMockRunner(t, func(m *MockShellRunner) {
+		m.Responses = map[string]string{
+			"LocalMachine.OpenSubKey": `C:\Windows`,
+			"CurrentUser.OpenSubKey":  `%USERPROFILE%\bin`,
+			"GetValueKind":            "ExpandString|String",
+		}
+	}, func() {
+		result, err := AnalyzeAll(DefaultOptions())
+		if err != nil {
+			t.Fatalf("AnalyzeAll failed: %v", err)
+		}
+		if !result.User.StoredAsString {
+			t.Error("Expected User PATH to be flagged as REG_SZ")
+		}
+		if result.System.StoredAsString {
+			t.Error("System PATH is REG_EXPAND_SZ and should not be flagged")
+		}
+	})
+}
+
+func TestRejectShortenings_KeepsRejectedLongNames(t *testing.T) {
+	long1, short1 := `C:\Program Files\Common Tools\bin`, `C:\PROGRA~1\COMMON~1\bin`
+	long2, short2 := `C:\Program Files (x86)\Legacy App`, `C:\PROGRA~2\LEGACY~1`
+	original := JoinPath([]string{`C:\Windows`, long1, long2})
+	result := OptimizeResult{
+		Original:  PathInfo{Raw: original, Entries: ParsePath(original), Length: len(original), Count: 3},
+		Optimized: PathInfo{Raw: JoinPath([]string{short2, `C:\Windows`, short1}), Entries: []string{short2, `C:\Windows`, short1}},
+		Changes: []PathChange{
+			{Type: "shortened", Original: long1, New: short1, Saved: len(long1) - len(short1)},
+			{Type: "shortened", Original: long2, New: short2, Saved: len(long2) - len(short2)},
+			{Type: "reordered", Original: short2},
+		},
+		Metrics:             OptimizeMetrics{PathsShortened: 2, TotalSaved: len(long1) - len(short1) + len(long2) - len(short2)},
+		ProtectedViolations: []ProtectedViolation{{Entry: long2, Action: "modified", New: short2}},
+	}
+
+	got := RejectShortenings(result, map[string]bool{long2: true})
+
+	want := JoinPath([]string{long2, `C:\Windows`, short1})
+	if got.Optimized.Raw != want || got.Optimized.Length != len(want) {
+		t.Errorf("Optimized = %q (%d), want %q", got.Optimized.Raw, got.Optimized.Length, want)
+	}
+	if got.Metrics.PathsShortened != 1 || got.Metrics.TotalSaved != len(long1)-len(short1) {
+		t.Errorf("Expected only the accepted shortening counted, got %+v", got.Metrics)
+	}
+	if len(got.Changes) != 2 || got.Changes[0].Original != long1 || got.Changes[1].Original != long2 {
+		t.Errorf("Expected the rejected change dropped and the reorder renamed, got %+v", got.Changes)
+	}
+	if len(got.ProtectedViolations) != 0 {
+		t.Errorf("Expected the kept long name to no longer violate protection, got %+v", got.ProtectedViolations)
+	}
+	if result.Optimized.Entries[0] != short2 {
+		t.Error("Expected the input result left unchanged")
+	}
+	if same := RejectShortenings(result, nil); same.Optimized.Raw != result.Optimized.Raw {
+		t.Errorf("Expected nothing rejected to keep the result, got %q", same.Optimized.Raw)
+	}
+}
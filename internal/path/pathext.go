@@ -212,6 +212,27 @@ func checkRemovableExtensions(analysis *PathExtAnalysis, current []string) {
 	}
 }
 
+// checkDuplicateExtensions checks for extensions listed more than once
+func checkDuplicateExtensions(analysis *PathExtAnalysis, current []string) {
+	var duplicates []string
+	seen := make(map[string]bool)
+	for _, ext := range current {
+		if seen[ext] && !contains(duplicates, ext) {
+			duplicates = append(duplicates, ext)
+		}
+		seen[ext] = true
+	}
+	if len(duplicates) > 0 {
+		analysis.Issues = append(analysis.Issues, PathExtIssue{
+			Type:    "bloat",
+			Message: "Duplicate extensions: " + strings.Join(duplicates, ", "),
+			Impact:  "medium",
+		})
+		analysis.Recommendations = append(analysis.Recommendations, "Remove repeated: "+strings.Join(duplicates, ", "))
+		analysis.IsOptimal = false
+	}
+}
+
 // checkComBeforeExe checks if .COM is checked before .EXE
 func checkComBeforeExe(analysis *PathExtAnalysis, current []string) {
 	comIndex := indexOf(current, ".COM")
@@ -246,6 +267,7 @@ func AnalyzePathExt() PathExtAnalysis {
 	checkPythonPosition(&analysis, current)
 	checkRemovableExtensions(&analysis, current)
 	checkComBeforeExe(&analysis, current)
+	checkDuplicateExtensions(&analysis, current)
 
 	return analysis
 }
@@ -269,11 +291,12 @@ func OptimizePathExt(keepAll bool) PathExtOptimization {
 		}
 	}
 
-	// Add any remaining extensions not in optimal order
+	// Add any remaining extensions not in optimal order, dropping later duplicates
 	for _, ext := range current {
 		if !added[ext] {
 			if keepAll || !contains(RemovableExtensions, ext) {
 				optimized = append(optimized, ext)
+				added[ext] = true
 			}
 		}
 	}
@@ -288,6 +311,33 @@ func OptimizePathExt(keepAll bool) PathExtOptimization {
 	}
 }
 
+// ExeFirstPathExt moves .EXE to the front of PATHEXT and leaves every other
+// extension where it is; this is the fix auto-fix applies
+func ExeFirstPathExt() PathExtOptimization {
+	current := ParsePathExt("")
+	original := strings.Join(current, ";")
+
+	optimized := make([]string, 0, len(current))
+	for _, ext := range current {
+		if ext == ".EXE" {
+			optimized = append(optimized, ext)
+		}
+	}
+	for _, ext := range current {
+		if ext != ".EXE" {
+			optimized = append(optimized, ext)
+		}
+	}
+
+	optimizedStr := strings.Join(optimized, ";")
+	return PathExtOptimization{
+		Original:        original,
+		Optimized:       optimized,
+		OptimizedString: optimizedStr,
+		Changed:         original != optimizedStr,
+	}
+}
+
 // ApplyPathExt applies optimized PATHEXT
 func ApplyPathExt(value, scope string) error {
 	target := "User"
@@ -295,9 +345,10 @@ func ApplyPathExt(value, scope string) error {
 		target = "Machine"
 	}
 
-	command := `[Environment]::SetEnvironmentVariable('PATHEXT', '` + value + `', '` + target + `')`
+	command := `[Environment]::SetEnvironmentVariable('PATHEXT', '` + escapePSString(value) + `', '` + target + `')`
 	_, err := RunPowerShell(command)
 	if err == nil {
+		LogAudit(AuditApplyPathExt, scope+" PATHEXT = "+value)
 		BroadcastEnvChange()
 	}
 	return err
@@ -261,6 +261,53 @@ func TestOptimizePathExt(t *testing.T) {
 	}
 }
 
+func TestExeFirstPathExt(t *testing.T) {
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.Responses = map[string]string{"'PATHEXT'": ".COM;.BAT;.EXE;.WSF"}
+	}, func() {
+		result := ExeFirstPathExt()
+		if result.OptimizedString != ".EXE;.COM;.BAT;.WSF" {
+			t.Errorf("Expected only .EXE to move, got %s", result.OptimizedString)
+		}
+		if !result.Changed {
+			t.Error("Expected the result to be marked changed")
+		}
+	})
+
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.Responses = map[string]string{"'PATHEXT'": ".EXE;.COM"}
+	}, func() {
+		if ExeFirstPathExt().Changed {
+			t.Error("Expected no change when .EXE is already first")
+		}
+	})
+}
+
+func TestPathExt_DuplicateExtensions(t *testing.T) {
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.Responses = map[string]string{"'PATHEXT'": ".EXE;.CMD;.EXE;.FOO;.foo"}
+	}, func() {
+		analysis := AnalyzePathExt()
+		found := false
+		for _, issue := range analysis.Issues {
+			if issue.Type == "bloat" && issue.Impact == "medium" && strings.Contains(issue.Message, ".EXE") && strings.Contains(issue.Message, ".FOO") {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected a duplicate extensions issue, got %+v", analysis.Issues)
+		}
+		if analysis.IsOptimal {
+			t.Error("PATHEXT with duplicates should not be optimal")
+		}
+
+		result := OptimizePathExt(true)
+		if result.OptimizedString != ".EXE;.CMD;.FOO" {
+			t.Errorf("Expected later duplicates removed, got %s", result.OptimizedString)
+		}
+	})
+}
+
 func TestOptimizePathExt_RemoveRarely(t *testing.T) {
 	result := OptimizePathExt(false)
 
@@ -379,6 +426,28 @@ func TestApplyPathExt_System(t *testing.T) {
 	}
 }
 
+func TestApplyPathExt_EscapesApostrophe(t *testing.T) {
+	mock := getMockRunner(t)
+	beforeCount := len(mock.Calls)
+
+	if err := ApplyPathExt(".EXE;.O'K", "User"); err != nil {
+		t.Fatalf("ApplyPathExt error: %v", err)
+	}
+
+	found := false
+	for _, call := range mock.Calls[beforeCount:] {
+		if strings.Contains(call, "'PATHEXT'") && strings.Contains(call, "SetEnvironmentVariable") {
+			found = true
+			if !strings.Contains(call, `'.EXE;.O''K'`) {
+				t.Errorf("Expected apostrophe to be doubled in command, got: %s", call)
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected SetEnvironmentVariable call for PATHEXT")
+	}
+}
+
 func TestAnalyzePathExt_Issues(t *testing.T) {
 	analysis := AnalyzePathExt()
 
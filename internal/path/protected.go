@@ -0,0 +1,53 @@
+package path
+
+// ProtectedViolation is a protected PATH entry that an optimization would remove or rewrite
+// Applying such a result needs an explicit override for every violation
+type ProtectedViolation struct {
+	Entry  string `json:"entry"`         // Protected entry as it appears in the PATH
+	Action string `json:"action"`        // "removed" or "modified"
+	New    string `json:"new,omitempty"` // Replacement when modified
+}
+
+// FindProtectedViolations reports protected entries present in the original PATH that
+// do not survive unchanged in the optimized one
+// A protected duplicate is fine as long as one copy is kept as-is
+func FindProtectedViolations(result OptimizeResult, protected []string) []ProtectedViolation {
+	if len(protected) == 0 {
+		return nil
+	}
+	kept := make(map[string]bool, len(result.Optimized.Entries))
+	for _, e := range result.Optimized.Entries {
+		kept[NormalizePath(e)] = true
+	}
+
+	var violations []ProtectedViolation
+	checked := make(map[string]bool, len(protected))
+	for _, p := range protected {
+		key := NormalizePath(p)
+		if p == "" || checked[key] || kept[key] {
+			continue
+		}
+		checked[key] = true
+
+		entry := ""
+		for _, e := range result.Original.Entries {
+			if NormalizePath(e) == key {
+				entry = e
+				break
+			}
+		}
+		if entry == "" {
+			continue // Not in this scope
+		}
+
+		v := ProtectedViolation{Entry: entry, Action: "removed"}
+		for _, c := range result.Changes {
+			if c.New != "" && NormalizePath(c.Original) == key {
+				v.Action, v.New = "modified", c.New
+				break
+			}
+		}
+		violations = append(violations, v)
+	}
+	return violations
+}
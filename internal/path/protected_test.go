@@ -0,0 +1,56 @@
+package path
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindProtectedViolations(t *testing.T) {
+	result := OptimizeResult{
+		Original:  PathInfo{Entries: []string{`C:\Infra\bin`, `C:\Tools`, `C:\Program Files\Agent`, `C:\Tools`, `C:\Dup`, `C:\Dup`}},
+		Optimized: PathInfo{Entries: []string{`C:\Tools`, `C:\PROGRA~1\Agent`, `C:\Dup`}},
+		Changes: []PathChange{
+			{Type: "dead", Original: `C:\Infra\bin`},
+			{Type: "shortened", Original: `C:\Program Files\Agent`, New: `C:\PROGRA~1\Agent`},
+			{Type: "duplicate", Original: `C:\Tools`},
+			{Type: "duplicate", Original: `C:\Dup`},
+		},
+	}
+	protected := []string{`c:\infra\BIN\`, `C:\Program Files\Agent`, `C:\Dup`, `C:\Elsewhere`, `C:\Infra\bin`}
+
+	got := FindProtectedViolations(result, protected)
+	want := []ProtectedViolation{
+		{Entry: `C:\Infra\bin`, Action: "removed"},
+		{Entry: `C:\Program Files\Agent`, Action: "modified", New: `C:\PROGRA~1\Agent`},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindProtectedViolations = %+v, want %+v", got, want)
+	}
+
+	if v := FindProtectedViolations(result, nil); v != nil {
+		t.Errorf("Expected no violations without protected entries, got %+v", v)
+	}
+}
+
+func TestOptimize_ProtectedEntryRemoved(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+
+	config := LoadConfig()
+	config.ProtectedEntries = []string{`C:\Missing\Infra`}
+	if err := SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := DefaultOptions()
+	opts.ShortenPaths = false
+	opts.SubstituteVars = false
+	result := Optimize(`%SystemRoot%;C:\Missing\Infra`, opts)
+
+	if len(result.ProtectedViolations) != 1 {
+		t.Fatalf("Expected the dead protected entry to be reported, got %+v", result.ProtectedViolations)
+	}
+	if v := result.ProtectedViolations[0]; v.Entry != `C:\Missing\Infra` || v.Action != "removed" {
+		t.Errorf("Unexpected violation %+v", v)
+	}
+}
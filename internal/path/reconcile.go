@@ -0,0 +1,117 @@
+package path
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lastWrite is the PATH value WinPath last wrote to a scope
+type lastWrite struct {
+	Raw       string    `json:"raw"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// getLastWritesPath returns the file recording the last PATH written to each scope
+func getLastWritesPath() string {
+	return filepath.Join(getConfigDir(), "lastwrite.json")
+}
+
+// loadLastWrites returns the last PATH written to each scope, keyed by scope
+func loadLastWrites() map[string]lastWrite {
+	writes := make(map[string]lastWrite)
+	data, err := os.ReadFile(getLastWritesPath())
+	if err != nil {
+		return writes
+	}
+	_ = json.Unmarshal(data, &writes) // A damaged file just means no writes are known
+	return writes
+}
+
+// recordWrite remembers value as the PATH WinPath last wrote to scope
+// Like the audit log it is best effort and never fails the write itself
+func recordWrite(scope, value string) {
+	writes := loadLastWrites()
+	writes[scope] = lastWrite{Raw: value, Timestamp: time.Now()}
+	data, err := json.MarshalIndent(writes, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(getConfigDir(), 0755); err != nil {
+		return
+	}
+	_ = os.WriteFile(getLastWritesPath(), data, 0644)
+}
+
+// HasChangeBaseline reports whether there is a backup or a recorded write to compare
+// the live PATH against, without reading the registry
+func HasChangeBaseline() bool {
+	if len(ListBackups()) > 0 {
+		return true
+	}
+	_, err := os.Stat(getLastWritesPath())
+	return err == nil
+}
+
+// ExternalChanges describes how the live PATH differs from the last state WinPath
+// knows about: the newest backup, or what WinPath wrote after it
+type ExternalChanges struct {
+	Backup string   // Newest backup filename, empty when there are no backups
+	System PathDiff // Entries added to or removed from the System PATH since
+	User   PathDiff // Entries added to or removed from the User PATH since
+}
+
+// Count returns how many entries differ across both scopes
+func (c ExternalChanges) Count() int {
+	return len(c.System.Added) + len(c.System.Removed) + len(c.User.Added) + len(c.User.Removed)
+}
+
+// CheckExternalChanges compares the live PATH against the newest backup, or against
+// the value WinPath wrote itself if that is more recent, so an apply right after its
+// pre-change backup isn't mistaken for an outside change
+// It returns nil when there is nothing to compare against yet
+func CheckExternalChanges() (*ExternalChanges, error) {
+	var backup *Backup
+	changes := &ExternalChanges{}
+	if backups := ListBackups(); len(backups) > 0 {
+		b, err := LoadBackup(backups[0].Filename)
+		if err != nil {
+			return nil, err
+		}
+		backup = b
+		changes.Backup = backups[0].Filename
+	}
+	writes := loadLastWrites()
+	if backup == nil && len(writes) == 0 {
+		return nil, nil
+	}
+
+	for _, scope := range []string{"System", "User"} {
+		baseline, ok := "", false
+		if backup != nil {
+			baseline, ok = backup.UserPath.Raw, true
+			if scope == "System" {
+				baseline = backup.SystemPath.Raw
+			}
+		}
+		if w, found := writes[scope]; found && (backup == nil || !w.Timestamp.Before(backup.Timestamp)) {
+			baseline, ok = w.Raw, true
+		}
+		if !ok {
+			continue
+		}
+
+		current, err := GetPathRaw(scope)
+		if err != nil {
+			return nil, err
+		}
+		diff := DiffPaths(ParsePath(baseline), ParsePath(current))
+		if scope == "System" {
+			changes.System = diff
+		} else {
+			changes.User = diff
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,77 @@
+package path
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckExternalChanges_NothingToCompare(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+
+	changes, err := CheckExternalChanges()
+	if err != nil || changes != nil {
+		t.Errorf("Expected nothing to compare without backups, got %+v, %v", changes, err)
+	}
+}
+
+func TestCheckExternalChanges_AgainstNewestBackup(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetResponse("LocalMachine.OpenSubKey", `C:\Windows`)
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\Tools;C:\Go\bin`)
+	}, func() {
+		info, err := CreateBackup("manual")
+		if err != nil {
+			t.Fatalf("CreateBackup error: %v", err)
+		}
+
+		changes, err := CheckExternalChanges()
+		if err != nil || changes == nil || changes.Count() != 0 || changes.Backup != info.Filename {
+			t.Fatalf("Expected no differences right after a backup, got %+v, %v", changes, err)
+		}
+
+		// An installer adds an entry and drops another
+		mock := getMockRunner(t)
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\Tools;C:\Installer\bin`)
+		changes, err = CheckExternalChanges()
+		if err != nil {
+			t.Fatalf("CheckExternalChanges error: %v", err)
+		}
+		if changes.Count() != 2 {
+			t.Errorf("Expected 2 differing entries, got %d", changes.Count())
+		}
+		if !reflect.DeepEqual(changes.User.Added, []string{`C:\Installer\bin`}) || !reflect.DeepEqual(changes.User.Removed, []string{`C:\Go\bin`}) {
+			t.Errorf("Unexpected User diff: %+v", changes.User)
+		}
+		if !changes.System.Empty() {
+			t.Errorf("Expected System unchanged, got %+v", changes.System)
+		}
+	})
+}
+
+func TestCheckExternalChanges_OwnWriteIsBaseline(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetResponse("LocalMachine.OpenSubKey", `C:\Windows`)
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\Tools;C:\Tools;C:\Dead`)
+	}, func() {
+		if _, err := CreateBackup("pre-optimize"); err != nil {
+			t.Fatalf("CreateBackup error: %v", err)
+		}
+		if err := SetPath(`C:\Tools`, "User"); err != nil {
+			t.Fatalf("SetPath error: %v", err)
+		}
+		// The registry now holds what was written
+		getMockRunner(t).SetResponse("CurrentUser.OpenSubKey", `C:\Tools`)
+
+		changes, err := CheckExternalChanges()
+		if err != nil || changes == nil || changes.Count() != 0 {
+			t.Errorf("WinPath's own apply should not count as an external change, got %+v, %v", changes, err)
+		}
+	})
+}
@@ -1,11 +1,11 @@
 package path
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
-
-	"github.com/atotto/clipboard"
 )
 
 const (
@@ -14,9 +14,21 @@ const (
 )
 
 // RunPowerShell executes a PowerShell command and returns the output
-// Uses DefaultRunner which can be mocked for testing
+// Uses DefaultRunner which can be mocked for testing, and is cancelled with the current operation
 func RunPowerShell(command string) (string, error) {
-	return DefaultRunner.Run(command)
+	return RunPowerShellContext(operationContext(), command)
+}
+
+// RunPowerShellContext executes a PowerShell command that is killed once ctx is done
+func RunPowerShellContext(ctx context.Context, command string) (string, error) {
+	output, err := DefaultRunner.RunContext(ctx, command)
+	return output, shellError(err)
+}
+
+// escapePSString escapes a value for use inside a single-quoted PowerShell string
+// Single quotes are doubled so values like C:\Tom's Tools survive intact
+func escapePSString(value string) string {
+	return strings.ReplaceAll(value, "'", "''")
 }
 
 // GetPathRaw gets the raw PATH value from registry without expanding variables
@@ -36,6 +48,20 @@ func GetPathRaw(scope string) (string, error) {
 	return RunPowerShell(command)
 }
 
+// GetPathsRaw reads the raw System and User PATH, failing if either can't be read
+// so callers never mistake an unreadable PATH for an empty one
+func GetPathsRaw() (system, user string, err error) {
+	system, err = GetPathRaw("System")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read System PATH: %w", err)
+	}
+	user, err = GetPathRaw("User")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read User PATH: %w", err)
+	}
+	return system, user, nil
+}
+
 // GetPathExpanded gets the expanded PATH value with variables resolved
 func GetPathExpanded(scope string) (string, error) {
 	var command string
@@ -76,7 +102,7 @@ func expandShortNamesBatch(paths []string) []string {
 	var sb strings.Builder
 	sb.WriteString("$paths = @(\n")
 	for i, idx := range needsExpansion {
-		escaped := strings.ReplaceAll(paths[idx], "'", "''")
+		escaped := escapePSString(paths[idx])
 		if i > 0 {
 			sb.WriteString(",\n")
 		}
@@ -117,6 +143,73 @@ $results -join '|'
 	return output
 }
 
+// CanonicalPaths returns the on-disk form of each entry: 8.3 names expanded and every
+// component in its real casing. Entries with variables or that do not exist are unchanged
+func CanonicalPaths(entries []string) []string {
+	return canonicalCaseBatch(expandShortNamesBatch(entries))
+}
+
+// canonicalCaseBatch looks up the real casing of every existing path in one PowerShell call
+func canonicalCaseBatch(paths []string) []string {
+	lookup := make([]int, 0, len(paths))
+	for i, p := range paths {
+		if p != "" && !strings.Contains(p, "%") {
+			lookup = append(lookup, i)
+		}
+	}
+	if len(lookup) == 0 {
+		return paths
+	}
+
+	var sb strings.Builder
+	sb.WriteString("$entries = @(\n")
+	for i, idx := range lookup {
+		if i > 0 {
+			sb.WriteString(",\n")
+		}
+		sb.WriteString(fmt.Sprintf("    '%s'", escapePSString(paths[idx])))
+	}
+	sb.WriteString("\n)\n")
+	// Looking a name up in its parent returns the stored casing, and the long name for 8.3 input
+	sb.WriteString(`
+$canonical = foreach ($entry in $entries) {
+    $out = $entry
+    try {
+        $item = Get-Item -LiteralPath $entry -Force -ErrorAction Stop
+        $parts = @()
+        while ($item.Parent) {
+            $match = @($item.Parent.GetFileSystemInfos($item.Name))
+            if ($match.Count -gt 0) { $parts = @($match[0].Name) + $parts } else { $parts = @($item.Name) + $parts }
+            $item = $item.Parent
+        }
+        $root = $item.Name.TrimEnd('\')
+        if ($root -match '^[a-zA-Z]:$') { $root = $root.ToUpper() }
+        $out = $root + '\' + ($parts -join '\')
+    } catch {}
+    $out
+}
+$canonical -join '|'
+`)
+
+	result, err := RunPowerShell(sb.String())
+	if err != nil || result == "" {
+		return paths
+	}
+	canonical := strings.Split(strings.TrimSpace(result), "|")
+	if len(canonical) != len(lookup) {
+		return paths // Unexpected output; keep entries as they are
+	}
+
+	output := make([]string, len(paths))
+	copy(output, paths)
+	for i, idx := range lookup {
+		if canonical[i] != "" {
+			output[idx] = canonical[i]
+		}
+	}
+	return output
+}
+
 // expandShortName expands a single 8.3 short name (used by other code if needed)
 func expandShortName(p string) string {
 	// Skip paths with environment variables
@@ -140,10 +233,41 @@ func SetPath(value, scope string) error {
 	} else {
 		target = "User"
 	}
-	escaped := strings.ReplaceAll(value, "'", "''")
-	command := fmt.Sprintf(`[Environment]::SetEnvironmentVariable('Path', '%s', '%s')`, escaped, target)
-	_, err := RunPowerShell(command)
-	return err
+	// SetEnvironmentVariable can leave PATH as REG_SZ, where %VARS% never expand,
+	// so the value is rewritten as REG_EXPAND_SZ whenever it comes back as a plain string
+	command := fmt.Sprintf(`[Environment]::SetEnvironmentVariable('Path', '%s', '%s')
+		$item = Get-Item -LiteralPath '%s'
+		if ($item.GetValueKind('Path') -eq 'String') {
+			$value = $item.GetValue('Path', '', [Microsoft.Win32.RegistryValueOptions]::DoNotExpandEnvironmentNames)
+			New-ItemProperty -LiteralPath '%s' -Name 'Path' -Value $value -PropertyType ExpandString -Force | Out-Null
+		}`, escapePSString(value), target, pathKey(scope), pathKey(scope))
+	if _, err := RunPowerShell(command); err != nil {
+		return err
+	}
+	LogAudit(AuditSetPath, fmt.Sprintf("%s PATH, %d entries, %d chars", scope, len(ParsePath(value)), len(value)))
+	recordWrite(scope, value)
+	return nil
+}
+
+// pathKey returns the registry key holding PATH for a scope
+func pathKey(scope string) string {
+	if scope == "System" {
+		return SystemPathKey
+	}
+	return UserPathKey
+}
+
+// GetPathValueKind returns the registry value type of PATH ("ExpandString", "String", ...)
+// An empty result means PATH is not set in that scope
+func GetPathValueKind(scope string) (string, error) {
+	command := fmt.Sprintf(`(Get-Item -LiteralPath '%s').GetValueKind('Path')`, pathKey(scope))
+	return RunPowerShell(command)
+}
+
+// IsPathStoredAsString reports whether PATH is a REG_SZ value, where %VARS% do not expand
+func IsPathStoredAsString(scope string) bool {
+	kind, err := GetPathValueKind(scope)
+	return err == nil && strings.TrimSpace(kind) == "String"
 }
 
 // IsAdmin checks if running with administrator privileges
@@ -156,6 +280,38 @@ func IsAdmin() bool {
 	return strings.ToLower(result) == "true"
 }
 
+// ErrElevationCancelled is returned by RelaunchAsAdmin when the UAC prompt is declined
+var ErrElevationCancelled = errors.New("elevation cancelled")
+
+// relaunchCommand builds the PowerShell command that starts exe elevated
+// A declined UAC prompt (ERROR_CANCELLED, 1223) prints "cancelled" instead of failing
+func relaunchCommand(exe string) string {
+	return fmt.Sprintf(`try {
+			Start-Process -FilePath '%s' -Verb RunAs -ErrorAction Stop
+		} catch {
+			$e = $_.Exception
+			while ($e.InnerException) { $e = $e.InnerException }
+			if ($e.NativeErrorCode -eq 1223) { 'cancelled' } else { throw }
+		}`, escapePSString(exe))
+}
+
+// RelaunchAsAdmin starts another copy of the running executable with administrator rights
+// It returns once the elevated copy has started; the caller is expected to exit
+func RelaunchAsAdmin() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot locate WinPath executable: %w", err)
+	}
+	result, err := RunPowerShell(relaunchCommand(exe))
+	if err != nil {
+		return fmt.Errorf("relaunch failed: %w", err)
+	}
+	if strings.TrimSpace(result) == "cancelled" {
+		return ErrElevationCancelled
+	}
+	return nil
+}
+
 // BroadcastEnvChange notifies Windows of environment variable changes
 func BroadcastEnvChange() {
 	command := `
@@ -187,6 +343,25 @@ func GetHostname() string {
 	return result
 }
 
+// PathStoredAsString reports for both scopes at once whether PATH is a REG_SZ value,
+// so an analysis needs one shell call for this instead of one per scope
+func PathStoredAsString() (system, user bool) {
+	command := fmt.Sprintf(`
+		$kinds = foreach ($key in '%s', '%s') {
+			try { (Get-Item -LiteralPath $key).GetValueKind('Path') } catch { '' }
+		}
+		$kinds -join '|'`, SystemPathKey, UserPathKey)
+	out, err := RunPowerShell(command)
+	if err != nil {
+		return false, false
+	}
+	kinds := strings.SplitN(strings.TrimSpace(out), "|", 2)
+	if len(kinds) != 2 {
+		return false, false
+	}
+	return strings.TrimSpace(kinds[0]) == "String", strings.TrimSpace(kinds[1]) == "String"
+}
+
 // GetAllEnvVars returns all environment variables
 func GetAllEnvVars() map[string]string {
 	vars := make(map[string]string)
@@ -202,11 +377,6 @@ func GetAllEnvVars() map[string]string {
 	return vars
 }
 
-// CopyToClipboard copies text to the Windows clipboard
-func CopyToClipboard(text string) error {
-	return clipboard.WriteAll(text)
-}
-
 // GetRefreshCommand returns the PowerShell command to refresh PATH
 func GetRefreshCommand() string {
 	return `$env:Path = [Environment]::GetEnvironmentVariable('Path','Machine') + ';' + [Environment]::GetEnvironmentVariable('Path','User')`
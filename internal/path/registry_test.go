@@ -122,6 +122,43 @@ func TestSetPath_WithQuotes(t *testing.T) {
 	}
 }
 
+func TestSetPath_EscapesApostrophe(t *testing.T) {
+	mock := getMockRunner(t)
+	beforeCalls := len(mock.Calls)
+
+	if err := SetPath(`C:\Tom's Tools;C:\Windows`, "User"); err != nil {
+		t.Fatalf("SetPath error: %v", err)
+	}
+	if len(mock.Calls) <= beforeCalls {
+		t.Fatal("Mock should have been called for SetPath")
+	}
+
+	call := mock.Calls[len(mock.Calls)-1]
+	if !strings.Contains(call, `'C:\Tom''s Tools;C:\Windows'`) {
+		t.Errorf("Expected apostrophe to be doubled in command, got: %s", call)
+	}
+	if strings.Contains(call, `Tom's`) {
+		t.Errorf("Unescaped apostrophe leaked into command: %s", call)
+	}
+}
+
+func TestEscapePSString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`C:\Windows`, `C:\Windows`},
+		{`C:\Tom's Tools`, `C:\Tom''s Tools`},
+		{`'`, `''`},
+		{``, ``},
+	}
+	for _, tt := range tests {
+		if got := escapePSString(tt.input); got != tt.expected {
+			t.Errorf("escapePSString(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
 func TestIsAdmin(t *testing.T) {
 	result := IsAdmin()
 	t.Logf("IsAdmin: %v", result)
@@ -160,6 +197,59 @@ func TestIsAdmin_Error(t *testing.T) {
 	})
 }
 
+func TestRelaunchCommand(t *testing.T) {
+	cmd := relaunchCommand(`C:\Tom's Tools\winpath.exe`)
+	if !strings.Contains(cmd, "-Verb RunAs") {
+		t.Error("Expected the command to request elevation with RunAs")
+	}
+	if !strings.Contains(cmd, `-FilePath 'C:\Tom''s Tools\winpath.exe'`) {
+		t.Errorf("Expected the escaped executable path, got:\n%s", cmd)
+	}
+	if !strings.Contains(cmd, "1223") {
+		t.Error("Expected a declined UAC prompt to be detected")
+	}
+}
+
+func TestRelaunchAsAdmin(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skip("os.Executable unavailable")
+	}
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetResponse("-Verb RunAs", "")
+	}, func() {
+		if err := RelaunchAsAdmin(); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		mock := DefaultRunner.(*MockShellRunner)
+		last := mock.Calls[len(mock.Calls)-1]
+		if !strings.Contains(last, escapePSString(exe)) {
+			t.Errorf("Expected the running executable in the command, got:\n%s", last)
+		}
+	})
+}
+
+func TestRelaunchAsAdmin_Cancelled(t *testing.T) {
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetResponse("-Verb RunAs", "cancelled")
+	}, func() {
+		if err := RelaunchAsAdmin(); !errors.Is(err, ErrElevationCancelled) {
+			t.Errorf("Expected ErrElevationCancelled, got %v", err)
+		}
+	})
+}
+
+func TestRelaunchAsAdmin_Error(t *testing.T) {
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetError("-Verb RunAs", errors.New("exit status 1"))
+	}, func() {
+		err := RelaunchAsAdmin()
+		if err == nil || errors.Is(err, ErrElevationCancelled) {
+			t.Errorf("Expected a relaunch failure, got %v", err)
+		}
+	})
+}
+
 func TestBroadcastEnvChange(t *testing.T) {
 	BroadcastEnvChange()
 }
@@ -224,16 +314,51 @@ func TestGetAllEnvVars_Values(t *testing.T) {
 }
 
 func TestCopyToClipboard(t *testing.T) {
-	err := CopyToClipboard("test content")
-	if err != nil {
-		t.Logf("CopyToClipboard error (may be expected): %v", err)
+	mock, cleanup := SetDefaultClipboard()
+	defer cleanup()
+
+	if err := CopyToClipboard("test content"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mock.Writes) != 1 || mock.Last() != "test content" {
+		t.Errorf("Expected 'test content' to be copied once, got %q", mock.Writes)
 	}
 }
 
 func TestCopyToClipboard_Empty(t *testing.T) {
-	err := CopyToClipboard("")
-	if err != nil {
-		t.Logf("CopyToClipboard empty error: %v", err)
+	mock, cleanup := SetDefaultClipboard()
+	defer cleanup()
+
+	if err := CopyToClipboard(""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mock.Writes) != 1 || mock.Last() != "" {
+		t.Errorf("Expected an empty write, got %q", mock.Writes)
+	}
+}
+
+func TestCopyToClipboard_Error(t *testing.T) {
+	mock, cleanup := SetDefaultClipboard()
+	defer cleanup()
+	mock.Err = errors.New("clipboard unavailable")
+
+	if err := CopyToClipboard("x"); err != mock.Err {
+		t.Errorf("Expected clipboard error, got %v", err)
+	}
+	if len(mock.Writes) != 0 {
+		t.Error("Failed write should not be recorded")
+	}
+}
+
+func TestSetDefaultClipboard_Restores(t *testing.T) {
+	original := DefaultClipboard
+	_, cleanup := SetDefaultClipboard()
+	if DefaultClipboard == original {
+		t.Error("Expected DefaultClipboard to be replaced")
+	}
+	cleanup()
+	if DefaultClipboard != original {
+		t.Error("Expected cleanup to restore the previous clipboard")
 	}
 }
 
@@ -402,3 +527,80 @@ func TestGetPathExpanded_Expands8dot3(t *testing.T) {
 	}
 	t.Logf("GetPathExpanded result length: %d chars", len(result))
 }
+
+// withCanonicalMock answers only the 8.3 expansion and casing lookups
+func withCanonicalMock(t *testing.T, expanded, cased string, test func()) {
+	withMockRunner(t, func(m *MockShellRunner) {
+		m.Responses = map[string]string{
+			"foreach ($p in $paths)": expanded,
+			"GetFileSystemInfos":     cased,
+		}
+	}, test)
+}
+
+func TestCanonicalPaths(t *testing.T) {
+	withCanonicalMock(t, `C:\Program Files\git\CMD`, `C:\Program Files\Git\cmd|C:\Windows`, func() {
+		got := CanonicalPaths([]string{`c:\PROGRA~1\git\CMD`, `c:\windows`, `%USERPROFILE%\bin`})
+		want := []string{`C:\Program Files\Git\cmd`, `C:\Windows`, `%USERPROFILE%\bin`}
+		if strings.Join(got, ";") != strings.Join(want, ";") {
+			t.Errorf("CanonicalPaths = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestCanonicalPaths_UnexpectedOutput(t *testing.T) {
+	withCanonicalMock(t, "", `C:\Windows`, func() {
+		in := []string{`c:\windows`, `c:\tools`}
+		got := CanonicalPaths(in)
+		if strings.Join(got, ";") != strings.Join(in, ";") {
+			t.Errorf("Expected entries unchanged on mismatched output, got %v", got)
+		}
+	})
+}
+
+func TestIsPathStoredAsString(t *testing.T) {
+	withMockRunner(t, func(m *MockShellRunner) {
+		m.Responses = map[string]string{
+			`Get-Item -LiteralPath 'HKCU:\Environment').GetValueKind`: "String",
+			`Get-Item -LiteralPath 'HKLM:`:                            "ExpandString",
+		}
+	}, func() {
+		if !IsPathStoredAsString("User") {
+			t.Error("Expected User PATH stored as REG_SZ to be detected")
+		}
+		if IsPathStoredAsString("System") {
+			t.Error("REG_EXPAND_SZ System PATH should not be flagged")
+		}
+	})
+
+	withMockRunner(t, func(m *MockShellRunner) {
+		m.Responses = map[string]string{}
+	}, func() {
+		if IsPathStoredAsString("User") {
+			t.Error("A missing PATH should not be flagged")
+		}
+	})
+}
+
+func TestSetPath_ConvertsStringToExpandString(t *testing.T) {
+	mock := getMockRunner(t)
+	before := len(mock.Calls)
+
+	if err := SetPath(`%USERPROFILE%\bin`, "System"); err != nil {
+		t.Fatalf("SetPath error: %v", err)
+	}
+	if len(mock.Calls) != before+1 {
+		t.Fatalf("Expected a single PowerShell call, got %d", len(mock.Calls)-before)
+	}
+	call := mock.Calls[before]
+	for _, want := range []string{
+		`SetEnvironmentVariable('Path', '%USERPROFILE%\bin', 'Machine')`,
+		`Get-Item -LiteralPath '` + SystemPathKey + `'`,
+		`GetValueKind('Path') -eq 'String'`,
+		`-PropertyType ExpandString`,
+	} {
+		if !strings.Contains(call, want) {
+			t.Errorf("Expected SetPath command to contain %q, got: %s", want, call)
+		}
+	}
+}
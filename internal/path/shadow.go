@@ -0,0 +1,107 @@
+package path
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ShadowInfo is a command provided by more than one PATH directory
+// Windows runs the copy in the earliest directory; the later copies are shadowed
+type ShadowInfo struct {
+	Tool     string   `json:"tool"`     // Command name without extension, lowercased, e.g. git
+	Winner   string   `json:"winner"`   // File that runs, e.g. C:\Program Files\Git\cmd\git.exe
+	Shadowed []string `json:"shadowed"` // Later files with the same command name, in PATH order
+}
+
+// FindShadowedTools lists commands found in more than one PATH directory
+// Only files with an extension from pathext count, and within one directory the
+// extension listed first in pathext is the one that runs. Directories that don't
+// exist and repeats of an earlier entry are skipped, so exact duplicates (reported
+// by the optimizer already) don't show up here
+func FindShadowedTools(entries []string, pathext []string) []ShadowInfo {
+	rank := make(map[string]int, len(pathext))
+	for i, ext := range pathext {
+		ext = strings.ToLower(ext)
+		if _, ok := rank[ext]; !ok {
+			rank[ext] = i
+		}
+	}
+
+	var order []string
+	found := make(map[string]*ShadowInfo)
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		dir := ExpandEnvVars(entry)
+		key := NormalizePath(dir)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		for tool, file := range dirCommands(dir, rank) {
+			info, ok := found[tool]
+			if !ok {
+				found[tool] = &ShadowInfo{Tool: tool, Winner: file}
+				order = append(order, tool)
+				continue
+			}
+			info.Shadowed = append(info.Shadowed, file)
+		}
+	}
+
+	var result []ShadowInfo
+	for _, tool := range order {
+		if len(found[tool].Shadowed) > 0 {
+			result = append(result, *found[tool])
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Tool < result[j].Tool })
+	return result
+}
+
+// dirCommands maps each command name in dir to the file a lookup would run,
+// picking the extension ranked first when several share a name
+func dirCommands(dir string, rank map[string]int) map[string]string {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	commands := make(map[string]string)
+	best := make(map[string]int)
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		name := f.Name()
+		ext := strings.ToLower(filepath.Ext(name))
+		r, ok := rank[ext]
+		if !ok {
+			continue
+		}
+		tool := strings.ToLower(strings.TrimSuffix(name, filepath.Ext(name)))
+		if prev, ok := best[tool]; ok && prev <= r {
+			continue
+		}
+		best[tool] = r
+		commands[tool] = filepath.Join(dir, name)
+	}
+	return commands
+}
+
+// processPathExt returns this process's PATHEXT extensions, or the Windows default
+// when unset, without a shell call
+func processPathExt() []string {
+	pathext := os.Getenv("PATHEXT")
+	if pathext == "" {
+		pathext = DefaultPathExt
+	}
+	var exts []string
+	for _, ext := range strings.Split(pathext, ";") {
+		if ext = strings.TrimSpace(ext); ext != "" {
+			exts = append(exts, ext)
+		}
+	}
+	return exts
+}
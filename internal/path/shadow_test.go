@@ -0,0 +1,76 @@
+package path
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func touch(t *testing.T, dir, name string) string {
+	t.Helper()
+	file := filepath.Join(dir, name)
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestFindShadowedTools(t *testing.T) {
+	first, second, third := t.TempDir(), t.TempDir(), t.TempDir()
+	winner := touch(t, first, "tool.exe")
+	shadowed := touch(t, second, "tool.bat")
+	later := touch(t, third, "Tool.EXE")
+	touch(t, first, "only.exe")
+	touch(t, second, "readme.txt")
+	touch(t, third, "readme.txt")
+
+	pathext := []string{".COM", ".EXE", ".BAT", ".CMD"}
+	entries := []string{first, second, filepath.Join(first, "missing"), first, third}
+	got := FindShadowedTools(entries, pathext)
+
+	want := []ShadowInfo{{Tool: "tool", Winner: winner, Shadowed: []string{shadowed, later}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestFindShadowedTools_ExtensionOrderWithinDirectory(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	touch(t, first, "build.cmd")
+	exe := touch(t, first, "build.exe")
+	other := touch(t, second, "build.bat")
+
+	got := FindShadowedTools([]string{first, second}, []string{".EXE", ".BAT", ".CMD"})
+	if len(got) != 1 || got[0].Winner != exe || !reflect.DeepEqual(got[0].Shadowed, []string{other}) {
+		t.Errorf("Expected build.exe to win over build.cmd in the same directory, got %+v", got)
+	}
+}
+
+func TestFindShadowedTools_NoneWithoutRepeats(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	touch(t, first, "a.exe")
+	touch(t, second, "b.exe")
+	if got := FindShadowedTools([]string{first, second, first}, []string{".EXE"}); len(got) != 0 {
+		t.Errorf("Expected no shadowed tools, got %+v", got)
+	}
+}
+
+func TestAnalyzeAllFrom_ShadowedToolsGated(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	touch(t, first, "tool.exe")
+	touch(t, second, "tool.exe")
+	t.Setenv("PATHEXT", ".EXE")
+
+	opts := DefaultOptions()
+	result := AnalyzeAllFrom(first, second, opts, nil)
+	if result.ShadowedTools != nil {
+		t.Errorf("Shadowed tools should only be scanned when enabled, got %+v", result.ShadowedTools)
+	}
+
+	opts.DetectShadowedTools = true
+	result = AnalyzeAllFrom(first, second, opts, nil)
+	if len(result.ShadowedTools) != 1 || result.ShadowedTools[0].Tool != "tool" {
+		t.Errorf("Expected tool.exe in User PATH to be shadowed by System PATH, got %+v", result.ShadowedTools)
+	}
+}
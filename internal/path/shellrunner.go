@@ -1,23 +1,115 @@
 package path
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os/exec"
 	"strings"
+	"sync"
+	"time"
 )
 
 // ShellRunner interface for executing shell commands
 // This allows mocking in tests
 type ShellRunner interface {
 	Run(command string) (string, error)
+	RunContext(ctx context.Context, command string) (string, error)
+}
+
+// DefaultShellTimeout bounds a single PowerShell call unless configured otherwise
+const DefaultShellTimeout = 60 * time.Second
+
+// ShellTimeout is how long one PowerShell call may run before it is killed
+var ShellTimeout = DefaultShellTimeout
+
+// SetShellTimeout applies the configured shellTimeoutSeconds; 0 or less keeps the default
+func SetShellTimeout(seconds int) {
+	if seconds <= 0 {
+		ShellTimeout = DefaultShellTimeout
+		return
+	}
+	ShellTimeout = time.Duration(seconds) * time.Second
+}
+
+// The PowerShell executables WinPath knows, in order of preference
+const (
+	PwshExe           = "pwsh.exe"
+	WindowsPowerShell = "powershell.exe"
+)
+
+// PowerShellExe is the executable the real runner starts
+var PowerShellExe = WindowsPowerShell
+
+// lookPath finds an executable on PATH; tests replace it to control which shells exist
+var lookPath = exec.LookPath
+
+// SelectPowerShell returns the configured executable, or pwsh when it is installed and
+// powershell.exe otherwise
+func SelectPowerShell(configured string) string {
+	if configured = strings.TrimSpace(configured); configured != "" {
+		return configured
+	}
+	if _, err := lookPath(PwshExe); err == nil {
+		return PwshExe
+	}
+	return WindowsPowerShell
+}
+
+// SetPowerShellExe applies the configured powerShellExe; empty picks pwsh when present
+func SetPowerShellExe(configured string) {
+	PowerShellExe = SelectPowerShell(configured)
+}
+
+// ErrPowerShellNotFound is returned by every shell command when PowerShell isn't installed
+var ErrPowerShellNotFound = errors.New("PowerShell was not found on this machine; WinPath needs pwsh.exe or powershell.exe to read and change environment variables")
+
+// shellError turns the "executable not found" error into ErrPowerShellNotFound
+func shellError(err error) error {
+	if errors.Is(err, exec.ErrNotFound) {
+		return ErrPowerShellNotFound
+	}
+	return err
+}
+
+// CheckPowerShell reports ErrPowerShellNotFound when shell commands can't run at all
+// The real runner looks for the executable without starting it; other runners run a trivial command
+func CheckPowerShell() error {
+	if prober, ok := DefaultRunner.(interface{ Probe() error }); ok {
+		return shellError(prober.Probe())
+	}
+	if _, err := RunPowerShell(powerShellVersionCommand); errors.Is(err, ErrPowerShellNotFound) {
+		return err
+	}
+	return nil
 }
 
 // RealShellRunner executes actual PowerShell commands
 type RealShellRunner struct{}
 
+// Probe checks that PowerShellExe can be found
+func (r *RealShellRunner) Probe() error {
+	_, err := lookPath(PowerShellExe)
+	return err
+}
+
 // Run executes a PowerShell command
 func (r *RealShellRunner) Run(command string) (string, error) {
-	cmd := exec.Command("powershell.exe", "-NoProfile", "-NonInteractive", "-ExecutionPolicy", "Bypass", "-Command", command)
+	return r.RunContext(context.Background(), command)
+}
+
+// RunContext executes a PowerShell command, killing it when ctx is done or ShellTimeout passes
+func (r *RealShellRunner) RunContext(ctx context.Context, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, ShellTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, PowerShellExe, "-NoProfile", "-NonInteractive", "-ExecutionPolicy", "Bypass", "-Command", command)
 	output, err := cmd.Output()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		if ctxErr == context.DeadlineExceeded {
+			return "", fmt.Errorf("PowerShell did not respond within %s: %w", ShellTimeout, ctxErr)
+		}
+		return "", ctxErr
+	}
 	if err != nil {
 		return "", err
 	}
@@ -30,13 +122,48 @@ var DefaultRunner ShellRunner = &RealShellRunner{}
 
 // RunShell executes a command using the default runner
 func RunShell(command string) (string, error) {
-	return DefaultRunner.Run(command)
+	return RunPowerShellContext(operationContext(), command)
+}
+
+// The context shell commands run under while a cancellable operation is in progress
+var (
+	operationMu  sync.Mutex
+	operationCtx = context.Background()
+)
+
+// BeginOperation makes the shell commands that follow cancellable as one unit
+// Cancelling kills the in-flight PowerShell call and fails the remaining ones fast
+// until EndOperation is called with the returned context
+func BeginOperation() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	operationMu.Lock()
+	operationCtx = ctx
+	operationMu.Unlock()
+	return ctx, cancel
+}
+
+// EndOperation restores the default context once ctx's operation has finished
+// It does nothing if another operation has started since
+func EndOperation(ctx context.Context) {
+	operationMu.Lock()
+	if operationCtx == ctx {
+		operationCtx = context.Background()
+	}
+	operationMu.Unlock()
+}
+
+// operationContext returns the context of the current operation, if any
+func operationContext() context.Context {
+	operationMu.Lock()
+	defer operationMu.Unlock()
+	return operationCtx
 }
 
 // MockShellRunner for testing
 type MockShellRunner struct {
 	Responses       map[string]string
 	Errors          map[string]error
+	Blocking        []string // Patterns whose commands hang until their context is done
 	Calls           []string
 	DefaultResponse string
 }
@@ -53,8 +180,24 @@ func NewMockShellRunner() *MockShellRunner {
 
 // Run returns mocked responses
 func (m *MockShellRunner) Run(command string) (string, error) {
+	return m.RunContext(context.Background(), command)
+}
+
+// RunContext returns mocked responses, simulating a hung command for Blocking patterns
+// Like the real runner, it fails with ctx's error once ctx is done
+func (m *MockShellRunner) RunContext(ctx context.Context, command string) (string, error) {
 	m.Calls = append(m.Calls, command)
 
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	for _, pattern := range m.Blocking {
+		if strings.Contains(command, pattern) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+	}
+
 	// Check for exact error match first
 	if err, ok := m.Errors[command]; ok {
 		return "", err
@@ -93,9 +236,15 @@ func (m *MockShellRunner) SetError(pattern string, err error) {
 	m.Errors[pattern] = err
 }
 
+// SetBlocking makes commands matching pattern hang until cancelled
+func (m *MockShellRunner) SetBlocking(pattern string) {
+	m.Blocking = append(m.Blocking, pattern)
+}
+
 // Reset clears all mock data
 func (m *MockShellRunner) Reset() {
 	m.Responses = make(map[string]string)
 	m.Errors = make(map[string]error)
+	m.Blocking = nil
 	m.Calls = []string{}
 }
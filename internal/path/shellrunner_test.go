@@ -1,8 +1,12 @@
 package path
 
 import (
+	"context"
 	"errors"
+	"os/exec"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestShellRunnerInterface(t *testing.T) {
@@ -137,6 +141,156 @@ func TestMockShellRunner_PartialErrorMatch(t *testing.T) {
 	}
 }
 
+func TestMockShellRunner_BlockingWaitsForCancel(t *testing.T) {
+	mock := NewMockShellRunner()
+	mock.SetBlocking("Get-Slow")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := mock.RunContext(ctx, "Get-Slow -Path C:\\")
+		done <- err
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Blocking command returned before it was cancelled")
+	case <-time.After(20 * time.Millisecond):
+	}
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Blocking command ignored cancellation")
+	}
+}
+
+func TestMockShellRunner_CancelledContext(t *testing.T) {
+	mock := NewMockShellRunner()
+	mock.SetResponse("cmd", "response")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := mock.RunContext(ctx, "cmd")
+	if !errors.Is(err, context.Canceled) || result != "" {
+		t.Errorf("Expected a cancelled call to fail without a response, got %q, %v", result, err)
+	}
+	if len(mock.Calls) != 1 {
+		t.Error("Cancelled calls should still be tracked")
+	}
+}
+
+func TestRunPowerShellContext_Timeout(t *testing.T) {
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetBlocking("Get-Slow")
+	}, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := RunPowerShellContext(ctx, "Get-Slow")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+		if time.Since(start) > time.Second {
+			t.Error("Expected the call to return at the deadline")
+		}
+	})
+}
+
+func TestRunPowerShellContext_Cancel(t *testing.T) {
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetBlocking("Get-Slow")
+	}, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			_, err := RunPowerShellContext(ctx, "Get-Slow")
+			done <- err
+		}()
+		cancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+}
+
+func TestRunPowerShell_UsesBackgroundContext(t *testing.T) {
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetResponse("Get-Fast", "ok")
+	}, func() {
+		if result, err := RunPowerShell("Get-Fast"); err != nil || result != "ok" {
+			t.Errorf("Expected RunPowerShell to run outside an operation, got %q, %v", result, err)
+		}
+	})
+}
+
+func TestRealShellRunner_CancelledContext(t *testing.T) {
+	runner := &RealShellRunner{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := runner.RunContext(ctx, "Write-Output hi"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled without starting PowerShell, got %v", err)
+	}
+}
+
+func TestBeginOperation_CancelsRunPowerShell(t *testing.T) {
+	withMockRunner(t, func(mock *MockShellRunner) {
+		mock.SetBlocking("Get-Slow")
+	}, func() {
+		ctx, cancel := BeginOperation()
+		done := make(chan error, 1)
+		go func() {
+			_, err := RunPowerShell("Get-Slow")
+			done <- err
+		}()
+		cancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the in-flight call to be cancelled, got %v", err)
+		}
+
+		EndOperation(ctx)
+		if _, err := RunPowerShell("Get-Fast"); err != nil {
+			t.Errorf("Expected calls after EndOperation to run normally, got %v", err)
+		}
+	})
+}
+
+func TestEndOperation_KeepsNewerOperation(t *testing.T) {
+	first, cancelFirst := BeginOperation()
+	defer cancelFirst()
+	second, cancelSecond := BeginOperation()
+	defer cancelSecond()
+
+	EndOperation(first)
+	if operationContext() != second {
+		t.Error("Ending a stale operation should not reset the current one")
+	}
+	EndOperation(second)
+	if operationContext() != context.Background() {
+		t.Error("Expected the background context once the operation ended")
+	}
+}
+
+func TestSetShellTimeout(t *testing.T) {
+	defer SetShellTimeout(0)
+
+	SetShellTimeout(5)
+	if ShellTimeout != 5*time.Second {
+		t.Errorf("Expected 5s, got %s", ShellTimeout)
+	}
+	SetShellTimeout(0)
+	if ShellTimeout != DefaultShellTimeout {
+		t.Errorf("Expected default timeout for 0, got %s", ShellTimeout)
+	}
+	SetShellTimeout(-3)
+	if ShellTimeout != DefaultShellTimeout {
+		t.Errorf("Expected default timeout for negative values, got %s", ShellTimeout)
+	}
+}
+
 func TestRunShell(t *testing.T) {
 	// RunShell uses DefaultRunner
 	result, err := RunShell("test")
@@ -169,3 +323,94 @@ func BenchmarkMockShellRunner_PartialMatch(b *testing.B) {
 		mock.Run("this is a longer command with partial in it")
 	}
 }
+
+// notFoundRunner fails every command the way exec does when powershell.exe is missing
+type notFoundRunner struct{}
+
+func (notFoundRunner) Run(command string) (string, error) {
+	return notFoundRunner{}.RunContext(context.Background(), command)
+}
+
+func (notFoundRunner) RunContext(ctx context.Context, command string) (string, error) {
+	return "", &exec.Error{Name: "powershell.exe", Err: exec.ErrNotFound}
+}
+
+func TestPowerShellNotFound_FriendlyErrorAndFallback(t *testing.T) {
+	original := DefaultRunner
+	defer func() { DefaultRunner = original }()
+	DefaultRunner = notFoundRunner{}
+
+	if err := CheckPowerShell(); !errors.Is(err, ErrPowerShellNotFound) {
+		t.Fatalf("Expected ErrPowerShellNotFound from the probe, got %v", err)
+	}
+	_, err := GetPathRaw("User")
+	if err == nil || !strings.Contains(err.Error(), "PowerShell was not found") {
+		t.Errorf("Expected the friendly message from a registry read, got %v", err)
+	}
+
+	t.Setenv("PATH", `C:\Windows;C:\Tools`)
+	if got := LivePathEntries(); strings.Join(got, ";") != `C:\Windows;C:\Tools` {
+		t.Errorf("Expected the process PATH as fallback, got %v", got)
+	}
+}
+
+func TestCheckPowerShell_Available(t *testing.T) {
+	withMockRunner(t, nil, func() {
+		if err := CheckPowerShell(); err != nil {
+			t.Errorf("Expected no error with a working runner, got %v", err)
+		}
+	})
+}
+
+// withShells makes lookPath find only the named executables
+func withShells(t *testing.T, installed ...string) {
+	t.Helper()
+	original := lookPath
+	t.Cleanup(func() { lookPath = original })
+	lookPath = func(file string) (string, error) {
+		for _, name := range installed {
+			if strings.EqualFold(name, file) {
+				return `C:\Program Files\PowerShell\7\` + file, nil
+			}
+		}
+		return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+	}
+}
+
+func TestSelectPowerShell(t *testing.T) {
+	tests := []struct {
+		name       string
+		installed  []string
+		configured string
+		want       string
+	}{
+		{"prefers pwsh", []string{PwshExe, WindowsPowerShell}, "", PwshExe},
+		{"falls back without pwsh", []string{WindowsPowerShell}, "", WindowsPowerShell},
+		{"neither installed", nil, "", WindowsPowerShell},
+		{"configured wins", []string{PwshExe, WindowsPowerShell}, WindowsPowerShell, WindowsPowerShell},
+		{"configured path kept", nil, ` C:\Tools\pwsh.exe `, `C:\Tools\pwsh.exe`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withShells(t, tt.installed...)
+			if got := SelectPowerShell(tt.configured); got != tt.want {
+				t.Errorf("SelectPowerShell(%q) = %q, want %q", tt.configured, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRealShellRunner_ProbeUsesSelectedShell(t *testing.T) {
+	original := PowerShellExe
+	defer func() { PowerShellExe = original }()
+	withShells(t, PwshExe)
+
+	SetPowerShellExe("")
+	if err := (&RealShellRunner{}).Probe(); err != nil {
+		t.Errorf("Expected pwsh selected and found, got %v", err)
+	}
+	SetPowerShellExe(WindowsPowerShell)
+	if err := (&RealShellRunner{}).Probe(); !errors.Is(shellError(err), ErrPowerShellNotFound) {
+		t.Errorf("Expected the configured powershell.exe reported missing, got %v", err)
+	}
+}
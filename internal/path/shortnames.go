@@ -11,26 +11,7 @@ func ToShortPath(path string) (string, bool) {
 		return path, false
 	}
 
-	command := fmt.Sprintf(`
-		$ErrorActionPreference = 'SilentlyContinue'
-		$path = '%s'
-		if (Test-Path -LiteralPath $path) {
-			$fso = New-Object -ComObject Scripting.FileSystemObject
-			try {
-				if ((Get-Item -LiteralPath $path).PSIsContainer) {
-					$fso.GetFolder($path).ShortPath
-				} else {
-					$fso.GetFile($path).ShortPath
-				}
-			} catch { $path }
-		} else { $path }
-	`, strings.ReplaceAll(path, "'", "''"))
-
-	result, err := RunPowerShell(command)
-	if err != nil || result == "" || result == path {
-		return path, false
-	}
-
+	result := shortPathOf(path)
 	// Only return if actually shorter
 	if len(result) < len(path) {
 		return result, true
@@ -38,6 +19,71 @@ func ToShortPath(path string) (string, bool) {
 	return path, false
 }
 
+// shortPathOf looks up the 8.3 form of an existing path with one PowerShell call
+// It returns path unchanged when there is none or the lookup fails
+func shortPathOf(path string) string {
+	result, err := RunPowerShell(getShortPathCommand(path))
+	if err != nil || result == "" {
+		return path
+	}
+	return result
+}
+
+// ToShortPathBatch converts every path to its 8.3 short form in a single PowerShell call
+// Each result is the short form when it is shorter, and the path unchanged otherwise, so
+// entries with variables, missing folders and a failed lookup all come back as they were
+func ToShortPathBatch(paths []string) []string {
+	lookup := make([]int, 0, len(paths))
+	for i, p := range paths {
+		if p != "" && !strings.Contains(p, "%") {
+			lookup = append(lookup, i)
+		}
+	}
+	if len(lookup) == 0 {
+		return paths
+	}
+
+	var sb strings.Builder
+	sb.WriteString("$paths = @(\n")
+	for i, idx := range lookup {
+		if i > 0 {
+			sb.WriteString(",\n")
+		}
+		sb.WriteString(fmt.Sprintf("    '%s'", escapePSString(paths[idx])))
+	}
+	sb.WriteString("\n)\n")
+	sb.WriteString(`
+$fso = New-Object -ComObject Scripting.FileSystemObject
+$short = foreach ($p in $paths) {
+    $out = $p
+    try {
+        $item = Get-Item -LiteralPath $p -Force -ErrorAction Stop
+        if ($item.PSIsContainer) { $out = $fso.GetFolder($p).ShortPath } else { $out = $fso.GetFile($p).ShortPath }
+    } catch {}
+    $out
+}
+$short -join '|'
+`)
+
+	result, err := RunPowerShell(sb.String())
+	if err != nil || result == "" {
+		return paths
+	}
+	short := strings.Split(strings.TrimSpace(result), "|")
+	if len(short) != len(lookup) {
+		return paths // Unexpected output; keep entries as they are
+	}
+
+	output := make([]string, len(paths))
+	copy(output, paths)
+	for i, idx := range lookup {
+		if short[i] != "" && len(short[i]) < len(paths[idx]) {
+			output[idx] = short[i]
+		}
+	}
+	return output
+}
+
 // ShortenSuffix shortens the suffix of a path that contains environment variables
 // E.g., %LOCALAPPDATA%\Microsoft\WinGet -> %LOCALAPPDATA%\MICROS~1\WinGet
 // extractVarAndSuffix extracts the variable part and suffix from a path
@@ -76,10 +122,15 @@ func getShortPathCommand(expanded string) string {
 				}
 			} catch { $path }
 		} else { $path }
-	`, strings.ReplaceAll(expanded, "'", "''"))
+	`, escapePSString(expanded))
 }
 
 func ShortenSuffix(pathWithVar string) (string, bool) {
+	return shortenSuffixWith(pathWithVar, shortPathOf)
+}
+
+// shortenSuffixWith is ShortenSuffix with the 8.3 lookup of the expanded path supplied
+func shortenSuffixWith(pathWithVar string, shortPath func(string) string) (string, bool) {
 	varPart, _, ok := extractVarAndSuffix(pathWithVar)
 	if !ok {
 		return pathWithVar, false
@@ -90,9 +141,8 @@ func ShortenSuffix(pathWithVar string) (string, bool) {
 		return pathWithVar, false
 	}
 
-	command := getShortPathCommand(expanded)
-	shortExpanded, err := RunPowerShell(command)
-	if err != nil || shortExpanded == "" || shortExpanded == expanded {
+	shortExpanded := shortPath(expanded)
+	if shortExpanded == "" || shortExpanded == expanded {
 		return pathWithVar, false
 	}
 
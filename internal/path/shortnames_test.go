@@ -1,6 +1,7 @@
 package path
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -264,3 +265,82 @@ func TestGetShortPathCommand_WithQuotes(t *testing.T) {
 		t.Error("Single quotes should be escaped")
 	}
 }
+
+func TestToShortPathBatch_MapsEachPath(t *testing.T) {
+	paths := []string{`C:\Program Files\Tool A`, `%USERPROFILE%\bin`, `C:\Missing Folder`, `C:\Tools`, `C:\It's Here`}
+	withMockRunner(t, func(m *MockShellRunner) {
+		m.Responses = map[string]string{"$short -join": `C:\PROGRA~1\TOOLA~1|C:\Missing Folder|C:\Tools|C:\ITSHER~1`}
+	}, func() {
+		mock := getMockRunner(t)
+		before := len(mock.Calls)
+		got := ToShortPathBatch(paths)
+
+		want := []string{`C:\PROGRA~1\TOOLA~1`, `%USERPROFILE%\bin`, `C:\Missing Folder`, `C:\Tools`, `C:\ITSHER~1`}
+		if strings.Join(got, ";") != strings.Join(want, ";") {
+			t.Errorf("ToShortPathBatch = %v, want %v", got, want)
+		}
+		calls := mock.Calls[before:]
+		if len(calls) != 1 {
+			t.Fatalf("Expected one shell call for every path, got %d", len(calls))
+		}
+		if !strings.Contains(calls[0], `'C:\It''s Here'`) || strings.Contains(calls[0], "USERPROFILE") {
+			t.Errorf("Expected quoted paths and no variable entries in the script, got:\n%s", calls[0])
+		}
+	})
+}
+
+func TestToShortPathBatch_UnexpectedOutputKeepsPaths(t *testing.T) {
+	paths := []string{`C:\Program Files\Tool A`, `C:\Program Files\Tool B`}
+	withMockRunner(t, func(m *MockShellRunner) {
+		m.Responses = map[string]string{"$short -join": `C:\PROGRA~1\TOOLA~1`}
+	}, func() {
+		if got := ToShortPathBatch(paths); strings.Join(got, ";") != strings.Join(paths, ";") {
+			t.Errorf("Expected paths unchanged on a short answer, got %v", got)
+		}
+	})
+}
+
+func TestOptimize_ShortensWithOneShellCall(t *testing.T) {
+	defer SetConfigDir(getConfigDir())
+	SetConfigDir(t.TempDir())
+	long := []string{`C:\Program Files\Tool A\bin`, `C:\Program Files\Tool B\bin`, `C:\Program Files\Tool C\bin`}
+	short := []string{`C:\PROGRA~1\TOOLA~1\bin`, `C:\PROGRA~1\TOOLB~1\bin`, `C:\PROGRA~1\TOOLC~1\bin`}
+	withMockRunner(t, func(m *MockShellRunner) {
+		m.Responses = map[string]string{"$short -join": strings.Join(short, "|")}
+	}, func() {
+		mock := getMockRunner(t)
+		before := len(mock.Calls)
+		opts := DefaultOptions()
+		opts.RemoveDeadPaths = false
+		opts.SubstituteVars = false
+		result := Optimize(JoinPath(long), opts)
+
+		if result.Optimized.Raw != JoinPath(short) || result.Metrics.PathsShortened != 3 {
+			t.Errorf("Expected every entry shortened, got %q (%d shortened)", result.Optimized.Raw, result.Metrics.PathsShortened)
+		}
+		if n := countMockCalls(mock.Calls[before:], "ShortPath"); n != 1 {
+			t.Errorf("Expected one batched short name lookup, got %d", n)
+		}
+	})
+}
+
+func BenchmarkToShortPathBatch(b *testing.B) {
+	paths := make([]string, 50)
+	short := make([]string, len(paths))
+	for i := range paths {
+		paths[i] = fmt.Sprintf(`C:\Program Files\Vendor Tool %02d\bin`, i)
+		short[i] = fmt.Sprintf(`C:\PROGRA~1\VENDOR~%d\bin`, i+1)
+	}
+	mock, ok := DefaultRunner.(*MockShellRunner)
+	if !ok {
+		b.Skip("Not running with mock runner")
+	}
+	oldResponses, oldCalls := mock.Responses, len(mock.Calls)
+	defer func() { mock.Responses, mock.Calls = oldResponses, mock.Calls[:oldCalls] }()
+	mock.Responses = map[string]string{"$short -join": strings.Join(short, "|")}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ToShortPathBatch(paths)
+	}
+}
@@ -2,6 +2,7 @@ package path
 
 import (
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -19,11 +20,15 @@ func TestMain(m *testing.M) {
 	mock, cleanup := SetDefaultTestRunner()
 	_ = mock // available if tests need to modify it
 
+	// Keep tests off the real clipboard, which headless CI doesn't have
+	_, cleanupClipboard := SetDefaultClipboard()
+
 	// Run tests
 	code := m.Run()
 
-	// Restore original runner
+	// Restore original runner and clipboard
 	cleanup()
+	cleanupClipboard()
 
 	// Exit with test result code
 	os.Exit(code)
@@ -49,6 +54,7 @@ func withMockRunner(t *testing.T, setup func(*MockShellRunner), test func()) {
 		oldErrors[k] = v
 	}
 	oldDefault := mock.DefaultResponse
+	oldBlocking := mock.Blocking
 
 	// Apply custom setup
 	if setup != nil {
@@ -61,6 +67,7 @@ func withMockRunner(t *testing.T, setup func(*MockShellRunner), test func()) {
 	// Restore state
 	mock.Responses = oldResponses
 	mock.Errors = oldErrors
+	mock.Blocking = oldBlocking
 	mock.DefaultResponse = oldDefault
 }
 
@@ -74,3 +81,14 @@ func getMockRunner(t *testing.T) *MockShellRunner {
 	}
 	return mock
 }
+
+// countMockCalls returns how many calls contain pattern
+func countMockCalls(calls []string, pattern string) int {
+	n := 0
+	for _, c := range calls {
+		if strings.Contains(c, pattern) {
+			n++
+		}
+	}
+	return n
+}
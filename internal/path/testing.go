@@ -66,6 +66,16 @@ func SetDefaultTestRunner() (*MockShellRunner, func()) {
 	}
 }
 
+// SetDefaultClipboard replaces the default clipboard with a mock and returns a cleanup function
+func SetDefaultClipboard() (*MockClipboard, func()) {
+	original := DefaultClipboard
+	mock := NewMockClipboard()
+	DefaultClipboard = mock
+	return mock, func() {
+		DefaultClipboard = original
+	}
+}
+
 // IsTestMockActive returns true if the default runner is a mock (safety check)
 func IsTestMockActive() bool {
 	_, ok := DefaultRunner.(*MockShellRunner)
@@ -1,9 +1,14 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/quantumJLBass/winpath/internal/path"
 
@@ -35,6 +40,25 @@ const (
 	ScreenPathExtDone
 	ScreenSettings
 	ScreenHotPaths
+	ScreenOptimizerRollback
+	ScreenHelp
+	ScreenOptimizerConfirmReorder
+	ScreenJunctionRewriteConfirm
+	ScreenJunctionPruneConfirm
+	ScreenBackupDiff
+	ScreenPathEditor
+	ScreenPathEditorConfirm
+	ScreenProtectedOverride
+	ScreenOptimizerUndoConfirm
+	ScreenAutoFixConfirm
+	ScreenAutoFixDone
+	ScreenChangeDetail
+	ScreenAuditLog
+	ScreenExternalChanges
+	ScreenJunctionPreview
+	ScreenJunctionExcludes
+	ScreenEnvVars
+	ScreenShortNames
 )
 
 // LoadingTask represents a background task
@@ -46,27 +70,72 @@ const (
 	TaskJunctions
 	TaskSuggestions
 	TaskCreateJunction
+	TaskAutoFix
 )
 
 // Messages for async operations
-type analysisCompleteMsg struct{ result path.AnalysisResult }
+type analysisCompleteMsg struct {
+	result   path.AnalysisResult
+	cacheKey string // analysisKeyFor the values the analysis read
+	cacheHit bool   // Nothing changed since the cached analysis, so none was run
+	err      error  // Reading PATH failed; there is no result
+}
 type junctionsLoadedMsg struct{ junctions []path.Junction }
-type suggestionsLoadedMsg struct{ suggestions []path.JunctionSuggestion }
+type suggestionsLoadedMsg struct {
+	suggestions []path.JunctionSuggestion
+	projection  []int // Total PATH length after applying the first n suggestions
+	preview     path.JunctionPreview
+	err         error // Reading PATH failed or timed out; there are no suggestions
+}
 type junctionCreatedMsg struct {
-	success bool
-	name    string
-	err     error
+	success      bool
+	name         string
+	err          error
+	target       string
+	junctionPath string
+	rewrite      bool
+}
+type junctionsBatchCreatedMsg struct {
+	created int
+	total   int
+	errs    []error
 }
 type applyCompleteMsg struct {
-	backup *path.BackupInfo
-	err    error
+	backup       *path.BackupInfo
+	err          error
+	backupFailed bool
+	written      []string // Scopes whose PATH was written
+	skipped      []string // Scopes in the selection that were not written
+}
+type autoFixPlanMsg struct {
+	analysis path.AnalysisResult
+	pathExt  path.PathExtOptimization
+	err      error // Reading PATH failed or timed out; there is no plan
+}
+type autoFixCompleteMsg struct {
+	backup       *path.BackupInfo
+	err          error
+	backupFailed bool
 }
 type progressMsg struct {
 	current int
 	total   int
 	item    string
 }
+type relaunchMsg struct{ err error }
+type externalChangesMsg struct{ changes *path.ExternalChanges }
+type operationMsg struct {
+	id  int
+	msg tea.Msg // Result of the wrapped command
+}
 type tickMsg time.Time
+type rollbackTickMsg struct{ id int }
+type rollbackRevertedMsg struct {
+	reason string
+	errs   []string // Scopes that could not be written back
+	quit   bool     // The revert was started by Ctrl+C; quit once it succeeds
+}
+type undoCompleteMsg struct{ errs []string }
 
 // Model is the main application model
 type Model struct {
@@ -74,6 +143,7 @@ type Model struct {
 	width       int
 	height      int
 	isAdmin     bool
+	shellErr    error // Set when PowerShell can't be found; only the Process PATH is readable then
 	err         error
 	message     string
 	clipboardOK bool
@@ -85,34 +155,120 @@ type Model struct {
 	loadingCurrent int
 	loadingTotal   int
 	loadingItem    string
+	loadingFrom    Screen // Screen to return to when loading is cancelled
+
+	// Cancellable operation behind the loading screen
+	operationID     int
+	cancelOperation context.CancelFunc
+	loadingWrite    bool    // The operation changes the system and can't be cancelled
+	startupCmd      tea.Cmd // Auto-analyze started by New, run from Init
 
 	// Menu
 	menuIndex int
 	menuItems []string
 
+	// Help overlay
+	helpReturnScreen Screen
+
 	// Optimizer
 	analysis       *path.AnalysisResult
 	optimizerScope string
 	viewMode       int
 	scrollOffset   int
 	backupInfo     *path.BackupInfo
+	backupFailed   bool
+	appliedScopes  []string // Scopes the last apply wrote
+	skippedScopes  []string // Scopes the last apply left alone, i.e. System without admin
+
+	// Last analysis, reused while the PATH and the settings it depends on are unchanged
+	cachedAnalysis    *path.AnalysisResult
+	cachedAnalysisKey string
+	analysisCached    bool // Shown analysis came from the cache
+	lastAnalyzedAt    time.Time
+
+	// Protected entries the user has agreed to let the optimizer change, keyed by scope and entry
+	protectedOverrides map[string]bool
+	protectedIndex     int
+
+	// 8.3 conversions under review, keyed by scope and original entry when rejected
+	shortNameRejected map[string]bool
+	shortNameIndex    int
+
+	// Changes tab filter
+	changeFilterIndex int
+	hiddenChangeTypes map[string]bool
+	changeIndex       int // Selected row in the Changes tab
+
+	// Rollback timer
+	rollbackArmed     bool
+	rollbackID        int
+	rollbackRemaining int
+	rollbackSnapshot  map[string]string
+
+	// PATH of each scope the last apply wrote, as it was just before; U writes it back
+	// A coalesced backup can predate earlier applies, so undo doesn't restore from it
+	undoSnapshot map[string]string
+
+	// Exact value preview on apply confirms
+	confirmShowValue bool
+	confirmScroll    int
 
 	// Path Viewer
-	viewerScope    string
-	viewerExpanded bool
+	viewerScope       string
+	viewerExpanded    bool
+	viewerIndex       int
+	viewerImporting   bool
+	viewerImportInput string
+	viewerRaw         string // PATH shown in the viewer, read by loadViewer
+	viewerLoaded      bool   // viewerRaw holds the current scope and expansion
+
+	// Path Editor
+	editorScope    string
+	editorEntries  []string
+	editorOriginal []string
+	editorIndex    int
+	editorAdding   bool
+	editorInput    string
 
 	// Backup
-	backups       []path.BackupInfo
-	backupIndex   int
-	backupPreview *path.Backup
+	backups           []path.BackupInfo
+	backupIndex       int
+	backupPreview     *path.Backup
+	compareIndex      int // Backup marked for comparison, -1 when none
+	backupLabeling    bool
+	backupLabelInput  string
+	backupImporting   bool
+	backupImportInput string
+	backupDiff        *backupDiff
 
 	// Junctions
 	junctions         []path.Junction
 	suggestions       []path.JunctionSuggestion
+	suggestionLengths []int // Total PATH length after applying the first n suggestions
+	suggestionPreview path.JunctionPreview
+	suggestionFilter  string // Only suggestions whose original path contains this are listed
+	suggestionTyping  bool   // Typing into suggestionFilter
+	excludeIndex      int
+	excludeAdding     bool
+	excludeInput      string
+	excludeChanged    bool // Suggestions are reloaded when leaving the exclusions editor
+
+	// Environment variables browser
+	envVars           []envVar
+	envIndex          int
+	envFilter         string // Only variables whose name or value contains this are listed
+	envTyping         bool   // Typing into envFilter
+	envExpanded       bool   // Showing the selected variable's full value
 	junctionIndex     int
 	junctionName      string
 	junctionTarget    string
 	junctionInputMode int
+	junctionRenaming  string // Junction being renamed on the create screen
+
+	// PATH rewrite after junction creation
+	junctionRewriteOld    string
+	junctionRewriteNew    string
+	junctionRewriteScopes []string
 
 	// PATHEXT
 	pathExtAnalysis *path.PathExtAnalysis
@@ -120,25 +276,52 @@ type Model struct {
 	pathExtEditing  bool
 	pathExtList     []string
 	pathExtIndex    int
+	pathExtAdding   bool
+	pathExtInput    string
+	pathExtReset    bool
+	pathExtPrevOpt  *path.PathExtOptimization
+
+	// Auto-fix
+	autoFixAnalysis *path.AnalysisResult
+	autoFixPathExt  *path.PathExtOptimization
 
 	// Settings
-	settingsIndex int
-	config        path.Config
+	settingsIndex   int
+	settingsEditing bool // Typing a new Junction Folder
+	settingsInput   string
+	config          path.Config
 
 	// Hot Paths
-	hotPathIndex  int
-	hotPathAdding bool
-	hotPathInput  string
+	hotPathIndex        int
+	hotPathAdding       bool
+	hotPathInput        string
+	hotPathPreview      bool
+	hotPathPreviewScope string
+	hotPathPreviewRaw   string          // PATH of the preview scope, read when the preview is shown
+	hotPathUndo         []string        // HotPaths before the last edit
+	hotPathMissing      map[string]bool // Hot paths not found on disk, checked when the list is shown or edited
+	hotPathWarning      string
+	hotPathCanUndo      bool
+
+	// Audit log
+	auditLines []string
+
+	// PATH edits made outside WinPath since the last backup, nil when there are none
+	externalChanges *path.ExternalChanges
 }
 
 // New creates a new model
 func New() Model {
-	return Model{
-		screen:         ScreenMenu,
-		isAdmin:        path.IsAdmin(),
-		optimizerScope: "both",
-		viewerScope:    "User",
-		config:         path.LoadConfig(),
+	shellErr := path.CheckPowerShell()
+	m := Model{
+		screen:              ScreenMenu,
+		isAdmin:             shellErr == nil && path.IsAdmin(),
+		shellErr:            shellErr,
+		optimizerScope:      "both",
+		viewerScope:         "User",
+		hotPathPreviewScope: "User",
+		compareIndex:        -1,
+		config:              path.LoadConfig(),
 		menuItems: []string{
 			"Optimize PATH",
 			"View Current PATH",
@@ -147,30 +330,169 @@ func New() Model {
 			"PATHEXT Optimizer",
 			"Hot Paths Config",
 			"Settings",
+			"Auto-fix (recommended)",
+			"Audit Log",
+			"Environment Variables",
 			"Exit",
 		},
 	}
+	ApplyTheme(m.config.Theme)
+	switch m.config.LastViewerScope {
+	case "User", "System", "Process":
+		m.viewerScope = m.config.LastViewerScope
+	}
+	switch m.config.LastOptimizerScope {
+	case "both", "system", "user":
+		m.optimizerScope = m.config.LastOptimizerScope
+	}
+	if m.shellErr != nil {
+		m.viewerScope = "Process"
+		return m
+	}
+	if m.config.AutoAnalyzeOnStart {
+		var cmd tea.Cmd
+		m, cmd = m.startLoading(TaskAnalyze, "Analyzing PATH", analyzeCmd())
+		m.startupCmd = cmd
+	}
+	if path.HasChangeBaseline() {
+		if m.startupCmd == nil {
+			m.startupCmd = checkExternalChangesCmd()
+		} else {
+			m.startupCmd = tea.Batch(m.startupCmd, checkExternalChangesCmd())
+		}
+	}
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.startupCmd
+}
+
+// startLoading shows the loading screen while cmd runs as a cancellable operation
+// Esc or Ctrl+C cancels it and returns to the screen loading started from
+func (m Model) startLoading(task LoadingTask, message string, cmd tea.Cmd) (Model, tea.Cmd) {
+	if m.screen != ScreenLoading {
+		m.loadingFrom = m.screen
+	}
+	m.screen = ScreenLoading
+	m.loadingTask = task
+	m.loadingMessage = message
+	m.loadingWrite = false
+	m.operationID++
+	ctx, cancel := path.BeginOperation()
+	m.cancelOperation = cancel
+	return m, tea.Batch(operationCmd(m.operationID, ctx, cmd), tickCmd())
+}
+
+// startWriting shows the loading screen while cmd changes the system
+// Unlike a read it can't be cancelled: killing PowerShell mid-write could leave PATH
+// half-written, so the write always finishes and reports its result
+func (m Model) startWriting(task LoadingTask, message string, cmd tea.Cmd) (Model, tea.Cmd) {
+	m, loading := m.startLoading(task, message, cmd)
+	m.loadingWrite = true
+	return m, loading
+}
+
+// operationCmd runs cmd under ctx and tags its result with the operation id
+func operationCmd(id int, ctx context.Context, cmd tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		defer path.EndOperation(ctx)
+		return operationMsg{id: id, msg: cmd()}
+	}
+}
+
+// loadFailed leaves the loading screen for the screen loading started from and shows err
+func (m Model) loadFailed(action string, err error) Model {
+	m.err = err
+	m.message = action + " failed: " + err.Error()
+	m.screen = m.loadingFrom
+	m.loadingTask = TaskNone
+	m.loadingCurrent = 0
+	m.loadingTotal = 0
+	m.loadingItem = ""
+	return m
 }
 
-func (m Model) Init() tea.Cmd { return nil }
+// cancelLoading kills the in-flight PowerShell call and goes back to where loading started
+func (m Model) cancelLoading() Model {
+	if m.cancelOperation != nil {
+		m.cancelOperation()
+		m.cancelOperation = nil
+	}
+	m.operationID++ // The cancelled command's result is dropped when it arrives
+	m.err = errors.New("cancelled")
+	m.message = "Cancelled: " + m.loadingMessage
+	m.screen = m.loadingFrom
+	m.loadingTask = TaskNone
+	m.loadingCurrent = 0
+	m.loadingTotal = 0
+	m.loadingItem = ""
+	return m
+}
 
 func tickCmd() tea.Cmd {
 	return tea.Tick(time.Millisecond*300, func(t time.Time) tea.Msg { return tickMsg(t) })
 }
 
+func rollbackTickCmd(id int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return rollbackTickMsg{id: id} })
+}
+
+// restoreBackup is swapped out in tests
+var restoreBackup = path.RestoreBackup
+
+// relaunchAsAdmin is swapped out in tests
+var relaunchAsAdmin = path.RelaunchAsAdmin
+
 // Progress channel for async operations
 var progressChan = make(chan progressMsg, 100)
 
+// analysisKeyFor builds the cache key for an analysis of the given raw PATH values
+// It captures everything a cached analysis depends on that can change without the
+// user asking for a refresh: both raw PATH values and the settings the optimizer
+// reads. Directories appearing or disappearing are not tracked; R refreshes.
+func analysisKeyFor(config path.Config, sys, usr string) string {
+	return strings.Join([]string{
+		sys, usr,
+		strings.Join(config.HotPaths, ";"),
+		strings.Join(config.ProtectedEntries, ";"),
+		fmt.Sprint(config.CanonicalizePaths),
+		config.OptimizerPreset,
+		fmt.Sprint(config.DetectShadowedTools),
+	}, "\x00")
+}
+
+// analyzeCmd reads both PATH values and analyzes them
 func analyzeCmd() tea.Cmd {
+	return analyzeUnlessCachedCmd("")
+}
+
+// analyzeUnlessCachedCmd reads both PATH values once, for the cache check and the
+// analysis alike. When they and the settings still match cachedKey it reports a
+// cache hit instead of analyzing again; an empty cachedKey always analyzes.
+func analyzeUnlessCachedCmd(cachedKey string) tea.Cmd {
 	return func() tea.Msg {
-		opts := path.DefaultOptions()
-		result := path.AnalyzeAllWithProgress(opts, func(current, total int, item string) {
+		sysPath, usrPath, err := path.GetPathsRaw()
+		if err != nil {
+			return analysisCompleteMsg{err: err}
+		}
+		config := path.LoadConfig()
+		key := analysisKeyFor(config, sysPath, usrPath)
+		if cachedKey != "" && key == cachedKey {
+			return analysisCompleteMsg{cacheKey: key, cacheHit: true}
+		}
+		opts := path.PresetOptions(config.OptimizerPreset)
+		if optimizerPreset(config) == "default" {
+			opts.CanonicalizePaths = config.CanonicalizePaths
+		}
+		opts.DetectShadowedTools = config.DetectShadowedTools
+		result := path.AnalyzeAllFrom(sysPath, usrPath, opts, func(current, total int, item string) {
 			select {
 			case progressChan <- progressMsg{current: current, total: total, item: item}:
 			default:
 			}
 		})
-		return analysisCompleteMsg{result: result}
+		return analysisCompleteMsg{result: result, cacheKey: key}
 	}
 }
 
@@ -191,8 +513,21 @@ func loadSuggestionsCmd() tea.Cmd {
 		case progressChan <- progressMsg{item: "Analyzing PATH for junction candidates..."}:
 		default:
 		}
-		suggestions := path.SuggestJunctionCandidates()
-		return suggestionsLoadedMsg{suggestions: suggestions}
+		sysPath, usrPath, err := path.GetPathsRaw()
+		if err != nil {
+			return suggestionsLoadedMsg{err: err}
+		}
+		suggestions := path.SuggestJunctionCandidatesFrom(sysPath, usrPath, func(current, total int, item string) {
+			select {
+			case progressChan <- progressMsg{current: current, total: total, item: item}:
+			default:
+			}
+		})
+		return suggestionsLoadedMsg{
+			suggestions: suggestions,
+			projection:  path.ProjectJunctionLengths(sysPath, usrPath, suggestions),
+			preview:     path.PreviewJunctionRewrites(sysPath, usrPath, suggestions),
+		}
 	}
 }
 
@@ -207,33 +542,140 @@ func listenForProgress() tea.Cmd {
 	}
 }
 
-func createJunctionCmd(name, target string) tea.Cmd {
+// createJunctionCmd creates a suggested junction; rewrite asks to point PATH at it afterwards
+func createJunctionCmd(s path.JunctionSuggestion, rewrite bool) tea.Cmd {
+	return func() tea.Msg {
+		err := path.CreateJunction(s.SuggestedName, s.OriginalPath)
+		return junctionCreatedMsg{
+			success:      err == nil,
+			name:         s.SuggestedName,
+			err:          err,
+			target:       s.OriginalPath,
+			junctionPath: s.JunctionPath,
+			rewrite:      rewrite,
+		}
+	}
+}
+
+// createAllJunctionsCmd creates a junction for every suggestion, reporting progress as it goes
+func createAllJunctionsCmd(suggestions []path.JunctionSuggestion) tea.Cmd {
 	return func() tea.Msg {
-		err := path.CreateJunction(name, target)
-		return junctionCreatedMsg{success: err == nil, name: name, err: err}
+		result := junctionsBatchCreatedMsg{total: len(suggestions)}
+		for i, s := range suggestions {
+			select {
+			case progressChan <- progressMsg{current: i + 1, total: len(suggestions), item: s.SuggestedName}:
+			default:
+			}
+			if err := path.CreateJunction(s.SuggestedName, s.OriginalPath); err != nil {
+				result.errs = append(result.errs, fmt.Errorf("%s: %w", s.SuggestedName, err))
+				continue
+			}
+			result.created++
+		}
+		return result
 	}
 }
 
-func applyOptimizationCmd(analysis *path.AnalysisResult, scope string, isAdmin bool) tea.Cmd {
+// applyOptimizationCmd backs up and writes the optimized PATH
+// A failed backup aborts the apply unless force is set
+func applyOptimizationCmd(analysis *path.AnalysisResult, scope string, isAdmin, force bool) tea.Cmd {
 	return func() tea.Msg {
 		// Create backup first
-		backup, _ := path.CreateBackup("pre-optimize")
+		backup, err := path.CreateBackup("pre-optimize")
+		if err != nil && !force {
+			return applyCompleteMsg{err: fmt.Errorf("backup failed, PATH unchanged: %w", err), backupFailed: true}
+		}
 
 		// Apply changes
-		var err error
+		err = nil
+		var written, skipped []string
 		if scope == "both" || scope == "user" {
 			err = path.SetPath(analysis.User.Optimized.Raw, "User")
+			if err == nil {
+				written = append(written, "User")
+			}
+		}
+		if scope == "both" || scope == "system" {
+			if !isAdmin {
+				skipped = append(skipped, "System")
+			} else if err == nil {
+				err = path.SetPath(analysis.System.Optimized.Raw, "System")
+				if err == nil {
+					written = append(written, "System")
+				}
+			}
+		}
+
+		if err == nil {
+			path.BroadcastEnvChange()
+		}
+
+		return applyCompleteMsg{backup: backup, err: err, written: written, skipped: skipped}
+	}
+}
+
+// relaunchAsAdminCmd starts an elevated copy of WinPath and reports whether it started
+func relaunchAsAdminCmd() tea.Cmd {
+	return func() tea.Msg {
+		return relaunchMsg{err: relaunchAsAdmin()}
+	}
+}
+
+// autoFixPlanCmd works out the recommended fixes: a conservative PATH optimization
+// and moving .EXE to the front of PATHEXT
+func autoFixPlanCmd() tea.Cmd {
+	return func() tea.Msg {
+		sysPath, usrPath, err := path.GetPathsRaw()
+		if err != nil {
+			return autoFixPlanMsg{err: err}
+		}
+		analysis := path.AnalyzeAllFrom(sysPath, usrPath, path.RecommendedOptions(), func(current, total int, item string) {
+			select {
+			case progressChan <- progressMsg{current: current, total: total, item: item}:
+			default:
+			}
+		})
+		return autoFixPlanMsg{analysis: analysis, pathExt: path.ExeFirstPathExt()}
+	}
+}
+
+// autoFixApplyCmd takes one backup, then writes every PATH scope and PATHEXT that changes
+// A failed backup aborts before anything is written
+func autoFixApplyCmd(analysis *path.AnalysisResult, pathExt *path.PathExtOptimization, isAdmin bool) tea.Cmd {
+	return func() tea.Msg {
+		backup, err := path.CreateBackup("pre-autofix")
+		if err != nil {
+			return autoFixCompleteMsg{err: fmt.Errorf("backup failed, nothing changed: %w", err), backupFailed: true}
+		}
+
+		if pathChanged(analysis.User) {
+			err = path.SetPath(analysis.User.Optimized.Raw, "User")
 		}
-		if isAdmin && (scope == "both" || scope == "system") && err == nil {
+		if isAdmin && pathChanged(analysis.System) && err == nil {
 			err = path.SetPath(analysis.System.Optimized.Raw, "System")
 		}
+		if pathExt.Changed && err == nil {
+			err = path.ApplyPathExt(pathExt.OptimizedString, pathExtScope(isAdmin))
+		}
 
 		if err == nil {
 			path.BroadcastEnvChange()
 		}
+		return autoFixCompleteMsg{backup: backup, err: err}
+	}
+}
+
+// pathChanged reports whether an optimization result would change the stored PATH
+func pathChanged(r path.OptimizeResult) bool {
+	return r.Optimized.Raw != r.Original.Raw
+}
 
-		return applyCompleteMsg{backup: backup, err: err}
+// pathExtScope is the PATHEXT scope writes go to: System for admins, otherwise User
+func pathExtScope(isAdmin bool) string {
+	if isAdmin {
+		return "System"
 	}
+	return "User"
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -243,6 +685,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case operationMsg:
+		if msg.id != m.operationID {
+			return m, nil
+		}
+		if m.cancelOperation != nil {
+			m.cancelOperation()
+			m.cancelOperation = nil
+		}
+		m.loadingWrite = false
+		return m.Update(msg.msg)
+
 	case tickMsg:
 		if m.screen == ScreenLoading {
 			m.loadingDots = (m.loadingDots + 1) % 4
@@ -259,8 +712,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case analysisCompleteMsg:
-		m.analysis = &msg.result
+		if msg.err != nil {
+			return m.loadFailed("Analysis", msg.err), nil
+		}
+		if msg.cacheHit {
+			m.analysis = m.cachedAnalysis
+			m.analysisCached = true
+		} else {
+			m.analysis = &msg.result
+			m.cachedAnalysis = m.analysis
+			m.cachedAnalysisKey = msg.cacheKey
+			m.analysisCached = false
+			m.lastAnalyzedAt = time.Now()
+		}
+		m.protectedOverrides = nil
+		m.changeIndex = 0
 		m.screen = ScreenOptimizerPreview
+		m.message = ""
+		m.err = nil
 		m.loadingTask = TaskNone
 		m.loadingCurrent = 0
 		m.loadingTotal = 0
@@ -278,7 +747,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case suggestionsLoadedMsg:
+		if msg.err != nil {
+			return m.loadFailed("Loading suggestions", msg.err), nil
+		}
 		m.suggestions = msg.suggestions
+		m.suggestionLengths = msg.projection
+		m.suggestionPreview = msg.preview
 		m.junctionIndex = 0
 		m.screen = ScreenJunctionSuggestions
 		m.loadingTask = TaskNone
@@ -294,10 +768,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loadingItem = ""
 		if msg.success {
 			m.message = "Junction '" + msg.name + "' created!"
-			m.screen = ScreenLoading
-			m.loadingTask = TaskSuggestions
-			m.loadingMessage = "Refreshing suggestions"
-			return m, tea.Batch(loadSuggestionsCmd(), tickCmd())
+			if msg.rewrite && !m.config.AdvisoryMode {
+				if scopes := path.ScopesContainingEntry(msg.target); len(scopes) > 0 {
+					m.junctionRewriteOld = msg.target
+					m.junctionRewriteNew = msg.junctionPath
+					m.junctionRewriteScopes = scopes
+					m.screen = ScreenJunctionRewriteConfirm
+					return m, nil
+				}
+			}
+			return m.startLoading(TaskSuggestions, "Refreshing suggestions", loadSuggestionsCmd())
 		}
 		m.err = msg.err
 		if msg.err != nil {
@@ -308,29 +788,155 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.screen = ScreenJunctionSuggestions
 		return m, nil
 
+	case junctionsBatchCreatedMsg:
+		m.loadingCurrent = 0
+		m.loadingTotal = 0
+		m.loadingItem = ""
+		m.err = errors.Join(msg.errs...)
+		if len(msg.errs) > 0 {
+			m.message = fmt.Sprintf("Created %d of %d (%d failed)", msg.created, msg.total, len(msg.errs))
+		} else {
+			m.message = fmt.Sprintf("Created %d of %d", msg.created, msg.total)
+		}
+		return m.startLoading(TaskSuggestions, "Refreshing suggestions", loadSuggestionsCmd())
+
 	case applyCompleteMsg:
 		m.loadingTask = TaskNone
 		m.loadingCurrent = 0
 		m.loadingTotal = 0
 		m.loadingItem = ""
+		m.backupFailed = msg.backupFailed
 		if msg.err != nil {
 			m.err = msg.err
 			m.message = "Failed to apply: " + msg.err.Error()
+			if msg.backupFailed {
+				m.message = "Backup failed - aborting, PATH unchanged: " + errors.Unwrap(msg.err).Error()
+			}
 			m.screen = ScreenOptimizerPreview
 		} else {
 			m.backupInfo = msg.backup
+			m.appliedScopes = msg.written
+			m.skippedScopes = msg.skipped
+			m.undoSnapshot = m.undoSnapshotFor(msg.written)
 			m.screen = ScreenOptimizerDone
+			m.message = ""
 			m.clipboardOK = false
+			if m.rollbackArmed {
+				return m.startRollbackTimer()
+			}
 		}
 		return m, nil
 
-	case tea.KeyMsg:
-		if msg.String() == "ctrl+c" {
+	case externalChangesMsg:
+		m.externalChanges = msg.changes
+		return m, nil
+
+	case relaunchMsg:
+		switch {
+		case msg.err == nil:
+			// The elevated copy takes over
+			return m, tea.Quit
+		case errors.Is(msg.err, path.ErrElevationCancelled):
+			m.err = nil
+			m.message = "Elevation cancelled, still running without admin rights"
+		default:
+			m.err = msg.err
+			m.message = "Could not relaunch as admin: " + msg.err.Error()
+		}
+		return m, nil
+
+	case autoFixPlanMsg:
+		if msg.err != nil {
+			return m.loadFailed("Checking recommended fixes", msg.err), nil
+		}
+		m.loadingTask = TaskNone
+		m.loadingCurrent = 0
+		m.loadingTotal = 0
+		m.loadingItem = ""
+		m.autoFixAnalysis = &msg.analysis
+		m.autoFixPathExt = &msg.pathExt
+		m.screen = ScreenAutoFixConfirm
+		return m, nil
+
+	case autoFixCompleteMsg:
+		m.loadingTask = TaskNone
+		if msg.err != nil {
+			m.err = msg.err
+			m.message = "Auto-fix failed: " + msg.err.Error()
+			if msg.backupFailed {
+				m.message = "Backup failed - aborting, nothing changed: " + errors.Unwrap(msg.err).Error()
+			}
+			m.screen = ScreenAutoFixConfirm
+			return m, nil
+		}
+		m.backupInfo = msg.backup
+		m.autoFixAnalysis = nil
+		m.autoFixPathExt = nil
+		m.message = ""
+		m.clipboardOK = false
+		m.screen = ScreenAutoFixDone
+		return m, nil
+
+	case rollbackRevertedMsg:
+		m.loadingTask = TaskNone
+		m.screen = ScreenOptimizerPreview
+		if len(msg.errs) > 0 {
+			m.err = fmt.Errorf("rollback failed: %s", strings.Join(msg.errs, "; "))
+			m.message = m.err.Error()
+			return m, nil
+		}
+		m.err = nil
+		m.message = msg.reason
+		if msg.quit {
 			return m, tea.Quit
 		}
+		return m, nil
+
+	case undoCompleteMsg:
+		m.loadingTask = TaskNone
+		if len(msg.errs) > 0 {
+			m.err = fmt.Errorf("%s", strings.Join(msg.errs, "; "))
+			m.message = "Undo failed: " + m.err.Error()
+			m.screen = ScreenOptimizerDone
+			return m, nil
+		}
+		m.err = nil
+		m.message = "Optimization undone, " + strings.Join(m.appliedScopes, " and ") + " PATH restored to before this apply"
+		m.backupInfo = nil
+		m.undoSnapshot = nil
+		m.analysis = nil
+		m.backups = path.ListBackups()
+		m.screen = ScreenBackupDone
+		m.clipboardOK = false
+		return m, nil
+
+	case rollbackTickMsg:
+		if m.screen != ScreenOptimizerRollback || msg.id != m.rollbackID {
+			return m, nil
+		}
+		m.rollbackRemaining--
+		if m.rollbackRemaining <= 0 {
+			return m.revertRollback("Timer expired: changes reverted", false)
+		}
+		return m, rollbackTickCmd(m.rollbackID)
+
+	case tea.KeyMsg:
 		if m.screen == ScreenLoading {
+			switch msg.String() {
+			case "ctrl+c", "esc":
+				if !m.loadingWrite {
+					return m.cancelLoading(), nil
+				}
+			}
 			return m, nil
 		}
+		if msg.String() == "ctrl+c" {
+			if m.screen == ScreenOptimizerRollback {
+				// Quitting must not leave unconfirmed changes in place, so quit once they're reverted
+				return m.revertRollback("Changes reverted", true)
+			}
+			return m, tea.Quit
+		}
 		return m.handleKey(msg)
 	}
 	return m, nil
@@ -339,21 +945,67 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m Model) handleKey(msg tea.KeyMsg) (Model, tea.Cmd) {
 	key := msg.String()
 
+	// Help is not offered during the rollback countdown, which only ticks on its own screen
+	if key == "?" && m.screen != ScreenHelp && m.screen != ScreenOptimizerRollback && !m.inTextInput() {
+		m.helpReturnScreen = m.screen
+		m.screen = ScreenHelp
+		return m, nil
+	}
+
+	// A paste arrives as one message holding every rune, so it goes in whole rather than key by key
+	if m.screen == ScreenHotPaths && m.hotPathAdding && msg.Type == tea.KeyRunes && len(msg.Runes) > 1 {
+		m.hotPathWarning = ""
+		return m.pasteHotPaths(string(msg.Runes)), nil
+	}
+
 	switch m.screen {
 	case ScreenMenu:
 		return m.handleMenuKey(key)
 	case ScreenOptimizer, ScreenOptimizerPreview:
 		return m.handleOptimizerKey(key)
-	case ScreenOptimizerConfirm:
+	case ScreenOptimizerConfirm, ScreenOptimizerConfirmReorder:
 		return m.handleOptimizerConfirmKey(key)
 	case ScreenOptimizerDone:
+		if key == "u" || key == "U" {
+			return m.handleUndoApplyKey(), nil
+		}
+		if key == "y" || key == "Y" {
+			return m.yankOptimizedPath(), nil
+		}
+		if (key == "e" || key == "E") && len(m.skippedScopes) > 0 {
+			return m.requestElevation("System PATH was skipped")
+		}
 		return m.handleDoneKey(key, ScreenMenu)
+	case ScreenOptimizerUndoConfirm:
+		return m.handleUndoConfirmKey(key)
+	case ScreenChangeDetail:
+		return m.handleChangeDetailKey(key)
+	case ScreenAuditLog:
+		return m.handleAuditLogKey(key), nil
+	case ScreenExternalChanges:
+		return m.handleExternalChangesKey(key), nil
+	case ScreenJunctionPreview:
+		return m.handleJunctionPreviewKey(key), nil
+	case ScreenJunctionExcludes:
+		return m.handleJunctionExcludesKey(key)
+	case ScreenEnvVars:
+		return m.handleEnvVarsKey(key), nil
+	case ScreenShortNames:
+		return m.handleShortNamesKey(key), nil
 	case ScreenPathViewer:
 		return m.handleViewerKey(key)
+	case ScreenPathEditor:
+		return m.handlePathEditorKey(key)
+	case ScreenPathEditorConfirm:
+		return m.handlePathEditorConfirmKey(key)
+	case ScreenProtectedOverride:
+		return m.handleProtectedOverrideKey(key)
 	case ScreenBackup:
 		return m.handleBackupKey(key)
 	case ScreenBackupPreview:
 		return m.handleBackupPreviewKey(key)
+	case ScreenBackupDiff:
+		return m.handleBackupDiffKey(key)
 	case ScreenBackupConfirmRestore, ScreenBackupConfirmDelete:
 		return m.handleBackupConfirmKey(key)
 	case ScreenBackupDone:
@@ -362,6 +1014,10 @@ func (m Model) handleKey(msg tea.KeyMsg) (Model, tea.Cmd) {
 		return m.handleJunctionsKey(key)
 	case ScreenJunctionSuggestions:
 		return m.handleJunctionSuggestionsKey(key)
+	case ScreenJunctionRewriteConfirm:
+		return m.handleJunctionRewriteKey(key)
+	case ScreenJunctionPruneConfirm:
+		return m.handleJunctionPruneKey(key)
 	case ScreenJunctionCreate:
 		return m.handleJunctionCreateKey(key)
 	case ScreenPathExt:
@@ -370,10 +1026,53 @@ func (m Model) handleKey(msg tea.KeyMsg) (Model, tea.Cmd) {
 		return m.handlePathExtConfirmKey(key)
 	case ScreenPathExtDone:
 		return m.handleDoneKey(key, ScreenMenu)
+	case ScreenAutoFixConfirm:
+		return m.handleAutoFixConfirmKey(key)
+	case ScreenAutoFixDone:
+		return m.handleDoneKey(key, ScreenMenu)
 	case ScreenSettings:
 		return m.handleSettingsKey(key)
 	case ScreenHotPaths:
 		return m.handleHotPathsKey(key)
+	case ScreenOptimizerRollback:
+		return m.handleRollbackKey(key)
+	case ScreenHelp:
+		return m.handleHelpKey(key)
+	}
+	return m, nil
+}
+
+// inTextInput reports whether keys are currently being typed into a text field
+func (m Model) inTextInput() bool {
+	switch m.screen {
+	case ScreenJunctionCreate:
+		return true
+	case ScreenHotPaths:
+		return m.hotPathAdding
+	case ScreenPathExt:
+		return m.pathExtAdding
+	case ScreenBackup:
+		return m.backupLabeling || m.backupImporting
+	case ScreenPathViewer:
+		return m.viewerImporting
+	case ScreenPathEditor:
+		return m.editorAdding
+	case ScreenSettings:
+		return m.settingsEditing
+	case ScreenJunctionSuggestions:
+		return m.suggestionTyping
+	case ScreenJunctionExcludes:
+		return m.excludeAdding
+	case ScreenEnvVars:
+		return m.envTyping
+	}
+	return false
+}
+
+func (m Model) handleHelpKey(key string) (Model, tea.Cmd) {
+	switch key {
+	case "esc", "q", "?":
+		m.screen = m.helpReturnScreen
 	}
 	return m, nil
 }
@@ -390,9 +1089,15 @@ func (m Model) handleMenuKey(key string) (Model, tea.Cmd) {
 		}
 	case "enter":
 		return m.selectMenuItem()
+	case "d", "D":
+		if m.externalChanges != nil {
+			m.scrollOffset = 0
+			m.message = ""
+			m.screen = ScreenExternalChanges
+		}
 	case "q", "esc":
 		return m, tea.Quit
-	case "1", "2", "3", "4", "5", "6", "7", "8":
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
 		idx := int(key[0] - '1')
 		if idx < len(m.menuItems) {
 			m.menuIndex = idx
@@ -405,23 +1110,27 @@ func (m Model) handleMenuKey(key string) (Model, tea.Cmd) {
 func (m Model) selectMenuItem() (Model, tea.Cmd) {
 	switch m.menuIndex {
 	case 0: // Optimize
-		m.screen = ScreenLoading
-		m.loadingTask = TaskAnalyze
-		m.loadingMessage = "Analyzing PATH"
-		return m, tea.Batch(analyzeCmd(), tickCmd())
+		// The cache check reads PATH, so it runs in the background like the analysis
+		key := ""
+		if m.cachedAnalysis != nil {
+			key = m.cachedAnalysisKey
+		}
+		return m.startLoading(TaskAnalyze, "Analyzing PATH", analyzeUnlessCachedCmd(key))
 	case 1: // View
 		m.screen = ScreenPathViewer
 		m.scrollOffset = 0
+		m.viewerIndex = 0
+		m.clipboardOK = false
+		m.message = ""
+		m, _ = m.loadViewer()
 	case 2: // Backup
 		m.screen = ScreenBackup
 		m.backups = path.ListBackups()
 		m.backupIndex = 0
+		m.compareIndex = -1
 		m.message = ""
 	case 3: // Junctions
-		m.screen = ScreenLoading
-		m.loadingTask = TaskJunctions
-		m.loadingMessage = "Loading junctions"
-		return m, tea.Batch(loadJunctionsCmd(), tickCmd())
+		return m.startLoading(TaskJunctions, "Loading junctions", loadJunctionsCmd())
 	case 4: // PATHEXT
 		m.screen = ScreenPathExt
 		analysis := path.AnalyzePathExt()
@@ -430,18 +1139,128 @@ func (m Model) selectMenuItem() (Model, tea.Cmd) {
 		m.pathExtOpt = &opt
 	case 5: // Hot Paths
 		m.screen = ScreenHotPaths
+		m = m.checkHotPaths()
+		if m.hotPathPreview {
+			m = m.loadHotPathPreview()
+		}
 	case 6: // Settings
 		m.screen = ScreenSettings
-	case 7: // Exit
+		m.message = ""
+	case 7: // Auto-fix
+		m.message = ""
+		m.err = nil
+		return m.startLoading(TaskAutoFix, "Checking recommended fixes", autoFixPlanCmd())
+	case 8: // Audit Log
+		m = m.openAuditLog()
+	case 9: // Environment Variables
+		m = m.openEnvVars()
+	case 10: // Exit
 		return m, tea.Quit
 	}
 	return m, nil
 }
 
+// envVar is one environment variable in the browser
+type envVar struct {
+	name     string
+	value    string
+	pathLike bool // A ;-separated list of folders, such as PATH or PSModulePath
+}
+
+// sortedEnvVars returns the variables sorted by name, case-insensitively
+func sortedEnvVars(vars map[string]string) []envVar {
+	list := make([]envVar, 0, len(vars))
+	for name, value := range vars {
+		list = append(list, envVar{name: name, value: value, pathLike: path.LooksLikePathList(name, value)})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return strings.ToLower(list[i].name) < strings.ToLower(list[j].name)
+	})
+	return list
+}
+
+// openEnvVars reads the environment once and shows the browser
+func (m Model) openEnvVars() Model {
+	m.screen = ScreenEnvVars
+	m.envVars = sortedEnvVars(path.GetAllEnvVars())
+	m.envIndex = 0
+	m.envFilter = ""
+	m.envExpanded = false
+	m.message = ""
+	return m
+}
+
+// visibleEnvVars returns the variables whose name or value contains the filter, case-insensitively
+func (m Model) visibleEnvVars() []envVar {
+	if m.envFilter == "" {
+		return m.envVars
+	}
+	filter := strings.ToLower(m.envFilter)
+	var visible []envVar
+	for _, v := range m.envVars {
+		if strings.Contains(strings.ToLower(v.name), filter) || strings.Contains(strings.ToLower(v.value), filter) {
+			visible = append(visible, v)
+		}
+	}
+	return visible
+}
+
+// handleEnvVarsKey moves through, filters and expands environment variables
+func (m Model) handleEnvVarsKey(key string) Model {
+	if m.envTyping {
+		switch key {
+		case "esc":
+			m.envTyping = false
+			m.envFilter = ""
+		case "enter":
+			m.envTyping = false
+		case "backspace":
+			if len(m.envFilter) > 0 {
+				m.envFilter = m.envFilter[:len(m.envFilter)-1]
+			}
+		default:
+			if r := []rune(key); len(r) == 1 && unicode.IsPrint(r[0]) {
+				m.envFilter += key
+			}
+		}
+		m.envIndex = 0
+		m.envExpanded = false
+		return m
+	}
+	visible := m.visibleEnvVars()
+	switch key {
+	case "esc", "q":
+		if key == "esc" && m.envFilter != "" {
+			m.envFilter = ""
+			m.envIndex = 0
+			m.envExpanded = false
+			return m
+		}
+		m.screen = ScreenMenu
+		m.envVars = nil
+	case "/":
+		m.envTyping = true
+	case "enter":
+		m.envExpanded = !m.envExpanded && len(visible) > 0
+	case "up", "k":
+		if m.envIndex > 0 {
+			m.envIndex--
+		}
+	case "down", "j":
+		if m.envIndex < len(visible)-1 {
+			m.envIndex++
+		}
+	case "g", "G", "home", "end", "pgup", "pgdown":
+		m.envIndex = jumpPosition(key, m.envIndex, len(visible)-1, listMaxVisible)
+	}
+	return m
+}
+
 // setViewMode sets the view mode and resets scroll
 func (m Model) setViewMode(mode int) Model {
 	m.viewMode = mode
 	m.scrollOffset = 0
+	m.changeIndex = 0
 	return m
 }
 
@@ -455,93 +1274,1431 @@ func (m Model) cycleScopeMode() Model {
 	default:
 		m.optimizerScope = "both"
 	}
+	m.config.LastOptimizerScope = m.optimizerScope
+	_ = path.SaveConfig(m.config) // Intentionally ignore error for UI config
 	return m
 }
 
+// optimizerPreset returns the name of the configured optimizer preset, "default" when unset or unknown
+func optimizerPreset(config path.Config) string {
+	for _, p := range path.OptimizePresets {
+		if strings.EqualFold(p.Name, config.OptimizerPreset) {
+			return p.Name
+		}
+	}
+	return path.OptimizePresets[0].Name
+}
+
+// cyclePreset switches to the next optimizer preset and re-analyzes with it
+func (m Model) cyclePreset() (Model, tea.Cmd) {
+	current := optimizerPreset(m.config)
+	for i, p := range path.OptimizePresets {
+		if p.Name == current {
+			m.config.OptimizerPreset = path.OptimizePresets[(i+1)%len(path.OptimizePresets)].Name
+			break
+		}
+	}
+	_ = path.SaveConfig(m.config) // Intentionally ignore error for UI config
+	return m.startAnalysis()
+}
+
+// startAnalysis runs a fresh analysis, bypassing the cache
+func (m Model) startAnalysis() (Model, tea.Cmd) {
+	return m.startLoading(TaskAnalyze, "Analyzing PATH", analyzeCmd())
+}
+
 func (m Model) handleOptimizerKey(key string) (Model, tea.Cmd) {
 	switch key {
 	case "esc", "q":
 		m.screen = ScreenMenu
 		m.analysis = nil
 		m.scrollOffset = 0
+		m.message = ""
 	case "1", "2", "3", "4":
 		mode := int(key[0] - '1')
 		m = m.setViewMode(mode)
 	case "s", "S":
 		m = m.cycleScopeMode()
+	case "p", "P":
+		return m.cyclePreset()
+	case "e", "E":
+		return m.requestElevation("System PATH changes need admin rights")
 	case "a", "A":
-		m.screen = ScreenOptimizerConfirm
-	case "up", "k":
+		if m.config.AdvisoryMode {
+			m.message = advisoryMessage
+			return m, nil
+		}
+		if m.needsAdminToApply() {
+			m.message = systemNeedsAdminMessage
+			return m, nil
+		}
+		m = m.openApplyConfirm()
+	case "r", "R":
+		return m.startAnalysis()
+	case "x", "X":
+		m = m.exportAnalysis()
+	case "w", "W":
+		m = m.exportOptimizedEntries()
+	case "c", "C":
+		m = m.copyOptimizedPath()
+	case "y", "Y":
+		m = m.yankOptimizedPath()
+	case "8":
+		m = m.openShortNames()
+	case "f", "F":
+		if m.viewMode == 1 {
+			m.changeFilterIndex = (m.changeFilterIndex + 1) % len(changeTypes)
+		}
+	case " ":
+		if m.viewMode == 1 {
+			m = m.toggleChangeType(changeTypes[m.changeFilterIndex].name)
+		}
+	case "enter":
+		if m.viewMode == 1 && len(m.visibleChanges()) > 0 {
+			m.screen = ScreenChangeDetail
+		}
+	case "up", "k":
+		if m.viewMode == 1 {
+			m = m.setChangeIndex(m.changeIndex - 1)
+		} else if m.scrollOffset > 0 {
+			m.scrollOffset--
+		}
+	case "down", "j":
+		if m.viewMode == 1 {
+			m = m.setChangeIndex(m.changeIndex + 1)
+		} else {
+			count, page := m.optimizerScrollWindow()
+			m.scrollOffset = clampScroll(m.scrollOffset+1, count, page)
+		}
+	case "g", "G", "home", "end", "pgup", "pgdown":
+		count, page := m.optimizerScrollWindow()
+		if m.viewMode == 1 {
+			m = m.setChangeIndex(jumpPosition(key, m.changeIndex, count-1, page))
+		} else {
+			m.scrollOffset = jumpPosition(key, m.scrollOffset, count-page, page)
+		}
+	}
+	return m, nil
+}
+
+// shortNameChanges lists every 8.3 conversion in the analysis, System first
+func (m Model) shortNameChanges() []scopedChange {
+	var changes []scopedChange
+	if m.analysis == nil {
+		return changes
+	}
+	for _, group := range []struct {
+		scope   string
+		changes []path.PathChange
+	}{{"SYS", m.analysis.System.Changes}, {"USR", m.analysis.User.Changes}} {
+		for _, c := range group.changes {
+			if c.Type == "shortened" {
+				changes = append(changes, scopedChange{scope: group.scope, change: c})
+			}
+		}
+	}
+	return changes
+}
+
+// shortNameKey identifies a change in shortNameRejected
+func shortNameKey(c scopedChange) string {
+	return c.scope + "|" + c.change.Original
+}
+
+// openShortNames lists the 8.3 conversions for review, all accepted to start with
+func (m Model) openShortNames() Model {
+	if len(m.shortNameChanges()) == 0 {
+		m.message = "No entries would be shortened to 8.3 names"
+		return m
+	}
+	m.shortNameRejected = map[string]bool{}
+	m.shortNameIndex = 0
+	m.message = ""
+	m.screen = ScreenShortNames
+	return m
+}
+
+// handleShortNamesKey accepts or rejects 8.3 conversions, then rebuilds the optimized PATH
+func (m Model) handleShortNamesKey(key string) Model {
+	changes := m.shortNameChanges()
+	switch key {
+	case "esc", "q":
+		m.screen = ScreenOptimizerPreview
+		m.message = ""
+	case "up", "k":
+		if m.shortNameIndex > 0 {
+			m.shortNameIndex--
+		}
+	case "down", "j":
+		if m.shortNameIndex < len(changes)-1 {
+			m.shortNameIndex++
+		}
+	case "g", "G", "home", "end", "pgup", "pgdown":
+		m.shortNameIndex = jumpPosition(key, m.shortNameIndex, len(changes)-1, listMaxVisible)
+	case " ":
+		if m.shortNameIndex < len(changes) {
+			k := shortNameKey(changes[m.shortNameIndex])
+			m.shortNameRejected[k] = !m.shortNameRejected[k]
+		}
+	case "a", "A":
+		m.shortNameRejected = map[string]bool{}
+	case "r", "R":
+		for _, c := range changes {
+			m.shortNameRejected[shortNameKey(c)] = true
+		}
+	case "enter":
+		m = m.applyShortNameChoices()
+	}
+	return m
+}
+
+// applyShortNameChoices rebuilds the optimized PATH with the rejected entries kept long
+// The cached analysis is left alone, so a refresh offers every conversion again
+func (m Model) applyShortNameChoices() Model {
+	rejected := map[string]map[string]bool{"SYS": {}, "USR": {}}
+	count := 0
+	for _, c := range m.shortNameChanges() {
+		if m.shortNameRejected[shortNameKey(c)] {
+			rejected[c.scope][c.change.Original] = true
+			count++
+		}
+	}
+	analysis := *m.analysis
+	analysis.System = path.RejectShortenings(analysis.System, rejected["SYS"])
+	analysis.User = path.RejectShortenings(analysis.User, rejected["USR"])
+	m.analysis = &analysis
+	m.changeIndex = 0
+	m.scrollOffset = 0
+	m.screen = ScreenOptimizerPreview
+	m.message = fmt.Sprintf("Kept %d long name(s); optimized PATH rebuilt", count)
+	return m
+}
+
+// scopedChange is a change together with the scope label it is listed under
+type scopedChange struct {
+	scope  string
+	change path.PathChange
+}
+
+// visibleChanges returns the changes shown in the Changes tab, System first
+func (m Model) visibleChanges() []scopedChange {
+	var changes []scopedChange
+	if m.analysis == nil {
+		return changes
+	}
+	for _, group := range []struct {
+		scope   string
+		changes []path.PathChange
+	}{{"SYS", m.analysis.System.Changes}, {"USR", m.analysis.User.Changes}} {
+		for _, c := range group.changes {
+			if !m.hiddenChangeTypes[c.Type] {
+				changes = append(changes, scopedChange{scope: group.scope, change: c})
+			}
+		}
+	}
+	return changes
+}
+
+// setChangeIndex selects a row in the Changes tab and scrolls it into view
+func (m Model) setChangeIndex(index int) Model {
+	count := len(m.visibleChanges())
+	m.changeIndex = max(0, min(index, count-1))
+	if m.changeIndex < m.scrollOffset {
+		m.scrollOffset = m.changeIndex
+	}
+	if m.changeIndex >= m.scrollOffset+changesMaxVisible {
+		m.scrollOffset = m.changeIndex - changesMaxVisible + 1
+	}
+	return m
+}
+
+// handleChangeDetailKey closes the change explanation
+func (m Model) handleChangeDetailKey(key string) (Model, tea.Cmd) {
+	switch key {
+	case "esc", "q", "enter":
+		m.screen = ScreenOptimizerPreview
+	}
+	return m, nil
+}
+
+// openAuditLog reads the audit log and shows it scrolled to the newest entries
+func (m Model) openAuditLog() Model {
+	lines, err := path.ReadAuditLog()
+	m.auditLines = lines
+	m.message = ""
+	if err != nil {
+		m.message = "Could not read audit log: " + err.Error()
+	}
+	m.scrollOffset = max(len(lines)-listMaxVisible, 0)
+	m.screen = ScreenAuditLog
+	return m
+}
+
+// handleAuditLogKey scrolls the audit log
+func (m Model) handleAuditLogKey(key string) Model {
+	switch key {
+	case "esc", "q":
+		m.screen = ScreenMenu
+		m.auditLines = nil
+		m.scrollOffset = 0
+		m.message = ""
+	case "r", "R":
+		m = m.openAuditLog()
+	case "up", "k":
+		if m.scrollOffset > 0 {
+			m.scrollOffset--
+		}
+	case "down", "j":
+		m.scrollOffset = clampScroll(m.scrollOffset+1, len(m.auditLines), listMaxVisible)
+	case "g", "G", "home", "end", "pgup", "pgdown":
+		m.scrollOffset = jumpPosition(key, m.scrollOffset, len(m.auditLines)-listMaxVisible, listMaxVisible)
+	}
+	return m
+}
+
+// checkExternalChangesCmd compares the live PATH with the last known state in the
+// background; the result is nil when it matches or can't be checked
+func checkExternalChangesCmd() tea.Cmd {
+	return func() tea.Msg {
+		changes, err := path.CheckExternalChanges()
+		if err != nil || changes == nil || changes.Count() == 0 {
+			return externalChangesMsg{}
+		}
+		return externalChangesMsg{changes: changes}
+	}
+}
+
+// externalChangeLines returns the rendered lines of the external changes
+func (m Model) externalChangeLines() []string {
+	if m.externalChanges == nil {
+		return nil
+	}
+	lines := renderPathDiffLines("System", m.externalChanges.System)
+	lines = append(lines, "")
+	return append(lines, renderPathDiffLines("User", m.externalChanges.User)...)
+}
+
+// handleExternalChangesKey scrolls the external changes, or accepts them by backing up
+func (m Model) handleExternalChangesKey(key string) Model {
+	lines := len(m.externalChangeLines())
+	switch key {
+	case "esc", "q":
+		m.screen = ScreenMenu
+		m.scrollOffset = 0
+		m.message = ""
+	case "b", "B":
+		if _, err := path.CreateLabeledBackup("accept external changes"); err != nil {
+			m.message = "Backup failed: " + err.Error()
+			return m
+		}
+		// The new backup holds the live PATH, so nothing differs any more
+		m.externalChanges = nil
+		m.screen = ScreenMenu
+		m.scrollOffset = 0
+		m.message = ""
+	case "up", "k":
 		if m.scrollOffset > 0 {
 			m.scrollOffset--
 		}
 	case "down", "j":
-		m.scrollOffset++
+		m.scrollOffset = clampScroll(m.scrollOffset+1, lines, listMaxVisible)
+	case "g", "G", "home", "end", "pgup", "pgdown":
+		m.scrollOffset = jumpPosition(key, m.scrollOffset, lines-listMaxVisible, listMaxVisible)
+	}
+	return m
+}
+
+// optimizerScrollWindow returns the item count and page size of the current optimizer tab
+func (m Model) optimizerScrollWindow() (int, int) {
+	if m.analysis == nil {
+		return 0, 0
+	}
+	switch m.viewMode {
+	case 1:
+		return len(m.visibleChanges()), changesMaxVisible
+	case 3:
+		if m.optimizerScope == "system" {
+			return len(m.analysis.System.Optimized.Entries), listMaxVisible
+		}
+		return len(m.analysis.User.Optimized.Entries), listMaxVisible
+	}
+	return 0, 0
+}
+
+// copyOptimizedPath copies the optimized raw PATH for the displayed scope
+// Like the Raw tab, "both" shows and copies the User scope
+func (m Model) copyOptimizedPath() Model {
+	if m.analysis == nil {
+		return m
+	}
+	data, label := m.analysis.User, "User"
+	if m.optimizerScope == "system" {
+		data, label = m.analysis.System, "System"
+	}
+	if err := path.CopyToClipboard(data.Optimized.Raw); err != nil {
+		m.err = err
+		m.message = "Copy failed: " + err.Error()
+		return m
+	}
+	m.err = nil
+	m.message = "Copied optimized " + label + " PATH to clipboard"
+	return m
+}
+
+// yankOptimizedPath copies the full optimized PATH for the selected scope.
+// Unlike copyOptimizedPath, "both" copies System then User under their own headings
+func (m Model) yankOptimizedPath() Model {
+	if m.analysis == nil {
+		m.message = "No analysis to copy"
+		return m
+	}
+	var text, label string
+	switch m.optimizerScope {
+	case "system":
+		text, label = m.analysis.System.Optimized.Raw, "System"
+	case "user":
+		text, label = m.analysis.User.Optimized.Raw, "User"
+	default:
+		text = "System PATH:\n" + m.analysis.System.Optimized.Raw + "\n\nUser PATH:\n" + m.analysis.User.Optimized.Raw
+		label = "System and User"
+	}
+	if err := path.CopyToClipboard(text); err != nil {
+		m.err = err
+		m.message = "Copy failed: " + err.Error()
+		return m
+	}
+	m.err = nil
+	m.message = "Yanked full optimized " + label + " PATH to clipboard"
+	return m
+}
+
+// copyJunctionTable copies a name -> target table of every junction for documentation
+func (m Model) copyJunctionTable() Model {
+	if len(m.junctions) == 0 {
+		m.message = "No junctions to copy"
+		return m
+	}
+	if err := path.CopyToClipboard(junctionTable(m.junctions)); err != nil {
+		m.err = err
+		m.message = "Copy failed: " + err.Error()
+		return m
+	}
+	m.err = nil
+	m.message = fmt.Sprintf("Copied %d junctions to clipboard", len(m.junctions))
+	return m
+}
+
+// junctionTable formats junctions as aligned plain-text columns
+func junctionTable(junctions []path.Junction) string {
+	width := len("Junction")
+	for _, j := range junctions {
+		width = max(width, len(j.Name))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-*s  Target\n", width, "Junction")
+	fmt.Fprintf(&b, "%s  %s\n", strings.Repeat("-", width), strings.Repeat("-", len("Target")))
+	for _, j := range junctions {
+		line := fmt.Sprintf("%-*s  %s", width, j.Name, j.Target)
+		if j.Broken {
+			line += " (broken)"
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// exportAnalysis writes the current analysis as JSON to the export directory
+func (m Model) exportAnalysis() Model {
+	if m.analysis == nil {
+		return m
+	}
+	file, err := path.SaveAnalysisExport(*m.analysis)
+	if err != nil {
+		m.err = err
+		m.message = "Export failed: " + err.Error()
+		return m
+	}
+	m.err = nil
+	m.message = "Exported to " + file
+	return m
+}
+
+// exportOptimizedEntries writes the optimized entries, one per line, for the selected scope
+// "both" writes one file per scope so each can be imported on its own
+func (m Model) exportOptimizedEntries() Model {
+	if m.analysis == nil {
+		return m
+	}
+	var files []string
+	for _, scope := range []struct {
+		name   string
+		result path.OptimizeResult
+	}{{"System", m.analysis.System}, {"User", m.analysis.User}} {
+		if m.optimizerScope != "both" && m.optimizerScope != strings.ToLower(scope.name) {
+			continue
+		}
+		file, err := path.SaveEntriesExport(scope.result.Optimized.Entries, scope.name, true)
+		if err != nil {
+			m.err = err
+			m.message = "Export failed: " + err.Error()
+			return m
+		}
+		files = append(files, file)
+	}
+	m.err = nil
+	m.message = "Wrote entries to " + strings.Join(files, " and ")
+	return m
+}
+
+// reorderOnly reports whether applying in the current scope would only move entries
+func (m Model) reorderOnly() bool {
+	if m.analysis == nil {
+		return false
+	}
+	var changes []path.PathChange
+	if m.optimizerScope == "both" || m.optimizerScope == "user" {
+		changes = append(changes, m.analysis.User.Changes...)
+	}
+	if m.optimizerScope == "both" || m.optimizerScope == "system" {
+		changes = append(changes, m.analysis.System.Changes...)
+	}
+	return path.IsReorderOnly(changes)
+}
+
+func (m Model) handleOptimizerConfirmKey(key string) (Model, tea.Cmd) {
+	if m.config.AdvisoryMode {
+		m.screen = ScreenOptimizerPreview
+		m.message = advisoryMessage
+		return m, nil
+	}
+	if m.screen == ScreenOptimizerConfirmReorder && key == "enter" {
+		key = "y"
+	}
+	switch key {
+	case "y", "Y", "t", "T", "!":
+		if m.needsAdminToApply() {
+			m.screen = ScreenOptimizerPreview
+			m.message = systemNeedsAdminMessage
+			return m, nil
+		}
+		if m.unresolvedProtected() > 0 {
+			m.screen = ScreenProtectedOverride
+			m.protectedIndex = 0
+			return m, nil
+		}
+	}
+	if handled, next := m.handleExactValueKey(key); handled {
+		return next, nil
+	}
+	m.confirmShowValue = false
+	switch key {
+	case "y", "Y":
+		m.rollbackArmed = false
+		return m.startWriting(TaskAnalyze, "Applying optimization", applyOptimizationCmd(m.analysis, m.optimizerScope, m.isAdmin, false))
+	case "t", "T":
+		m.rollbackArmed = true
+		m.rollbackSnapshot = m.rollbackSnapshotFor(m.optimizerScope)
+		return m.startWriting(TaskAnalyze, "Applying optimization (with rollback timer)", applyOptimizationCmd(m.analysis, m.optimizerScope, m.isAdmin, false))
+	case "!":
+		if m.backupFailed {
+			m.rollbackArmed = false
+			return m.startWriting(TaskAnalyze, "Applying optimization without backup", applyOptimizationCmd(m.analysis, m.optimizerScope, m.isAdmin, true))
+		}
+	case "n", "N", "esc":
+		m.screen = ScreenOptimizerPreview
+	}
+	return m, nil
+}
+
+// openApplyConfirm moves to the apply confirm, or to the protected entry overrides first
+// when the optimization would remove or rewrite a protected entry
+func (m Model) openApplyConfirm() Model {
+	switch {
+	case m.unresolvedProtected() > 0:
+		m.screen = ScreenProtectedOverride
+		m.protectedIndex = 0
+	case m.reorderOnly():
+		m.screen = ScreenOptimizerConfirmReorder
+	default:
+		m.screen = ScreenOptimizerConfirm
+	}
+	return m
+}
+
+// protectedItem is a protected entry violation in the scope it applies to
+type protectedItem struct {
+	scope string
+	path.ProtectedViolation
+}
+
+// key identifies the item in protectedOverrides
+func (p protectedItem) key() string {
+	return p.scope + "|" + p.Entry
+}
+
+// protectedViolations lists the violations in the scopes an apply would write
+func (m Model) protectedViolations() []protectedItem {
+	if m.analysis == nil {
+		return nil
+	}
+	var items []protectedItem
+	if m.optimizerScope == "both" || m.optimizerScope == "user" {
+		for _, v := range m.analysis.User.ProtectedViolations {
+			items = append(items, protectedItem{"User", v})
+		}
+	}
+	if m.isAdmin && (m.optimizerScope == "both" || m.optimizerScope == "system") {
+		for _, v := range m.analysis.System.ProtectedViolations {
+			items = append(items, protectedItem{"System", v})
+		}
+	}
+	return items
+}
+
+// unresolvedProtected counts violations that have not been overridden yet
+func (m Model) unresolvedProtected() int {
+	n := 0
+	for _, item := range m.protectedViolations() {
+		if !m.protectedOverrides[item.key()] {
+			n++
+		}
+	}
+	return n
+}
+
+// handleProtectedOverrideKey handles the per-entry override list for protected entries
+func (m Model) handleProtectedOverrideKey(key string) (Model, tea.Cmd) {
+	items := m.protectedViolations()
+	switch key {
+	case "esc", "n", "N", "q":
+		m.screen = ScreenOptimizerPreview
+		m.message = ""
+	case "up", "k":
+		if m.protectedIndex > 0 {
+			m.protectedIndex--
+		}
+	case "down", "j":
+		if m.protectedIndex < len(items)-1 {
+			m.protectedIndex++
+		}
+	case " ", "o", "O":
+		if m.protectedIndex < len(items) {
+			overrides := make(map[string]bool, len(m.protectedOverrides)+1)
+			for k, v := range m.protectedOverrides {
+				overrides[k] = v
+			}
+			k := items[m.protectedIndex].key()
+			overrides[k] = !overrides[k]
+			m.protectedOverrides = overrides
+		}
+	case "enter", "a", "A":
+		if n := m.unresolvedProtected(); n > 0 {
+			m.message = fmt.Sprintf("Override all protected entries to continue (%d left)", n)
+			return m, nil
+		}
+		m.message = ""
+		m = m.openApplyConfirm()
+	}
+	return m, nil
+}
+
+// pendingPathWrite is a PATH value an apply confirm is about to write
+type pendingPathWrite struct {
+	scope string
+	value string
+}
+
+// pendingPathWrites returns the exact values the current confirm screen would pass to SetPath
+func (m Model) pendingPathWrites() []pendingPathWrite {
+	var writes []pendingPathWrite
+	switch m.screen {
+	case ScreenOptimizerConfirm, ScreenOptimizerConfirmReorder:
+		if m.analysis == nil {
+			return nil
+		}
+		if m.optimizerScope == "both" || m.optimizerScope == "user" {
+			writes = append(writes, pendingPathWrite{"User", m.analysis.User.Optimized.Raw})
+		}
+		if m.isAdmin && (m.optimizerScope == "both" || m.optimizerScope == "system") {
+			writes = append(writes, pendingPathWrite{"System", m.analysis.System.Optimized.Raw})
+		}
+	case ScreenPathEditorConfirm:
+		writes = append(writes, pendingPathWrite{m.editorScope, path.JoinPath(m.editorEntries)})
+	}
+	return writes
+}
+
+// exactValueLines wraps the pending values into the lines shown by the exact value preview
+func (m Model) exactValueLines() []string {
+	var lines []string
+	for _, w := range m.pendingPathWrites() {
+		lines = append(lines, SubtitleStyle.Render(fmt.Sprintf("%s PATH (%d chars):", w.scope, len(w.value))))
+		lines = append(lines, strings.Split(wrapText(w.value, 72), "\n")...)
+	}
+	return lines
+}
+
+// handleExactValueKey toggles and scrolls the exact value preview on apply confirms
+func (m Model) handleExactValueKey(key string) (bool, Model) {
+	switch key {
+	case "v", "V":
+		m.confirmShowValue = !m.confirmShowValue
+		m.confirmScroll = 0
+		return true, m
+	case "up", "k":
+		if m.confirmShowValue {
+			m.confirmScroll = max(m.confirmScroll-1, 0)
+			return true, m
+		}
+	case "down", "j":
+		if m.confirmShowValue {
+			m.confirmScroll = min(m.confirmScroll+1, max(len(m.exactValueLines())-listMaxVisible, 0))
+			return true, m
+		}
+	}
+	return false, m
+}
+
+// renderExactValue shows the full raw values to be written, or the key to reveal them
+func (m Model) renderExactValue() string {
+	if !m.confirmShowValue {
+		return "\n\n" + RenderKey("V", "Show exact value")
+	}
+	lines := m.exactValueLines()
+	start := min(m.confirmScroll, max(len(lines)-listMaxVisible, 0))
+	end := min(start+listMaxVisible, len(lines))
+
+	var b strings.Builder
+	b.WriteString("\n\n")
+	if start > 0 {
+		b.WriteString(DimStyle.Render(fmt.Sprintf("... %d lines above", start)) + "\n")
+	}
+	for _, line := range lines[start:end] {
+		b.WriteString(NormalStyle.Render(line) + "\n")
+	}
+	if end < len(lines) {
+		b.WriteString(DimStyle.Render(fmt.Sprintf("... %d lines below (j/k to scroll)", len(lines)-end)) + "\n")
+	}
+	b.WriteString(RenderKey("V", "Hide exact value"))
+	return b.String()
+}
+
+// renderRegistryTargets lists the registry key and new PATH length of each pending write
+func (m Model) renderRegistryTargets() string {
+	var b strings.Builder
+	for _, w := range m.pendingPathWrites() {
+		key := path.UserPathKey
+		if w.scope == "System" {
+			key = path.SystemPathKey
+		}
+		b.WriteString("\n" + DimStyle.Render(w.scope+": ") + NormalStyle.Render(key+`\Path`) + DimStyle.Render(fmt.Sprintf(" (%d chars)", len(w.value))))
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "\n\n" + SubtitleStyle.Render("Registry values written:") + b.String()
+}
+
+// maintenanceReminder returns the configured reminder for confirms that write System PATH
+func (m Model) maintenanceReminder(writesSystem bool) string {
+	text := strings.TrimSpace(m.config.MaintenanceReminder)
+	if !writesSystem || text == "" {
+		return ""
+	}
+	return "\n\n" + WarningStyle.Render(text)
+}
+
+// rollbackSnapshotFor captures the last-good raw PATH for each scope the apply will write
+func (m Model) rollbackSnapshotFor(scope string) map[string]string {
+	snapshot := make(map[string]string)
+	if m.analysis == nil {
+		return snapshot
+	}
+	if scope == "both" || scope == "user" {
+		snapshot["User"] = m.analysis.User.Original.Raw
+	}
+	if m.isAdmin && (scope == "both" || scope == "system") {
+		snapshot["System"] = m.analysis.System.Original.Raw
+	}
+	return snapshot
+}
+
+// startRollbackTimer shows the keep/revert countdown after an armed apply
+func (m Model) startRollbackTimer() (Model, tea.Cmd) {
+	m.rollbackArmed = false
+	m.rollbackID++
+	m.rollbackRemaining = m.rollbackSeconds()
+	m.screen = ScreenOptimizerRollback
+	return m, rollbackTickCmd(m.rollbackID)
+}
+
+// undoSnapshotFor returns the pre-apply PATH of the written scopes
+func (m Model) undoSnapshotFor(written []string) map[string]string {
+	if m.analysis == nil || len(written) == 0 {
+		return nil
+	}
+	snapshot := make(map[string]string, len(written))
+	for _, scope := range written {
+		if scope == "System" {
+			snapshot[scope] = m.analysis.System.Original.Raw
+		} else {
+			snapshot[scope] = m.analysis.User.Original.Raw
+		}
+	}
+	return snapshot
+}
+
+// writeSnapshot writes each scope's PATH in snapshot back and reports the scopes that failed
+func writeSnapshot(snapshot map[string]string) []string {
+	var errs []string
+	for _, scope := range []string{"User", "System"} {
+		raw, ok := snapshot[scope]
+		if !ok {
+			continue
+		}
+		if err := path.SetPath(raw, scope); err != nil {
+			errs = append(errs, scope+": "+err.Error())
+		}
+	}
+	path.BroadcastEnvChange()
+	return errs
+}
+
+// revertRollback writes the snapshot back in the background; quit exits once it succeeds
+func (m Model) revertRollback(reason string, quit bool) (Model, tea.Cmd) {
+	snapshot := m.rollbackSnapshot
+	m.rollbackSnapshot = nil
+	m.rollbackRemaining = 0
+	return m.startWriting(TaskAnalyze, "Reverting changes", func() tea.Msg { // reuse
+		return rollbackRevertedMsg{reason: reason, errs: writeSnapshot(snapshot), quit: quit}
+	})
+}
+
+func (m Model) handleRollbackKey(key string) (Model, tea.Cmd) {
+	switch key {
+	case "k", "K", "y", "Y":
+		m.rollbackSnapshot = nil
+		m.rollbackRemaining = 0
+		m.screen = ScreenOptimizerDone
+		m.message = ""
+		m.clipboardOK = false
+	case "r", "R", "n", "N", "esc":
+		return m.revertRollback("Changes reverted", false)
+	}
+	return m, nil
+}
+
+func (m Model) handleDoneKey(key string, backTo Screen) (Model, tea.Cmd) {
+	switch key {
+	case "c", "C":
+		err := path.CopyToClipboard(path.GetRefreshCommand())
+		m.clipboardOK = err == nil
+	case "esc", "q":
+		m.screen = backTo
+		m.analysis = nil
+		if backTo == ScreenBackup {
+			m.backups = path.ListBackups()
+		}
+	}
+	return m, nil
+}
+
+// handleUndoApplyKey asks to write back the PATH as it was just before the last apply
+// Undo only needs the snapshot taken at apply time, so it works without a backup too
+func (m Model) handleUndoApplyKey() Model {
+	if len(m.undoSnapshot) == 0 {
+		m.message = "Nothing was written by this apply, nothing to undo"
+		return m
+	}
+	m.message = ""
+	m.screen = ScreenOptimizerUndoConfirm
+	return m
+}
+
+// handleUndoConfirmKey writes back the scopes the last apply changed
+func (m Model) handleUndoConfirmKey(key string) (Model, tea.Cmd) {
+	switch key {
+	case "y", "Y":
+		snapshot := m.undoSnapshot
+		return m.startWriting(TaskAnalyze, "Undoing last apply", func() tea.Msg { // reuse
+			return undoCompleteMsg{errs: writeSnapshot(snapshot)}
+		})
+	case "n", "N", "esc":
+		m.screen = ScreenOptimizerDone
+	}
+	return m, nil
+}
+
+// advisoryMessage is shown when an apply action is attempted in advisory mode
+const advisoryMessage = "Advisory mode: applying changes is disabled"
+
+// systemNeedsAdminMessage is shown when applying a scope that includes the System PATH without admin rights
+const systemNeedsAdminMessage = "Applying the System PATH needs admin rights: press E to relaunch WinPath as administrator, or S for the user scope"
+
+// requestElevation relaunches WinPath as administrator; the UAC prompt is the confirmation
+func (m Model) requestElevation(reason string) (Model, tea.Cmd) {
+	if m.isAdmin {
+		return m, nil
+	}
+	m.err = nil
+	m.message = reason + ", relaunching as administrator..."
+	return m, relaunchAsAdminCmd()
+}
+
+// needsAdminToApply reports whether the optimizer scope writes the System PATH without admin rights
+func (m Model) needsAdminToApply() bool {
+	return !m.isAdmin && (m.optimizerScope == "both" || m.optimizerScope == "system")
+}
+
+// systemSkipNote warns on the apply confirms that the scope cannot be applied without admin rights
+func (m Model) systemSkipNote() string {
+	if !m.needsAdminToApply() {
+		return ""
+	}
+	return "\n\n" + ErrorStyle.Render("Not running as admin: the System PATH cannot be written.") +
+		"\n" + DimStyle.Render("Cancel and press E to relaunch WinPath as administrator, or S for the user scope.")
+}
+
+// appliedSummary describes which scopes the last optimizer apply wrote
+func (m Model) appliedSummary() string {
+	if len(m.skippedScopes) == 0 {
+		return "PATH optimization applied successfully!"
+	}
+	skipped := strings.Join(m.skippedScopes, ", ") + " skipped (needs admin)"
+	if len(m.appliedScopes) == 0 {
+		return "Nothing applied; " + skipped
+	}
+	return strings.Join(m.appliedScopes, ", ") + " applied; " + skipped
+}
+
+// Rows shown at once in the windowed lists, also used as the PageUp/PageDown step
+const (
+	viewerMaxVisible      = 18
+	changesMaxVisible     = 12
+	listMaxVisible        = 16
+	suggestionsMaxVisible = 12
+	defaultPageSize       = 10 // for lists that are not windowed
+)
+
+// jumpPosition applies a top/bottom/page key to a position in [0, last]
+func jumpPosition(key string, pos, last, page int) int {
+	switch key {
+	case "g", "home":
+		pos = 0
+	case "G", "end":
+		pos = last
+	case "pgup":
+		pos -= page
+	case "pgdown":
+		pos += page
+	}
+	if pos > last {
+		pos = last
+	}
+	if pos < 0 {
+		pos = 0
+	}
+	return pos
+}
+
+// clampScroll keeps a scroll offset between 0 and the last full page of count items
+func clampScroll(offset, count, page int) int {
+	return max(0, min(offset, count-page))
+}
+
+// referencingVarsMessage describes which environment variables reference a PATH entry
+func referencingVarsMessage(entry string) string {
+	names := path.FindVarsReferencing(entry)
+	if len(names) == 0 {
+		return "No environment variables reference " + entry
+	}
+	return "Referenced by: " + strings.Join(names, ", ")
+}
+
+// viewerScopes is the order the path viewer cycles through with S
+// Process is the PATH this WinPath process inherited, i.e. what its parent shell sees
+var viewerScopes = []string{"User", "System", "Process"}
+
+// nextViewerScope returns the scope after scope in viewerScopes
+func nextViewerScope(scope string) string {
+	for i, s := range viewerScopes {
+		if s == scope {
+			return viewerScopes[(i+1)%len(viewerScopes)]
+		}
+	}
+	return viewerScopes[0]
+}
+
+// readViewerPath reads the PATH for a viewer scope
+// The Process PATH is already expanded, so it reads the same either way
+func readViewerPath(scope string, expanded bool) (string, error) {
+	switch {
+	case scope == "Process":
+		return os.Getenv("PATH"), nil
+	case expanded:
+		return path.GetPathExpanded(scope)
+	default:
+		return path.GetPathRaw(scope)
+	}
+}
+
+// loadViewer reads the viewed scope once so scrolling and rendering don't re-read it
+func (m Model) loadViewer() (Model, error) {
+	raw, err := readViewerPath(m.viewerScope, m.viewerExpanded)
+	m.viewerRaw = raw
+	m.viewerLoaded = true
+	return m, err
+}
+
+// viewerPath returns the PATH string shown in the path viewer
+func (m Model) viewerPath() string {
+	return m.viewerRaw
+}
+
+// viewerEntries returns the entries shown in the path viewer
+func (m Model) viewerEntries() []string {
+	return path.ParsePath(m.viewerPath())
+}
+
+// refreshViewer re-reads the viewed scope from the registry and returns to the top
+// so entries changed outside WinPath don't leave the selection on a stale row
+func (m Model) refreshViewer() Model {
+	m.scrollOffset = 0
+	m.viewerIndex = 0
+	m, err := m.loadViewer()
+	if err != nil {
+		m.message = "Refresh failed: " + err.Error()
+		return m
+	}
+	m.message = fmt.Sprintf("Reloaded %s PATH (%d entries)", m.viewerScope, len(m.viewerEntries()))
+	return m
+}
+
+// setViewerIndex moves the viewer selection and scrolls to keep it visible
+func (m Model) setViewerIndex(index, count int) Model {
+	if index > count-1 {
+		index = count - 1
+	}
+	if index < 0 {
+		index = 0
+	}
+	m.viewerIndex = index
+	if m.viewerIndex < m.scrollOffset {
+		m.scrollOffset = m.viewerIndex
+	}
+	if m.viewerIndex >= m.scrollOffset+viewerMaxVisible {
+		m.scrollOffset = m.viewerIndex - viewerMaxVisible + 1
+	}
+	return m
+}
+
+// editorBlockedReason explains why the viewed scope cannot be edited, or returns ""
+func (m Model) editorBlockedReason() string {
+	if m.viewerScope == "Process" {
+		return "The Process PATH is read-only, switch to User or System to edit"
+	}
+	if m.viewerScope == "System" && !m.isAdmin {
+		return "Editing System PATH requires administrator privileges"
+	}
+	return ""
+}
+
+// openPathEditor starts editing the raw entries of the viewed scope
+// Raw entries are edited so variables such as %USERPROFILE% are kept
+func (m Model) openPathEditor() Model {
+	if reason := m.editorBlockedReason(); reason != "" {
+		m.message = reason
+		return m
+	}
+	raw, err := path.GetPathRaw(m.viewerScope)
+	if err != nil {
+		m.message = "Could not read " + m.viewerScope + " PATH: " + err.Error()
+		return m
+	}
+	m.editorScope = m.viewerScope
+	m.editorOriginal = path.ParsePath(raw)
+	m.editorEntries = append([]string{}, m.editorOriginal...)
+	m.editorIndex = min(m.viewerIndex, max(len(m.editorEntries)-1, 0))
+	m.editorAdding = false
+	m.editorInput = ""
+	m.err = nil
+	m.screen = ScreenPathEditor
+	return m
+}
+
+// editorChanged reports whether the edited entries differ from the original PATH
+func (m Model) editorChanged() bool {
+	return path.JoinPath(m.editorEntries) != path.JoinPath(m.editorOriginal)
+}
+
+// handlePathEditorKey handles keys in the PATH editor
+func (m Model) handlePathEditorKey(key string) (Model, tea.Cmd) {
+	if m.editorAdding {
+		return m.handlePathEditorInputKey(key), nil
+	}
+	m.message = ""
+	switch key {
+	case "esc", "q":
+		m.screen = ScreenPathViewer
+		m.editorEntries = nil
+		m.editorOriginal = nil
+	case "up", "k":
+		if m.editorIndex > 0 {
+			m.editorIndex--
+		}
+	case "down", "j":
+		if m.editorIndex < len(m.editorEntries)-1 {
+			m.editorIndex++
+		}
+	case "g", "G", "home", "end", "pgup", "pgdown":
+		m.editorIndex = jumpPosition(key, m.editorIndex, len(m.editorEntries)-1, viewerMaxVisible)
+	case "K", "U", "u":
+		m = m.movePathEditorEntry(-1)
+	case "J", "D", "d":
+		m = m.movePathEditorEntry(1)
+	case "x", "X", "delete":
+		m = m.removePathEditorEntry()
+	case "i", "I", "+":
+		m.editorAdding = true
+		m.editorInput = ""
+		m.err = nil
+	case "a", "A":
+		switch {
+		case m.config.AdvisoryMode:
+			m.message = advisoryMessage
+		case !m.editorChanged():
+			m.message = "No changes to apply"
+		default:
+			m.screen = ScreenPathEditorConfirm
+		}
+	}
+	return m, nil
+}
+
+// handlePathEditorInputKey handles keys while typing a new PATH entry
+func (m Model) handlePathEditorInputKey(key string) Model {
+	switch key {
+	case "esc":
+		m.editorAdding = false
+		m.editorInput = ""
+		m.err = nil
+		m.message = ""
+	case "enter":
+		m = m.insertPathEditorEntry()
+	case "backspace":
+		if len(m.editorInput) > 0 {
+			m.editorInput = m.editorInput[:len(m.editorInput)-1]
+		}
+	default:
+		if len(key) == 1 && key[0] >= 32 && key[0] <= 126 {
+			m.editorInput += key
+		}
+	}
+	return m
+}
+
+// insertPathEditorEntry validates the typed directory and inserts it above the selection
+func (m Model) insertPathEditorEntry() Model {
+	entry := strings.Trim(strings.TrimSpace(m.editorInput), `"`)
+	m.err = nil
+	switch {
+	case entry == "":
+		m.err = fmt.Errorf("enter a directory")
+	case strings.Contains(entry, ";"):
+		m.err = fmt.Errorf("an entry cannot contain ';'")
+	default:
+		for _, existing := range m.editorEntries {
+			if path.NormalizePath(existing) == path.NormalizePath(entry) {
+				m.err = fmt.Errorf("%s is already in PATH", entry)
+				break
+			}
+		}
+	}
+	if m.err != nil {
+		m.message = m.err.Error()
+		return m
+	}
+
+	list := make([]string, 0, len(m.editorEntries)+1)
+	list = append(list, m.editorEntries[:m.editorIndex]...)
+	list = append(list, entry)
+	list = append(list, m.editorEntries[m.editorIndex:]...)
+	m.editorEntries = list
+	m.editorAdding = false
+	m.editorInput = ""
+	m.message = entry + " added"
+	if !path.PathExists(entry) {
+		m.message += " (directory does not exist)"
+	}
+	return m
+}
+
+// movePathEditorEntry moves the selected entry by delta positions
+func (m Model) movePathEditorEntry(delta int) Model {
+	to := m.editorIndex + delta
+	if to < 0 || to >= len(m.editorEntries) {
+		return m
+	}
+	m.editorEntries = append([]string{}, m.editorEntries...)
+	m.editorEntries[m.editorIndex], m.editorEntries[to] = m.editorEntries[to], m.editorEntries[m.editorIndex]
+	m.editorIndex = to
+	return m
+}
+
+// removePathEditorEntry deletes the selected entry
+func (m Model) removePathEditorEntry() Model {
+	if len(m.editorEntries) == 0 {
+		return m
+	}
+	removed := m.editorEntries[m.editorIndex]
+	list := make([]string, 0, len(m.editorEntries)-1)
+	list = append(list, m.editorEntries[:m.editorIndex]...)
+	m.editorEntries = append(list, m.editorEntries[m.editorIndex+1:]...)
+	if m.editorIndex >= len(m.editorEntries) && m.editorIndex > 0 {
+		m.editorIndex--
+	}
+	m.message = removed + " removed"
+	return m
+}
+
+// handlePathEditorConfirmKey backs up and writes the edited PATH
+func (m Model) handlePathEditorConfirmKey(key string) (Model, tea.Cmd) {
+	if handled, next := m.handleExactValueKey(key); handled {
+		return next, nil
+	}
+	m.confirmShowValue = false
+	switch key {
+	case "y", "Y":
+		if m.config.AdvisoryMode {
+			m.message = advisoryMessage
+			m.screen = ScreenPathEditor
+			return m, nil
+		}
+		backup, err := path.CreateBackup("pre-edit")
+		if err != nil {
+			m.err = err
+			m.message = "Backup failed, PATH unchanged: " + err.Error()
+			m.screen = ScreenPathEditor
+			return m, nil
+		}
+		if err := path.SetPath(path.JoinPath(m.editorEntries), m.editorScope); err != nil {
+			m.err = err
+			m.message = "Failed to write " + m.editorScope + " PATH: " + err.Error()
+			m.screen = ScreenPathEditor
+			return m, nil
+		}
+		path.BroadcastEnvChange()
+		m.err = nil
+		m.message = m.editorScope + " PATH updated (backup: " + backup.Filename + ")"
+		m.viewerScope = m.editorScope
+		m.viewerIndex = 0
+		m.scrollOffset = 0
+		m.editorEntries = nil
+		m.editorOriginal = nil
+		m.screen = ScreenPathViewer
+		m, _ = m.loadViewer()
+	case "n", "N", "esc":
+		m.screen = ScreenPathEditor
+	}
+	return m, nil
+}
+
+func (m Model) handleViewerKey(key string) (Model, tea.Cmd) {
+	if m.viewerImporting {
+		return m.handleViewerImportKey(key), nil
+	}
+	if key != "c" && key != "C" {
+		m.clipboardOK = false
+	}
+	m.message = ""
+	if !m.viewerLoaded {
+		m, _ = m.loadViewer()
+	}
+	switch key {
+	case "esc", "q":
+		m.screen = ScreenMenu
+		m.scrollOffset = 0
+		m.viewerIndex = 0
+	case "s", "S":
+		m.viewerScope = nextViewerScope(m.viewerScope)
+		m.config.LastViewerScope = m.viewerScope
+		_ = path.SaveConfig(m.config) // Intentionally ignore error for UI config
+		m.scrollOffset = 0
+		m.viewerIndex = 0
+		m, _ = m.loadViewer()
+	case "e":
+		m.viewerExpanded = !m.viewerExpanded
+		m, _ = m.loadViewer()
+	case "E":
+		if m.viewerScope == "System" && !m.isAdmin {
+			return m.requestElevation("Editing System PATH requires administrator privileges")
+		}
+		m = m.openPathEditor()
+	case "r", "R":
+		m = m.refreshViewer()
+	case "l", "L":
+		if m.viewerScope == "System" && !m.isAdmin {
+			return m.requestElevation("Replacing System PATH requires administrator privileges")
+		}
+		if reason := m.editorBlockedReason(); reason != "" {
+			m.message = reason
+			break
+		}
+		m.viewerImporting = true
+		m.viewerImportInput = ""
+	case "c", "C":
+		entries := m.viewerEntries()
+		if m.viewerIndex < len(entries) {
+			err := path.CopyToClipboard(entries[m.viewerIndex])
+			m.clipboardOK = err == nil
+		}
+	case "w", "W":
+		file, err := path.SaveEntriesExport(m.viewerEntries(), m.viewerScope, false)
+		if err != nil {
+			m.message = "Export failed: " + err.Error()
+		} else {
+			m.message = "Wrote entries to " + file
+		}
+	case "f", "F":
+		entries := m.viewerEntries()
+		if m.viewerIndex < len(entries) {
+			m.message = referencingVarsMessage(entries[m.viewerIndex])
+		}
+	case "up", "k":
+		if m.viewerIndex > 0 {
+			m = m.setViewerIndex(m.viewerIndex-1, m.viewerIndex)
+		}
+	case "down", "j":
+		m = m.setViewerIndex(m.viewerIndex+1, len(m.viewerEntries()))
+	case "g", "G", "home", "end", "pgup", "pgdown":
+		count := len(m.viewerEntries())
+		m = m.setViewerIndex(jumpPosition(key, m.viewerIndex, count-1, viewerMaxVisible), count)
+	}
+	return m, nil
+}
+
+// handleViewerImportKey handles keys while typing the path of an entries file to load
+func (m Model) handleViewerImportKey(key string) Model {
+	switch key {
+	case "esc":
+		m.viewerImporting = false
+		m.viewerImportInput = ""
+	case "enter":
+		m.viewerImporting = false
+		m = m.loadEntriesFile()
+		m.viewerImportInput = ""
+	case "backspace":
+		if len(m.viewerImportInput) > 0 {
+			m.viewerImportInput = m.viewerImportInput[:len(m.viewerImportInput)-1]
+		}
+	default:
+		if len(key) == 1 && key[0] >= 32 && key[0] <= 126 {
+			m.viewerImportInput += key
+		}
+	}
+	return m
+}
+
+// loadEntriesFile reads a newline-delimited list of directories and opens it in the
+// PATH editor as a replacement for the viewed scope, so it is previewed before applying
+func (m Model) loadEntriesFile() Model {
+	// Explorer's "Copy as path" wraps the path in quotes
+	src := strings.Trim(strings.TrimSpace(m.viewerImportInput), `"`)
+	if src == "" {
+		return m
+	}
+	entries, err := path.ImportEntriesFile(src)
+	if err != nil {
+		m.message = "Load failed: " + err.Error()
+		return m
 	}
-	return m, nil
+	m = m.openPathEditor()
+	if m.screen != ScreenPathEditor {
+		return m
+	}
+	m.editorEntries = entries
+	m.editorIndex = 0
+	m.message = fmt.Sprintf("Loaded %d entries from %s, review and press A to apply", len(entries), src)
+	return m
 }
 
-func (m Model) handleOptimizerConfirmKey(key string) (Model, tea.Cmd) {
+// handleBackupLabelKey handles typing the description for a new backup
+func (m Model) handleBackupLabelKey(key string) Model {
 	switch key {
-	case "y", "Y":
-		m.screen = ScreenLoading
-		m.loadingTask = TaskAnalyze // reuse
-		m.loadingMessage = "Applying optimization"
-		return m, tea.Batch(applyOptimizationCmd(m.analysis, m.optimizerScope, m.isAdmin), tickCmd())
-	case "n", "N", "esc":
-		m.screen = ScreenOptimizerPreview
+	case "esc":
+		m.backupLabeling = false
+		m.backupLabelInput = ""
+	case "enter":
+		m.backupLabeling = false
+		m = m.handleBackupCreate()
+		m.backupLabelInput = ""
+	case "backspace":
+		if len(m.backupLabelInput) > 0 {
+			m.backupLabelInput = m.backupLabelInput[:len(m.backupLabelInput)-1]
+		}
+	default:
+		if len(key) == 1 && key[0] >= 32 && key[0] <= 126 {
+			m.backupLabelInput += key
+		}
 	}
-	return m, nil
+	return m
 }
 
-func (m Model) handleDoneKey(key string, backTo Screen) (Model, tea.Cmd) {
-	switch key {
-	case "c", "C":
-		err := path.CopyToClipboard(path.GetRefreshCommand())
-		m.clipboardOK = err == nil
-	case "esc", "q":
-		m.screen = backTo
-		m.analysis = nil
-		if backTo == ScreenBackup {
-			m.backups = path.ListBackups()
-		}
+// handleBackupCreate creates a manual backup described by the entered label
+func (m Model) handleBackupCreate() Model {
+	_, err := path.CreateLabeledBackup(m.backupLabelInput)
+	if err != nil {
+		m.message = "Backup failed: " + err.Error()
+		return m
 	}
-	return m, nil
+	m.backups = path.ListBackups()
+	m.compareIndex = -1
+	m.message = "Backup created!"
+	return m
 }
 
-func (m Model) handleViewerKey(key string) (Model, tea.Cmd) {
+// handleBackupImportKey handles keys while typing the path of a backup file to import
+func (m Model) handleBackupImportKey(key string) Model {
 	switch key {
-	case "esc", "q":
-		m.screen = ScreenMenu
-		m.scrollOffset = 0
-	case "s", "S":
-		if m.viewerScope == "User" {
-			m.viewerScope = "System"
-		} else {
-			m.viewerScope = "User"
+	case "esc":
+		m.backupImporting = false
+		m.backupImportInput = ""
+	case "enter":
+		m.backupImporting = false
+		m = m.handleBackupImport()
+		m.backupImportInput = ""
+	case "backspace":
+		if len(m.backupImportInput) > 0 {
+			m.backupImportInput = m.backupImportInput[:len(m.backupImportInput)-1]
 		}
-		m.scrollOffset = 0
-	case "e", "E":
-		m.viewerExpanded = !m.viewerExpanded
-	case "up", "k":
-		if m.scrollOffset > 0 {
-			m.scrollOffset--
+	default:
+		if len(key) == 1 && key[0] >= 32 && key[0] <= 126 {
+			m.backupImportInput += key
 		}
-	case "down", "j":
-		m.scrollOffset++
 	}
-	return m, nil
+	return m
 }
 
-// handleBackupCreate creates a manual backup
-func (m Model) handleBackupCreate() Model {
-	_, err := path.CreateBackup("manual")
+// handleBackupImport imports the backup file at the entered path
+func (m Model) handleBackupImport() Model {
+	// Explorer's "Copy as path" wraps the path in quotes
+	src := strings.Trim(strings.TrimSpace(m.backupImportInput), `"`)
+	if src == "" {
+		return m
+	}
+	info, err := path.ImportBackup(src)
 	if err != nil {
-		m.message = "Backup failed: " + err.Error()
+		m.message = "Import failed: " + err.Error()
 		return m
 	}
 	m.backups = path.ListBackups()
-	m.message = "Backup created!"
+	m.compareIndex = -1
+	for i, b := range m.backups {
+		if b.Filename == info.Filename {
+			m.backupIndex = i
+		}
+	}
+	m.message = "Imported " + info.Filename
 	return m
 }
 
@@ -554,15 +2711,89 @@ func (m Model) handleBackupView() Model {
 		m.backupPreview = backup
 		m.screen = ScreenBackupPreview
 		m.scrollOffset = 0
+		m.message = ""
+	}
+	return m
+}
+
+// backupDiff holds the comparison between two backups shown on ScreenBackupDiff
+type backupDiff struct {
+	older, newer path.BackupInfo
+	system, user path.PathDiff
+}
+
+// handleBackupCompare marks the selected backup, or diffs it against the marked one
+func (m Model) handleBackupCompare() Model {
+	if len(m.backups) == 0 {
+		return m
+	}
+	if m.compareIndex < 0 || m.compareIndex >= len(m.backups) {
+		m.compareIndex = m.backupIndex
+		m.message = "Marked for comparison - select another backup and press M"
+		return m
+	}
+	if m.compareIndex == m.backupIndex {
+		m.compareIndex = -1
+		m.message = "Comparison mark cleared"
+		return m
+	}
+
+	older, newer := m.backups[m.compareIndex], m.backups[m.backupIndex]
+	if older.Timestamp.After(newer.Timestamp) {
+		older, newer = newer, older
 	}
+	olderBackup, err := path.LoadBackup(older.Filename)
+	if err != nil {
+		m.message = "Compare failed: " + err.Error()
+		return m
+	}
+	newerBackup, err := path.LoadBackup(newer.Filename)
+	if err != nil {
+		m.message = "Compare failed: " + err.Error()
+		return m
+	}
+
+	system, user := path.DiffBackups(olderBackup, newerBackup)
+	m.backupDiff = &backupDiff{older: older, newer: newer, system: system, user: user}
+	m.compareIndex = -1
+	m.scrollOffset = 0
+	m.message = ""
+	m.screen = ScreenBackupDiff
 	return m
 }
 
+func (m Model) handleBackupDiffKey(key string) (Model, tea.Cmd) {
+	switch key {
+	case "esc", "q":
+		m.screen = ScreenBackup
+		m.backupDiff = nil
+		m.scrollOffset = 0
+	case "up", "k":
+		if m.scrollOffset > 0 {
+			m.scrollOffset--
+		}
+	case "down", "j":
+		if m.scrollOffset < len(m.backupDiffLines())-listMaxVisible {
+			m.scrollOffset++
+		}
+	}
+	return m, nil
+}
+
 func (m Model) handleBackupKey(key string) (Model, tea.Cmd) {
+	if m.backupLabeling {
+		return m.handleBackupLabelKey(key), nil
+	}
+	if m.backupImporting {
+		return m.handleBackupImportKey(key), nil
+	}
 	switch key {
 	case "esc", "q":
 		m.screen = ScreenMenu
 		m.message = ""
+		m.compareIndex = -1
+	case "m", "M":
+		m = m.handleBackupCompare()
 	case "up", "k":
 		if m.backupIndex > 0 {
 			m.backupIndex--
@@ -571,12 +2802,22 @@ func (m Model) handleBackupKey(key string) (Model, tea.Cmd) {
 		if m.backupIndex < len(m.backups)-1 {
 			m.backupIndex++
 		}
+	case "g", "G", "home", "end", "pgup", "pgdown":
+		m.backupIndex = jumpPosition(key, m.backupIndex, len(m.backups)-1, defaultPageSize)
 	case "c", "C":
-		m = m.handleBackupCreate()
+		m.backupLabeling = true
+		m.backupLabelInput = ""
+		m.message = ""
+	case "i", "I":
+		m.backupImporting = true
+		m.backupImportInput = ""
+		m.message = ""
 	case "v", "V":
 		m = m.handleBackupView()
 	case "r", "R":
-		if len(m.backups) > 0 {
+		if m.config.AdvisoryMode {
+			m.message = advisoryMessage
+		} else if len(m.backups) > 0 {
 			m.screen = ScreenBackupConfirmRestore
 		}
 	case "d", "D":
@@ -587,18 +2828,39 @@ func (m Model) handleBackupKey(key string) (Model, tea.Cmd) {
 	return m, nil
 }
 
+// exportBackupReg writes the previewed backup as a .reg file for restoring outside the app
+func (m Model) exportBackupReg() Model {
+	if m.backupPreview == nil {
+		return m
+	}
+	file, err := path.SaveBackupReg(m.backupPreview, "both")
+	if err != nil {
+		m.err = err
+		m.message = "Export failed: " + err.Error()
+		return m
+	}
+	m.err = nil
+	m.message = "Exported to " + file
+	return m
+}
+
 func (m Model) handleBackupPreviewKey(key string) (Model, tea.Cmd) {
 	switch key {
 	case "esc", "q":
 		m.screen = ScreenBackup
 		m.backupPreview = nil
 		m.scrollOffset = 0
+		m.message = ""
+		m.err = nil
+	case "x", "X":
+		m = m.exportBackupReg()
 	case "up", "k":
 		if m.scrollOffset > 0 {
 			m.scrollOffset--
 		}
 	case "down", "j":
-		m.scrollOffset++
+		// The preview is a fixed-size summary, so there is nothing below it to scroll to
+		m.scrollOffset = clampScroll(m.scrollOffset+1, 0, 0)
 	}
 	return m, nil
 }
@@ -607,7 +2869,12 @@ func (m Model) handleBackupConfirmKey(key string) (Model, tea.Cmd) {
 	switch key {
 	case "y", "Y":
 		if m.screen == ScreenBackupConfirmRestore {
-			if err := path.RestoreBackup(m.backups[m.backupIndex].Filename, m.isAdmin); err != nil {
+			if m.config.AdvisoryMode {
+				m.message = advisoryMessage
+				m.screen = ScreenBackup
+				return m, nil
+			}
+			if err := restoreBackup(m.backups[m.backupIndex].Filename, m.isAdmin); err != nil {
 				m.err = err
 			} else {
 				m.message = "Backup restored successfully!"
@@ -621,6 +2888,7 @@ func (m Model) handleBackupConfirmKey(key string) (Model, tea.Cmd) {
 				m.message = "Backup deleted"
 			}
 			m.backups = path.ListBackups()
+			m.compareIndex = -1
 			m.screen = ScreenBackup
 			if m.backupIndex >= len(m.backups) && m.backupIndex > 0 {
 				m.backupIndex--
@@ -634,19 +2902,13 @@ func (m Model) handleBackupConfirmKey(key string) (Model, tea.Cmd) {
 
 // handleJunctionsRefresh starts junction refresh
 func (m Model) handleJunctionsRefresh() (Model, tea.Cmd) {
-	m.screen = ScreenLoading
-	m.loadingTask = TaskJunctions
-	m.loadingMessage = "Refreshing junctions"
-	return m, tea.Batch(loadJunctionsCmd(), tickCmd())
+	return m.startLoading(TaskJunctions, "Refreshing junctions", loadJunctionsCmd())
 }
 
 // handleJunctionsSuggestions starts suggestion loading
 func (m Model) handleJunctionsSuggestions() (Model, tea.Cmd) {
-	m.screen = ScreenLoading
-	m.loadingTask = TaskSuggestions
-	m.loadingMessage = "Analyzing PATH for suggestions"
 	m.message = ""
-	return m, tea.Batch(loadSuggestionsCmd(), tickCmd())
+	return m.startLoading(TaskSuggestions, "Analyzing PATH for suggestions", loadSuggestionsCmd())
 }
 
 // handleJunctionsCreate opens junction create screen
@@ -655,6 +2917,23 @@ func (m Model) handleJunctionsCreate() Model {
 	m.junctionName = ""
 	m.junctionTarget = ""
 	m.junctionInputMode = 0
+	m.junctionRenaming = ""
+	m.message = ""
+	m.err = nil
+	return m
+}
+
+// handleJunctionsRename opens the create screen to rename the selected junction
+func (m Model) handleJunctionsRename() Model {
+	if len(m.junctions) == 0 {
+		return m
+	}
+	j := m.junctions[m.junctionIndex]
+	m.screen = ScreenJunctionCreate
+	m.junctionRenaming = j.Name
+	m.junctionName = j.Name
+	m.junctionTarget = j.Target
+	m.junctionInputMode = 0
 	m.message = ""
 	m.err = nil
 	return m
@@ -674,64 +2953,362 @@ func (m Model) handleJunctionsDelete() Model {
 	if m.junctionIndex >= len(m.junctions) && m.junctionIndex > 0 {
 		m.junctionIndex--
 	}
-	return m
+	return m
+}
+
+func (m Model) handleJunctionsKey(key string) (Model, tea.Cmd) {
+	switch key {
+	case "3", "d", "D", "n", "N", "p", "P":
+		if m.config.AdvisoryMode {
+			m.message = advisoryMessage
+			return m, nil
+		}
+	}
+	switch key {
+	case "esc", "q":
+		m.screen = ScreenMenu
+		m.message = ""
+	case "1":
+		return m.handleJunctionsRefresh()
+	case "2":
+		return m.handleJunctionsSuggestions()
+	case "3":
+		m = m.handleJunctionsCreate()
+	case "d", "D":
+		m = m.handleJunctionsDelete()
+	case "n", "N":
+		m = m.handleJunctionsRename()
+	case "c", "C":
+		m = m.copyJunctionTable()
+	case "p", "P":
+		if len(brokenJunctions(m.junctions)) == 0 {
+			m.message = "No broken junctions"
+		} else {
+			m.screen = ScreenJunctionPruneConfirm
+			m.message = ""
+		}
+	case "up", "k":
+		if m.junctionIndex > 0 {
+			m.junctionIndex--
+		}
+	case "down", "j":
+		if m.junctionIndex < len(m.junctions)-1 {
+			m.junctionIndex++
+		}
+	case "g", "G", "home", "end", "pgup", "pgdown":
+		m.junctionIndex = jumpPosition(key, m.junctionIndex, len(m.junctions)-1, defaultPageSize)
+	}
+	return m, nil
+}
+
+// visibleSuggestions returns the suggestions matching the filter, case-insensitively
+func (m Model) visibleSuggestions() []path.JunctionSuggestion {
+	if m.suggestionFilter == "" {
+		return m.suggestions
+	}
+	filter := strings.ToLower(m.suggestionFilter)
+	var visible []path.JunctionSuggestion
+	for _, s := range m.suggestions {
+		if strings.Contains(strings.ToLower(s.OriginalPath), filter) {
+			visible = append(visible, s)
+		}
+	}
+	return visible
+}
+
+// handleSuggestionFilterKey handles keys while typing the suggestions filter
+func (m Model) handleSuggestionFilterKey(key string) Model {
+	switch key {
+	case "esc":
+		m.suggestionTyping = false
+		m.suggestionFilter = ""
+	case "enter":
+		m.suggestionTyping = false
+	case "backspace":
+		if len(m.suggestionFilter) > 0 {
+			m.suggestionFilter = m.suggestionFilter[:len(m.suggestionFilter)-1]
+		}
+	default:
+		if len(key) == 1 && key[0] >= 32 && key[0] <= 126 {
+			m.suggestionFilter += key
+		}
+	}
+	m.junctionIndex = 0
+	return m
+}
+
+func (m Model) handleJunctionSuggestionsKey(key string) (Model, tea.Cmd) {
+	if m.suggestionTyping {
+		return m.handleSuggestionFilterKey(key), nil
+	}
+	switch key {
+	case "c", "C", "w", "W", "a", "A":
+		if m.config.AdvisoryMode {
+			m.message = advisoryMessage
+			return m, nil
+		}
+	}
+	visible := m.visibleSuggestions()
+	switch key {
+	case "esc", "q":
+		if key == "esc" && m.suggestionFilter != "" {
+			m.suggestionFilter = ""
+			m.junctionIndex = 0
+			return m, nil
+		}
+		m.screen = ScreenJunctions
+		m.junctions = path.ListJunctions()
+		m.junctionIndex = 0
+		m.suggestionFilter = ""
+		m.message = ""
+	case "/":
+		m.suggestionTyping = true
+		m.message = ""
+	case "c", "C":
+		if len(visible) > 0 && m.junctionIndex < len(visible) {
+			s := visible[m.junctionIndex]
+			return m.startWriting(TaskCreateJunction, "Creating junction '"+s.SuggestedName+"'", createJunctionCmd(s, m.config.RewritePathOnJunction))
+		}
+	case "w", "W":
+		if len(visible) > 0 && m.junctionIndex < len(visible) {
+			s := visible[m.junctionIndex]
+			return m.startWriting(TaskCreateJunction, "Creating junction '"+s.SuggestedName+"'", createJunctionCmd(s, true))
+		}
+	case "a", "A":
+		if len(visible) > 0 {
+			m.message = ""
+			m.err = nil
+			return m.startWriting(TaskCreateJunction, fmt.Sprintf("Creating %d junctions", len(visible)), createAllJunctionsCmd(visible))
+		}
+	case "e", "E":
+		m.screen = ScreenJunctionExcludes
+		m.excludeIndex = 0
+		m.excludeChanged = false
+		m.message = ""
+	case "p", "P":
+		if len(m.suggestions) > 0 {
+			m.screen = ScreenJunctionPreview
+			m.scrollOffset = 0
+			m.message = ""
+		}
+	case "up", "k":
+		if m.junctionIndex > 0 {
+			m.junctionIndex--
+		}
+	case "down", "j":
+		if m.junctionIndex < len(visible)-1 {
+			m.junctionIndex++
+		}
+	case "g", "G", "home", "end", "pgup", "pgdown":
+		m.junctionIndex = jumpPosition(key, m.junctionIndex, len(visible)-1, suggestionsMaxVisible)
+	}
+	return m, nil
+}
+
+// handleJunctionExcludesKey edits the folders excluded from junction suggestions
+func (m Model) handleJunctionExcludesKey(key string) (Model, tea.Cmd) {
+	if m.excludeAdding {
+		return m.handleJunctionExcludeInputKey(key), nil
+	}
+	excludes := m.config.JunctionExcludePrefixes
+	switch key {
+	case "esc", "q":
+		m.message = ""
+		if m.excludeChanged {
+			m.excludeChanged = false
+			return m.startLoading(TaskSuggestions, "Refreshing suggestions", loadSuggestionsCmd())
+		}
+		m.screen = ScreenJunctionSuggestions
+	case "a", "A":
+		m.excludeAdding = true
+		m.excludeInput = ""
+		m.message = ""
+	case "x", "X", "d":
+		if m.excludeIndex < len(excludes) {
+			removed := excludes[m.excludeIndex]
+			m.config.JunctionExcludePrefixes = append(append([]string(nil), excludes[:m.excludeIndex]...), excludes[m.excludeIndex+1:]...)
+			_ = path.SaveConfig(m.config) // Intentionally ignore error for UI config
+			m.excludeChanged = true
+			m.excludeIndex = min(m.excludeIndex, max(len(m.config.JunctionExcludePrefixes)-1, 0))
+			m.message = removed + " will be suggested again"
+		}
+	case "up", "k":
+		if m.excludeIndex > 0 {
+			m.excludeIndex--
+		}
+	case "down", "j":
+		if m.excludeIndex < len(excludes)-1 {
+			m.excludeIndex++
+		}
+	}
+	return m, nil
+}
+
+// handleJunctionExcludeInputKey handles keys while typing a folder to exclude
+func (m Model) handleJunctionExcludeInputKey(key string) Model {
+	switch key {
+	case "esc":
+		m.excludeAdding = false
+		m.excludeInput = ""
+	case "enter":
+		folder := strings.TrimSpace(m.excludeInput)
+		m.excludeAdding = false
+		m.excludeInput = ""
+		if folder == "" {
+			return m
+		}
+		for _, e := range m.config.JunctionExcludePrefixes {
+			if path.NormalizePath(e) == path.NormalizePath(folder) {
+				m.message = e + " is already excluded"
+				return m
+			}
+		}
+		m.config.JunctionExcludePrefixes = append(m.config.JunctionExcludePrefixes, folder)
+		_ = path.SaveConfig(m.config) // Intentionally ignore error for UI config
+		m.excludeChanged = true
+		m.excludeIndex = len(m.config.JunctionExcludePrefixes) - 1
+		m.message = folder + " excluded"
+	case "backspace":
+		if len(m.excludeInput) > 0 {
+			m.excludeInput = m.excludeInput[:len(m.excludeInput)-1]
+		}
+	default:
+		if len(key) == 1 && key[0] >= 32 && key[0] <= 126 {
+			m.excludeInput += key
+		}
+	}
+	return m
+}
+
+// junctionPreviewLines returns the rendered lines of both PATHs with every suggestion applied
+func (m Model) junctionPreviewLines() []string {
+	var lines []string
+	for _, scope := range []struct {
+		label   string
+		entries []path.EntryRewrite
+	}{{"System", m.suggestionPreview.System}, {"User", m.suggestionPreview.User}} {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, SubtitleStyle.Render(scope.label+" PATH"))
+		if len(scope.entries) == 0 {
+			lines = append(lines, DimStyle.Render("  (empty)"))
+		}
+		for _, e := range scope.entries {
+			if e.After == e.Before {
+				lines = append(lines, DimStyle.Render("  "+e.After))
+				continue
+			}
+			lines = append(lines, SuccessStyle.Render("~ "+e.After)+DimStyle.Render(" <- "+e.Before))
+		}
+	}
+	return lines
 }
 
-func (m Model) handleJunctionsKey(key string) (Model, tea.Cmd) {
+// handleJunctionPreviewKey scrolls the all-suggestions preview
+func (m Model) handleJunctionPreviewKey(key string) Model {
+	lines := len(m.junctionPreviewLines())
 	switch key {
-	case "esc", "q":
-		m.screen = ScreenMenu
-		m.message = ""
-	case "1":
-		return m.handleJunctionsRefresh()
-	case "2":
-		return m.handleJunctionsSuggestions()
-	case "3":
-		m = m.handleJunctionsCreate()
-	case "d", "D":
-		m = m.handleJunctionsDelete()
+	case "esc", "q", "p", "P":
+		m.screen = ScreenJunctionSuggestions
+		m.scrollOffset = 0
 	case "up", "k":
-		if m.junctionIndex > 0 {
-			m.junctionIndex--
+		if m.scrollOffset > 0 {
+			m.scrollOffset--
 		}
 	case "down", "j":
-		if m.junctionIndex < len(m.junctions)-1 {
-			m.junctionIndex++
+		m.scrollOffset = clampScroll(m.scrollOffset+1, lines, listMaxVisible)
+	case "g", "G", "home", "end", "pgup", "pgdown":
+		m.scrollOffset = jumpPosition(key, m.scrollOffset, lines-listMaxVisible, listMaxVisible)
+	}
+	return m
+}
+
+// brokenJunctions returns the junctions whose target no longer exists
+func brokenJunctions(junctions []path.Junction) []path.Junction {
+	var broken []path.Junction
+	for _, j := range junctions {
+		if j.Broken {
+			broken = append(broken, j)
 		}
 	}
-	return m, nil
+	return broken
 }
 
-func (m Model) handleJunctionSuggestionsKey(key string) (Model, tea.Cmd) {
+// handleJunctionPruneKey confirms removing every broken junction
+func (m Model) handleJunctionPruneKey(key string) (Model, tea.Cmd) {
 	switch key {
-	case "esc", "q":
-		m.screen = ScreenJunctions
-		m.junctions = path.ListJunctions()
-		m.junctionIndex = 0
-		m.message = ""
-	case "c", "C":
-		if len(m.suggestions) > 0 && m.junctionIndex < len(m.suggestions) {
-			s := m.suggestions[m.junctionIndex]
-			m.screen = ScreenLoading
-			m.loadingTask = TaskCreateJunction
-			m.loadingMessage = "Creating junction '" + s.SuggestedName + "'"
-			return m, tea.Batch(createJunctionCmd(s.SuggestedName, s.OriginalPath), tickCmd())
+	case "y", "Y":
+		if m.config.AdvisoryMode {
+			m.message = advisoryMessage
+			break
 		}
-	case "up", "k":
-		if m.junctionIndex > 0 {
-			m.junctionIndex--
+		var failed []string
+		removed := 0
+		for _, j := range brokenJunctions(m.junctions) {
+			if err := path.RemoveJunction(j.Name); err != nil {
+				failed = append(failed, j.Name)
+				continue
+			}
+			removed++
 		}
-	case "down", "j":
-		if m.junctionIndex < len(m.suggestions)-1 {
-			m.junctionIndex++
+		if len(failed) > 0 {
+			m.message = fmt.Sprintf("Pruned %d broken junction(s); failed: %s", removed, strings.Join(failed, ", "))
+		} else {
+			m.message = fmt.Sprintf("Pruned %d broken junction(s)", removed)
+		}
+		m.junctions = path.ListJunctions()
+		if m.junctionIndex >= len(m.junctions) {
+			m.junctionIndex = max(len(m.junctions)-1, 0)
 		}
+	case "n", "N", "esc":
+		m.message = ""
+	default:
+		return m, nil
 	}
+	m.screen = ScreenJunctions
 	return m, nil
 }
 
+// handleJunctionRewriteKey confirms pointing PATH entries at a newly created junction
+func (m Model) handleJunctionRewriteKey(key string) (Model, tea.Cmd) {
+	switch key {
+	case "y", "Y":
+		var failed []string
+		rewritten := 0
+		for _, scope := range m.junctionRewriteScopes {
+			if scope == "System" && !m.isAdmin {
+				failed = append(failed, "System (needs admin)")
+				continue
+			}
+			if err := path.RewritePathEntry(m.junctionRewriteOld, m.junctionRewriteNew, scope); err != nil {
+				failed = append(failed, scope+": "+err.Error())
+				continue
+			}
+			rewritten++
+		}
+		if len(failed) > 0 {
+			m.err = fmt.Errorf("rewrite failed")
+			m.message = fmt.Sprintf("PATH rewritten in %d scope(s); skipped %s", rewritten, strings.Join(failed, ", "))
+		} else {
+			m.err = nil
+			m.message = "PATH now uses " + m.junctionRewriteNew
+		}
+	case "n", "N", "esc":
+		m.message += " PATH unchanged."
+	default:
+		return m, nil
+	}
+	m.junctionRewriteOld = ""
+	m.junctionRewriteNew = ""
+	m.junctionRewriteScopes = nil
+	return m.startLoading(TaskSuggestions, "Refreshing suggestions", loadSuggestionsCmd())
+}
+
 // handleJunctionCreateEscape handles escape key in junction create
 func (m Model) handleJunctionCreateEscape() Model {
 	m.screen = ScreenJunctions
+	m.junctionRenaming = ""
 	m.junctions = path.ListJunctions()
 	m.err = nil
 	return m
@@ -739,6 +3316,25 @@ func (m Model) handleJunctionCreateEscape() Model {
 
 // handleJunctionCreateEnter handles enter key in junction create
 func (m Model) handleJunctionCreateEnter() Model {
+	if m.config.AdvisoryMode {
+		m.message = advisoryMessage
+		m.screen = ScreenJunctions
+		m.junctionRenaming = ""
+		return m
+	}
+	if m.junctionName != "" {
+		if err := path.ValidateJunctionName(m.junctionName); err != nil {
+			m.err = err
+			m.message = err.Error()
+			m.junctionInputMode = 0
+			return m
+		}
+		m.err = nil
+		m.message = ""
+	}
+	if m.junctionRenaming != "" {
+		return m.handleJunctionRenameEnter()
+	}
 	if m.junctionInputMode == 0 && m.junctionName != "" {
 		m.junctionInputMode = 1
 		return m
@@ -757,6 +3353,24 @@ func (m Model) handleJunctionCreateEnter() Model {
 	return m
 }
 
+// handleJunctionRenameEnter renames the junction being edited to the typed name
+func (m Model) handleJunctionRenameEnter() Model {
+	if m.junctionName == "" {
+		return m
+	}
+	if err := path.RenameJunction(m.junctionRenaming, m.junctionName); err != nil {
+		m.err = err
+		m.message = "Failed: " + err.Error()
+		return m
+	}
+	m.message = "Junction '" + m.junctionRenaming + "' renamed to '" + m.junctionName + "'"
+	m.junctionRenaming = ""
+	m.screen = ScreenJunctions
+	m.junctions = path.ListJunctions()
+	m.err = nil
+	return m
+}
+
 // handleJunctionCreateBackspace handles backspace in junction create
 func (m Model) handleJunctionCreateBackspace() Model {
 	if m.junctionInputMode == 0 && len(m.junctionName) > 0 {
@@ -784,7 +3398,9 @@ func (m Model) handleJunctionCreateKey(key string) (Model, tea.Cmd) {
 	case "esc":
 		m = m.handleJunctionCreateEscape()
 	case "tab":
-		m.junctionInputMode = (m.junctionInputMode + 1) % 2
+		if m.junctionRenaming == "" {
+			m.junctionInputMode = (m.junctionInputMode + 1) % 2
+		}
 	case "enter":
 		m = m.handleJunctionCreateEnter()
 	case "backspace":
@@ -814,13 +3430,77 @@ func (m Model) handlePathExtEditKey(key string) Model {
 		m = m.movePathExtDown()
 	case "x", "X", "delete":
 		m = m.removePathExtEntry()
+	case "i", "I", "+":
+		m.pathExtAdding = true
+		m.pathExtInput = ""
+		m.err = nil
+		m.message = ""
 	case "a", "A":
+		if m.config.AdvisoryMode {
+			m.message = advisoryMessage
+			return m
+		}
 		m.pathExtEditing = false
 		m.screen = ScreenPathExtConfirm
 	}
 	return m
 }
 
+// handlePathExtInputKey handles keys while typing a new extension in edit mode
+func (m Model) handlePathExtInputKey(key string) Model {
+	switch key {
+	case "esc":
+		m.pathExtAdding = false
+		m.pathExtInput = ""
+		m.err = nil
+		m.message = ""
+	case "enter":
+		m = m.insertPathExtEntry()
+	case "backspace":
+		if len(m.pathExtInput) > 0 {
+			m.pathExtInput = m.pathExtInput[:len(m.pathExtInput)-1]
+		}
+	default:
+		if len(key) == 1 && key[0] > 32 && key[0] <= 126 {
+			m.pathExtInput += key
+		}
+	}
+	return m
+}
+
+// insertPathExtEntry validates the typed extension and inserts it at the current index
+func (m Model) insertPathExtEntry() Model {
+	ext := strings.ToUpper(strings.TrimSpace(m.pathExtInput))
+	switch {
+	case len(ext) < 2 || !strings.HasPrefix(ext, "."):
+		m.err = fmt.Errorf("extension must start with a dot, e.g. .PS1")
+	case strings.ContainsAny(ext[1:], ".;"):
+		m.err = fmt.Errorf("invalid extension %q", ext)
+	default:
+		for _, existing := range m.pathExtList {
+			if strings.EqualFold(existing, ext) {
+				m.err = fmt.Errorf("%s is already in PATHEXT", ext)
+				break
+			}
+		}
+	}
+	if m.err != nil {
+		m.message = m.err.Error()
+		return m
+	}
+
+	list := make([]string, 0, len(m.pathExtList)+1)
+	list = append(list, m.pathExtList[:m.pathExtIndex]...)
+	list = append(list, ext)
+	list = append(list, m.pathExtList[m.pathExtIndex:]...)
+	m.pathExtList = list
+	m.pathExtAdding = false
+	m.pathExtInput = ""
+	m.message = ext + " added"
+	m.updatePathExtOpt()
+	return m
+}
+
 // movePathExtUp moves current extension up in priority
 func (m Model) movePathExtUp() Model {
 	if m.pathExtIndex > 0 {
@@ -863,22 +3543,53 @@ func (m Model) handlePathExtNormalKey(key string) Model {
 		m.pathExtList = make([]string, len(m.pathExtAnalysis.Current))
 		copy(m.pathExtList, m.pathExtAnalysis.Current)
 		m.pathExtIndex = 0
+		m.message = ""
 	case "o", "O":
 		if m.pathExtOpt != nil && m.pathExtOpt.Changed {
 			m.pathExtList = make([]string, len(m.pathExtOpt.Optimized))
 			copy(m.pathExtList, m.pathExtOpt.Optimized)
 			m.pathExtEditing = true
 			m.pathExtIndex = 0
+			m.message = ""
 		}
 	case "a", "A":
-		if m.pathExtOpt != nil && m.pathExtOpt.Changed {
+		if m.config.AdvisoryMode {
+			m.message = advisoryMessage
+		} else if m.pathExtOpt != nil && m.pathExtOpt.Changed {
 			m.screen = ScreenPathExtConfirm
 		}
+	case "r", "R":
+		if m.config.AdvisoryMode {
+			m.message = advisoryMessage
+		} else {
+			m = m.stagePathExtReset()
+		}
+	}
+	return m
+}
+
+// stagePathExtReset stages the Windows default PATHEXT and asks for confirmation
+func (m Model) stagePathExtReset() Model {
+	if m.pathExtAnalysis == nil {
+		return m
 	}
+	original := strings.Join(m.pathExtAnalysis.Current, ";")
+	m.pathExtPrevOpt = m.pathExtOpt
+	m.pathExtOpt = &path.PathExtOptimization{
+		Original:        original,
+		Optimized:       strings.Split(path.DefaultPathExt, ";"),
+		OptimizedString: path.DefaultPathExt,
+		Changed:         !strings.EqualFold(original, path.DefaultPathExt),
+	}
+	m.pathExtReset = true
+	m.screen = ScreenPathExtConfirm
 	return m
 }
 
 func (m Model) handlePathExtKey(key string) (Model, tea.Cmd) {
+	if m.pathExtEditing && m.pathExtAdding {
+		return m.handlePathExtInputKey(key), nil
+	}
 	if m.pathExtEditing {
 		return m.handlePathExtEditKey(key), nil
 	}
@@ -896,9 +3607,82 @@ func (m *Model) updatePathExtOpt() {
 	}
 }
 
+// autoFixScopes are the PATH scopes auto-fix writes: User, plus System for admins
+func (m Model) autoFixScopes() []string {
+	if m.isAdmin {
+		return []string{"User", "System"}
+	}
+	return []string{"User"}
+}
+
+// autoFixResult returns the planned auto-fix result for a PATH scope
+func (m Model) autoFixResult(scope string) path.OptimizeResult {
+	if scope == "System" {
+		return m.autoFixAnalysis.System
+	}
+	return m.autoFixAnalysis.User
+}
+
+// autoFixHasChanges reports whether the planned auto-fix would write anything
+func (m Model) autoFixHasChanges() bool {
+	if m.autoFixAnalysis == nil || m.autoFixPathExt == nil {
+		return false
+	}
+	for _, scope := range m.autoFixScopes() {
+		if pathChanged(m.autoFixResult(scope)) {
+			return true
+		}
+	}
+	return m.autoFixPathExt.Changed
+}
+
+// autoFixProtected returns protected entries the planned auto-fix would remove
+func (m Model) autoFixProtected() []path.ProtectedViolation {
+	var violations []path.ProtectedViolation
+	if m.autoFixAnalysis == nil {
+		return violations
+	}
+	for _, scope := range m.autoFixScopes() {
+		violations = append(violations, m.autoFixResult(scope).ProtectedViolations...)
+	}
+	return violations
+}
+
+// handleAutoFixConfirmKey applies the recommended fixes after one confirmation
+func (m Model) handleAutoFixConfirmKey(key string) (Model, tea.Cmd) {
+	switch key {
+	case "y", "Y":
+		switch {
+		case m.config.AdvisoryMode:
+			m.message = advisoryMessage
+		case !m.autoFixHasChanges():
+			m.screen = ScreenMenu
+			m.autoFixAnalysis = nil
+			m.autoFixPathExt = nil
+		case len(m.autoFixProtected()) > 0:
+			m.message = "Protected entries would change - review them in Optimize PATH instead"
+		default:
+			m.message = ""
+			return m.startWriting(TaskAutoFix, "Applying recommended fixes", autoFixApplyCmd(m.autoFixAnalysis, m.autoFixPathExt, m.isAdmin))
+		}
+	case "n", "N", "esc", "q":
+		m.screen = ScreenMenu
+		m.autoFixAnalysis = nil
+		m.autoFixPathExt = nil
+		m.message = ""
+		m.err = nil
+	}
+	return m, nil
+}
+
 func (m Model) handlePathExtConfirmKey(key string) (Model, tea.Cmd) {
 	switch key {
 	case "y", "Y":
+		if m.config.AdvisoryMode {
+			m.message = advisoryMessage
+			m.screen = ScreenPathExt
+			return m, nil
+		}
 		if m.pathExtOpt == nil {
 			m.err = fmt.Errorf("no optimization to apply")
 			m.screen = ScreenPathExt
@@ -908,6 +3692,8 @@ func (m Model) handlePathExtConfirmKey(key string) (Model, tea.Cmd) {
 		if m.isAdmin {
 			scope = "System"
 		}
+		m.pathExtReset = false
+		m.pathExtPrevOpt = nil
 		if err := path.ApplyPathExt(m.pathExtOpt.OptimizedString, scope); err != nil {
 			m.err = err
 		} else {
@@ -915,21 +3701,46 @@ func (m Model) handlePathExtConfirmKey(key string) (Model, tea.Cmd) {
 			m.clipboardOK = false
 		}
 	case "n", "N", "esc":
+		if m.pathExtReset {
+			m.pathExtOpt = m.pathExtPrevOpt
+			m.pathExtPrevOpt = nil
+			m.pathExtReset = false
+		}
 		m.screen = ScreenPathExt
 	}
 	return m, nil
 }
 
 func (m Model) handleSettingsKey(key string) (Model, tea.Cmd) {
+	if m.settingsEditing {
+		return m.handleSettingsInputKey(key), nil
+	}
 	switch key {
 	case "esc", "q":
 		m.screen = ScreenMenu
+		m.message = ""
+		m.err = nil
+	case "r", "R":
+		// Typed "r"s never reach here while editing the Junction Folder
+		m.config = path.LoadConfig()
+		ApplyTheme(m.config.Theme)
+		path.SetShellTimeout(m.config.ShellTimeoutSeconds)
+		m.message = "Settings reloaded from " + path.GetConfigPath()
+		m.err = nil
+	case "o", "O":
+		dir, err := path.OpenConfigDir()
+		m.err = err
+		if err != nil {
+			m.message = "Couldn't open " + dir + ": " + err.Error()
+		} else {
+			m.message = "Opened " + dir + " in Explorer"
+		}
 	case "up", "k":
 		if m.settingsIndex > 0 {
 			m.settingsIndex--
 		}
 	case "down", "j":
-		if m.settingsIndex < 2 {
+		if m.settingsIndex < 9 {
 			m.settingsIndex++
 		}
 	case "enter", "+", "-":
@@ -942,12 +3753,87 @@ func (m Model) handleSettingsKey(key string) (Model, tea.Cmd) {
 			}
 		case 1:
 			m.config.AutoBackup = !m.config.AutoBackup
+		case 2:
+			m.config.AutoAnalyzeOnStart = !m.config.AutoAnalyzeOnStart
+		case 3:
+			m.config.RewritePathOnJunction = !m.config.RewritePathOnJunction
+		case 4:
+			if key == "+" || key == "enter" {
+				m.config.JunctionMinSavings++
+			} else if m.config.JunctionMinSavings > 1 {
+				m.config.JunctionMinSavings--
+			}
+		case 5:
+			if key == "+" || key == "enter" {
+				m.config.JunctionMinPathLength += 5
+			} else if m.config.JunctionMinPathLength > 5 {
+				m.config.JunctionMinPathLength -= 5
+			}
+		case 6:
+			m.config.CanonicalizePaths = !m.config.CanonicalizePaths
+		case 7:
+			m.config.Theme = ApplyTheme(nextTheme(themeName(m.config.Theme)))
+		case 8:
+			m.config.DetectShadowedTools = !m.config.DetectShadowedTools
+		case 9:
+			if key == "enter" {
+				m.settingsEditing = true
+				m.settingsInput = m.config.JunctionFolder
+				m.message = ""
+			}
+			return m, nil
 		}
 		_ = path.SaveConfig(m.config) // Intentionally ignore error for UI config
 	}
 	return m, nil
 }
 
+// handleSettingsInputKey handles keys while typing a new Junction Folder
+func (m Model) handleSettingsInputKey(key string) Model {
+	switch key {
+	case "esc":
+		m.settingsEditing = false
+		m.settingsInput = ""
+	case "enter":
+		folder := strings.Trim(strings.TrimSpace(m.settingsInput), `"`)
+		if len(folder) > 3 {
+			folder = strings.TrimRight(folder, `\`)
+		}
+		if err := path.ValidateJunctionFolder(folder); err != nil {
+			m.message = err.Error()
+			return m
+		}
+		m.config.JunctionFolder = folder
+		_ = path.SaveConfig(m.config) // Intentionally ignore error for UI config
+		m.settingsEditing = false
+		m.settingsInput = ""
+		m.message = "Junction folder set to " + folder
+	case "backspace":
+		if len(m.settingsInput) > 0 {
+			m.settingsInput = m.settingsInput[:len(m.settingsInput)-1]
+		}
+	default:
+		if len(key) == 1 && key[0] >= 32 && key[0] <= 126 {
+			m.settingsInput += key
+		}
+	}
+	return m
+}
+
+// findHotPath returns the hot path that matches entry after normalization
+func findHotPath(hotPaths []string, entry string) (string, bool) {
+	if entry == "" {
+		return "", false
+	}
+	normalized := path.NormalizePath(entry)
+	for _, hp := range hotPaths {
+		if path.NormalizePath(hp) == normalized {
+			return hp, true
+		}
+	}
+	return "", false
+}
+
 // handleHotPathsInputKey handles keys when in hot path input mode
 func (m Model) handleHotPathsInputKey(key string) Model {
 	switch key {
@@ -955,9 +3841,20 @@ func (m Model) handleHotPathsInputKey(key string) Model {
 		m.hotPathAdding = false
 		m.hotPathInput = ""
 	case "enter":
+		if existing, ok := findHotPath(m.config.HotPaths, m.hotPathInput); ok {
+			m.message = existing + " is already added"
+			m.hotPathInput = ""
+			m.hotPathAdding = false
+			return m
+		}
 		if m.hotPathInput != "" {
+			m = m.stashHotPaths()
 			m.config.HotPaths = append(m.config.HotPaths, m.hotPathInput)
 			_ = path.SaveConfig(m.config) // Intentionally ignore error for UI config
+			m = m.checkHotPaths()
+			if m.hotPathMissing[m.hotPathInput] {
+				m.hotPathWarning = m.hotPathInput + " doesn't exist right now; check it for typos"
+			}
 			m.hotPathInput = ""
 			m.hotPathAdding = false
 			m.message = "Path added!"
@@ -967,19 +3864,45 @@ func (m Model) handleHotPathsInputKey(key string) Model {
 			m.hotPathInput = m.hotPathInput[:len(m.hotPathInput)-1]
 		}
 	default:
-		if len(key) == 1 && key[0] >= 32 && key[0] <= 126 {
+		if r := []rune(key); len(r) == 1 && unicode.IsPrint(r[0]) {
 			m.hotPathInput += key
 		}
 	}
 	return m
 }
 
+// pasteHotPaths inserts pasted text into the hot path input
+// Every complete line of a multi-line paste is added as its own hot path
+func (m Model) pasteHotPaths(text string) Model {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		m.hotPathInput += strings.Map(func(r rune) rune {
+			if unicode.IsPrint(r) {
+				return r
+			}
+			return -1
+		}, line)
+		if i == len(lines)-1 {
+			// A paste ending in a newline has nothing left to type
+			m.hotPathAdding = line != "" || len(lines) == 1
+			break
+		}
+		m = m.handleHotPathsInputKey("enter")
+		m.hotPathAdding = true
+	}
+	return m
+}
+
 // handleHotPathsNavKey handles navigation and action keys for hot paths
 func (m Model) handleHotPathsNavKey(key string) Model {
 	switch key {
 	case "esc", "q":
 		m.screen = ScreenMenu
 		m.message = ""
+		m.hotPathUndo = nil
+		m.hotPathCanUndo = false
+	case "u":
+		m = m.undoHotPaths()
 	case "a", "A":
 		m.hotPathAdding = true
 		m.hotPathInput = ""
@@ -997,13 +3920,77 @@ func (m Model) handleHotPathsNavKey(key string) Model {
 		m = m.moveHotPathUp()
 	case "J", "D":
 		m = m.moveHotPathDown()
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		m = m.moveHotPathTo(int(key[0] - '1'))
+	case "p", "P":
+		m.hotPathPreview = !m.hotPathPreview
+		if m.hotPathPreview {
+			m = m.loadHotPathPreview()
+		}
+	case "s", "S":
+		if m.hotPathPreview {
+			if m.hotPathPreviewScope == "User" {
+				m.hotPathPreviewScope = "System"
+			} else {
+				m.hotPathPreviewScope = "User"
+			}
+			m = m.loadHotPathPreview()
+		}
+	}
+	return m
+}
+
+// loadHotPathPreview reads the PATH of the preview scope for the preview to reorder
+func (m Model) loadHotPathPreview() Model {
+	m.hotPathPreviewRaw, _ = path.GetPathRaw(m.hotPathPreviewScope)
+	return m
+}
+
+// hotPathPreviewEntries returns the loaded PATH for the preview scope with hot paths applied
+func (m Model) hotPathPreviewEntries() []string {
+	return path.PreviewHotPaths(m.hotPathPreviewRaw, m.config.HotPaths)
+}
+
+// checkHotPaths records which hot paths are missing on disk; entries with %var% are never flagged
+func (m Model) checkHotPaths() Model {
+	m.hotPathMissing = make(map[string]bool)
+	for _, hp := range m.config.HotPaths {
+		if !strings.Contains(hp, "%") && !path.PathExists(hp) {
+			m.hotPathMissing[hp] = true
+		}
+	}
+	return m
+}
+
+// stashHotPaths remembers the current hot paths so the next edit can be undone
+func (m Model) stashHotPaths() Model {
+	m.hotPathUndo = append([]string(nil), m.config.HotPaths...)
+	m.hotPathCanUndo = true
+	return m
+}
+
+// undoHotPaths restores the hot paths saved before the last edit
+func (m Model) undoHotPaths() Model {
+	if !m.hotPathCanUndo {
+		m.message = "Nothing to undo"
+		return m
+	}
+	m.config.HotPaths = m.hotPathUndo
+	m.hotPathUndo = nil
+	m.hotPathCanUndo = false
+	_ = path.SaveConfig(m.config) // Intentionally ignore error for UI config
+	m = m.checkHotPaths()
+	if m.hotPathIndex >= len(m.config.HotPaths) {
+		m.hotPathIndex = max(len(m.config.HotPaths)-1, 0)
 	}
+	m.message = "Undone"
 	return m
 }
 
 // deleteCurrentHotPath removes the currently selected hot path
 func (m Model) deleteCurrentHotPath() Model {
 	if len(m.config.HotPaths) > 0 && m.hotPathIndex < len(m.config.HotPaths) {
+		m = m.stashHotPaths()
 		m.config.HotPaths = append(m.config.HotPaths[:m.hotPathIndex], m.config.HotPaths[m.hotPathIndex+1:]...)
 		_ = path.SaveConfig(m.config) // Intentionally ignore error for UI config
 		if m.hotPathIndex >= len(m.config.HotPaths) && m.hotPathIndex > 0 {
@@ -1017,6 +4004,7 @@ func (m Model) deleteCurrentHotPath() Model {
 // moveHotPathUp moves current hot path up in priority
 func (m Model) moveHotPathUp() Model {
 	if m.hotPathIndex > 0 {
+		m = m.stashHotPaths()
 		m.config.HotPaths[m.hotPathIndex], m.config.HotPaths[m.hotPathIndex-1] = m.config.HotPaths[m.hotPathIndex-1], m.config.HotPaths[m.hotPathIndex]
 		m.hotPathIndex--
 		_ = path.SaveConfig(m.config) // Intentionally ignore error for UI config
@@ -1025,8 +4013,26 @@ func (m Model) moveHotPathUp() Model {
 }
 
 // moveHotPathDown moves current hot path down in priority
+// moveHotPathTo moves the selected hot path to index pos, shifting the ones in between
+// Positions past the end move it to the end
+func (m Model) moveHotPathTo(pos int) Model {
+	pos = min(pos, len(m.config.HotPaths)-1)
+	if m.hotPathIndex >= len(m.config.HotPaths) || pos == m.hotPathIndex {
+		return m
+	}
+	m = m.stashHotPaths()
+	moved := m.config.HotPaths[m.hotPathIndex]
+	rest := append(append([]string(nil), m.config.HotPaths[:m.hotPathIndex]...), m.config.HotPaths[m.hotPathIndex+1:]...)
+	m.config.HotPaths = append(append(rest[:pos:pos], moved), rest[pos:]...)
+	m.hotPathIndex = pos
+	_ = path.SaveConfig(m.config) // Intentionally ignore error for UI config
+	m.message = fmt.Sprintf("Moved to position %d", pos+1)
+	return m
+}
+
 func (m Model) moveHotPathDown() Model {
 	if m.hotPathIndex < len(m.config.HotPaths)-1 {
+		m = m.stashHotPaths()
 		m.config.HotPaths[m.hotPathIndex], m.config.HotPaths[m.hotPathIndex+1] = m.config.HotPaths[m.hotPathIndex+1], m.config.HotPaths[m.hotPathIndex]
 		m.hotPathIndex++
 		_ = path.SaveConfig(m.config) // Intentionally ignore error for UI config
@@ -1035,6 +4041,7 @@ func (m Model) moveHotPathDown() Model {
 }
 
 func (m Model) handleHotPathsKey(key string) (Model, tea.Cmd) {
+	m.hotPathWarning = ""
 	if m.hotPathAdding {
 		return m.handleHotPathsInputKey(key), nil
 	}
@@ -1043,6 +4050,14 @@ func (m Model) handleHotPathsKey(key string) (Model, tea.Cmd) {
 
 // View renders the UI
 func (m Model) View() string {
+	if m.config.AdvisoryMode && m.screen != ScreenLoading {
+		return WarningStyle.Render("ADVISORY MODE - analysis only, changes cannot be applied") + "\n\n" + m.viewScreen()
+	}
+	return m.viewScreen()
+}
+
+// viewScreen renders the current screen
+func (m Model) viewScreen() string {
 	switch m.screen {
 	case ScreenLoading:
 		return m.viewLoading()
@@ -1051,15 +4066,50 @@ func (m Model) View() string {
 	case ScreenOptimizer, ScreenOptimizerPreview:
 		return m.viewOptimizer()
 	case ScreenOptimizerConfirm:
-		return m.viewConfirm("Apply PATH Optimization?", "Scope: "+m.optimizerScope, ScreenOptimizerPreview)
+		detail := "Scope: " + m.optimizerScope + m.renderRegistryTargets() + "\n\n" + RenderKey("T", fmt.Sprintf("Apply with %ds rollback timer", m.rollbackSeconds()))
+		if m.backupFailed {
+			detail += "\n\n" + ErrorStyle.Render("The last backup attempt failed.") + "\n" + RenderKey("!", "Apply without backup")
+		}
+		detail += m.systemSkipNote()
+		detail += m.maintenanceReminder(m.isAdmin && m.optimizerScope != "user")
+		detail += m.renderExactValue()
+		return m.viewConfirm("Apply PATH Optimization?", detail, ScreenOptimizerPreview)
+	case ScreenOptimizerConfirmReorder:
+		return m.viewConfirmReorder()
+	case ScreenProtectedOverride:
+		return m.viewProtectedOverride()
+	case ScreenOptimizerRollback:
+		return m.viewRollback()
+	case ScreenHelp:
+		return m.viewHelp()
 	case ScreenOptimizerDone:
-		return m.viewDone("PATH optimization applied successfully!", m.backupInfo)
+		return m.viewDone(m.appliedSummary(), m.backupInfo)
+	case ScreenOptimizerUndoConfirm:
+		detail := "Write back the " + strings.Join(m.appliedScopes, " and ") + " PATH as it was just before this apply?"
+		if m.backupInfo != nil {
+			detail += "\n" + DimStyle.Render("Backup "+m.backupInfo.Filename+" is kept.")
+		}
+		return m.viewConfirm("Undo Last Apply?", detail, ScreenOptimizerDone)
 	case ScreenPathViewer:
 		return m.viewPathViewer()
+	case ScreenPathEditor:
+		return m.viewPathEditor()
+	case ScreenPathEditorConfirm:
+		diff := path.DiffPaths(m.editorOriginal, m.editorEntries)
+		detail := fmt.Sprintf("Scope: %s\nEntries: %d -> %d  Length: %d -> %d chars\nAdded: %d  Removed: %d",
+			m.editorScope, len(m.editorOriginal), len(m.editorEntries),
+			len(path.JoinPath(m.editorOriginal)), len(path.JoinPath(m.editorEntries)),
+			len(diff.Added), len(diff.Removed))
+		detail += m.renderRegistryTargets()
+		detail += "\n\n" + DimStyle.Render("A backup is created first.") + m.maintenanceReminder(m.editorScope == "System")
+		detail += m.renderExactValue()
+		return m.viewConfirm("Write edited PATH?", detail, ScreenPathEditor)
 	case ScreenBackup:
 		return m.viewBackup()
 	case ScreenBackupPreview:
 		return m.viewBackupPreview()
+	case ScreenBackupDiff:
+		return m.viewBackupDiff()
 	case ScreenBackupConfirmRestore:
 		return m.viewConfirmBackup("Restore", Yellow)
 	case ScreenBackupConfirmDelete:
@@ -1070,6 +4120,17 @@ func (m Model) View() string {
 		return m.viewJunctions()
 	case ScreenJunctionSuggestions:
 		return m.viewJunctionSuggestions()
+	case ScreenJunctionRewriteConfirm:
+		detail := DimStyle.Render(m.junctionRewriteOld) + "\n  -> " + NormalStyle.Render(m.junctionRewriteNew) + "\n\n" +
+			"Scopes: " + strings.Join(m.junctionRewriteScopes, ", ") + "\n" + DimStyle.Render("A backup is created first.")
+		return m.viewConfirm("Rewrite PATH to use the new junction?", detail, ScreenJunctionSuggestions)
+	case ScreenJunctionPruneConfirm:
+		var detail string
+		for _, j := range brokenJunctions(m.junctions) {
+			detail += ErrorStyle.Render("! "+j.Name) + DimStyle.Render(" -> "+j.Target) + "\n"
+		}
+		detail += "\n" + DimStyle.Render("Only the junctions are removed; targets are already gone.")
+		return m.viewConfirm("Remove all broken junctions?", detail, ScreenJunctions)
 	case ScreenJunctionCreate:
 		return m.viewJunctionCreate()
 	case ScreenPathExt:
@@ -1079,9 +4140,31 @@ func (m Model) View() string {
 		if m.isAdmin {
 			scope = "System"
 		}
-		return m.viewConfirm("Apply PATHEXT Optimization?", "Scope: "+scope, ScreenPathExt)
+		if m.pathExtReset {
+			detail := "Scope: " + scope + "\n\n" + DimStyle.Render("PATHEXT will be reset to the Windows default:") + "\n" + m.viewPathExtDiff()
+			return m.viewConfirm("Reset PATHEXT to Windows Default?", detail+m.maintenanceReminder(m.isAdmin), ScreenPathExt)
+		}
+		return m.viewConfirm("Apply PATHEXT Optimization?", "Scope: "+scope+"\n\n"+m.viewPathExtDiff()+m.maintenanceReminder(m.isAdmin), ScreenPathExt)
 	case ScreenPathExtDone:
 		return m.viewDone("PATHEXT optimized successfully!", nil)
+	case ScreenAutoFixConfirm:
+		return m.viewAutoFixConfirm()
+	case ScreenChangeDetail:
+		return m.viewChangeDetail()
+	case ScreenAuditLog:
+		return m.viewAuditLog()
+	case ScreenExternalChanges:
+		return m.viewExternalChanges()
+	case ScreenJunctionPreview:
+		return m.viewJunctionPreview()
+	case ScreenJunctionExcludes:
+		return m.viewJunctionExcludes()
+	case ScreenEnvVars:
+		return m.viewEnvVars()
+	case ScreenShortNames:
+		return m.viewShortNames()
+	case ScreenAutoFixDone:
+		return m.viewDone("Recommended fixes applied!", m.backupInfo)
 	case ScreenSettings:
 		return m.viewSettings()
 	case ScreenHotPaths:
@@ -1121,6 +4204,11 @@ func (m Model) viewLoading() string {
 	} else {
 		b.WriteString("  " + DimStyle.Render("Please wait...") + "\n")
 	}
+	if m.loadingWrite {
+		b.WriteString("\n  " + DimStyle.Render("Writing changes, this can't be cancelled"))
+	} else {
+		b.WriteString("\n  " + RenderKey("Esc", "Cancel"))
+	}
 
 	return b.String()
 }
@@ -1131,8 +4219,17 @@ func (m Model) viewMenu() string {
 	if m.isAdmin {
 		title += SuccessStyle.Render(" [Admin]")
 	}
+	if m.config.AdvisoryMode {
+		title += WarningStyle.Render(" [Advisory]")
+	}
 	b.WriteString(title + "\n\n")
-
+	if m.shellErr != nil {
+		b.WriteString(ErrorStyle.Render(m.shellErr.Error()) + "\n")
+		b.WriteString(DimStyle.Render("Read-only: View Current PATH shows the PATH WinPath inherited; nothing can be changed") + "\n\n")
+	}
+	if m.externalChanges != nil {
+		b.WriteString(WarningStyle.Render(fmt.Sprintf("PATH changed since last backup (%d entries differ)", m.externalChanges.Count())) + "  " + RenderKey("D", "Details") + "\n\n")
+	}
 	for i, item := range m.menuItems {
 		cursor := "  "
 		style := NormalStyle
@@ -1142,8 +4239,11 @@ func (m Model) viewMenu() string {
 		}
 		b.WriteString(cursor + DimStyle.Render(fmt.Sprintf("[%d] ", i+1)) + style.Render(item) + "\n")
 	}
+	if m.err != nil && m.message != "" {
+		b.WriteString("\n" + ErrorStyle.Render(m.message) + "\n")
+	}
 
-	b.WriteString("\n" + FooterStyle.Render("Use arrows or numbers, Enter to select, Q to quit"))
+	b.WriteString("\n" + FooterStyle.Render("Use arrows or numbers, Enter to select, ? for help, Q to quit"))
 	return b.String()
 }
 
@@ -1153,7 +4253,22 @@ func (m Model) viewOptimizer() string {
 	}
 
 	var b strings.Builder
-	b.WriteString(TitleStyle.Render("PATH Optimization Preview") + "\n")
+	b.WriteString(TitleStyle.Render("PATH Optimization Preview"))
+	if !m.lastAnalyzedAt.IsZero() {
+		analyzed := "analyzed " + m.lastAnalyzedAt.Format("15:04:05")
+		if m.analysisCached {
+			b.WriteString(" " + WarningStyle.Render("[cached]") + DimStyle.Render(" "+analyzed+", R to refresh"))
+		} else {
+			b.WriteString(" " + DimStyle.Render(analyzed))
+		}
+	}
+	b.WriteString("\n")
+	preset := optimizerPreset(m.config)
+	for _, p := range path.OptimizePresets {
+		if p.Name == preset {
+			b.WriteString(DimStyle.Render("Preset: "+p.Name+" ("+p.Description+")") + "\n")
+		}
+	}
 
 	// Simple tab bar without boxes
 	tabs := []string{"Summary", "Changes", "Raw", "List"}
@@ -1162,10 +4277,18 @@ func (m Model) viewOptimizer() string {
 		if i == m.viewMode {
 			tabLine += SelectedStyle.Render(fmt.Sprintf("[%d] %s", i+1, tab)) + "  "
 		} else {
-			tabLine += DimStyle.Render(fmt.Sprintf("[%d] %s", i+1, tab)) + "  "
+			tabLine += DimStyle.Render(fmt.Sprintf("[%d] %s", i+1, tab)) + "  "
+		}
+	}
+	b.WriteString(tabLine + "\n\n")
+
+	if m.message != "" {
+		if m.err != nil {
+			b.WriteString(ErrorStyle.Render(m.message) + "\n\n")
+		} else {
+			b.WriteString(SuccessStyle.Render(m.message) + "\n\n")
 		}
 	}
-	b.WriteString(tabLine + "\n\n")
 
 	switch m.viewMode {
 	case 0:
@@ -1178,10 +4301,28 @@ func (m Model) viewOptimizer() string {
 		b.WriteString(m.renderList())
 	}
 
-	b.WriteString("\n" + RenderKey("1-4", "Tab") + "  " + RenderKey("A", "Apply") + "  " + RenderKey("S", "Scope: "+m.optimizerScope) + "  " + RenderKey("Esc", "Menu"))
+	b.WriteString("\n" + RenderKey("1-4", "Tab") + "  ")
+	if !m.config.AdvisoryMode {
+		b.WriteString(RenderKey("A", "Apply") + "  ")
+	}
+	if !m.isAdmin && m.optimizerScope != "user" {
+		b.WriteString(RenderKey("E", "Relaunch as admin") + "  ")
+	}
+	if len(m.shortNameChanges()) > 0 {
+		b.WriteString(RenderKey("8", "8.3 names") + "  ")
+	}
+	b.WriteString(RenderKey("S", "Scope: "+m.optimizerScope) + "  " + RenderKey("P", "Preset") + "  " + RenderKey("R", "Refresh") + "  " + RenderKey("X", "Export JSON") + "  " + RenderKey("W", "Write entries") + "  " + RenderKey("Y", "Yank PATH") + "  " + RenderKey("Esc", "Menu"))
 	return b.String()
 }
 
+// lookupEstimate renders the estimated lookup speedup as a summary line, or "" when there is none
+func lookupEstimate(metrics path.OptimizeMetrics) string {
+	if metrics.EstimatedLookupsAvoided == 0 {
+		return ""
+	}
+	return DimStyle.Render(fmt.Sprintf("Lookups: ~%d fewer file checks per command (rough estimate)", metrics.EstimatedLookupsAvoided)) + "\n"
+}
+
 func (m Model) renderSummary() string {
 	var b strings.Builder
 	sys := m.analysis.System
@@ -1199,6 +4340,9 @@ func (m Model) renderSummary() string {
 	sysContent += DimStyle.Render(fmt.Sprintf("Dup: %d  Dead: %d  Short: %d  Vars: %d",
 		sys.Metrics.DuplicatesRemoved, sys.Metrics.DeadPathsRemoved,
 		sys.Metrics.PathsShortened, sys.Metrics.VarsSubstituted)) + "\n"
+	sysContent += DimStyle.Render(fmt.Sprintf("Checked: %d  Unchecked (vars): %d",
+		sys.Metrics.CheckedEntries, sys.Metrics.UncheckedEntries)) + "\n"
+	sysContent += lookupEstimate(sys.Metrics)
 	sysContent += SuccessStyle.Render(fmt.Sprintf("Saved: %.1f%%", sys.Metrics.PercentageSaved))
 	if !m.isAdmin {
 		sysContent += "\n" + WarningStyle.Render("(Read-only - needs admin)")
@@ -1212,6 +4356,9 @@ func (m Model) renderSummary() string {
 	usrContent += DimStyle.Render(fmt.Sprintf("Dup: %d  Dead: %d  Short: %d  Vars: %d",
 		usr.Metrics.DuplicatesRemoved, usr.Metrics.DeadPathsRemoved,
 		usr.Metrics.PathsShortened, usr.Metrics.VarsSubstituted)) + "\n"
+	usrContent += DimStyle.Render(fmt.Sprintf("Checked: %d  Unchecked (vars): %d",
+		usr.Metrics.CheckedEntries, usr.Metrics.UncheckedEntries)) + "\n"
+	usrContent += lookupEstimate(usr.Metrics)
 	usrContent += SuccessStyle.Render(fmt.Sprintf("Saved: %.1f%%", usr.Metrics.PercentageSaved))
 	b.WriteString(usrStyle.Render(usrContent))
 
@@ -1225,46 +4372,298 @@ func (m Model) renderSummary() string {
 		b.WriteString(customStyle.Render(strings.TrimSuffix(customContent, "\n")))
 	}
 
+	if len(m.analysis.JunctionEquivalents) > 0 {
+		b.WriteString("\n\n")
+		eqStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Yellow).Padding(0, 1)
+		eqContent := WarningStyle.Render("Duplicates via Junctions") + "\n"
+		for _, eq := range m.analysis.JunctionEquivalents {
+			eqContent += DimStyle.Render(fmt.Sprintf("  %s = %s (junction %s)", eq.Entry, eq.Equivalent, eq.Junction)) + "\n"
+		}
+		b.WriteString(eqStyle.Render(strings.TrimSuffix(eqContent, "\n")))
+	}
+
+	if len(m.analysis.ShadowedTools) > 0 {
+		b.WriteString("\n\n")
+		shadowStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Yellow).Padding(0, 1)
+		shadowContent := WarningStyle.Render("Shadowed Tools") + " " + DimStyle.Render("(the first copy on PATH runs)") + "\n"
+		for _, s := range m.analysis.ShadowedTools {
+			shadowContent += DimStyle.Render(fmt.Sprintf("  %s: %s hides %s", s.Tool, s.Winner, strings.Join(s.Shadowed, ", "))) + "\n"
+		}
+		b.WriteString(shadowStyle.Render(strings.TrimSuffix(shadowContent, "\n")))
+	}
+
+	if len(m.analysis.UserVarsInSystem) > 0 {
+		b.WriteString("\n\n")
+		userVarStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Red).Padding(0, 1)
+		userVarContent := ErrorStyle.Render("Per-User Variables in System PATH") + " " + DimStyle.Render("(move these to User PATH)") + "\n"
+		for _, u := range m.analysis.UserVarsInSystem {
+			userVarContent += ErrorStyle.Render("  %"+u.Variable+"%") + DimStyle.Render(" in "+u.Entry) + "\n"
+		}
+		b.WriteString(userVarStyle.Render(strings.TrimSuffix(userVarContent, "\n")))
+	}
+
+	if len(m.analysis.MisdirectedVars) > 0 {
+		b.WriteString("\n\n")
+		misStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Yellow).Padding(0, 1)
+		misContent := WarningStyle.Render("Possibly Misdirected PATH Additions") + " " + DimStyle.Render("(folder lists outside PATH)") + "\n"
+		for _, v := range m.analysis.MisdirectedVars {
+			misContent += WarningStyle.Render("  %"+v.Name+"%") + DimStyle.Render(" = "+strings.Join(v.Entries, ";")) + "\n"
+		}
+		b.WriteString(misStyle.Render(strings.TrimSuffix(misContent, "\n")))
+	}
+
+	if len(sys.ProtectedViolations)+len(usr.ProtectedViolations) > 0 {
+		b.WriteString("\n\n")
+		protStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Red).Padding(0, 1)
+		protContent := ErrorStyle.Render("Protected Entries Affected") + " " + DimStyle.Render("(apply needs an override for each)") + "\n"
+		for _, v := range append(append([]path.ProtectedViolation{}, sys.ProtectedViolations...), usr.ProtectedViolations...) {
+			if v.Action == "modified" {
+				protContent += DimStyle.Render(fmt.Sprintf("  %s -> %s", v.Entry, v.New)) + "\n"
+			} else {
+				protContent += DimStyle.Render(fmt.Sprintf("  %s (removed)", v.Entry)) + "\n"
+			}
+		}
+		b.WriteString(protStyle.Render(strings.TrimSuffix(protContent, "\n")))
+	}
+
+	if sys.StoredAsString || usr.StoredAsString {
+		b.WriteString("\n\n")
+		kindStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Red).Padding(0, 1)
+		kindContent := ErrorStyle.Render("PATH Stored as REG_SZ") + " " + DimStyle.Render("(%VARS% will not expand)") + "\n"
+		for _, s := range []struct {
+			label string
+			r     path.OptimizeResult
+		}{{"System", sys}, {"User", usr}} {
+			if s.r.StoredAsString {
+				kindContent += DimStyle.Render("  "+s.label+" PATH will be converted to REG_EXPAND_SZ on the next apply") + "\n"
+			}
+		}
+		b.WriteString(kindStyle.Render(strings.TrimSuffix(kindContent, "\n")))
+	}
+
+	if conflicts := append(append([]path.CaseConflict{}, sys.CaseConflicts...), usr.CaseConflicts...); len(conflicts) > 0 {
+		b.WriteString("\n\n")
+		caseStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Yellow).Padding(0, 1)
+		caseContent := WarningStyle.Render("Case-Sensitive Directories") + " " + DimStyle.Render("(case variants kept, check both)") + "\n"
+		for _, c := range conflicts {
+			caseContent += DimStyle.Render(fmt.Sprintf("  %s / %s (in %s)", c.Entry, c.Variant, c.Directory)) + "\n"
+		}
+		b.WriteString(caseStyle.Render(strings.TrimSuffix(caseContent, "\n")))
+	}
+
+	if len(m.analysis.NestedEntries) > 0 {
+		b.WriteString("\n\n")
+		nestedStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Gray).Padding(0, 1)
+		nestedContent := InfoStyle.Render("Nested Entries") + " " + DimStyle.Render("(check if both are needed)") + "\n"
+		for _, n := range m.analysis.NestedEntries {
+			nestedContent += DimStyle.Render(fmt.Sprintf("  %s contains %s", n.Parent, n.Child)) + "\n"
+		}
+		b.WriteString(nestedStyle.Render(strings.TrimSuffix(nestedContent, "\n")))
+	}
+
+	return b.String()
+}
+
+// viewChangeDetail explains why the selected change was made
+func (m Model) viewAuditLog() string {
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("Audit Log") + " " + DimStyle.Render(fmt.Sprintf("(%d entries)", len(m.auditLines))) + "\n")
+	b.WriteString(DimStyle.Render(path.GetAuditLogPath()) + "\n\n")
+
+	if m.message != "" {
+		b.WriteString(ErrorStyle.Render(m.message) + "\n\n")
+	}
+
+	boxStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Gray).Padding(0, 1)
+	var content string
+	start := clampScroll(m.scrollOffset, len(m.auditLines), listMaxVisible)
+	end := min(start+listMaxVisible, len(m.auditLines))
+	if start > 0 {
+		content += DimStyle.Render(fmt.Sprintf("... %d older", start)) + "\n"
+	}
+	for _, line := range m.auditLines[start:end] {
+		content += NormalStyle.Render(line) + "\n"
+	}
+	if end < len(m.auditLines) {
+		content += DimStyle.Render(fmt.Sprintf("... %d newer", len(m.auditLines)-end)) + "\n"
+	}
+	if len(m.auditLines) == 0 {
+		content = DimStyle.Render("No changes recorded yet.")
+	}
+	b.WriteString(boxStyle.Render(strings.TrimSuffix(content, "\n")) + "\n\n")
+	b.WriteString(RenderKey("j/k", "Scroll") + "  " + RenderKey("g/G", "Oldest / Newest") + "  " + RenderKey("R", "Reload") + "  " + RenderKey("Esc", "Menu"))
+	return b.String()
+}
+
+// viewExternalChanges lists PATH entries added or removed outside WinPath
+func (m Model) viewExternalChanges() string {
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("PATH Changed Outside WinPath") + "\n")
+	if m.externalChanges != nil && m.externalChanges.Backup != "" {
+		b.WriteString(DimStyle.Render("Compared with "+m.externalChanges.Backup+" and WinPath's own later changes") + "\n")
+	}
+	b.WriteString("\n")
+
+	if m.message != "" {
+		b.WriteString(ErrorStyle.Render(m.message) + "\n\n")
+	}
+
+	lines := m.externalChangeLines()
+	start := clampScroll(m.scrollOffset, len(lines), listMaxVisible)
+	end := min(start+listMaxVisible, len(lines))
+	boxStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Gray).Padding(0, 1)
+	b.WriteString(boxStyle.Render(strings.Join(lines[start:end], "\n")) + "\n\n")
+
+	if len(lines) > listMaxVisible {
+		b.WriteString(RenderKey("j/k", "Scroll") + "  ")
+	}
+	b.WriteString(RenderKey("B", "Back up to accept") + "  " + RenderKey("Esc", "Menu"))
 	return b.String()
 }
 
+func (m Model) viewChangeDetail() string {
+	changes := m.visibleChanges()
+	if m.changeIndex >= len(changes) {
+		return DimStyle.Render("No change selected") + "\n\n" + RenderKey("Esc", "Back")
+	}
+	sc := changes[m.changeIndex]
+	c := sc.change
+	scope := "User"
+	if sc.scope == "SYS" {
+		scope = "System"
+	}
+
+	var title, detail string
+	switch c.Type {
+	case "duplicate":
+		title = "Removed as a duplicate"
+		detail = DimStyle.Render("Entry:      ") + NormalStyle.Render(c.Original) + "\n"
+		detail += DimStyle.Render("Matches:    ") + NormalStyle.Render(c.MatchedWith) + DimStyle.Render(" (kept, listed earlier)") + "\n"
+		detail += DimStyle.Render("Normalized: ") + NormalStyle.Render(path.NormalizePath(c.Original)) + "\n\n"
+		detail += DimStyle.Render("Both compare equal ignoring case and trailing separators, so only the first is kept.")
+	case "dead":
+		title = "Removed as a dead path"
+		detail = DimStyle.Render("Entry:  ") + NormalStyle.Render(c.Original) + "\n"
+		detail += DimStyle.Render("Reason: ") + ErrorStyle.Render(c.Reason) + "\n\n"
+		detail += DimStyle.Render("Windows skips folders that don't exist, so the entry only slows lookups.")
+	case "reordered":
+		title = "Moved by a Hot Paths rule"
+		detail = DimStyle.Render("Entry: ") + NormalStyle.Render(c.Original) + "\n\n"
+		detail += DimStyle.Render("Hot paths are moved to the front so their tools win over other copies.")
+	default:
+		title = map[string]string{
+			"malformed": "Rewritten with backslashes",
+			"canonical": "Rewritten to its on-disk form",
+			"shortened": "Shortened to its 8.3 name",
+			"variable":  "Shortened with an environment variable",
+		}[c.Type]
+		detail = DimStyle.Render("Before: ") + NormalStyle.Render(c.Original) + "\n"
+		detail += DimStyle.Render("After:  ") + NormalStyle.Render(c.New) + "\n"
+		detail += DimStyle.Render("Saved:  ") + SuccessStyle.Render(fmt.Sprintf("%d chars", c.Saved))
+	}
+
+	boxStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Cyan).Padding(1, 2)
+	content := InfoStyle.Render(title) + " " + DimStyle.Render("("+scope+" PATH)") + "\n\n" + detail + "\n\n" + RenderKey("Esc", "Back")
+	return boxStyle.Render(content)
+}
+
+// changeTypes lists the change categories in the order shown in the Changes filter bar
+var changeTypes = []struct {
+	name  string
+	label string
+}{
+	{"duplicate", "DUP"},
+	{"dead", "DEAD"},
+	{"shortened", "8.3"},
+	{"variable", "VAR"},
+	{"reordered", "MOVE"},
+	{"canonical", "CASE"},
+	{"malformed", "SLASH"},
+}
+
+// toggleChangeType shows or hides a change type in the Changes tab
+func (m Model) toggleChangeType(changeType string) Model {
+	hidden := make(map[string]bool, len(m.hiddenChangeTypes)+1)
+	for k, v := range m.hiddenChangeTypes {
+		hidden[k] = v
+	}
+	hidden[changeType] = !hidden[changeType]
+	m.hiddenChangeTypes = hidden
+	m.scrollOffset = 0
+	m.changeIndex = 0
+	return m
+}
+
+// renderChangeFilter renders the per-type visibility toggles
+func (m Model) renderChangeFilter() string {
+	var parts []string
+	for i, ct := range changeTypes {
+		box := "[x]"
+		if m.hiddenChangeTypes[ct.name] {
+			box = "[ ]"
+		}
+		item := box + " " + ct.label
+		if i == m.changeFilterIndex {
+			parts = append(parts, SelectedStyle.Render(item))
+		} else {
+			parts = append(parts, DimStyle.Render(item))
+		}
+	}
+	return strings.Join(parts, "  ")
+}
+
 func (m Model) renderChanges() string {
 	var b strings.Builder
 	var allChanges []string
-
-	addChanges := func(changes []path.PathChange, scope string) {
-		for _, c := range changes {
-			var line string
-			switch c.Type {
-			case "duplicate":
-				line = WarningStyle.Render("[DUP]") + " " + DimStyle.Render(c.Original)
-			case "dead":
-				line = ErrorStyle.Render("[DEAD]") + " " + DimStyle.Render(c.Original)
-			case "shortened":
-				line = SuccessStyle.Render("[8.3]") + " " + DimStyle.Render(c.Original) + "\n        -> " + NormalStyle.Render(c.New)
-			case "variable":
-				line = SuccessStyle.Render("[VAR]") + " " + DimStyle.Render(c.Original) + "\n        -> " + NormalStyle.Render(c.New)
+	totalChanges := len(m.analysis.System.Changes) + len(m.analysis.User.Changes)
+
+	b.WriteString(m.renderChangeFilter() + "\n\n")
+
+	for i, sc := range m.visibleChanges() {
+		c := sc.change
+		var line string
+		switch c.Type {
+		case "duplicate":
+			line = WarningStyle.Render("[DUP]") + " " + DimStyle.Render(c.Original)
+		case "dead":
+			line = ErrorStyle.Render("[DEAD]") + " " + DimStyle.Render(c.Original)
+		case "malformed":
+			line = ErrorStyle.Render("[SLASH]") + " " + DimStyle.Render(c.Original) + "\n        -> " + NormalStyle.Render(c.New)
+			if path.IsWSLPath(c.Original) {
+				line += DimStyle.Render(" (suggested, fix by hand)")
 			}
-			allChanges = append(allChanges, SubtitleStyle.Render("["+scope+"]")+" "+line)
+		case "canonical":
+			line = InfoStyle.Render("[CASE]") + " " + DimStyle.Render(c.Original) + "\n        -> " + NormalStyle.Render(c.New)
+		case "shortened":
+			line = SuccessStyle.Render("[8.3]") + " " + DimStyle.Render(c.Original) + "\n        -> " + NormalStyle.Render(c.New)
+		case "variable":
+			line = SuccessStyle.Render("[VAR]") + " " + DimStyle.Render(c.Original) + "\n        -> " + NormalStyle.Render(c.New)
+		case "reordered":
+			line = InfoStyle.Render("[MOVE]") + " " + DimStyle.Render(c.Original)
+		}
+		cursor := "  "
+		if i == m.changeIndex {
+			cursor = SelectedStyle.Render("> ")
 		}
+		allChanges = append(allChanges, cursor+SubtitleStyle.Render("["+sc.scope+"]")+" "+line)
 	}
 
-	addChanges(m.analysis.System.Changes, "SYS")
-	addChanges(m.analysis.User.Changes, "USR")
-
-	if len(allChanges) == 0 {
+	if totalChanges == 0 {
 		return DimStyle.Render("No changes - PATH is already optimized!")
 	}
+	if len(allChanges) == 0 {
+		b.WriteString(DimStyle.Render(fmt.Sprintf("All %d changes hidden by filter", totalChanges)))
+		return b.String()
+	}
 
-	maxVisible := 12
 	start := m.scrollOffset
-	if start > len(allChanges)-maxVisible {
-		start = len(allChanges) - maxVisible
+	if start > len(allChanges)-changesMaxVisible {
+		start = len(allChanges) - changesMaxVisible
 	}
 	if start < 0 {
 		start = 0
 	}
-	end := start + maxVisible
+	end := start + changesMaxVisible
 	if end > len(allChanges) {
 		end = len(allChanges)
 	}
@@ -1278,7 +4677,12 @@ func (m Model) renderChanges() string {
 	if end < len(allChanges) {
 		b.WriteString(DimStyle.Render(fmt.Sprintf("     ... %d more below\n", len(allChanges)-end)))
 	}
-	b.WriteString(DimStyle.Render(fmt.Sprintf("\n%d total changes", len(allChanges))))
+	if len(allChanges) < totalChanges {
+		b.WriteString(DimStyle.Render(fmt.Sprintf("\n%d of %d changes shown", len(allChanges), totalChanges)))
+	} else {
+		b.WriteString(DimStyle.Render(fmt.Sprintf("\n%d total changes", len(allChanges))))
+	}
+	b.WriteString("\n" + RenderKey("F", "Next filter") + "  " + RenderKey("Space", "Toggle type") + "  " + RenderKey("Enter", "Why?"))
 
 	return b.String()
 }
@@ -1302,7 +4706,7 @@ func (m Model) renderRaw() string {
 
 	boxStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Gray).Padding(0, 1)
 	b.WriteString(boxStyle.Render(wrapText(data.Optimized.Raw, 72)))
-	b.WriteString("\n\n" + DimStyle.Render("Press [S] to switch scope (showing: "+label+")"))
+	b.WriteString("\n\n" + DimStyle.Render("Press [S] to switch scope (showing: "+label+"), [C] to copy"))
 
 	return b.String()
 }
@@ -1324,15 +4728,14 @@ func (m Model) renderList() string {
 	entries := data.Optimized.Entries
 	b.WriteString(SubtitleStyle.Render(fmt.Sprintf("Optimized %s (%d entries):", label, len(entries))) + "\n\n")
 
-	maxVisible := 16
 	start := m.scrollOffset
-	if start > len(entries)-maxVisible {
-		start = len(entries) - maxVisible
+	if start > len(entries)-listMaxVisible {
+		start = len(entries) - listMaxVisible
 	}
 	if start < 0 {
 		start = 0
 	}
-	end := start + maxVisible
+	end := start + listMaxVisible
 	if end > len(entries) {
 		end = len(entries)
 	}
@@ -1342,10 +4745,11 @@ func (m Model) renderList() string {
 	}
 	for i := start; i < end; i++ {
 		entry := entries[i]
+		marker := existenceMarker(entry, false)
 		if len(entry) > 64 {
 			entry = entry[:61] + "..."
 		}
-		b.WriteString(DimStyle.Render(fmt.Sprintf("%3d. ", i+1)) + NormalStyle.Render(entry) + "\n")
+		b.WriteString(DimStyle.Render(fmt.Sprintf("%3d. ", i+1)) + marker + " " + NormalStyle.Render(entry) + "\n")
 	}
 	if end < len(entries) {
 		b.WriteString(DimStyle.Render(fmt.Sprintf("     ... %d below\n", len(entries)-end)))
@@ -1354,12 +4758,232 @@ func (m Model) renderList() string {
 	return b.String()
 }
 
+// existenceMarker returns the marker shown before a PATH entry: "?" when it has unresolved
+// variables, "!" when it is missing on disk and "*" otherwise. Entries with %var% are
+// never flagged as missing, since they can't be checked without expanding them
+func existenceMarker(entry string, unresolved bool) string {
+	switch {
+	case unresolved:
+		return WarningStyle.Render("?")
+	case !strings.Contains(entry, "%") && !path.PathExists(entry):
+		return ErrorStyle.Render("!")
+	}
+	return SuccessStyle.Render("*")
+}
+
 func (m Model) viewConfirm(title, detail string, _ Screen) string {
 	boxStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Yellow).Padding(1, 2)
 	content := WarningStyle.Render(title) + "\n\n" + detail + "\n\n" + RenderKey("Y", "Yes") + "  " + RenderKey("N", "No")
 	return boxStyle.Render(content)
 }
 
+// pathExtDiffMinWidth is the narrowest terminal that gets the side-by-side PATHEXT diff
+const pathExtDiffMinWidth = 60
+
+// viewPathExtDiff shows the current PATHEXT order next to the one about to be applied,
+// marking removed (-), added (+) and moved (~) extensions; narrow terminals get it stacked
+func (m Model) viewPathExtDiff() string {
+	if m.pathExtOpt == nil {
+		return ""
+	}
+	var current []string
+	if m.pathExtAnalysis != nil {
+		current = m.pathExtAnalysis.Current
+	} else {
+		current = path.ParsePath(m.pathExtOpt.Original)
+	}
+	optimized := m.pathExtOpt.Optimized
+
+	indexOf := func(list []string, ext string) int {
+		for i, e := range list {
+			if strings.EqualFold(e, ext) {
+				return i
+			}
+		}
+		return -1
+	}
+	column := func(title string, list, other []string, missing string, missingStyle lipgloss.Style) string {
+		col := InfoStyle.Render(title) + "\n"
+		for i, ext := range list {
+			switch j := indexOf(other, ext); {
+			case j == -1:
+				col += missingStyle.Render(missing+" "+ext) + "\n"
+			case j != i:
+				col += WarningStyle.Render("~ "+ext) + "\n"
+			default:
+				col += NormalStyle.Render("  "+ext) + "\n"
+			}
+		}
+		return strings.TrimSuffix(col, "\n")
+	}
+
+	before := column("Current", current, optimized, "-", ErrorStyle)
+	after := column("New", optimized, current, "+", SuccessStyle)
+	var columns string
+	if m.width > 0 && m.width < pathExtDiffMinWidth {
+		columns = before + "\n\n" + after
+	} else {
+		columns = lipgloss.JoinHorizontal(lipgloss.Top, before, "    ", after)
+	}
+
+	return columns + "\n\n" +
+		DimStyle.Render("Before: ") + NormalStyle.Render(strings.Join(current, ";")) + "\n" +
+		DimStyle.Render("After:  ") + NormalStyle.Render(m.pathExtOpt.OptimizedString)
+}
+
+// viewAutoFixConfirm lists what auto-fix will change before the single confirmation
+func (m Model) viewAutoFixConfirm() string {
+	if !m.autoFixHasChanges() {
+		boxStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Green).Padding(1, 2)
+		content := SuccessStyle.Render("Nothing to fix") + "\n\n"
+		content += DimStyle.Render("PATH has no duplicate or dead entries and .EXE is already first in PATHEXT.") + "\n\n"
+		content += RenderKey("Esc", "Back")
+		return boxStyle.Render(content)
+	}
+
+	detail := DimStyle.Render("Removes duplicate and dead PATH entries and moves .EXE to the front of PATHEXT.") + "\n"
+	detail += DimStyle.Render("One backup is taken before anything is written.") + "\n\n"
+
+	writesSystem := false
+	for _, scope := range m.autoFixScopes() {
+		r := m.autoFixResult(scope)
+		if !pathChanged(r) {
+			detail += NormalStyle.Render(scope+" PATH: ") + DimStyle.Render("unchanged") + "\n"
+			continue
+		}
+		writesSystem = writesSystem || scope == "System"
+		detail += NormalStyle.Render(scope+" PATH: ") + SuccessStyle.Render(fmt.Sprintf("%d duplicates, %d dead paths removed (%d -> %d chars)",
+			r.Metrics.DuplicatesRemoved, r.Metrics.DeadPathsRemoved, r.Original.Length, r.Optimized.Length)) + "\n"
+	}
+	if !m.isAdmin {
+		detail += NormalStyle.Render("System PATH: ") + DimStyle.Render("skipped (run as administrator)") + "\n"
+	}
+
+	extScope := pathExtScope(m.isAdmin)
+	if m.autoFixPathExt.Changed {
+		writesSystem = writesSystem || m.isAdmin
+		detail += NormalStyle.Render("PATHEXT ("+extScope+"): ") + DimStyle.Render(m.autoFixPathExt.Original+" -> ") + SuccessStyle.Render(m.autoFixPathExt.OptimizedString)
+	} else {
+		detail += NormalStyle.Render("PATHEXT ("+extScope+"): ") + DimStyle.Render(".EXE already first")
+	}
+
+	if protected := m.autoFixProtected(); len(protected) > 0 {
+		detail += "\n\n" + ErrorStyle.Render("Protected entries would change:")
+		for _, v := range protected {
+			detail += "\n" + DimStyle.Render("  "+v.Entry)
+		}
+	}
+	if m.message != "" {
+		detail += "\n\n" + ErrorStyle.Render(m.message)
+	}
+	detail += m.maintenanceReminder(writesSystem)
+	return m.viewConfirm("Apply Recommended Fixes?", detail, ScreenMenu)
+}
+
+// viewConfirmReorder is the lighter prompt used when applying only moves entries
+func (m Model) viewConfirmReorder() string {
+	boxStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Gray).Padding(0, 2)
+	content := InfoStyle.Render("Reorder PATH?") + " " + DimStyle.Render("(scope: "+m.optimizerScope+")") + "\n"
+	content += DimStyle.Render("Only the order changes; no entries are removed or rewritten.")
+	content += m.renderRegistryTargets()
+	content += m.systemSkipNote()
+	content += m.maintenanceReminder(m.isAdmin && m.optimizerScope != "user")
+	content += m.renderExactValue() + "\n\n"
+	content += RenderKey("Enter", "Apply") + "  " + RenderKey("N", "Cancel")
+	return boxStyle.Render(content)
+}
+
+func (m Model) viewShortNames() string {
+	var b strings.Builder
+	changes := m.shortNameChanges()
+	b.WriteString(TitleStyle.Render("8.3 Short Names") + "\n")
+	b.WriteString(WarningStyle.Render("Short names like PROGRA~1 are assigned by the file system and can change") + "\n")
+	b.WriteString(WarningStyle.Render("after an uninstall or reinstall, leaving the PATH entry pointing elsewhere.") + "\n")
+	b.WriteString(DimStyle.Render("Reject a conversion to keep its long name.") + "\n\n")
+
+	boxStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Yellow).Padding(0, 1)
+	saved := 0
+	for _, c := range changes {
+		if !m.shortNameRejected[shortNameKey(c)] {
+			saved += c.change.Saved
+		}
+	}
+	var content string
+	start := clampScroll(m.shortNameIndex-listMaxVisible/2, len(changes), listMaxVisible)
+	for i := start; i < min(start+listMaxVisible, len(changes)); i++ {
+		c := changes[i]
+		cursor := "  "
+		style := NormalStyle
+		if i == m.shortNameIndex {
+			cursor = SelectedStyle.Render("> ")
+			style = SelectedStyle
+		}
+		check := "[x] "
+		if m.shortNameRejected[shortNameKey(c)] {
+			check = "[ ] "
+		}
+		content += cursor + style.Render(check+"["+c.scope+"] "+c.change.Original) + "\n"
+		content += "      " + DimStyle.Render(fmt.Sprintf("-> %s (saves %d chars)", c.change.New, c.change.Saved)) + "\n"
+	}
+	b.WriteString(boxStyle.Render(strings.TrimSuffix(content, "\n")) + "\n")
+	b.WriteString(DimStyle.Render(fmt.Sprintf("Accepted conversions save %d chars", saved)) + "\n\n")
+
+	b.WriteString(RenderKey("Space", "Accept/Reject") + "  " + RenderKey("A", "Accept all") + "  " + RenderKey("R", "Reject all") + "  " + RenderKey("Enter", "Build PATH") + "  " + RenderKey("Esc", "Cancel"))
+	return b.String()
+}
+
+func (m Model) viewProtectedOverride() string {
+	var b strings.Builder
+	b.WriteString(ErrorStyle.Render("Protected Entries") + "\n")
+	b.WriteString(DimStyle.Render("This optimization would change entries marked as protected.") + "\n")
+	b.WriteString(DimStyle.Render("Override each one to apply anyway.") + "\n\n")
+
+	if m.message != "" {
+		b.WriteString(WarningStyle.Render(m.message) + "\n\n")
+	}
+
+	boxStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Red).Padding(0, 1)
+	var content string
+	for i, item := range m.protectedViolations() {
+		cursor := "  "
+		style := NormalStyle
+		if i == m.protectedIndex {
+			cursor = SelectedStyle.Render("> ")
+			style = SelectedStyle
+		}
+		check := "[ ] "
+		if m.protectedOverrides[item.key()] {
+			check = "[x] "
+		}
+		detail := " would be removed"
+		if item.Action == "modified" {
+			detail = " -> " + item.New
+		}
+		content += cursor + style.Render(check+"["+item.scope+"] "+item.Entry) + DimStyle.Render(detail) + "\n"
+	}
+	b.WriteString(boxStyle.Render(strings.TrimSuffix(content, "\n")) + "\n\n")
+
+	b.WriteString(RenderKey("Space", "Override") + "  " + RenderKey("Enter", "Continue") + "  " + RenderKey("Esc", "Cancel"))
+	return b.String()
+}
+
+// rollbackSeconds returns the configured rollback window
+func (m Model) rollbackSeconds() int {
+	if m.config.RollbackSeconds > 0 {
+		return m.config.RollbackSeconds
+	}
+	return path.DefaultConfig().RollbackSeconds
+}
+
+func (m Model) viewRollback() string {
+	boxStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Yellow).Padding(1, 2)
+	content := WarningStyle.Render("Keep these changes?") + "\n\n"
+	content += NormalStyle.Render(fmt.Sprintf("PATH will be reverted in %d seconds unless you keep it.", m.rollbackRemaining)) + "\n\n"
+	content += DimStyle.Render("Open a new terminal now to check your tools still work.") + "\n\n"
+	content += RenderKey("K", "Keep") + "  " + RenderKey("R", "Revert now")
+	return boxStyle.Render(content)
+}
+
 func (m Model) viewDone(msg string, backup *path.BackupInfo) string {
 	var b strings.Builder
 	b.WriteString(SuccessStyle.Render(msg) + "\n\n")
@@ -1379,62 +5003,184 @@ func (m Model) viewDone(msg string, backup *path.BackupInfo) string {
 	} else {
 		b.WriteString(RenderKey("C", "Copy to clipboard") + "\n")
 	}
+	if m.screen == ScreenOptimizerDone {
+		if m.message != "" {
+			style := InfoStyle
+			if m.err != nil {
+				style = ErrorStyle
+			}
+			b.WriteString(style.Render(m.message) + "\n")
+		}
+		b.WriteString(RenderKey("Y", "Yank full optimized PATH") + "\n")
+		if len(m.skippedScopes) > 0 {
+			b.WriteString(RenderKey("E", "Relaunch as admin to apply System") + "\n")
+		}
+		if len(m.undoSnapshot) > 0 {
+			b.WriteString(RenderKey("U", "Undo this apply") + "\n")
+		}
+	}
 	b.WriteString(RenderKey("Esc", "Back"))
 	return b.String()
 }
 
 func (m Model) viewPathViewer() string {
 	var b strings.Builder
-	b.WriteString(TitleStyle.Render("Current PATH") + " " + SelectedStyle.Render("["+m.viewerScope+"]") + "\n\n")
-
-	var pathStr string
-	if m.viewerExpanded {
-		pathStr, _ = path.GetPathExpanded(m.viewerScope)
-	} else {
-		pathStr, _ = path.GetPathRaw(m.viewerScope)
+	b.WriteString(TitleStyle.Render("Current PATH") + " " + SelectedStyle.Render("["+m.viewerScope+"]"))
+	if m.viewerScope == "Process" {
+		b.WriteString(" " + DimStyle.Render("(live, as inherited from the launching shell)"))
 	}
+	b.WriteString("\n\n")
 
+	pathStr := m.viewerPath()
 	entries := path.ParsePath(pathStr)
-	maxVisible := 18
 	start := m.scrollOffset
-	if start > len(entries)-maxVisible {
-		start = len(entries) - maxVisible
+	if start > len(entries)-viewerMaxVisible {
+		start = len(entries) - viewerMaxVisible
 	}
 	if start < 0 {
 		start = 0
 	}
-	end := start + maxVisible
+	end := start + viewerMaxVisible
 	if end > len(entries) {
 		end = len(entries)
 	}
 
+	// Variables still literal after expansion are unset or misconfigured
+	unresolved := 0
+	if m.viewerExpanded {
+		for _, entry := range entries {
+			if len(path.UnresolvedVars(entry)) > 0 {
+				unresolved++
+			}
+		}
+	}
+
 	if start > 0 {
 		b.WriteString(DimStyle.Render(fmt.Sprintf("      ... %d above\n", start)))
 	}
 	for i := start; i < end; i++ {
 		entry := entries[i]
-		exists := path.PathExists(entry)
-		marker := SuccessStyle.Render("*")
-		if !exists && !strings.Contains(entry, "%") {
-			marker = ErrorStyle.Render("!")
+		var missing []string
+		if m.viewerExpanded {
+			missing = path.UnresolvedVars(entry)
+		}
+		marker := existenceMarker(entry, len(missing) > 0)
+		displayEntry := entry
+		if len(displayEntry) > 64 {
+			displayEntry = displayEntry[:61] + "..."
+		}
+		cursor := "  "
+		style := NormalStyle
+		if i == m.viewerIndex {
+			cursor = SelectedStyle.Render("> ")
+			style = SelectedStyle
+		}
+		line := fmt.Sprintf("%s%s %s %s", cursor, DimStyle.Render(fmt.Sprintf("%3d.", i+1)), marker, style.Render(displayEntry))
+		if len(missing) > 0 {
+			line += " " + WarningStyle.Render("(unresolved: %"+strings.Join(missing, "%, %")+"%)")
+		}
+		b.WriteString(line + "\n")
+	}
+	if end < len(entries) {
+		b.WriteString(DimStyle.Render(fmt.Sprintf("      ... %d below\n", len(entries)-end)))
+	}
+
+	b.WriteString(DimStyle.Render(fmt.Sprintf("\n%d entries, %d chars", len(entries), len(pathStr))))
+	if unresolved > 0 {
+		b.WriteString("  " + WarningStyle.Render(fmt.Sprintf("%d with unresolved variables", unresolved)))
+	}
+
+	expandLabel := "expanded"
+	if m.viewerExpanded {
+		expandLabel = "raw"
+	}
+	editLabel := "Edit"
+	if m.viewerScope == "System" && !m.isAdmin {
+		editLabel = "Relaunch as admin to edit"
+	}
+	if m.clipboardOK {
+		b.WriteString("\n\n" + SuccessStyle.Render("Copied entry to clipboard!"))
+	}
+	if m.message != "" {
+		b.WriteString("\n\n" + InfoStyle.Render(m.message))
+	}
+	if m.viewerImporting {
+		b.WriteString("\n\n" + SubtitleStyle.Render("Path of entries file to load as the "+m.viewerScope+" PATH:") + "\n")
+		b.WriteString(SelectedStyle.Render("> ") + NormalStyle.Render(m.viewerImportInput) + SelectedStyle.Render("_") + "\n\n")
+		b.WriteString(RenderKey("Enter", "Load") + "  " + RenderKey("Esc", "Cancel"))
+		return b.String()
+	}
+	b.WriteString("\n\n" + RenderKey("S", "Scope: "+m.viewerScope) + "  " + RenderKey("e", "Show "+expandLabel) + "  " + RenderKey("E", editLabel) + "  " + RenderKey("C", "Copy entry") + "  " + RenderKey("W", "Write entries") + "  " + RenderKey("L", "Load entries") + "  " + RenderKey("F", "Find vars") + "  " + RenderKey("R", "Refresh") + "  " + RenderKey("Esc", "Menu"))
+	return b.String()
+}
+
+func (m Model) viewPathEditor() string {
+	var b strings.Builder
+	title := TitleStyle.Render("Edit " + m.editorScope + " PATH")
+	if m.editorChanged() {
+		title += " " + WarningStyle.Render("(modified)")
+	}
+	b.WriteString(title + "\n\n")
+
+	start := 0
+	if m.editorIndex >= viewerMaxVisible {
+		start = m.editorIndex - viewerMaxVisible + 1
+	}
+	end := min(start+viewerMaxVisible, len(m.editorEntries))
+
+	original := make(map[string]bool, len(m.editorOriginal))
+	for _, e := range m.editorOriginal {
+		original[path.NormalizePath(e)] = true
+	}
+
+	boxStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Cyan).Padding(0, 1)
+	var content string
+	if start > 0 {
+		content += DimStyle.Render(fmt.Sprintf("      ... %d above", start)) + "\n"
+	}
+	for i := start; i < end; i++ {
+		entry := m.editorEntries[i]
+		cursor := "  "
+		style := NormalStyle
+		if !original[path.NormalizePath(entry)] {
+			style = SuccessStyle
 		}
-		displayEntry := entry
-		if len(displayEntry) > 64 {
-			displayEntry = displayEntry[:61] + "..."
+		if i == m.editorIndex {
+			cursor = SelectedStyle.Render("> ")
+			style = SelectedStyle
 		}
-		b.WriteString(fmt.Sprintf("%s %s %s\n", DimStyle.Render(fmt.Sprintf("%3d.", i+1)), marker, NormalStyle.Render(displayEntry)))
+		content += cursor + DimStyle.Render(fmt.Sprintf("%3d. ", i+1)) + style.Render(entry) + "\n"
 	}
-	if end < len(entries) {
-		b.WriteString(DimStyle.Render(fmt.Sprintf("      ... %d below\n", len(entries)-end)))
+	if end < len(m.editorEntries) {
+		content += DimStyle.Render(fmt.Sprintf("      ... %d below", len(m.editorEntries)-end)) + "\n"
+	}
+	if len(m.editorEntries) == 0 {
+		content = DimStyle.Render("(empty)")
 	}
+	b.WriteString(boxStyle.Render(strings.TrimSuffix(content, "\n")) + "\n")
+	b.WriteString(DimStyle.Render(fmt.Sprintf("%d entries, %d chars (was %d)",
+		len(m.editorEntries), len(path.JoinPath(m.editorEntries)), len(path.JoinPath(m.editorOriginal)))) + "\n\n")
 
-	b.WriteString(DimStyle.Render(fmt.Sprintf("\n%d entries, %d chars", len(entries), len(pathStr))))
+	if m.message != "" {
+		if m.err != nil {
+			b.WriteString(ErrorStyle.Render(m.message) + "\n\n")
+		} else {
+			b.WriteString(SuccessStyle.Render(m.message) + "\n\n")
+		}
+	}
 
-	expandLabel := "expanded"
-	if m.viewerExpanded {
-		expandLabel = "raw"
+	if m.editorAdding {
+		b.WriteString(SubtitleStyle.Render("Insert directory above selection:") + "\n")
+		b.WriteString(SelectedStyle.Render("> ") + NormalStyle.Render(m.editorInput) + SelectedStyle.Render("_") + "\n\n")
+		b.WriteString(RenderKey("Enter", "Insert") + "  " + RenderKey("Esc", "Cancel"))
+		return b.String()
 	}
-	b.WriteString("\n\n" + RenderKey("S", "Switch scope") + "  " + RenderKey("E", "Show "+expandLabel) + "  " + RenderKey("Esc", "Menu"))
+
+	b.WriteString(RenderKey("j/k", "Select") + "  " + RenderKey("J/K", "Move") + "  " + RenderKey("I", "Insert") + "  " + RenderKey("X", "Remove") + "  ")
+	if !m.config.AdvisoryMode {
+		b.WriteString(RenderKey("A", "Apply") + "  ")
+	}
+	b.WriteString(RenderKey("Esc", "Cancel"))
 	return b.String()
 }
 
@@ -1447,6 +5193,20 @@ func (m Model) viewBackup() string {
 		b.WriteString(SuccessStyle.Render(m.message) + "\n\n")
 	}
 
+	if m.backupLabeling {
+		b.WriteString(SubtitleStyle.Render("Describe this backup (optional):") + "\n")
+		b.WriteString(SelectedStyle.Render("> ") + NormalStyle.Render(m.backupLabelInput) + SelectedStyle.Render("_") + "\n\n")
+		b.WriteString(RenderKey("Enter", "Create") + "  " + RenderKey("Esc", "Cancel"))
+		return b.String()
+	}
+
+	if m.backupImporting {
+		b.WriteString(SubtitleStyle.Render("Path of backup JSON to import:") + "\n")
+		b.WriteString(SelectedStyle.Render("> ") + NormalStyle.Render(m.backupImportInput) + SelectedStyle.Render("_") + "\n\n")
+		b.WriteString(RenderKey("Enter", "Import") + "  " + RenderKey("Esc", "Cancel"))
+		return b.String()
+	}
+
 	if len(m.backups) == 0 {
 		b.WriteString(DimStyle.Render("No backups found.") + "\n\n")
 	} else {
@@ -1459,14 +5219,27 @@ func (m Model) viewBackup() string {
 				cursor = SelectedStyle.Render("> ")
 				style = SelectedStyle
 			}
-			content += cursor + style.Render(fmt.Sprintf("%s [%s]", backup.FormattedDate, backup.Suffix)) + "\n"
+			mark := ""
+			if i == m.compareIndex {
+				mark = InfoStyle.Render(" [compare]")
+			}
+			content += cursor + style.Render(fmt.Sprintf("%s [%s]", backup.FormattedDate, backup.Suffix)) + mark
+			if backup.Description != "" {
+				content += DimStyle.Render("  " + backup.Description)
+			}
+			content += "\n"
 		}
 		b.WriteString(boxStyle.Render(strings.TrimSuffix(content, "\n")) + "\n\n")
 	}
 
-	b.WriteString(RenderKey("C", "Create") + "  ")
+	b.WriteString(RenderKey("C", "Create") + "  " + RenderKey("I", "Import") + "  ")
 	if len(m.backups) > 0 {
-		b.WriteString(RenderKey("V", "Preview") + "  " + RenderKey("R", "Restore") + "  " + RenderKey("D", "Delete") + "  ")
+		b.WriteString(RenderKey("V", "Preview") + "  ")
+		if !m.config.AdvisoryMode {
+			b.WriteString(RenderKey("R", "Restore") + "  ")
+		}
+		b.WriteString(RenderKey("D", "Delete") + "  ")
+		b.WriteString(RenderKey("M", "Compare") + "  ")
 	}
 	b.WriteString(RenderKey("Esc", "Menu"))
 	return b.String()
@@ -1479,7 +5252,19 @@ func (m Model) viewBackupPreview() string {
 	}
 
 	b.WriteString(TitleStyle.Render("Backup Preview") + "\n")
-	b.WriteString(DimStyle.Render(fmt.Sprintf("Created: %s  Host: %s", m.backupPreview.Timestamp.Format("2006-01-02 15:04:05"), m.backupPreview.Hostname)) + "\n\n")
+	b.WriteString(DimStyle.Render(fmt.Sprintf("Created: %s  Host: %s", m.backupPreview.Timestamp.Format("2006-01-02 15:04:05"), m.backupPreview.Hostname)) + "\n")
+	if m.backupPreview.Description != "" {
+		b.WriteString(NormalStyle.Render(m.backupPreview.Description) + "\n")
+	}
+	b.WriteString("\n")
+
+	if m.message != "" {
+		if m.err != nil {
+			b.WriteString(ErrorStyle.Render(m.message) + "\n\n")
+		} else {
+			b.WriteString(SuccessStyle.Render(m.message) + "\n\n")
+		}
+	}
 
 	boxStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Gray).Padding(0, 1)
 
@@ -1509,6 +5294,54 @@ func (m Model) viewBackupPreview() string {
 	}
 	b.WriteString(boxStyle.Render(strings.TrimSuffix(usrContent, "\n")) + "\n\n")
 
+	b.WriteString(RenderKey("X", "Export .reg") + "  " + RenderKey("Esc", "Back"))
+	return b.String()
+}
+
+// renderPathDiffLines renders one scope of a diff as +/- lines
+func renderPathDiffLines(label string, diff path.PathDiff) []string {
+	lines := []string{SubtitleStyle.Render(fmt.Sprintf("%s PATH (+%d / -%d)", label, len(diff.Added), len(diff.Removed)))}
+	if diff.Empty() {
+		return append(lines, DimStyle.Render("  No changes"))
+	}
+	for _, e := range diff.Added {
+		lines = append(lines, SuccessStyle.Render("  + "+e))
+	}
+	for _, e := range diff.Removed {
+		lines = append(lines, ErrorStyle.Render("  - "+e))
+	}
+	return lines
+}
+
+// backupDiffLines returns the rendered lines of the current backup comparison
+func (m Model) backupDiffLines() []string {
+	if m.backupDiff == nil {
+		return nil
+	}
+	lines := renderPathDiffLines("System", m.backupDiff.system)
+	lines = append(lines, "")
+	return append(lines, renderPathDiffLines("User", m.backupDiff.user)...)
+}
+
+func (m Model) viewBackupDiff() string {
+	if m.backupDiff == nil {
+		return "Loading..."
+	}
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("Compare Backups") + "\n")
+	b.WriteString(DimStyle.Render(fmt.Sprintf("%s [%s] -> %s [%s]",
+		m.backupDiff.older.FormattedDate, m.backupDiff.older.Suffix,
+		m.backupDiff.newer.FormattedDate, m.backupDiff.newer.Suffix)) + "\n\n")
+
+	lines := m.backupDiffLines()
+	end := min(m.scrollOffset+listMaxVisible, len(lines))
+	start := min(m.scrollOffset, end)
+	boxStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Gray).Padding(0, 1)
+	b.WriteString(boxStyle.Render(strings.Join(lines[start:end], "\n")) + "\n\n")
+
+	if len(lines) > listMaxVisible {
+		b.WriteString(RenderKey("j/k", "Scroll") + "  ")
+	}
 	b.WriteString(RenderKey("Esc", "Back"))
 	return b.String()
 }
@@ -1519,7 +5352,8 @@ func (m Model) viewConfirmBackup(action string, color lipgloss.Color) string {
 	content := style.Render(action+" this backup?") + "\n\n"
 	content += NormalStyle.Render(m.backups[m.backupIndex].Filename) + "\n\n"
 	if action == "Restore" {
-		content += DimStyle.Render("Current PATH will be backed up first.") + "\n\n"
+		content += DimStyle.Render("Current PATH will be backed up first.")
+		content += m.maintenanceReminder(m.isAdmin) + "\n\n"
 	} else {
 		content += ErrorStyle.Render("This cannot be undone!") + "\n\n"
 	}
@@ -1551,23 +5385,63 @@ func (m Model) viewJunctions() string {
 				cursor = SelectedStyle.Render("> ")
 				style = SelectedStyle
 			}
+			marker := ""
+			if j.Broken {
+				marker = ErrorStyle.Render(" !")
+				if i != m.junctionIndex {
+					style = ErrorStyle
+				}
+			}
 			target := j.Target
 			if len(target) > 48 {
 				target = target[:45] + "..."
 			}
-			content += cursor + style.Render(j.Name) + DimStyle.Render(" -> "+target) + "\n"
+			content += cursor + style.Render(j.Name) + marker + DimStyle.Render(" -> "+target) + "\n"
 		}
 		b.WriteString(boxStyle.Render(strings.TrimSuffix(content, "\n")) + "\n\n")
-		b.WriteString(RenderKey("D", "Delete") + "  ")
+		b.WriteString(RenderKey("D", "Delete") + "  " + RenderKey("N", "Rename") + "  " + RenderKey("C", "Copy table") + "  ")
+		if len(brokenJunctions(m.junctions)) > 0 {
+			b.WriteString(RenderKey("P", "Prune broken") + "  ")
+		}
 	}
 
 	b.WriteString(RenderKey("Esc", "Menu"))
 	return b.String()
 }
 
+// renderSuggestionProjection shows the total PATH length if the suggestions up to the
+// selected one were all applied
+func (m Model) renderSuggestionProjection() string {
+	n := m.junctionIndex + 1
+	if n >= len(m.suggestionLengths) {
+		return ""
+	}
+	before, after := m.suggestionLengths[0], m.suggestionLengths[n]
+	return InfoStyle.Render(fmt.Sprintf("Top %d applied: ", n)) +
+		NormalStyle.Render(fmt.Sprintf("%d -> %d chars", before, after)) + " " +
+		SuccessStyle.Render(fmt.Sprintf("(-%d)", before-after))
+}
+
 func (m Model) viewJunctionSuggestions() string {
 	var b strings.Builder
-	b.WriteString(TitleStyle.Render("Suggestions") + " " + DimStyle.Render(fmt.Sprintf("(%d)", len(m.suggestions))) + "\n\n")
+	visible := m.visibleSuggestions()
+	count := fmt.Sprintf("(%d)", len(m.suggestions))
+	if m.suggestionFilter != "" {
+		count = fmt.Sprintf("(%d of %d)", len(visible), len(m.suggestions))
+	}
+	b.WriteString(TitleStyle.Render("Suggestions") + " " + DimStyle.Render(count) + "\n")
+	if len(m.suggestions) > 0 {
+		b.WriteString(InfoStyle.Render("Total potential savings: ") + SuccessStyle.Render(fmt.Sprintf("%d chars", path.CalculateJunctionSavings(visible))) + "\n")
+	}
+	b.WriteString("\n")
+
+	if m.suggestionTyping || m.suggestionFilter != "" {
+		filter := SubtitleStyle.Render("Filter: ") + NormalStyle.Render(m.suggestionFilter)
+		if m.suggestionTyping {
+			filter += SelectedStyle.Render("_")
+		}
+		b.WriteString(filter + "\n\n")
+	}
 
 	if m.message != "" {
 		if m.err != nil {
@@ -1579,28 +5453,29 @@ func (m Model) viewJunctionSuggestions() string {
 
 	if len(m.suggestions) == 0 {
 		b.WriteString(DimStyle.Render("No paths would benefit from junctions.") + "\n\n")
+	} else if len(visible) == 0 {
+		b.WriteString(DimStyle.Render("No suggestions match the filter.") + "\n\n")
 	} else {
-		maxVisible := 12
 		start := 0
-		if m.junctionIndex > maxVisible/2 {
-			start = m.junctionIndex - maxVisible/2
+		if m.junctionIndex > suggestionsMaxVisible/2 {
+			start = m.junctionIndex - suggestionsMaxVisible/2
 		}
-		if start+maxVisible > len(m.suggestions) {
-			start = len(m.suggestions) - maxVisible
+		if start+suggestionsMaxVisible > len(visible) {
+			start = len(visible) - suggestionsMaxVisible
 		}
 		if start < 0 {
 			start = 0
 		}
-		end := start + maxVisible
-		if end > len(m.suggestions) {
-			end = len(m.suggestions)
+		end := start + suggestionsMaxVisible
+		if end > len(visible) {
+			end = len(visible)
 		}
 
 		if start > 0 {
 			b.WriteString(DimStyle.Render(fmt.Sprintf("      ... %d above\n", start)))
 		}
 		for i := start; i < end; i++ {
-			s := m.suggestions[i]
+			s := visible[i]
 			cursor := "  "
 			style := NormalStyle
 			if i == m.junctionIndex {
@@ -1614,20 +5489,188 @@ func (m Model) viewJunctionSuggestions() string {
 			}
 			b.WriteString(fmt.Sprintf("%s%s %s <- %s\n", cursor, saved, style.Render(s.SuggestedName), DimStyle.Render(origPath)))
 		}
-		if end < len(m.suggestions) {
-			b.WriteString(DimStyle.Render(fmt.Sprintf("      ... %d below\n", len(m.suggestions)-end)))
+		if end < len(visible) {
+			b.WriteString(DimStyle.Render(fmt.Sprintf("      ... %d below\n", len(visible)-end)))
+		}
+		// The projection follows the unfiltered order, so it only applies without a filter
+		if projection := m.renderSuggestionProjection(); projection != "" && m.suggestionFilter == "" {
+			b.WriteString("\n" + projection + "\n")
+		}
+
+		createAll := "Create all"
+		if m.suggestionFilter != "" {
+			createAll = "Create all shown"
+		}
+		b.WriteString("\n" + RenderKey("C", "Create selected") + "  " + RenderKey("W", "Create + rewrite PATH") + "  " + RenderKey("A", createAll) + "  ")
+		b.WriteString(RenderKey("P", "Preview all") + "  ")
+	}
+	if m.suggestionTyping {
+		b.WriteString(RenderKey("Enter", "Keep filter") + "  " + RenderKey("Esc", "Clear"))
+		return b.String()
+	}
+	if len(m.suggestions) > 0 {
+		b.WriteString(RenderKey("/", "Filter") + "  ")
+	}
+	b.WriteString(RenderKey("E", "Exclusions") + "  ")
+	if m.suggestionFilter != "" {
+		b.WriteString(RenderKey("Esc", "Clear filter"))
+		return b.String()
+	}
+	b.WriteString(RenderKey("Esc", "Back"))
+	return b.String()
+}
+
+func (m Model) viewEnvVars() string {
+	var b strings.Builder
+	visible := m.visibleEnvVars()
+	count := fmt.Sprintf("(%d)", len(m.envVars))
+	if m.envFilter != "" {
+		count = fmt.Sprintf("(%d of %d)", len(visible), len(m.envVars))
+	}
+	b.WriteString(TitleStyle.Render("Environment Variables") + " " + DimStyle.Render(count) + "\n")
+	b.WriteString(DimStyle.Render("Any of these can be used as %NAME% in a PATH entry. ") + InfoStyle.Render("Highlighted") + DimStyle.Render(" variables hold folder lists.") + "\n\n")
+
+	if m.envTyping || m.envFilter != "" {
+		filter := SubtitleStyle.Render("Filter: ") + NormalStyle.Render(m.envFilter)
+		if m.envTyping {
+			filter += SelectedStyle.Render("_")
+		}
+		b.WriteString(filter + "\n\n")
+	}
+
+	if len(visible) == 0 {
+		b.WriteString(DimStyle.Render("No variables match the filter.") + "\n\n")
+	} else {
+		start := clampScroll(m.envIndex-listMaxVisible/2, len(visible), listMaxVisible)
+		end := min(start+listMaxVisible, len(visible))
+		if start > 0 {
+			b.WriteString(DimStyle.Render(fmt.Sprintf("      ... %d above\n", start)))
+		}
+		for i := start; i < end; i++ {
+			v := visible[i]
+			cursor := "  "
+			nameStyle := NormalStyle
+			if v.pathLike {
+				nameStyle = InfoStyle
+			}
+			if i == m.envIndex {
+				cursor = SelectedStyle.Render("> ")
+				nameStyle = SelectedStyle
+			}
+			value := v.value
+			if len(value) > 50 {
+				value = value[:47] + "..."
+			}
+			b.WriteString(cursor + nameStyle.Render(fmt.Sprintf("%-24s", v.name)) + " " + DimStyle.Render(value) + "\n")
+		}
+		if end < len(visible) {
+			b.WriteString(DimStyle.Render(fmt.Sprintf("      ... %d below\n", len(visible)-end)))
+		}
+		b.WriteString("\n")
+
+		if m.envExpanded && m.envIndex < len(visible) {
+			v := visible[m.envIndex]
+			value := wrapText(v.value, 72)
+			if v.pathLike {
+				value = strings.Join(path.ParsePath(v.value), "\n")
+			}
+			boxStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Gray).Padding(0, 1)
+			b.WriteString(SubtitleStyle.Render(v.name) + "\n" + boxStyle.Render(value) + "\n\n")
+		}
+	}
+
+	if m.envTyping {
+		b.WriteString(RenderKey("Enter", "Keep filter") + "  " + RenderKey("Esc", "Clear"))
+		return b.String()
+	}
+	expand := "Show value"
+	if m.envExpanded {
+		expand = "Hide value"
+	}
+	b.WriteString(RenderKey("Enter", expand) + "  " + RenderKey("/", "Filter") + "  ")
+	if m.envFilter != "" {
+		b.WriteString(RenderKey("Esc", "Clear filter"))
+		return b.String()
+	}
+	b.WriteString(RenderKey("Esc", "Menu"))
+	return b.String()
+}
+
+func (m Model) viewJunctionExcludes() string {
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("Excluded Folders") + "\n\n")
+	b.WriteString(DimStyle.Render("Entries in these folders are never suggested for a junction.") + "\n\n")
+
+	if m.message != "" {
+		b.WriteString(SuccessStyle.Render(m.message) + "\n\n")
+	}
+
+	if m.excludeAdding {
+		b.WriteString(SubtitleStyle.Render("Folder to exclude (e.g. C:\\Windows):") + "\n")
+		b.WriteString(SelectedStyle.Render("> ") + NormalStyle.Render(m.excludeInput) + SelectedStyle.Render("_") + "\n\n")
+		b.WriteString(RenderKey("Enter", "Add") + "  " + RenderKey("Esc", "Cancel"))
+		return b.String()
+	}
+
+	excludes := m.config.JunctionExcludePrefixes
+	if len(excludes) == 0 {
+		b.WriteString(DimStyle.Render("No folders excluded.") + "\n\n")
+	} else {
+		boxStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Gray).Padding(0, 1)
+		var content string
+		for i, e := range excludes {
+			cursor := "  "
+			style := NormalStyle
+			if i == m.excludeIndex {
+				cursor = SelectedStyle.Render("> ")
+				style = SelectedStyle
+			}
+			content += cursor + style.Render(e) + "\n"
 		}
+		b.WriteString(boxStyle.Render(strings.TrimSuffix(content, "\n")) + "\n\n")
+	}
+
+	b.WriteString(RenderKey("A", "Add folder") + "  ")
+	if len(excludes) > 0 {
+		b.WriteString(RenderKey("x", "Remove") + "  ")
+	}
+	b.WriteString(RenderKey("Esc", "Back"))
+	return b.String()
+}
 
-		b.WriteString("\n" + RenderKey("C", "Create selected") + "  ")
+func (m Model) viewJunctionPreview() string {
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("PATH With All Junctions") + "\n")
+	b.WriteString(DimStyle.Render("Every suggested junction created and the PATH entries pointed at it") + "\n\n")
+
+	saved := m.suggestionPreview.SavedChars
+	summary := InfoStyle.Render(fmt.Sprintf("%d junctions: ", len(m.suggestions)))
+	if len(m.suggestionLengths) > 0 {
+		before := m.suggestionLengths[0]
+		summary += NormalStyle.Render(fmt.Sprintf("%d -> %d chars", before, before-saved)) + " "
 	}
+	b.WriteString(summary + SuccessStyle.Render(fmt.Sprintf("(-%d)", saved)) + "\n\n")
 
+	lines := m.junctionPreviewLines()
+	start := clampScroll(m.scrollOffset, len(lines), listMaxVisible)
+	end := min(start+listMaxVisible, len(lines))
+	boxStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Gray).Padding(0, 1)
+	b.WriteString(boxStyle.Render(strings.Join(lines[start:end], "\n")) + "\n\n")
+
+	if len(lines) > listMaxVisible {
+		b.WriteString(RenderKey("j/k", "Scroll") + "  ")
+	}
 	b.WriteString(RenderKey("Esc", "Back"))
 	return b.String()
 }
 
 func (m Model) viewJunctionCreate() string {
 	var b strings.Builder
-	b.WriteString(TitleStyle.Render("Create Junction") + "\n\n")
+	if m.junctionRenaming != "" {
+		b.WriteString(TitleStyle.Render("Rename Junction") + " " + DimStyle.Render("("+m.junctionRenaming+")") + "\n\n")
+	} else {
+		b.WriteString(TitleStyle.Render("Create Junction") + "\n\n")
+	}
 
 	if m.message != "" {
 		if m.err != nil {
@@ -1660,6 +5703,10 @@ func (m Model) viewJunctionCreate() string {
 	b.WriteString("\n\n")
 
 	b.WriteString(DimStyle.Render(fmt.Sprintf("Creates: %s\\%s", path.GetJunctionFolder(), m.junctionName)) + "\n\n")
+	if m.junctionRenaming != "" {
+		b.WriteString(RenderKey("Enter", "Rename") + "  " + RenderKey("Esc", "Cancel"))
+		return b.String()
+	}
 	b.WriteString(RenderKey("Tab", "Switch") + "  " + RenderKey("Enter", "Create") + "  " + RenderKey("Esc", "Cancel"))
 	return b.String()
 }
@@ -1694,7 +5741,26 @@ func (m Model) viewPathExt() string {
 			b.WriteString(SubtitleStyle.Render("Result: ") + NormalStyle.Render(m.pathExtOpt.OptimizedString) + "\n\n")
 		}
 
-		b.WriteString(RenderKey("j/k", "Select") + "  " + RenderKey("J/K", "Move") + "  " + RenderKey("X", "Remove") + "  " + RenderKey("A", "Apply") + "  " + RenderKey("Esc", "Cancel"))
+		if m.message != "" {
+			if m.err != nil {
+				b.WriteString(ErrorStyle.Render(m.message) + "\n\n")
+			} else {
+				b.WriteString(SuccessStyle.Render(m.message) + "\n\n")
+			}
+		}
+
+		if m.pathExtAdding {
+			b.WriteString(SubtitleStyle.Render("Insert extension above selection:") + "\n")
+			b.WriteString(SelectedStyle.Render("> ") + NormalStyle.Render(m.pathExtInput) + SelectedStyle.Render("_") + "\n\n")
+			b.WriteString(RenderKey("Enter", "Insert") + "  " + RenderKey("Esc", "Cancel"))
+			return b.String()
+		}
+
+		b.WriteString(RenderKey("j/k", "Select") + "  " + RenderKey("J/K", "Move") + "  " + RenderKey("I", "Insert") + "  " + RenderKey("X", "Remove") + "  ")
+		if !m.config.AdvisoryMode {
+			b.WriteString(RenderKey("A", "Apply") + "  ")
+		}
+		b.WriteString(RenderKey("Esc", "Cancel"))
 		return b.String()
 	}
 
@@ -1738,7 +5804,13 @@ func (m Model) viewPathExt() string {
 
 	b.WriteString(RenderKey("E", "Edit manually") + "  ")
 	if m.pathExtOpt != nil && m.pathExtOpt.Changed {
-		b.WriteString(RenderKey("O", "Use optimized") + "  " + RenderKey("A", "Apply suggested") + "  ")
+		b.WriteString(RenderKey("O", "Use optimized") + "  ")
+		if !m.config.AdvisoryMode {
+			b.WriteString(RenderKey("A", "Apply suggested") + "  ")
+		}
+	}
+	if !m.config.AdvisoryMode {
+		b.WriteString(RenderKey("R", "Reset to default") + "  ")
 	}
 	b.WriteString(RenderKey("Esc", "Menu"))
 	return b.String()
@@ -1754,6 +5826,13 @@ func (m Model) viewSettings() string {
 	}{
 		{"Max Backups", fmt.Sprintf("%d", m.config.MaxBackups)},
 		{"Auto Backup", fmt.Sprintf("%v", m.config.AutoBackup)},
+		{"Analyze on Start", fmt.Sprintf("%v", m.config.AutoAnalyzeOnStart)},
+		{"Rewrite PATH on Junction", fmt.Sprintf("%v", m.config.RewritePathOnJunction)},
+		{"Junction Min Savings", fmt.Sprintf("%d chars", m.config.JunctionMinSavings)},
+		{"Junction Min Path Length", fmt.Sprintf("%d chars", m.config.JunctionMinPathLength)},
+		{"Canonical Casing", fmt.Sprintf("%v", m.config.CanonicalizePaths)},
+		{"Theme", themeName(m.config.Theme)},
+		{"Detect Shadowed Tools", fmt.Sprintf("%v", m.config.DetectShadowedTools)},
 		{"Junction Folder", m.config.JunctionFolder},
 	}
 
@@ -1767,8 +5846,28 @@ func (m Model) viewSettings() string {
 		b.WriteString(cursor + style.Render(s.name+": ") + NormalStyle.Render(s.value) + "\n")
 	}
 
-	b.WriteString("\n" + DimStyle.Render("+/- to change") + "\n")
-	b.WriteString(RenderKey("Esc", "Menu"))
+	if m.settingsEditing {
+		b.WriteString("\n" + SubtitleStyle.Render("New junction folder:") + "\n")
+		b.WriteString(SelectedStyle.Render("> ") + NormalStyle.Render(m.settingsInput) + SelectedStyle.Render("_") + "\n")
+		if m.message != "" {
+			b.WriteString(ErrorStyle.Render(m.message) + "\n")
+		}
+		b.WriteString("\n" + RenderKey("Enter", "Save") + "  " + RenderKey("Esc", "Cancel"))
+		return b.String()
+	}
+	b.WriteString("\n" + DimStyle.Render("Config:  "+path.GetConfigPath()) + "\n")
+	b.WriteString(DimStyle.Render("Backups: "+path.GetBackupDir()) + "\n")
+
+	if m.message != "" {
+		if m.err != nil {
+			b.WriteString("\n" + ErrorStyle.Render(m.message) + "\n")
+		} else {
+			b.WriteString("\n" + SuccessStyle.Render(m.message) + "\n")
+		}
+	}
+
+	b.WriteString("\n" + DimStyle.Render("+/- to change, Enter to edit Junction Folder") + "\n")
+	b.WriteString(RenderKey("R", "Reload from disk") + "  " + RenderKey("O", "Open config folder") + "  " + RenderKey("Esc", "Menu"))
 	return b.String()
 }
 
@@ -1779,7 +5878,11 @@ func (m Model) viewHotPaths() string {
 	b.WriteString(DimStyle.Render("Higher in list = higher priority (checked first).") + "\n\n")
 
 	if m.message != "" {
-		b.WriteString(SuccessStyle.Render(m.message) + "\n\n")
+		b.WriteString(SuccessStyle.Render(m.message) + "\n")
+		if m.hotPathWarning != "" {
+			b.WriteString(WarningStyle.Render(m.hotPathWarning) + "\n")
+		}
+		b.WriteString("\n")
 	}
 
 	if m.hotPathAdding {
@@ -1805,33 +5908,382 @@ func (m Model) viewHotPaths() string {
 			if len(displayPath) > 60 {
 				displayPath = displayPath[:57] + "..."
 			}
-			content += cursor + DimStyle.Render(fmt.Sprintf("%d. ", i+1)) + style.Render(displayPath) + "\n"
+			missing := ""
+			if m.hotPathMissing[p] {
+				missing = " " + ErrorStyle.Render("! not found")
+			}
+			content += cursor + DimStyle.Render(fmt.Sprintf("%d. ", i+1)) + style.Render(displayPath) + missing + "\n"
 		}
 		b.WriteString(boxStyle.Render(strings.TrimSuffix(content, "\n")) + "\n\n")
 	}
 
+	if m.hotPathPreview {
+		b.WriteString(m.renderHotPathPreview() + "\n\n")
+	}
+
 	b.WriteString(RenderKey("A", "Add path") + "  ")
 	if len(m.config.HotPaths) > 0 {
-		b.WriteString(RenderKey("x", "Delete") + "  " + RenderKey("J/K", "Reorder") + "  ")
+		b.WriteString(RenderKey("x", "Delete") + "  " + RenderKey("J/K", "Reorder") + "  " + RenderKey("1-9", "Move to") + "  ")
+	}
+	if m.hotPathCanUndo {
+		b.WriteString(RenderKey("u", "Undo") + "  ")
 	}
+	previewLabel := "Preview"
+	if m.hotPathPreview {
+		previewLabel = "Hide preview"
+		b.WriteString(RenderKey("S", "Scope: "+m.hotPathPreviewScope) + "  ")
+	}
+	b.WriteString(RenderKey("P", previewLabel) + "  ")
 	b.WriteString(RenderKey("Esc", "Menu"))
 	return b.String()
 }
 
+// renderHotPathPreview shows the current PATH order with hot paths applied
+func (m Model) renderHotPathPreview() string {
+	entries := m.hotPathPreviewEntries()
+
+	hot := make(map[string]bool, len(m.config.HotPaths))
+	for _, hp := range m.config.HotPaths {
+		hot[path.NormalizePath(hp)] = true
+	}
+
+	var b strings.Builder
+	b.WriteString(SubtitleStyle.Render(fmt.Sprintf("Preview: %s PATH with hot paths applied", m.hotPathPreviewScope)) + "\n")
+	if len(entries) == 0 {
+		b.WriteString(DimStyle.Render("  (PATH is empty)"))
+		return b.String()
+	}
+
+	maxVisible := 10
+	for i, entry := range entries {
+		if i >= maxVisible {
+			b.WriteString(DimStyle.Render(fmt.Sprintf("      +%d more", len(entries)-maxVisible)) + "\n")
+			break
+		}
+		display := entry
+		if len(display) > 60 {
+			display = display[:57] + "..."
+		}
+		num := DimStyle.Render(fmt.Sprintf("%3d. ", i+1))
+		if hot[path.NormalizePath(entry)] {
+			b.WriteString(num + SuccessStyle.Render("* "+display) + "\n")
+		} else {
+			b.WriteString(num + NormalStyle.Render("  "+display) + "\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// helpBinding is a single key/description pair shown on the help screen
+type helpBinding struct {
+	key  string
+	desc string
+}
+
+// helpBindings returns the keybindings relevant to a screen
+func helpBindings(screen Screen) (string, []helpBinding) {
+	switch screen {
+	case ScreenMenu:
+		return "Menu", []helpBinding{
+			{"j/k", "Move selection"},
+			{"1-9", "Jump to item"},
+			{"Enter", "Select"},
+			{"D", "Show PATH changes made outside WinPath (when the banner is shown)"},
+			{"Q", "Quit"},
+		}
+	case ScreenOptimizer, ScreenOptimizerPreview:
+		return "Optimize PATH", []helpBinding{
+			{"1-4", "Summary / Changes / Raw / List"},
+			{"j/k", "Scroll"},
+			{"g/G", "Jump to top / bottom"},
+			{"PgUp/PgDn", "Page up / down"},
+			{"S", "Cycle scope (both, system, user)"},
+			{"P", "Cycle preset (default, conservative, existing, aggressive) and re-analyze"},
+			{"R", "Re-analyze, ignoring the cached result"},
+			{"A", "Apply"},
+			{"E", "Relaunch as admin (when not elevated)"},
+			{"X", "Export analysis as JSON"},
+			{"W", "Write optimized entries to a text file, one per line"},
+			{"C", "Copy optimized PATH for the shown scope"},
+			{"Y", "Yank full optimized PATH (both: System, then User)"},
+			{"8", "Review 8.3 short name conversions one by one"},
+			{"F", "Changes tab: select change type"},
+			{"Space", "Changes tab: show/hide selected type"},
+			{"Esc", "Back to menu"},
+		}
+	case ScreenOptimizerConfirm:
+		return "Confirm Apply", []helpBinding{
+			{"Y", "Apply"},
+			{"T", "Apply with rollback timer"},
+			{"!", "Apply without backup (after a failed backup)"},
+			{"V", "Show / hide the exact value to be written"},
+			{"N", "Cancel"},
+		}
+	case ScreenProtectedOverride:
+		return "Protected Entries", []helpBinding{
+			{"j/k", "Move selection"},
+			{"Space/O", "Override / keep protection for the selected entry"},
+			{"Enter", "Continue to apply once every entry is overridden"},
+			{"Esc", "Cancel"},
+		}
+	case ScreenOptimizerConfirmReorder:
+		return "Confirm Reorder", []helpBinding{
+			{"Enter/Y", "Apply"},
+			{"T", "Apply with rollback timer"},
+			{"V", "Show / hide the exact value to be written"},
+			{"N", "Cancel"},
+		}
+	case ScreenJunctionRewriteConfirm:
+		return "Rewrite PATH", []helpBinding{
+			{"Y", "Rewrite PATH entries to the junction"},
+			{"N", "Keep PATH as is"},
+		}
+	case ScreenOptimizerRollback:
+		return "Rollback Timer", []helpBinding{
+			{"K", "Keep changes"},
+			{"R", "Revert now"},
+		}
+	case ScreenPathViewer:
+		return "View Current PATH", []helpBinding{
+			{"j/k", "Move selection"},
+			{"g/G", "Jump to top / bottom"},
+			{"PgUp/PgDn", "Page up / down"},
+			{"C", "Copy selected entry"},
+			{"W", "Write entries to a text file, one per line"},
+			{"L", "Load entries from a text file and preview them in the editor"},
+			{"F", "Find variables referencing selected entry"},
+			{"S", "Switch scope (User / System / Process)"},
+			{"e", "Toggle expanded / raw"},
+			{"E", "Edit entries (relaunches as admin for System)"},
+			{"R", "Re-read PATH from the registry"},
+			{"Esc", "Back to menu"},
+		}
+	case ScreenPathEditor:
+		return "Edit PATH", []helpBinding{
+			{"j/k", "Move selection"},
+			{"J/K", "Move entry down / up"},
+			{"I", "Insert a directory above the selection"},
+			{"X", "Remove selected entry"},
+			{"A", "Apply (backs up first)"},
+			{"Esc", "Discard changes"},
+		}
+	case ScreenBackup:
+		return "Backup Manager", []helpBinding{
+			{"j/k", "Move selection"},
+			{"g/G", "Jump to top / bottom"},
+			{"PgUp/PgDn", "Page up / down"},
+			{"C", "Create backup (with optional description)"},
+			{"I", "Import a backup JSON file"},
+			{"V", "Preview"},
+			{"R", "Restore"},
+			{"D", "Delete"},
+			{"M", "Mark for comparison / compare with marked"},
+			{"Esc", "Back to menu"},
+		}
+	case ScreenBackupPreview:
+		return "Backup Preview", []helpBinding{
+			{"j/k", "Scroll"},
+			{"X", "Export as .reg file"},
+			{"Esc", "Back"},
+		}
+	case ScreenBackupDiff:
+		return "Compare Backups", []helpBinding{
+			{"j/k", "Scroll"},
+			{"Esc", "Back"},
+		}
+	case ScreenJunctions:
+		return "Junction Manager", []helpBinding{
+			{"j/k", "Move selection"},
+			{"g/G", "Jump to top / bottom"},
+			{"PgUp/PgDn", "Page up / down"},
+			{"1", "Refresh"},
+			{"2", "Suggestions"},
+			{"3", "Create"},
+			{"D", "Delete"},
+			{"N", "Rename"},
+			{"C", "Copy all junctions as a table"},
+			{"P", "Prune broken junctions"},
+			{"Esc", "Back to menu"},
+		}
+	case ScreenJunctionPruneConfirm:
+		return "Prune Junctions", []helpBinding{
+			{"Y", "Remove all broken junctions"},
+			{"N", "Cancel"},
+		}
+	case ScreenJunctionSuggestions:
+		return "Junction Suggestions", []helpBinding{
+			{"j/k", "Move selection"},
+			{"g/G", "Jump to top / bottom"},
+			{"PgUp/PgDn", "Page up / down"},
+			{"C", "Create selected"},
+			{"A", "Create all suggestions"},
+			{"W", "Create selected and rewrite PATH to use it"},
+			{"P", "Preview the PATH with every suggestion applied"},
+			{"/", "Filter by path, e.g. C:\\Program Files; Enter keeps it"},
+			{"E", "Edit the folders never suggested for a junction"},
+			{"Esc", "Clear the filter, or go back"},
+		}
+	case ScreenShortNames:
+		return "8.3 Short Names", []helpBinding{
+			{"j/k", "Move selection"},
+			{"g/G", "Jump to top / bottom"},
+			{"Space", "Accept / reject the selected conversion"},
+			{"A", "Accept all"},
+			{"R", "Reject all"},
+			{"Enter", "Rebuild the optimized PATH with these choices"},
+			{"Esc", "Back without changing anything"},
+		}
+	case ScreenEnvVars:
+		return "Environment Variables", []helpBinding{
+			{"j/k", "Move selection"},
+			{"g/G", "Jump to top / bottom"},
+			{"PgUp/PgDn", "Page up / down"},
+			{"Enter", "Show or hide the full value"},
+			{"/", "Filter by name or value; Enter keeps it"},
+			{"Esc", "Clear the filter, or back to menu"},
+		}
+	case ScreenJunctionExcludes:
+		return "Excluded Folders", []helpBinding{
+			{"j/k", "Move selection"},
+			{"A", "Add a folder"},
+			{"X", "Remove the selected folder"},
+			{"Esc", "Back to suggestions, refreshed if the list changed"},
+		}
+	case ScreenJunctionPreview:
+		return "PATH With All Junctions", []helpBinding{
+			{"j/k", "Scroll"},
+			{"g/G", "Jump to top / bottom"},
+			{"Esc", "Back to suggestions"},
+		}
+	case ScreenPathExt:
+		return "PATHEXT Optimizer", []helpBinding{
+			{"E", "Edit manually"},
+			{"O", "Edit the optimized order"},
+			{"A", "Apply suggested"},
+			{"R", "Reset to Windows default"},
+			{"j/k", "Select (edit mode)"},
+			{"J/K", "Move extension (edit mode)"},
+			{"X", "Remove extension (edit mode)"},
+			{"I/+", "Insert extension (edit mode)"},
+			{"Esc", "Back"},
+		}
+	case ScreenSettings:
+		return "Settings", []helpBinding{
+			{"j/k", "Move selection"},
+			{"+/-", "Change value"},
+			{"Enter", "Toggle / increase / edit Junction Folder"},
+			{"R", "Reload config.json from disk"},
+			{"O", "Open the folder holding config.json and backups in Explorer"},
+			{"Esc", "Back to menu"},
+		}
+	case ScreenOptimizerDone:
+		return "Optimization Applied", []helpBinding{
+			{"C", "Copy the terminal refresh command"},
+			{"Y", "Yank full optimized PATH (both: System, then User)"},
+			{"E", "Relaunch as admin when System was skipped"},
+			{"U", "Undo this apply, writing back the PATH from just before it"},
+			{"Esc", "Back to menu"},
+		}
+	case ScreenChangeDetail:
+		return "Why This Change", []helpBinding{
+			{"Esc", "Back to the Changes tab"},
+		}
+	case ScreenAuditLog:
+		return "Audit Log", []helpBinding{
+			{"j/k", "Scroll"},
+			{"g/G", "Jump to oldest / newest"},
+			{"PgUp/PgDn", "Page up / down"},
+			{"R", "Re-read the log"},
+			{"Esc", "Back to menu"},
+		}
+	case ScreenExternalChanges:
+		return "PATH Changed Outside WinPath", []helpBinding{
+			{"j/k", "Scroll"},
+			{"B", "Create a backup so the current PATH becomes the new baseline"},
+			{"Esc", "Back to menu"},
+		}
+	case ScreenAutoFixConfirm:
+		return "Auto-fix", []helpBinding{
+			{"Y", "Back up, then apply the recommended fixes"},
+			{"N", "Cancel"},
+		}
+	case ScreenAutoFixDone:
+		return "Auto-fix Applied", []helpBinding{
+			{"C", "Copy the terminal refresh command"},
+			{"Esc", "Back to menu"},
+		}
+	case ScreenOptimizerUndoConfirm:
+		return "Undo Last Apply", []helpBinding{
+			{"Y", "Write back the PATH from just before the apply"},
+			{"N", "Cancel"},
+		}
+	case ScreenHotPaths:
+		return "Hot Paths", []helpBinding{
+			{"j/k", "Move selection"},
+			{"A", "Add path"},
+			{"X", "Delete"},
+			{"J/K", "Reorder"},
+			{"1-9", "Move the selected path to that position"},
+			{"u", "Undo last change"},
+			{"P", "Preview resulting PATH"},
+			{"S", "Switch preview scope"},
+			{"Esc", "Back to menu"},
+		}
+	}
+	return "General", []helpBinding{
+		{"Esc", "Back"},
+		{"Ctrl+C", "Quit"},
+	}
+}
+
+func (m Model) viewHelp() string {
+	title, bindings := helpBindings(m.helpReturnScreen)
+
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("Help") + " " + SubtitleStyle.Render("- "+title) + "\n\n")
+
+	boxStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Gray).Padding(0, 1)
+	var content string
+	for _, hb := range bindings {
+		content += RenderKey(hb.key, hb.desc) + "\n"
+	}
+	content += "\n" + RenderKey("?", "Help (from any screen)") + "\n" + RenderKey("Ctrl+C", "Quit")
+	b.WriteString(boxStyle.Render(content) + "\n\n")
+
+	b.WriteString(RenderKey("Esc", "Back"))
+	return b.String()
+}
+
+// wrapText wraps text to lines of at most width runes
+// Lines break after the last ';' in reach, else after a backslash, slash or space,
+// and hard-break only tokens longer than width. No characters are dropped, so
+// joining the lines gives back the original text.
 func wrapText(text string, width int) string {
-	if width <= 0 || len(text) <= width {
+	runes := []rune(text)
+	if width <= 0 || len(runes) <= width {
 		return text
 	}
-	var result strings.Builder
-	for i := 0; i < len(text); i += width {
-		end := i + width
-		if end > len(text) {
-			end = len(text)
+	var lines []string
+	for len(runes) > width {
+		cut := width
+		if i := lastBreak(runes[:width], ";"); i > 0 {
+			cut = i
+		} else if i := lastBreak(runes[:width], `\/ `); i > 0 {
+			cut = i
 		}
-		result.WriteString(text[i:end])
-		if end < len(text) {
-			result.WriteString("\n")
+		lines = append(lines, string(runes[:cut]))
+		runes = runes[cut:]
+	}
+	lines = append(lines, string(runes))
+	return strings.Join(lines, "\n")
+}
+
+// lastBreak returns the index just after the last rune of line found in breaks, or 0
+func lastBreak(line []rune, breaks string) int {
+	for i := len(line) - 1; i > 0; i-- {
+		if strings.ContainsRune(breaks, line[i]) {
+			return i + 1
 		}
 	}
-	return result.String()
+	return 0
 }
@@ -1,10 +1,16 @@
 package tui
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/quantumJLBass/winpath/internal/path"
 
@@ -21,12 +27,14 @@ func TestMain(m *testing.M) {
 
 	// Set up mock shell runner to avoid real PowerShell calls
 	_, cleanup := path.SetDefaultTestRunner()
+	_, cleanupClipboard := path.SetDefaultClipboard()
 
 	// Run tests
 	code := m.Run()
 
 	// Clean up (defer doesn't work with os.Exit)
 	cleanup()
+	cleanupClipboard()
 	os.RemoveAll(tempDir)
 
 	os.Exit(code)
@@ -53,6 +61,10 @@ func TestNewModel(t *testing.T) {
 }
 
 func TestNewModel_Defaults(t *testing.T) {
+	// Scopes saved by other tests would override the defaults
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+
 	model := New()
 
 	if model.optimizerScope != "both" {
@@ -123,6 +135,9 @@ func TestModel_MenuItems(t *testing.T) {
 		"PATHEXT Optimizer",
 		"Hot Paths Config",
 		"Settings",
+		"Auto-fix (recommended)",
+		"Audit Log",
+		"Environment Variables",
 		"Exit",
 	}
 
@@ -230,8 +245,8 @@ func TestModel_HandleKeyMsg_Menu_Number(t *testing.T) {
 	model := New()
 	model.screen = ScreenMenu
 
-	// Test pressing "1" through "8"
-	for i := 1; i <= 8; i++ {
+	// Test pressing "1" through "9"
+	for i := 1; i <= 9; i++ {
 		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{rune('0' + i)}}
 		newModel, _ := model.Update(msg)
 		m := newModel.(Model)
@@ -523,6 +538,12 @@ func TestScreenConstants(t *testing.T) {
 		ScreenPathExtDone,
 		ScreenSettings,
 		ScreenHotPaths,
+		ScreenOptimizerRollback,
+		ScreenHelp,
+		ScreenOptimizerConfirmReorder,
+		ScreenJunctionRewriteConfirm,
+		ScreenJunctionPruneConfirm,
+		ScreenBackupDiff,
 	}
 
 	seen := make(map[Screen]bool)
@@ -655,6 +676,47 @@ func TestModel_ViewerScope_Toggle(t *testing.T) {
 	}
 }
 
+func TestModel_ViewerScope_CyclesThroughProcess(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+
+	model := New()
+	model.screen = ScreenPathViewer
+	model.viewerScope = "User"
+
+	for _, want := range []string{"System", "Process", "User"} {
+		model = pressKey(t, model, "s")
+		if model.viewerScope != want {
+			t.Fatalf("Expected scope %s, got %s", want, model.viewerScope)
+		}
+	}
+}
+
+func TestModel_Viewer_ProcessScopeReadsEnvironment(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+	t.Setenv("PATH", `C:\Shell\Only;C:\Windows`)
+	mock := withMock(t, nil)
+
+	model := New()
+	model.screen = ScreenPathViewer
+	model.viewerScope = "Process"
+	before := len(mock.Calls)
+	model, _ = model.loadViewer()
+	view := model.viewPathViewer()
+	if len(mock.Calls) != before {
+		t.Error("Process scope should not query the registry")
+	}
+	if !strings.Contains(view, "[Process]") || !strings.Contains(view, `C:\Shell\Only`) {
+		t.Errorf("Expected process PATH in the view, got:\n%s", view)
+	}
+
+	m := pressKey(t, model, "E")
+	if m.screen != ScreenPathViewer || !strings.Contains(m.message, "read-only") {
+		t.Errorf("Expected the editor to be refused for Process, got screen %d message %q", m.screen, m.message)
+	}
+}
+
 func TestModel_ViewerExpanded_Toggle(t *testing.T) {
 	model := New()
 	model.screen = ScreenPathViewer
@@ -897,6 +959,49 @@ func TestModel_ClipboardOK(t *testing.T) {
 // Helpers
 // ============================================================================
 
+// withMock returns the shared mock runner after applying setup
+// Responses, errors and the default response are restored when the test ends
+func withMock(t *testing.T, setup func(*path.MockShellRunner)) *path.MockShellRunner {
+	t.Helper()
+	mock, ok := path.DefaultRunner.(*path.MockShellRunner)
+	if !ok {
+		t.Fatal("Mock runner not set up - tests could modify real system!")
+	}
+
+	oldResponses := make(map[string]string, len(mock.Responses))
+	for k, v := range mock.Responses {
+		oldResponses[k] = v
+	}
+	oldErrors := make(map[string]error, len(mock.Errors))
+	for k, v := range mock.Errors {
+		oldErrors[k] = v
+	}
+	oldDefault := mock.DefaultResponse
+	oldBlocking := mock.Blocking
+	t.Cleanup(func() {
+		mock.Responses = oldResponses
+		mock.Errors = oldErrors
+		mock.DefaultResponse = oldDefault
+		mock.Blocking = oldBlocking
+	})
+
+	if setup != nil {
+		setup(mock)
+	}
+	return mock
+}
+
+// countCalls counts mock calls containing pattern
+func countCalls(calls []string, pattern string) int {
+	n := 0
+	for _, c := range calls {
+		if strings.Contains(c, pattern) {
+			n++
+		}
+	}
+	return n
+}
+
 // ============================================================================
 // Benchmarks
 // ============================================================================
@@ -1492,6 +1597,35 @@ func TestModel_SettingsKey_Navigation(t *testing.T) {
 	}
 }
 
+func TestModel_SettingsKey_OpenConfigFolder(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	dir := t.TempDir()
+	path.SetConfigDir(dir)
+	mock := withMock(t, nil)
+
+	model := New()
+	model.screen = ScreenSettings
+	view := model.viewSettings()
+	if !strings.Contains(view, path.GetConfigPath()) || !strings.Contains(view, path.GetBackupDir()) {
+		t.Errorf("Expected the config and backup paths on the settings screen:\n%s", view)
+	}
+
+	before := len(mock.Calls)
+	m, _ := model.handleSettingsKey("o")
+	if n := countCalls(mock.Calls[before:], "explorer.exe"); n != 1 {
+		t.Errorf("Expected one Explorer launch, got %d", n)
+	}
+	if !strings.Contains(m.viewSettings(), "Opened "+dir) {
+		t.Error("Expected the opened folder in the message")
+	}
+
+	mock.SetError("explorer.exe", fmt.Errorf("not found"))
+	m, _ = m.handleSettingsKey("o")
+	if m.err == nil || !strings.Contains(m.viewSettings(), "Couldn't open") {
+		t.Error("Expected the failure to be shown")
+	}
+}
+
 func TestModel_SettingsKey_Toggle(t *testing.T) {
 	model := New()
 	model.screen = ScreenSettings
@@ -1671,6 +1805,48 @@ func TestWrapText_ShortText(t *testing.T) {
 	}
 }
 
+func TestWrapText_BreaksAtSeparators(t *testing.T) {
+	text := `C:\Windows\System32;C:\Program Files\Git\cmd;C:\Tools`
+	wrapped := wrapText(text, 24)
+	want := "C:\\Windows\\System32;\nC:\\Program Files\\Git\\\ncmd;C:\\Tools"
+	if wrapped != want {
+		t.Errorf("wrapText = %q, want %q", wrapped, want)
+	}
+	if strings.ReplaceAll(wrapped, "\n", "") != text {
+		t.Error("Wrapping should not drop any characters")
+	}
+}
+
+func TestWrapText_MultiByte(t *testing.T) {
+	text := `C:\Users\Jürgen\データ\bin;C:\Users\Jürgen\ツール`
+	wrapped := wrapText(text, 12)
+	for _, line := range strings.Split(wrapped, "\n") {
+		if !utf8.ValidString(line) {
+			t.Errorf("Line %q splits a multi-byte rune", line)
+		}
+		if n := utf8.RuneCountInString(line); n > 12 {
+			t.Errorf("Line %q is %d runes, want at most 12", line, n)
+		}
+	}
+	if strings.ReplaceAll(wrapped, "\n", "") != text {
+		t.Error("Wrapping should not drop any characters")
+	}
+
+	// Fits in runes even though it is longer in bytes
+	if got := wrapText("データ", 3); got != "データ" {
+		t.Errorf("Expected text that fits in runes to be unchanged, got %q", got)
+	}
+}
+
+func TestWrapText_LongToken(t *testing.T) {
+	text := strings.Repeat("x", 25) + " end"
+	wrapped := wrapText(text, 10)
+	want := "xxxxxxxxxx\nxxxxxxxxxx\nxxxxx end"
+	if wrapped != want {
+		t.Errorf("wrapText = %q, want %q", wrapped, want)
+	}
+}
+
 func TestWrapText_Empty(t *testing.T) {
 	wrapped := wrapText("", 40)
 	if wrapped != "" {
@@ -1782,7 +1958,7 @@ func TestScreenFlow_Menu_To_Settings(t *testing.T) {
 func TestScreenFlow_Menu_Exit(t *testing.T) {
 	model := New()
 	model.screen = ScreenMenu
-	model.menuIndex = 7
+	model.menuIndex = 10
 
 	msg := tea.KeyMsg{Type: tea.KeyEnter}
 	_, cmd := model.Update(msg)
@@ -1796,7 +1972,7 @@ func TestScreenFlow_Menu_Exit(t *testing.T) {
 func TestScreenFlow_Escape_Returns_To_Menu(t *testing.T) {
 	screens := []Screen{
 		ScreenOptimizer, ScreenPathViewer, ScreenBackup,
-		ScreenJunctions, ScreenPathExt, ScreenSettings, ScreenHotPaths,
+		ScreenJunctions, ScreenPathExt, ScreenSettings, ScreenHotPaths, ScreenAuditLog,
 	}
 
 	for _, screen := range screens {
@@ -2199,6 +2375,8 @@ func TestModel_HandleOptimizerKey_Scope(t *testing.T) {
 func TestModel_HandleOptimizerKey_Apply(t *testing.T) {
 	model := New()
 	model.screen = ScreenOptimizerPreview
+	model.optimizerScope = "both" // earlier tests may have saved another scope
+	model.isAdmin = true
 
 	result, _ := model.handleOptimizerKey("a")
 
@@ -2223,10 +2401,34 @@ func TestModel_HandleOptimizerKey_Scroll(t *testing.T) {
 		t.Error("scrollOffset should not go negative")
 	}
 
+	model.viewMode = 1
+	model.analysis = &path.AnalysisResult{}
+	for i := 0; i < changesMaxVisible+3; i++ {
+		model.analysis.User.Changes = append(model.analysis.User.Changes, path.PathChange{Type: "dead", Original: fmt.Sprintf(`C:\Gone%d`, i)})
+	}
 	model.scrollOffset = 0
 	resultDown, _ := model.handleOptimizerKey("down")
-	if resultDown.scrollOffset != 1 {
-		t.Error("scrollOffset should increase on down")
+	if resultDown.changeIndex != 1 || resultDown.scrollOffset != 0 {
+		t.Errorf("Expected down to move the selection, got index %d offset %d", resultDown.changeIndex, resultDown.scrollOffset)
+	}
+
+	m := model
+	for i := 0; i < 50; i++ {
+		m, _ = m.handleOptimizerKey("down")
+	}
+	if m.changeIndex != changesMaxVisible+2 || m.scrollOffset != 3 {
+		t.Errorf("Expected the selection to stop at the last change with offset 3, got index %d offset %d", m.changeIndex, m.scrollOffset)
+	}
+	m, _ = m.handleOptimizerKey("up")
+	if m.changeIndex != changesMaxVisible+1 {
+		t.Errorf("Expected up to respond immediately after overscrolling, got %d", m.changeIndex)
+	}
+
+	// Tabs without a scrollable list stay at the top
+	model.viewMode = 0
+	resultSummary, _ := model.handleOptimizerKey("down")
+	if resultSummary.scrollOffset != 0 {
+		t.Errorf("Expected summary tab not to scroll, got %d", resultSummary.scrollOffset)
 	}
 }
 
@@ -2234,6 +2436,7 @@ func TestModel_HandleOptimizerConfirmKey_Yes(t *testing.T) {
 	model := New()
 	model.screen = ScreenOptimizerConfirm
 	model.analysis = &path.AnalysisResult{}
+	model.optimizerScope = "user"
 
 	result, cmd := model.handleOptimizerConfirmKey("y")
 
@@ -2260,10 +2463,22 @@ func TestModel_HandleDoneKey_Copy(t *testing.T) {
 	model := New()
 	model.screen = ScreenOptimizerDone
 
+	mock, cleanup := path.SetDefaultClipboard()
+	defer cleanup()
+
 	result, _ := model.handleDoneKey("c", ScreenMenu)
+	if !result.clipboardOK {
+		t.Error("Expected clipboardOK after a successful copy")
+	}
+	if mock.Last() != path.GetRefreshCommand() {
+		t.Errorf("Expected refresh command on clipboard, got %q", mock.Last())
+	}
 
-	// clipboardOK will be set based on whether clipboard works
-	t.Logf("clipboardOK: %v", result.clipboardOK)
+	mock.Err = fmt.Errorf("clipboard unavailable")
+	result, _ = model.handleDoneKey("c", ScreenMenu)
+	if result.clipboardOK {
+		t.Error("clipboardOK should stay false when the copy fails")
+	}
 }
 
 func TestModel_HandleDoneKey_Escape(t *testing.T) {
@@ -2308,20 +2523,44 @@ func TestModel_HandleViewerKey_Escape(t *testing.T) {
 }
 
 func TestModel_HandleViewerKey_Scroll(t *testing.T) {
+	withMock(t, func(mock *path.MockShellRunner) {
+		var entries []string
+		for i := 0; i < 30; i++ {
+			entries = append(entries, fmt.Sprintf(`C:\Tools\t%d`, i))
+		}
+		mock.SetResponse("CurrentUser.OpenSubKey", strings.Join(entries, ";"))
+	})
+
 	model := New()
 	model.screen = ScreenPathViewer
+	model.viewerScope = "User"
 	model.scrollOffset = 5
+	model.viewerIndex = 5
 
-	// Test scroll up
+	// Moving above the visible window scrolls up with the selection
 	resultUp, _ := model.handleViewerKey("up")
-	if resultUp.scrollOffset != 4 {
-		t.Errorf("scrollOffset should decrease from 5 to 4, got %d", resultUp.scrollOffset)
+	if resultUp.viewerIndex != 4 || resultUp.scrollOffset != 4 {
+		t.Errorf("Expected index 4 and offset 4, got %d and %d", resultUp.viewerIndex, resultUp.scrollOffset)
 	}
 
-	// Test scroll down (from original model with offset=5)
+	// Moving within the window keeps the offset
 	resultDown, _ := model.handleViewerKey("down")
+	if resultDown.viewerIndex != 6 || resultDown.scrollOffset != 5 {
+		t.Errorf("Expected index 6 and offset 5, got %d and %d", resultDown.viewerIndex, resultDown.scrollOffset)
+	}
+
+	// Moving below the window scrolls down
+	model.viewerIndex = 5 + viewerMaxVisible - 1
+	resultDown, _ = model.handleViewerKey("down")
 	if resultDown.scrollOffset != 6 {
-		t.Errorf("scrollOffset should increase from 5 to 6, got %d", resultDown.scrollOffset)
+		t.Errorf("Expected offset 6, got %d", resultDown.scrollOffset)
+	}
+
+	// Selection stops at the last entry
+	model.viewerIndex = 29
+	resultDown, _ = model.handleViewerKey("down")
+	if resultDown.viewerIndex != 29 {
+		t.Errorf("Expected index to stay at 29, got %d", resultDown.viewerIndex)
 	}
 }
 
@@ -2381,12 +2620,121 @@ func TestModel_HandleBackupKey_Create(t *testing.T) {
 	model.screen = ScreenBackup
 
 	result, _ := model.handleBackupKey("c")
+	if !result.backupLabeling {
+		t.Fatal("Expected 'c' to prompt for a description")
+	}
 
+	result, _ = result.handleBackupKey("enter")
 	if result.message != "Backup created!" {
 		t.Error("Expected backup created message")
 	}
 }
 
+func TestModel_HandleBackupKey_CreateWithDescription(t *testing.T) {
+	original := filepath.Dir(path.GetConfigPath())
+	path.SetConfigDir(t.TempDir())
+	t.Cleanup(func() { path.SetConfigDir(original) })
+
+	model := New()
+	model.screen = ScreenBackup
+
+	m, _ := model.handleBackupKey("c")
+	for _, r := range "Before VS install" {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	if m.backupLabelInput != "Before VS install" {
+		t.Fatalf("Unexpected input %q", m.backupLabelInput)
+	}
+	m, _ = m.handleBackupKey("enter")
+
+	if m.backupLabeling || len(m.backups) != 1 {
+		t.Fatalf("Expected one backup created, got %d", len(m.backups))
+	}
+	if m.backups[0].Suffix != "before-vs-install" || m.backups[0].Description != "Before VS install" {
+		t.Errorf("Unexpected backup %+v", m.backups[0])
+	}
+	if !strings.Contains(m.viewBackup(), "Before VS install") {
+		t.Error("Backup list should show the description")
+	}
+
+	m = m.handleBackupView()
+	if !strings.Contains(m.viewBackupPreview(), "Before VS install") {
+		t.Error("Backup preview should show the description")
+	}
+}
+
+func TestModel_HandleBackupKey_CreateCancel(t *testing.T) {
+	model := New()
+	model.screen = ScreenBackup
+	model.backups = path.ListBackups()
+	before := len(model.backups)
+
+	m, _ := model.handleBackupKey("c")
+	m, _ = m.handleBackupKey("esc")
+	if m.backupLabeling || m.screen != ScreenBackup {
+		t.Error("Escape should cancel the description prompt")
+	}
+	if len(path.ListBackups()) != before {
+		t.Error("Cancelling should not create a backup")
+	}
+}
+
+func TestModel_HandleBackupKey_Import(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+
+	src := filepath.Join(t.TempDir(), "teammate.json")
+	data := `{"timestamp": "2025-03-04T09:08:07Z", "userPath": {"raw": "C:\\Tools"}}`
+	if err := os.WriteFile(src, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	model := New()
+	model.screen = ScreenBackup
+	m, _ := model.handleBackupKey("i")
+	if !m.backupImporting || !m.inTextInput() {
+		t.Fatal("Expected the import prompt to open")
+	}
+	for _, r := range `"` + src + `"` {
+		m, _ = m.handleBackupKey(string(r))
+	}
+	m, _ = m.handleBackupKey("enter")
+
+	if m.backupImporting {
+		t.Error("Expected the prompt to close after enter")
+	}
+	if len(m.backups) != 1 || m.backups[0].Filename != "path_20250304_090807_imported.json" {
+		t.Errorf("Expected the imported backup to be listed, got %+v", m.backups)
+	}
+	if !strings.Contains(m.message, "Imported") {
+		t.Errorf("Expected success message, got %q", m.message)
+	}
+}
+
+func TestModel_HandleBackupKey_ImportInvalid(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+
+	src := filepath.Join(t.TempDir(), "broken.json")
+	if err := os.WriteFile(src, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	model := New()
+	model.screen = ScreenBackup
+	model.backupImporting = true
+	model.backupImportInput = src
+	m, _ := model.handleBackupKey("enter")
+
+	if len(path.ListBackups()) != 0 {
+		t.Error("Invalid file should not be imported")
+	}
+	if !strings.Contains(m.message, "Import failed") || !strings.Contains(m.viewBackup(), "not a valid backup file") {
+		t.Errorf("Expected a clear import error, got %q", m.message)
+	}
+}
+
 func TestModel_HandleBackupKey_Restore(t *testing.T) {
 	model := New()
 	model.screen = ScreenBackup
@@ -2441,10 +2789,14 @@ func TestModel_HandleBackupPreviewKey_Scroll(t *testing.T) {
 		t.Errorf("scrollOffset should decrease from 5 to 4, got %d", resultUp.scrollOffset)
 	}
 
-	// Test scroll down (from original model with offset=5)
-	resultDown, _ := model.handleBackupPreviewKey("down")
-	if resultDown.scrollOffset != 6 {
-		t.Errorf("scrollOffset should increase from 5 to 6, got %d", resultDown.scrollOffset)
+	// The preview is a fixed-size summary, so down never scrolls past the top
+	model.scrollOffset = 0
+	m := model
+	for i := 0; i < 10; i++ {
+		m, _ = m.handleBackupPreviewKey("down")
+	}
+	if m.scrollOffset != 0 {
+		t.Errorf("scrollOffset should stay at its maximum of 0, got %d", m.scrollOffset)
 	}
 }
 
@@ -2895,6 +3247,49 @@ func TestModel_RenderSummary(t *testing.T) {
 	}
 }
 
+func TestModel_RenderSummary_LookupEstimate(t *testing.T) {
+	model := New()
+	model.analysis = &path.AnalysisResult{
+		User: path.OptimizeResult{Metrics: path.OptimizeMetrics{DuplicatesRemoved: 2, EstimatedLookupsAvoided: 22}},
+	}
+	summary := model.renderSummary()
+	if !strings.Contains(summary, "~22 fewer file checks") || !strings.Contains(summary, "estimate") {
+		t.Errorf("Expected the lookup estimate with a disclaimer, got:\n%s", summary)
+	}
+
+	model.analysis.User.Metrics = path.OptimizeMetrics{}
+	if strings.Contains(model.renderSummary(), "fewer file checks") {
+		t.Error("Expected no estimate line when nothing was removed")
+	}
+}
+
+func TestModel_RenderSummary_ShadowedTools(t *testing.T) {
+	model := New()
+	model.analysis = &path.AnalysisResult{
+		ShadowedTools: []path.ShadowInfo{{Tool: "git", Winner: `C:\Git\cmd\git.exe`, Shadowed: []string{`C:\Old\git.exe`}}},
+	}
+	summary := model.renderSummary()
+	if !strings.Contains(summary, "Shadowed Tools") || !strings.Contains(summary, `git: C:\Git\cmd\git.exe hides C:\Old\git.exe`) {
+		t.Errorf("Expected the shadowed tool in the summary, got:\n%s", summary)
+	}
+}
+
+func TestModel_Settings_ToggleShadowedTools(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+
+	model := New()
+	model.screen = ScreenSettings
+	model.settingsIndex = 8
+	model = pressKey(t, model, "enter")
+	if !path.LoadConfig().DetectShadowedTools {
+		t.Error("Expected the shadowed tools scan to be enabled and saved")
+	}
+	if !strings.Contains(model.View(), "Detect Shadowed Tools: true") {
+		t.Error("Expected the setting in the view")
+	}
+}
+
 func TestModel_RenderChanges(t *testing.T) {
 	model := New()
 	model.width = 80
@@ -2948,6 +3343,37 @@ func TestModel_RenderList(t *testing.T) {
 	}
 }
 
+func TestModel_RenderList_MarksMissingEntries(t *testing.T) {
+	existing := os.TempDir()
+	missing := `Z:\nowhere\winpath-missing`
+	model := New()
+	model.optimizerScope = "user"
+	model.analysis = &path.AnalysisResult{
+		User: path.OptimizeResult{
+			Optimized: path.PathInfo{Entries: []string{existing, missing, `%NOPE_NOT_SET%\bin`}},
+		},
+	}
+
+	lines := strings.Split(model.renderList(), "\n")
+	marks := map[string]string{}
+	for _, line := range lines {
+		for _, e := range []string{existing, missing, `%NOPE_NOT_SET%\bin`} {
+			if strings.HasSuffix(line, " "+e) {
+				marks[e] = line
+			}
+		}
+	}
+	if !strings.Contains(marks[missing], "! ") {
+		t.Errorf("Expected the missing entry to get the error marker, got %q", marks[missing])
+	}
+	if !strings.Contains(marks[existing], "* ") {
+		t.Errorf("Expected the existing entry to get the ok marker, got %q", marks[existing])
+	}
+	if !strings.Contains(marks[`%NOPE_NOT_SET%\bin`], "* ") {
+		t.Errorf("Variable entries should not be flagged, got %q", marks[`%NOPE_NOT_SET%\bin`])
+	}
+}
+
 func TestModel_ViewConfirm(t *testing.T) {
 	model := New()
 	model.width = 80
@@ -3211,3 +3637,4255 @@ func TestModel_IndexOutOfBounds(t *testing.T) {
 	// Should not panic
 	t.Logf("Delete with out of bounds index: message=%s", result.message)
 }
+
+// ============================================================================
+// Hot Path Preview Tests
+// ============================================================================
+
+func TestModel_HotPaths_MissingPathsFlagged(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+
+	existing := t.TempDir()
+	missing := filepath.Join(existing, "typo")
+	model := New()
+	model.screen = ScreenMenu
+	model.menuIndex = 5
+	model.config.HotPaths = []string{existing, missing, `%GOPATH%\bin`}
+	m, _ := model.handleMenuKey("enter")
+	if m.screen != ScreenHotPaths {
+		t.Fatalf("Expected the hot paths screen, got %d", m.screen)
+	}
+
+	view := m.viewHotPaths()
+	if strings.Count(view, "! not found") != 1 {
+		t.Fatalf("Expected only the missing path flagged:\n%s", view)
+	}
+	for _, line := range strings.Split(view, "\n") {
+		if strings.Contains(line, "! not found") && !strings.Contains(line, "2. ") {
+			t.Errorf("Marker on the wrong path: %s", line)
+		}
+	}
+
+	m, _ = m.handleHotPathsKey("a")
+	for _, k := range strings.Split(filepath.Join(existing, "nope"), "") {
+		m, _ = m.handleHotPathsKey(k)
+	}
+	m, _ = m.handleHotPathsKey("enter")
+	if len(m.config.HotPaths) != 4 {
+		t.Fatal("A missing path should still be added")
+	}
+	if !strings.Contains(m.viewHotPaths(), "doesn't exist right now") {
+		t.Error("Expected a warning when adding a missing path")
+	}
+	if strings.Count(m.viewHotPaths(), "! not found") != 2 {
+		t.Error("Expected the new path flagged too")
+	}
+
+	m, _ = m.handleHotPathsKey("j")
+	if strings.Contains(m.viewHotPaths(), "doesn't exist right now") {
+		t.Error("The warning should clear on the next key")
+	}
+}
+
+func TestModel_HotPaths_PasteInsertsWholeText(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+
+	model := New()
+	model.screen = ScreenHotPaths
+	m, _ := model.handleHotPathsKey("a")
+
+	pasted := `C:\Users\Zoë\Tools\bin`
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(pasted)})
+	m = updated.(Model)
+	if m.hotPathInput != pasted {
+		t.Fatalf("Expected the whole paste in one step, got %q", m.hotPathInput)
+	}
+	if !m.hotPathAdding || len(m.config.HotPaths) != 0 {
+		t.Error("A single-line paste should stay in the input until Enter")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ë")})
+	m = updated.(Model)
+	if m.hotPathInput != pasted+"ë" {
+		t.Errorf("Expected a typed non-ASCII letter to be accepted, got %q", m.hotPathInput)
+	}
+}
+
+func TestModel_HotPaths_PasteMultipleLines(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+
+	model := New()
+	model.screen = ScreenHotPaths
+	m, _ := model.handleHotPathsKey("a")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C:\\Go\\bin\r\nC:\\Git\\cmd\r\n")})
+	m = updated.(Model)
+	want := []string{`C:\Go\bin`, `C:\Git\cmd`}
+	if strings.Join(m.config.HotPaths, ";") != strings.Join(want, ";") {
+		t.Fatalf("Expected each line added, got %v", m.config.HotPaths)
+	}
+	if m.hotPathAdding || m.hotPathInput != "" {
+		t.Error("A paste ending in a newline should leave the input closed")
+	}
+	if strings.Join(path.LoadConfig().HotPaths, ";") != strings.Join(want, ";") {
+		t.Error("Expected the pasted paths saved")
+	}
+}
+
+func TestModel_HotPaths_MoveToPosition(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+
+	model := New()
+	model.screen = ScreenHotPaths
+	model.config.HotPaths = []string{`C:\A`, `C:\B`, `C:\C`, `C:\D`}
+
+	m := model.handleHotPathsNavKey("3")
+	if got := strings.Join(m.config.HotPaths, ";"); got != `C:\B;C:\C;C:\A;C:\D` {
+		t.Fatalf("Expected the first path moved to index 2, got %s", got)
+	}
+	if m.hotPathIndex != 2 {
+		t.Errorf("Selection should follow the moved path, got %d", m.hotPathIndex)
+	}
+	if got := strings.Join(path.LoadConfig().HotPaths, ";"); got != `C:\B;C:\C;C:\A;C:\D` {
+		t.Errorf("Expected the new order saved, got %s", got)
+	}
+
+	m = m.handleHotPathsNavKey("1")
+	if got := strings.Join(m.config.HotPaths, ";"); got != `C:\A;C:\B;C:\C;C:\D` || m.hotPathIndex != 0 {
+		t.Errorf("Expected it moved back to the front, got %s", got)
+	}
+
+	m = m.handleHotPathsNavKey("9")
+	if got := strings.Join(m.config.HotPaths, ";"); got != `C:\B;C:\C;C:\D;C:\A` {
+		t.Errorf("A position past the end should move it last, got %s", got)
+	}
+
+	m = m.handleHotPathsNavKey("u")
+	if got := strings.Join(m.config.HotPaths, ";"); got != `C:\A;C:\B;C:\C;C:\D` {
+		t.Errorf("Expected the move to be undoable, got %s", got)
+	}
+}
+
+func TestModel_HotPathPreview_Toggle(t *testing.T) {
+	model := New()
+	model.screen = ScreenHotPaths
+
+	result := model.handleHotPathsNavKey("p")
+	if !result.hotPathPreview {
+		t.Error("'p' should enable the preview")
+	}
+	result = result.handleHotPathsNavKey("s")
+	if result.hotPathPreviewScope != "System" {
+		t.Errorf("Expected preview scope System, got %s", result.hotPathPreviewScope)
+	}
+	result = result.handleHotPathsNavKey("p")
+	if result.hotPathPreview {
+		t.Error("'p' again should hide the preview")
+	}
+}
+
+func TestModel_HotPathPreview_Ordering(t *testing.T) {
+	withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\Windows;C:\Tools;C:\Python;C:\Git`)
+	})
+
+	model := New()
+	model.screen = ScreenHotPaths
+	model.config.HotPaths = []string{`C:\Git`, `C:\Python`}
+	model = model.loadHotPathPreview()
+
+	entries := model.hotPathPreviewEntries()
+	expected := []string{`C:\Git`, `C:\Python`, `C:\Windows`, `C:\Tools`}
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %v", len(expected), entries)
+	}
+	for i := range expected {
+		if entries[i] != expected[i] {
+			t.Errorf("Entry %d: expected %s, got %s", i, expected[i], entries[i])
+		}
+	}
+
+	model.hotPathPreview = true
+	view := model.viewHotPaths()
+	if !strings.Contains(view, "Preview: User PATH") {
+		t.Error("View should contain the preview header")
+	}
+	gitPos := strings.Index(view, `* C:\Git`)
+	pyPos := strings.Index(view, `* C:\Python`)
+	winPos := strings.Index(view, `C:\Windows`)
+	if gitPos == -1 || pyPos == -1 || winPos == -1 {
+		t.Fatalf("Preview missing entries:\n%s", view)
+	}
+	if !(gitPos < pyPos && pyPos < winPos) {
+		t.Error("Hot path should render before regular entries")
+	}
+}
+
+func TestModel_HotPathPreview_ReadsPathOnlyWhenShown(t *testing.T) {
+	mock := withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\Windows;C:\Git`)
+		mock.SetResponse("LocalMachine.OpenSubKey", `C:\System32`)
+	})
+
+	model := New()
+	model.screen = ScreenHotPaths
+	model.config.HotPaths = []string{`C:\Git`}
+
+	before := len(mock.Calls)
+	m := model.handleHotPathsNavKey("p")
+	if n := countCalls(mock.Calls[before:], "OpenSubKey"); n != 1 {
+		t.Fatalf("Expected one PATH read when the preview opens, got %d", n)
+	}
+
+	before = len(mock.Calls)
+	for i := 0; i < 3; i++ {
+		_ = m.View()
+	}
+	if n := countCalls(mock.Calls[before:], "OpenSubKey"); n != 0 {
+		t.Errorf("Rendering should not read the PATH, got %d reads", n)
+	}
+	if !strings.Contains(m.View(), `* C:\Git`) {
+		t.Error("Expected the cached PATH in the preview")
+	}
+
+	m = m.handleHotPathsNavKey("s")
+	if n := countCalls(mock.Calls[before:], "LocalMachine.OpenSubKey"); n != 1 {
+		t.Errorf("Expected the System PATH read when switching scope, got %d", n)
+	}
+	if !strings.Contains(m.View(), `C:\System32`) {
+		t.Error("Expected the System PATH in the preview")
+	}
+}
+
+// ============================================================================
+// Analysis Export Tests
+// ============================================================================
+
+func TestModel_OptimizerExport(t *testing.T) {
+	model := New()
+	model.screen = ScreenOptimizerPreview
+	model.analysis = &path.AnalysisResult{}
+	model.analysis.User.Optimized.Raw = `C:\Tools`
+
+	result, _ := model.handleOptimizerKey("x")
+
+	if result.err != nil {
+		t.Fatalf("Export failed: %v", result.err)
+	}
+	if !strings.HasPrefix(result.message, "Exported to ") {
+		t.Fatalf("Expected export message, got %q", result.message)
+	}
+	file := strings.TrimPrefix(result.message, "Exported to ")
+	defer os.Remove(file)
+	if _, err := os.Stat(file); err != nil {
+		t.Errorf("Exported file should exist: %v", err)
+	}
+	if !strings.Contains(result.viewOptimizer(), file) {
+		t.Error("Optimizer view should show the export path")
+	}
+}
+
+func TestModel_OptimizerExport_NoAnalysis(t *testing.T) {
+	model := New()
+	model.screen = ScreenOptimizerPreview
+
+	result, _ := model.handleOptimizerKey("x")
+	if result.message != "" {
+		t.Errorf("Expected no message without analysis, got %q", result.message)
+	}
+}
+
+func TestModel_OptimizerWriteEntries(t *testing.T) {
+	model := New()
+	model.screen = ScreenOptimizerPreview
+	model.analysis = &path.AnalysisResult{}
+	model.analysis.System.Optimized.Entries = []string{`C:\Windows`, `C:\Windows\System32`}
+	model.analysis.User.Optimized.Entries = []string{`C:\Tools`}
+
+	model.optimizerScope = "system"
+	result, _ := model.handleOptimizerKey("w")
+	if result.err != nil || !strings.HasPrefix(result.message, "Wrote entries to ") {
+		t.Fatalf("Expected write message, got %q", result.message)
+	}
+	file := strings.TrimPrefix(result.message, "Wrote entries to ")
+	defer os.Remove(file)
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Written file should exist: %v", err)
+	}
+	if !strings.Contains(string(data), "# System PATH (optimized)") || !strings.HasSuffix(string(data), "C:\\Windows\nC:\\Windows\\System32\n") {
+		t.Errorf("Unexpected file content:\n%s", data)
+	}
+
+	model.optimizerScope = "both"
+	result, _ = model.handleOptimizerKey("w")
+	files := strings.Split(strings.TrimPrefix(result.message, "Wrote entries to "), " and ")
+	for _, f := range files {
+		defer os.Remove(f)
+	}
+	if len(files) != 2 || !strings.Contains(files[0], "path_system_") || !strings.Contains(files[1], "path_user_") {
+		t.Errorf("Expected one file per scope, got %q", result.message)
+	}
+}
+
+func TestModel_ViewerWriteEntries(t *testing.T) {
+	withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\First;C:\Second;C:\Third`)
+	})
+
+	model := New()
+	model.screen = ScreenPathViewer
+	model.viewerScope = "User"
+	m := pressKey(t, model, "w")
+	if !strings.HasPrefix(m.message, "Wrote entries to ") {
+		t.Fatalf("Expected write message, got %q", m.message)
+	}
+	file := strings.TrimPrefix(m.message, "Wrote entries to ")
+	defer os.Remove(file)
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Written file should exist: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "# User PATH, 3 entries\n") || !strings.HasSuffix(string(data), "C:\\First\nC:\\Second\nC:\\Third\n") {
+		t.Errorf("Unexpected file content:\n%s", data)
+	}
+}
+
+// ============================================================================
+// Junction Equivalent Tests
+// ============================================================================
+
+func TestModel_RenderSummary_JunctionEquivalents(t *testing.T) {
+	model := New()
+	model.analysis = &path.AnalysisResult{
+		JunctionEquivalents: []path.JunctionEquivalent{
+			{Entry: `C:\l\la\bin`, Equivalent: `C:\Program Files\LongApp\bin`, Junction: "la"},
+		},
+	}
+
+	view := model.renderSummary()
+	if !strings.Contains(view, "Duplicates via Junctions") {
+		t.Error("Summary should show the junction duplicates box")
+	}
+	if !strings.Contains(view, `C:\l\la\bin`) {
+		t.Error("Summary should list the junction entry")
+	}
+}
+
+// ============================================================================
+// Rollback Timer Tests
+// ============================================================================
+
+func rollbackTestModel() Model {
+	model := New()
+	model.screen = ScreenOptimizerConfirm
+	model.optimizerScope = "user"
+	model.config.RollbackSeconds = 3
+	model.analysis = &path.AnalysisResult{}
+	model.analysis.User.Original.Raw = `C:\Original`
+	model.analysis.User.Optimized.Raw = `C:\Optimized`
+	return model
+}
+
+// armAndApply presses T on the confirm screen and delivers the apply result
+func armAndApply(t *testing.T, model Model) Model {
+	t.Helper()
+	m, cmd := model.handleOptimizerConfirmKey("t")
+	if cmd == nil || !m.rollbackArmed {
+		t.Fatal("'t' should arm the rollback timer and start the apply")
+	}
+	if m.rollbackSnapshot["User"] != `C:\Original` {
+		t.Fatalf("Snapshot should hold the original User PATH, got %v", m.rollbackSnapshot)
+	}
+	if _, ok := m.rollbackSnapshot["System"]; ok {
+		t.Fatal("System should not be snapshotted for user scope")
+	}
+
+	updated, _ := m.Update(applyCompleteMsg{backup: &path.BackupInfo{Filename: "x.json"}})
+	m = updated.(Model)
+	if m.screen != ScreenOptimizerRollback {
+		t.Fatalf("Expected rollback screen, got %d", m.screen)
+	}
+	if m.rollbackRemaining != 3 {
+		t.Fatalf("Expected 3 seconds remaining, got %d", m.rollbackRemaining)
+	}
+	return m
+}
+
+func TestModel_Rollback_RevertsWhenTimerExpires(t *testing.T) {
+	mock := withMock(t, nil)
+	m := armAndApply(t, rollbackTestModel())
+	before := len(mock.Calls)
+
+	var cmd tea.Cmd
+	for i := 0; i < 3; i++ {
+		var updated tea.Model
+		updated, cmd = m.Update(rollbackTickMsg{id: m.rollbackID})
+		m = updated.(Model)
+	}
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable"); n != 0 {
+		t.Fatal("The revert should run as a command, not inside Update")
+	}
+	m, _ = runWrite(t, m, cmd)
+
+	if m.screen != ScreenOptimizerPreview {
+		t.Errorf("Expected return to preview after revert, got %d", m.screen)
+	}
+	if n := countCalls(mock.Calls[before:], `SetEnvironmentVariable('Path', 'C:\Original', 'User')`); n != 1 {
+		t.Errorf("Expected original User PATH to be written back once, got %d", n)
+	}
+	if !strings.Contains(m.message, "reverted") {
+		t.Errorf("Expected revert message, got %q", m.message)
+	}
+}
+
+func TestModel_Rollback_KeepPersists(t *testing.T) {
+	mock := withMock(t, nil)
+	m := armAndApply(t, rollbackTestModel())
+	before := len(mock.Calls)
+
+	m, _ = m.handleRollbackKey("k")
+	if m.screen != ScreenOptimizerDone {
+		t.Errorf("Expected done screen after keep, got %d", m.screen)
+	}
+
+	// Late ticks must not revert anything
+	for i := 0; i < 5; i++ {
+		updated, _ := m.Update(rollbackTickMsg{id: m.rollbackID})
+		m = updated.(Model)
+	}
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable('Path'"); n != 0 {
+		t.Errorf("Expected no PATH writes after keep, got %d", n)
+	}
+}
+
+func TestModel_Rollback_RevertNow(t *testing.T) {
+	mock := withMock(t, nil)
+	m := armAndApply(t, rollbackTestModel())
+	before := len(mock.Calls)
+
+	m, cmd := m.handleRollbackKey("r")
+	m, _ = runWrite(t, m, cmd)
+	if m.screen != ScreenOptimizerPreview {
+		t.Errorf("Expected preview after revert, got %d", m.screen)
+	}
+	if n := countCalls(mock.Calls[before:], "C:\\Original"); n != 1 {
+		t.Errorf("Expected one revert write, got %d", n)
+	}
+}
+
+func TestModel_Rollback_CtrlCRevertsBeforeQuitting(t *testing.T) {
+	mock := withMock(t, nil)
+	m := armAndApply(t, rollbackTestModel())
+	before := len(mock.Calls)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	m, cmd = runWrite(t, updated.(Model), cmd)
+	if n := countCalls(mock.Calls[before:], `SetEnvironmentVariable('Path', 'C:\Original', 'User')`); n != 1 {
+		t.Errorf("Expected the original User PATH to be written back before quitting, got %d", n)
+	}
+	if cmd == nil {
+		t.Fatal("Ctrl+C should still quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("Expected to quit once the revert succeeded")
+	}
+}
+
+func TestModel_Rollback_HelpKeyKeepsCountdown(t *testing.T) {
+	m := armAndApply(t, rollbackTestModel())
+	m = pressKey(t, m, "?")
+	if m.screen != ScreenOptimizerRollback {
+		t.Fatalf("Help should not cover the rollback countdown, got screen %d", m.screen)
+	}
+	if _, cmd := m.Update(rollbackTickMsg{id: m.rollbackID}); cmd == nil {
+		t.Error("Expected the countdown to keep ticking")
+	}
+}
+
+func TestModel_Rollback_StaleTickIgnored(t *testing.T) {
+	m := armAndApply(t, rollbackTestModel())
+	updated, cmd := m.Update(rollbackTickMsg{id: m.rollbackID - 1})
+	m = updated.(Model)
+	if cmd != nil || m.rollbackRemaining != 3 {
+		t.Error("Ticks from an earlier timer should be ignored")
+	}
+}
+
+func TestModel_OptimizerConfirm_PlainApplyNotArmed(t *testing.T) {
+	model := rollbackTestModel()
+	m, _ := model.handleOptimizerConfirmKey("y")
+	if m.rollbackArmed {
+		t.Error("'y' should not arm the rollback timer")
+	}
+	updated, _ := m.Update(applyCompleteMsg{})
+	if updated.(Model).screen != ScreenOptimizerDone {
+		t.Error("Plain apply should go straight to the done screen")
+	}
+}
+
+// ============================================================================
+// Help Overlay Tests
+// ============================================================================
+
+// runWrite checks that a write started on the loading screen and delivers its result
+func runWrite(t *testing.T, m Model, cmd tea.Cmd) (Model, tea.Cmd) {
+	t.Helper()
+	if m.screen != ScreenLoading || !m.loadingWrite || cmd == nil {
+		t.Fatalf("Expected a write on the loading screen, got screen %d", m.screen)
+	}
+	updated, next := m.Update(cmd().(tea.BatchMsg)[0]())
+	return updated.(Model), next
+}
+
+func pressKey(t *testing.T, model Model, key string) Model {
+	t.Helper()
+	var msg tea.KeyMsg
+	switch key {
+	case "esc":
+		msg = tea.KeyMsg{Type: tea.KeyEsc}
+	case "enter":
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	default:
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+	updated, _ := model.Update(msg)
+	return updated.(Model)
+}
+
+func TestModel_Help_FromMenu(t *testing.T) {
+	model := New()
+	model.screen = ScreenMenu
+
+	m := pressKey(t, model, "?")
+	if m.screen != ScreenHelp {
+		t.Fatalf("Expected help screen, got %d", m.screen)
+	}
+	if !strings.Contains(m.View(), "Menu") {
+		t.Error("Help should show menu bindings")
+	}
+
+	m = pressKey(t, m, "esc")
+	if m.screen != ScreenMenu {
+		t.Errorf("Expected return to menu, got %d", m.screen)
+	}
+}
+
+func TestModel_Help_FromOptimizer(t *testing.T) {
+	model := New()
+	model.screen = ScreenOptimizerPreview
+	model.analysis = &path.AnalysisResult{}
+
+	m := pressKey(t, model, "?")
+	if m.screen != ScreenHelp {
+		t.Fatalf("Expected help screen, got %d", m.screen)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Optimize PATH") || !strings.Contains(view, "Cycle scope") {
+		t.Error("Help should show optimizer bindings")
+	}
+
+	m = pressKey(t, m, "esc")
+	if m.screen != ScreenOptimizerPreview {
+		t.Errorf("Expected return to optimizer preview, got %d", m.screen)
+	}
+	if m.analysis == nil {
+		t.Error("Returning from help should keep the analysis")
+	}
+}
+
+func TestModel_Help_IgnoredInTextInput(t *testing.T) {
+	model := New()
+	model.screen = ScreenHotPaths
+	model.hotPathAdding = true
+
+	m := pressKey(t, model, "?")
+	if m.screen != ScreenHotPaths {
+		t.Error("'?' should be typed, not open help, while entering text")
+	}
+	if m.hotPathInput != "?" {
+		t.Errorf("Expected '?' in input, got %q", m.hotPathInput)
+	}
+}
+
+// ============================================================================
+// Changes Filter Tests
+// ============================================================================
+
+func changesModel() Model {
+	model := New()
+	model.screen = ScreenOptimizerPreview
+	model.viewMode = 1
+	model.analysis = &path.AnalysisResult{}
+	model.analysis.User.Changes = []path.PathChange{
+		{Type: "duplicate", Original: `C:\Dup`},
+		{Type: "dead", Original: `C:\Dead`},
+		{Type: "shortened", Original: `C:\Program Files\Tool`, New: `C:\PROGRA~1\Tool`},
+	}
+	return model
+}
+
+func TestModel_Changes_ShowsMalformedEntries(t *testing.T) {
+	model := changesModel()
+	model.analysis.User.Changes = []path.PathChange{
+		{Type: "malformed", Original: "C:/Tools", New: `C:\Tools`},
+		{Type: "malformed", Original: "/mnt/c/tools", New: `C:\tools`},
+	}
+
+	view := model.renderChanges()
+	if !strings.Contains(view, "[SLASH]") || !strings.Contains(view, "C:/Tools") || !strings.Contains(view, `C:\Tools`) {
+		t.Errorf("Expected the malformed entry and its fix, got:\n%s", view)
+	}
+	if strings.Count(view, "suggested, fix by hand") != 1 {
+		t.Errorf("Expected only the WSL path to be marked as a suggestion, got:\n%s", view)
+	}
+}
+
+func TestModel_ChangeDetail_ExplainsSelectedChange(t *testing.T) {
+	model := New()
+	model.screen = ScreenOptimizerPreview
+	model.viewMode = 1
+	model.analysis = &path.AnalysisResult{}
+	model.analysis.User.Changes = []path.PathChange{
+		{Type: "duplicate", Original: `C:\Tools\`, MatchedWith: `c:\tools`},
+		{Type: "dead", Original: `C:\Gone`, Reason: "CreateFile C:\\Gone: The system cannot find the file specified."},
+		{Type: "shortened", Original: `C:\Program Files\Tool`, New: `C:\PROGRA~1\Tool`, Saved: 8},
+	}
+
+	m := pressKey(t, model, "enter")
+	if m.screen != ScreenChangeDetail {
+		t.Fatalf("Expected Enter to open the change detail, got screen %d", m.screen)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Removed as a duplicate") || !strings.Contains(view, `c:\tools`) {
+		t.Errorf("Expected the matched entry in the duplicate explanation, got:\n%s", view)
+	}
+
+	m = pressKey(t, m, "esc")
+	m = pressKey(t, m, "down")
+	m = pressKey(t, m, "enter")
+	if view := m.View(); !strings.Contains(view, "cannot find the file") {
+		t.Errorf("Expected the stat error in the dead path explanation, got:\n%s", view)
+	}
+
+	m = pressKey(t, m, "esc")
+	m = pressKey(t, m, "down")
+	m = pressKey(t, m, "enter")
+	if view := m.View(); !strings.Contains(view, `C:\PROGRA~1\Tool`) || !strings.Contains(view, "8 chars") {
+		t.Errorf("Expected before/after and savings for a shortened path, got:\n%s", view)
+	}
+	m = pressKey(t, m, "esc")
+	if m.screen != ScreenOptimizerPreview || m.viewMode != 1 {
+		t.Error("Expected Esc to return to the Changes tab")
+	}
+}
+
+func TestModel_ChangesFilter_HideShortened(t *testing.T) {
+	model := changesModel()
+
+	// Move the filter cursor to "shortened" and toggle it off
+	m := pressKey(t, model, "f")
+	m = pressKey(t, m, "f")
+	m = pressKey(t, m, " ")
+	if !m.hiddenChangeTypes["shortened"] {
+		t.Fatal("Expected shortened changes to be hidden")
+	}
+
+	view := m.renderChanges()
+	if strings.Contains(view, "[8.3]") {
+		t.Error("Shortened changes should be hidden")
+	}
+	if !strings.Contains(view, "[DUP]") || !strings.Contains(view, "[DEAD]") {
+		t.Error("Other change types should remain visible")
+	}
+	if !strings.Contains(view, "2 of 3 changes shown") {
+		t.Error("Expected filtered count in footer")
+	}
+
+	// Toggle back on
+	m = pressKey(t, m, " ")
+	if !strings.Contains(m.renderChanges(), "[8.3]") {
+		t.Error("Shortened changes should be visible again")
+	}
+}
+
+func TestModel_ChangesFilter_AllHidden(t *testing.T) {
+	m := changesModel()
+	for _, ct := range changeTypes {
+		m = m.toggleChangeType(ct.name)
+	}
+	if !strings.Contains(m.renderChanges(), "All 3 changes hidden by filter") {
+		t.Error("Expected all-hidden message")
+	}
+}
+
+func TestModel_ChangesFilter_OnlyOnChangesTab(t *testing.T) {
+	model := changesModel()
+	model.viewMode = 0
+
+	m := pressKey(t, model, " ")
+	if len(m.hiddenChangeTypes) != 0 {
+		t.Error("Space should not toggle filters outside the Changes tab")
+	}
+}
+
+// ============================================================================
+// Unresolved Variable Tests
+// ============================================================================
+
+func TestModel_Viewer_MarksUnresolvedVars(t *testing.T) {
+	withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetResponse("'Path', 'User'", `C:\Users\Test\bin;C:\Tools\%UNSET_TOOLS%\bin`)
+	})
+
+	model := New()
+	model.screen = ScreenPathViewer
+	model.viewerScope = "User"
+	model.viewerExpanded = true
+	model, _ = model.loadViewer()
+
+	view := model.viewPathViewer()
+	if !strings.Contains(view, "(unresolved: %UNSET_TOOLS%)") {
+		t.Errorf("Expected unset variable to be marked unresolved, got:\n%s", view)
+	}
+	if !strings.Contains(view, "1 with unresolved variables") {
+		t.Error("Expected unresolved count in footer")
+	}
+
+	// Raw view shows variables by design and should not flag them
+	model.viewerExpanded = false
+	if strings.Contains(model.viewPathViewer(), "unresolved") {
+		t.Error("Raw view should not mark variables as unresolved")
+	}
+}
+
+// ============================================================================
+// Viewer Copy Entry Tests
+// ============================================================================
+
+func TestModel_Viewer_CopySelectedEntry(t *testing.T) {
+	withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\First;C:\Second;C:\Third`)
+	})
+	clip, cleanup := path.SetDefaultClipboard()
+	defer cleanup()
+
+	model := New()
+	model.screen = ScreenPathViewer
+	model.viewerScope = "User"
+
+	m := pressKey(t, model, "down")
+	m = pressKey(t, m, "c")
+	if clip.Last() != `C:\Second` {
+		t.Errorf("Expected selected entry to be copied, got %q", clip.Last())
+	}
+	if !m.clipboardOK {
+		t.Error("Expected clipboardOK after copy")
+	}
+	if !strings.Contains(m.View(), "Copied entry to clipboard!") {
+		t.Error("Expected copy confirmation in view")
+	}
+	if !strings.Contains(m.viewPathViewer(), "> ") {
+		t.Error("Expected selected row marker")
+	}
+
+	// Moving the selection clears the confirmation
+	m = pressKey(t, m, "up")
+	if m.clipboardOK {
+		t.Error("clipboardOK should reset after moving selection")
+	}
+}
+
+// ============================================================================
+// Reorder-Only Confirm Tests
+// ============================================================================
+
+func TestModel_Apply_ReorderOnlyUsesLightConfirm(t *testing.T) {
+	model := New()
+	model.screen = ScreenOptimizerPreview
+	model.optimizerScope = "both"
+	model.isAdmin = true
+	model.analysis = &path.AnalysisResult{}
+	model.analysis.User.Changes = []path.PathChange{{Type: "reordered", Original: `C:\Git\bin`}}
+
+	m := pressKey(t, model, "a")
+	if m.screen != ScreenOptimizerConfirmReorder {
+		t.Fatalf("Expected light reorder confirm, got %d", m.screen)
+	}
+	if !strings.Contains(m.View(), "Reorder PATH?") {
+		t.Error("Expected reorder prompt in view")
+	}
+
+	m = pressKey(t, m, "enter")
+	if m.screen != ScreenLoading {
+		t.Errorf("Expected Enter to apply from reorder confirm, got %d", m.screen)
+	}
+}
+
+func TestModel_Apply_DeadRemovalUsesFullConfirm(t *testing.T) {
+	model := New()
+	model.screen = ScreenOptimizerPreview
+	model.optimizerScope = "both"
+	model.isAdmin = true
+	model.analysis = &path.AnalysisResult{}
+	model.analysis.User.Changes = []path.PathChange{{Type: "reordered", Original: `C:\Git\bin`}}
+	model.analysis.System.Changes = []path.PathChange{{Type: "dead", Original: `C:\Gone`}}
+
+	m := pressKey(t, model, "a")
+	if m.screen != ScreenOptimizerConfirm {
+		t.Errorf("Expected full confirm for dead path removal, got %d", m.screen)
+	}
+
+	// Limiting scope to the reorder-only side uses the light confirm
+	model.optimizerScope = "user"
+	m = pressKey(t, model, "a")
+	if m.screen != ScreenOptimizerConfirmReorder {
+		t.Errorf("Expected light confirm for user-only scope, got %d", m.screen)
+	}
+}
+
+// ============================================================================
+// Jump Navigation Tests
+// ============================================================================
+
+func TestJumpPosition(t *testing.T) {
+	tests := []struct {
+		key  string
+		pos  int
+		want int
+	}{
+		{"g", 7, 0},
+		{"G", 2, 20},
+		{"home", 7, 0},
+		{"end", 2, 20},
+		{"pgup", 7, 0},
+		{"pgup", 15, 5},
+		{"pgdown", 5, 15},
+		{"pgdown", 15, 20},
+	}
+	for _, tt := range tests {
+		if got := jumpPosition(tt.key, tt.pos, 20, 10); got != tt.want {
+			t.Errorf("jumpPosition(%q, %d) = %d, want %d", tt.key, tt.pos, got, tt.want)
+		}
+	}
+
+	if got := jumpPosition("G", 0, -1, 10); got != 0 {
+		t.Errorf("Empty list should stay at 0, got %d", got)
+	}
+}
+
+func TestModel_HandleViewerKey_Jump(t *testing.T) {
+	withMock(t, func(mock *path.MockShellRunner) {
+		var entries []string
+		for i := 0; i < 40; i++ {
+			entries = append(entries, fmt.Sprintf(`C:\Tools\t%d`, i))
+		}
+		mock.SetResponse("CurrentUser.OpenSubKey", strings.Join(entries, ";"))
+	})
+
+	model := New()
+	model.screen = ScreenPathViewer
+	model.viewerScope = "User"
+
+	m, _ := model.handleViewerKey("G")
+	if m.viewerIndex != 39 || m.scrollOffset != 40-viewerMaxVisible {
+		t.Errorf("G: expected index 39 and offset %d, got %d and %d", 40-viewerMaxVisible, m.viewerIndex, m.scrollOffset)
+	}
+	m, _ = m.handleViewerKey("g")
+	if m.viewerIndex != 0 || m.scrollOffset != 0 {
+		t.Errorf("g: expected index 0 and offset 0, got %d and %d", m.viewerIndex, m.scrollOffset)
+	}
+	m, _ = m.handleViewerKey("pgdown")
+	if m.viewerIndex != viewerMaxVisible {
+		t.Errorf("pgdown: expected index %d, got %d", viewerMaxVisible, m.viewerIndex)
+	}
+	m, _ = m.handleViewerKey("pgup")
+	if m.viewerIndex != 0 {
+		t.Errorf("pgup: expected index 0, got %d", m.viewerIndex)
+	}
+}
+
+func TestModel_HandleOptimizerKey_Jump(t *testing.T) {
+	model := New()
+	model.screen = ScreenOptimizerPreview
+	model.viewMode = 1
+	model.analysis = &path.AnalysisResult{}
+	for i := 0; i < 30; i++ {
+		model.analysis.User.Changes = append(model.analysis.User.Changes, path.PathChange{Type: "dead", Original: fmt.Sprintf(`C:\Gone%d`, i)})
+	}
+
+	m, _ := model.handleOptimizerKey("G")
+	if m.scrollOffset != 30-changesMaxVisible {
+		t.Errorf("G: expected offset %d, got %d", 30-changesMaxVisible, m.scrollOffset)
+	}
+	m, _ = m.handleOptimizerKey("g")
+	if m.scrollOffset != 0 {
+		t.Errorf("g: expected offset 0, got %d", m.scrollOffset)
+	}
+	m, _ = m.handleOptimizerKey("pgdown")
+	if m.changeIndex != changesMaxVisible {
+		t.Errorf("pgdown: expected selection %d, got %d", changesMaxVisible, m.changeIndex)
+	}
+	m, _ = m.handleOptimizerKey("pgup")
+	if m.scrollOffset != 0 {
+		t.Errorf("pgup: expected offset 0, got %d", m.scrollOffset)
+	}
+
+	// Summary tab has nothing to scroll
+	model.viewMode = 0
+	m, _ = model.handleOptimizerKey("G")
+	if m.scrollOffset != 0 {
+		t.Errorf("G on summary: expected offset 0, got %d", m.scrollOffset)
+	}
+}
+
+func TestModel_HandleBackupKey_Jump(t *testing.T) {
+	model := New()
+	model.screen = ScreenBackup
+	model.backups = make([]path.BackupInfo, 25)
+
+	m, _ := model.handleBackupKey("G")
+	if m.backupIndex != 24 {
+		t.Errorf("G: expected index 24, got %d", m.backupIndex)
+	}
+	m, _ = m.handleBackupKey("pgup")
+	if m.backupIndex != 24-defaultPageSize {
+		t.Errorf("pgup: expected index %d, got %d", 24-defaultPageSize, m.backupIndex)
+	}
+	m, _ = m.handleBackupKey("g")
+	if m.backupIndex != 0 {
+		t.Errorf("g: expected index 0, got %d", m.backupIndex)
+	}
+	m, _ = m.handleBackupKey("pgdown")
+	if m.backupIndex != defaultPageSize {
+		t.Errorf("pgdown: expected index %d, got %d", defaultPageSize, m.backupIndex)
+	}
+}
+
+func TestModel_HandleJunctionsKey_Jump(t *testing.T) {
+	model := New()
+	model.screen = ScreenJunctions
+	model.junctions = make([]path.Junction, 15)
+
+	m, _ := model.handleJunctionsKey("G")
+	if m.junctionIndex != 14 {
+		t.Errorf("G: expected index 14, got %d", m.junctionIndex)
+	}
+	m, _ = m.handleJunctionsKey("g")
+	if m.junctionIndex != 0 {
+		t.Errorf("g: expected index 0, got %d", m.junctionIndex)
+	}
+	m, _ = m.handleJunctionsKey("pgdown")
+	if m.junctionIndex != defaultPageSize {
+		t.Errorf("pgdown: expected index %d, got %d", defaultPageSize, m.junctionIndex)
+	}
+	m, _ = m.handleJunctionsKey("pgdown")
+	if m.junctionIndex != 14 {
+		t.Errorf("pgdown: expected index clamped to 14, got %d", m.junctionIndex)
+	}
+}
+
+func TestModel_HandleJunctionSuggestionsKey_Jump(t *testing.T) {
+	model := New()
+	model.screen = ScreenJunctionSuggestions
+	model.suggestions = make([]path.JunctionSuggestion, 30)
+
+	m, _ := model.handleJunctionSuggestionsKey("G")
+	if m.junctionIndex != 29 {
+		t.Errorf("G: expected index 29, got %d", m.junctionIndex)
+	}
+	m, _ = m.handleJunctionSuggestionsKey("pgup")
+	if m.junctionIndex != 29-suggestionsMaxVisible {
+		t.Errorf("pgup: expected index %d, got %d", 29-suggestionsMaxVisible, m.junctionIndex)
+	}
+	m, _ = m.handleJunctionSuggestionsKey("g")
+	if m.junctionIndex != 0 {
+		t.Errorf("g: expected index 0, got %d", m.junctionIndex)
+	}
+}
+
+// ============================================================================
+// Referencing Variables Tests
+// ============================================================================
+
+func TestModel_Viewer_FindReferencingVars(t *testing.T) {
+	t.Setenv("WINPATH_TUI_TOOLS", `C:\Tools\Bin`)
+	withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\Tools\Bin;C:\Nobody\Home`)
+	})
+
+	model := New()
+	model.screen = ScreenPathViewer
+	model.viewerScope = "User"
+
+	m := pressKey(t, model, "f")
+	if !strings.Contains(m.message, "WINPATH_TUI_TOOLS") {
+		t.Errorf("Expected referencing variable in message, got %q", m.message)
+	}
+	if !strings.Contains(m.viewPathViewer(), "Referenced by:") {
+		t.Error("Expected references in viewer")
+	}
+
+	m = pressKey(t, m, "down")
+	m = pressKey(t, m, "f")
+	if !strings.Contains(m.message, "No environment variables reference") {
+		t.Errorf("Expected no-reference message, got %q", m.message)
+	}
+}
+
+// ============================================================================
+// PATHEXT Insert Tests
+// ============================================================================
+
+func pathExtEditModel() Model {
+	model := New()
+	model.screen = ScreenPathExt
+	model.pathExtEditing = true
+	model.pathExtList = []string{".EXE", ".CMD", ".BAT"}
+	model.pathExtIndex = 1
+	model.pathExtAnalysis = &path.PathExtAnalysis{Current: []string{".EXE", ".CMD", ".BAT"}}
+	return model
+}
+
+func typeText(t *testing.T, model Model, text string) Model {
+	t.Helper()
+	for _, r := range text {
+		model = pressKey(t, model, string(r))
+	}
+	return model
+}
+
+func TestModel_PathExtInsert_Valid(t *testing.T) {
+	m := pressKey(t, pathExtEditModel(), "i")
+	if !m.pathExtAdding {
+		t.Fatal("Expected insert input to open")
+	}
+	m = typeText(t, m, ".ps1")
+	m = pressKey(t, m, "enter")
+
+	want := []string{".EXE", ".PS1", ".CMD", ".BAT"}
+	if strings.Join(m.pathExtList, ";") != strings.Join(want, ";") {
+		t.Errorf("Expected %v, got %v", want, m.pathExtList)
+	}
+	if m.pathExtAdding {
+		t.Error("Input should close after a valid insert")
+	}
+	if m.pathExtOpt == nil || !m.pathExtOpt.Changed || m.pathExtOpt.OptimizedString != ".EXE;.PS1;.CMD;.BAT" {
+		t.Errorf("Expected optimization to reflect insert, got %+v", m.pathExtOpt)
+	}
+}
+
+func TestModel_PathExtInsert_RejectsDuplicate(t *testing.T) {
+	m := pressKey(t, pathExtEditModel(), "+")
+	m = typeText(t, m, ".bat")
+	m = pressKey(t, m, "enter")
+
+	if len(m.pathExtList) != 3 {
+		t.Errorf("Duplicate should not be inserted, got %v", m.pathExtList)
+	}
+	if !m.pathExtAdding || m.err == nil {
+		t.Error("Expected input to stay open with an error")
+	}
+	if !strings.Contains(m.View(), "already in PATHEXT") {
+		t.Error("Expected duplicate error in view")
+	}
+}
+
+func TestModel_PathExtInsert_RejectsMissingDot(t *testing.T) {
+	m := pressKey(t, pathExtEditModel(), "i")
+	m = typeText(t, m, "PS1")
+	m = pressKey(t, m, "enter")
+
+	if len(m.pathExtList) != 3 {
+		t.Errorf("Extension without a dot should not be inserted, got %v", m.pathExtList)
+	}
+	if m.err == nil || !strings.Contains(m.message, "must start with a dot") {
+		t.Errorf("Expected missing-dot error, got %q", m.message)
+	}
+
+	// Empty input is rejected too
+	m = pressKey(t, pressKey(t, pressKey(t, m, "backspace"), "backspace"), "backspace")
+	m = pressKey(t, m, "enter")
+	if len(m.pathExtList) != 3 {
+		t.Error("Empty extension should not be inserted")
+	}
+}
+
+func TestModel_PathExtConfirm_ShowsDiff(t *testing.T) {
+	model := New()
+	model.screen = ScreenPathExtConfirm
+	model.pathExtAnalysis = &path.PathExtAnalysis{Current: []string{".PY", ".EXE", ".FOO"}}
+	model.pathExtOpt = &path.PathExtOptimization{
+		Original:        ".PY;.EXE;.FOO",
+		Optimized:       []string{".EXE", ".PY"},
+		OptimizedString: ".EXE;.PY",
+		Changed:         true,
+	}
+
+	view := model.View()
+	if !strings.Contains(view, ".PY;.EXE;.FOO") || !strings.Contains(view, ".EXE;.PY") {
+		t.Errorf("Expected both the original and optimized PATHEXT, got:\n%s", view)
+	}
+	if !strings.Contains(view, "- .FOO") || !strings.Contains(view, "~ .PY") {
+		t.Errorf("Expected removed and moved extensions to be marked, got:\n%s", view)
+	}
+}
+
+func TestModel_PathExtConfirm_StacksDiffWhenNarrow(t *testing.T) {
+	model := New()
+	model.pathExtAnalysis = &path.PathExtAnalysis{Current: []string{".PY", ".EXE"}}
+	model.pathExtOpt = &path.PathExtOptimization{Optimized: []string{".EXE", ".PY"}, OptimizedString: ".EXE;.PY"}
+
+	model.width = 120
+	for _, line := range strings.Split(model.viewPathExtDiff(), "\n") {
+		if strings.Contains(line, "Current") && !strings.Contains(line, "New") {
+			t.Error("Expected side-by-side columns on a wide terminal")
+		}
+	}
+
+	model.width = 40
+	for _, line := range strings.Split(model.viewPathExtDiff(), "\n") {
+		if strings.Contains(line, "Current") && strings.Contains(line, "New") {
+			t.Error("Expected stacked columns on a narrow terminal")
+		}
+	}
+}
+
+// ============================================================================
+// PATHEXT Reset Tests
+// ============================================================================
+
+func TestModel_PathExtReset_StagesDefault(t *testing.T) {
+	model := New()
+	model.screen = ScreenPathExt
+	model.pathExtAnalysis = &path.PathExtAnalysis{Current: []string{".EXE", ".PY"}}
+	suggested := &path.PathExtOptimization{OptimizedString: ".EXE;.PY", Changed: false}
+	model.pathExtOpt = suggested
+
+	m := pressKey(t, model, "r")
+	if m.pathExtOpt == nil || m.pathExtOpt.OptimizedString != path.DefaultPathExt {
+		t.Fatalf("Expected staged default PATHEXT, got %+v", m.pathExtOpt)
+	}
+	if !m.pathExtOpt.Changed {
+		t.Error("Expected staged reset to be marked changed")
+	}
+	if m.screen != ScreenPathExtConfirm {
+		t.Errorf("Expected confirm screen, got %d", m.screen)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Reset PATHEXT to Windows Default?") || !strings.Contains(view, path.DefaultPathExt) {
+		t.Error("Expected reset confirmation with default value")
+	}
+
+	// Cancelling restores the previous suggestion
+	m = pressKey(t, m, "n")
+	if m.pathExtOpt != suggested || m.pathExtReset {
+		t.Error("Cancel should restore the previous optimization")
+	}
+}
+
+// ============================================================================
+// Auto Analyze Tests
+// ============================================================================
+
+func TestModel_Init_AutoAnalyzeOnStart(t *testing.T) {
+	// No backups, so there is no external change check either
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+
+	original := path.LoadConfig()
+	defer func() { _ = path.SaveConfig(original) }()
+
+	config := original
+	config.AutoAnalyzeOnStart = true
+	if err := path.SaveConfig(config); err != nil {
+		t.Fatalf("SaveConfig error: %v", err)
+	}
+
+	model := New()
+	if model.screen != ScreenLoading || model.loadingTask != TaskAnalyze {
+		t.Errorf("Expected analysis loading screen on start, got screen %d", model.screen)
+	}
+	if model.Init() == nil {
+		t.Error("Expected Init to start analysis when AutoAnalyzeOnStart is set")
+	}
+
+	config.AutoAnalyzeOnStart = false
+	if err := path.SaveConfig(config); err != nil {
+		t.Fatalf("SaveConfig error: %v", err)
+	}
+	model = New()
+	if model.screen != ScreenMenu {
+		t.Errorf("Expected menu on start, got screen %d", model.screen)
+	}
+	if model.Init() != nil {
+		t.Error("Expected nil Init command when AutoAnalyzeOnStart is off")
+	}
+}
+
+func TestModel_Settings_ToggleAutoAnalyze(t *testing.T) {
+	original := path.LoadConfig()
+	defer func() { _ = path.SaveConfig(original) }()
+
+	model := New()
+	model.screen = ScreenSettings
+	model.settingsIndex = 2
+	before := model.config.AutoAnalyzeOnStart
+
+	m, _ := model.handleSettingsKey("enter")
+	if m.config.AutoAnalyzeOnStart == before {
+		t.Error("Expected Analyze on Start to toggle")
+	}
+	if path.LoadConfig().AutoAnalyzeOnStart != m.config.AutoAnalyzeOnStart {
+		t.Error("Expected toggle to be saved")
+	}
+}
+
+// ============================================================================
+// Backup Failure Tests
+// ============================================================================
+
+// withBrokenBackupDir points the config dir below a regular file so EnsureBackupDir fails
+func withBrokenBackupDir(t *testing.T) {
+	t.Helper()
+	original := filepath.Dir(path.GetConfigPath())
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+	path.SetConfigDir(filepath.Join(blocker, "config"))
+	t.Cleanup(func() { path.SetConfigDir(original) })
+
+	if err := path.EnsureBackupDir(); err == nil {
+		t.Fatal("Expected EnsureBackupDir to fail")
+	}
+}
+
+func TestApplyOptimizationCmd_BackupFailureAborts(t *testing.T) {
+	withBrokenBackupDir(t)
+	mock := withMock(t, nil)
+	before := len(mock.Calls)
+
+	analysis := &path.AnalysisResult{}
+	analysis.User.Optimized.Raw = `C:\Tools`
+
+	msg := applyOptimizationCmd(analysis, "user", false, false)().(applyCompleteMsg)
+	if msg.err == nil || !msg.backupFailed {
+		t.Fatal("Expected apply to abort when backup fails")
+	}
+	if !strings.Contains(msg.err.Error(), "backup failed, PATH unchanged") {
+		t.Errorf("Expected descriptive error, got %v", msg.err)
+	}
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable('Path'"); n != 0 {
+		t.Errorf("Expected no PATH writes, got %d", n)
+	}
+
+	// The model surfaces the error and offers the explicit override
+	model := New()
+	model.screen = ScreenLoading
+	model.analysis = analysis
+	updated, _ := model.Update(msg)
+	m := updated.(Model)
+	if m.screen != ScreenOptimizerPreview || !strings.HasPrefix(m.message, "Backup failed - aborting, PATH unchanged: ") {
+		t.Errorf("Expected error on preview screen, got screen %d message %q", m.screen, m.message)
+	}
+	m.screen = ScreenOptimizerConfirm
+	if !strings.Contains(m.View(), "Apply without backup") {
+		t.Error("Expected override option on confirm screen")
+	}
+}
+
+func TestApplyOptimizationCmd_ForceSkipsBackup(t *testing.T) {
+	withBrokenBackupDir(t)
+	mock := withMock(t, nil)
+	before := len(mock.Calls)
+
+	analysis := &path.AnalysisResult{}
+	analysis.User.Optimized.Raw = `C:\Tools`
+
+	msg := applyOptimizationCmd(analysis, "user", false, true)().(applyCompleteMsg)
+	if msg.err != nil {
+		t.Fatalf("Expected forced apply to succeed, got %v", msg.err)
+	}
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable('Path'"); n != 1 {
+		t.Errorf("Expected 1 PATH write, got %d", n)
+	}
+}
+
+func TestApplyOptimizationCmd_AppendsAuditLog(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+	withMock(t, nil)
+
+	analysis := &path.AnalysisResult{}
+	analysis.User.Optimized.Raw = `C:\Tools;C:\Bin`
+	msg := applyOptimizationCmd(analysis, "user", false, false)().(applyCompleteMsg)
+	if msg.err != nil {
+		t.Fatalf("Apply failed: %v", msg.err)
+	}
+
+	lines, err := path.ReadAuditLog()
+	if err != nil {
+		t.Fatalf("ReadAuditLog error: %v", err)
+	}
+	var backup, write bool
+	for _, line := range lines {
+		backup = backup || strings.Contains(line, path.AuditBackupCreate)
+		write = write || (strings.Contains(line, path.AuditSetPath) && strings.Contains(line, "User PATH, 2 entries"))
+	}
+	if !backup || !write {
+		t.Errorf("Expected the backup and the User PATH write in the audit log, got:\n%s", strings.Join(lines, "\n"))
+	}
+
+	model := New()
+	model.screen = ScreenMenu
+	model.menuIndex = 8
+	m, _ := model.handleMenuKey("enter")
+	if m.screen != ScreenAuditLog || len(m.auditLines) != len(lines) {
+		t.Fatalf("Expected the audit log screen with %d lines, got screen %d", len(lines), m.screen)
+	}
+	if !strings.Contains(m.View(), "User PATH, 2 entries") {
+		t.Error("Expected the audit lines in the view")
+	}
+}
+
+func TestApplyOptimizationCmd_NonAdminSkipsSystem(t *testing.T) {
+	mock := withMock(t, nil)
+	before := len(mock.Calls)
+
+	analysis := &path.AnalysisResult{}
+	analysis.User.Optimized.Raw = `C:\Tools`
+	analysis.System.Optimized.Raw = `C:\Windows`
+
+	msg := applyOptimizationCmd(analysis, "both", false, false)().(applyCompleteMsg)
+	if msg.err != nil {
+		t.Fatalf("Unexpected error: %v", msg.err)
+	}
+	if n := countCalls(mock.Calls[before:], "'Machine')"); n != 0 {
+		t.Errorf("Expected no System PATH write without admin, got %d", n)
+	}
+	if len(msg.written) != 1 || msg.written[0] != "User" {
+		t.Errorf("Expected only User written, got %v", msg.written)
+	}
+	if len(msg.skipped) != 1 || msg.skipped[0] != "System" {
+		t.Errorf("Expected System skipped, got %v", msg.skipped)
+	}
+
+	model := New()
+	model.screen = ScreenLoading
+	model.analysis = analysis
+	updated, _ := model.Update(msg)
+	m := updated.(Model)
+	view := m.View()
+	if !strings.Contains(view, "User applied; System skipped (needs admin)") {
+		t.Errorf("Expected skip summary on the done screen, got:\n%s", view)
+	}
+	if strings.Contains(view, "applied successfully") {
+		t.Error("Done screen should not claim a full success when System was skipped")
+	}
+}
+
+func TestApplyOptimizationCmd_AdminWritesBoth(t *testing.T) {
+	withMock(t, nil)
+
+	analysis := &path.AnalysisResult{}
+	msg := applyOptimizationCmd(analysis, "both", true, false)().(applyCompleteMsg)
+	if len(msg.written) != 2 || len(msg.skipped) != 0 {
+		t.Errorf("Expected both scopes written, got written %v skipped %v", msg.written, msg.skipped)
+	}
+
+	model := New()
+	model.screen = ScreenLoading
+	updated, _ := model.Update(msg)
+	if !strings.Contains(updated.(Model).View(), "PATH optimization applied successfully!") {
+		t.Error("Expected the success message when every scope was written")
+	}
+}
+
+func TestModel_Optimizer_SystemApplyNeedsAdmin(t *testing.T) {
+	model := New()
+	model.screen = ScreenOptimizerPreview
+	model.analysis = &path.AnalysisResult{}
+	model.isAdmin = false
+	model.optimizerScope = "system"
+
+	m := pressKey(t, model, "a")
+	if m.screen != ScreenOptimizerPreview {
+		t.Errorf("Expected apply to be refused, got screen %d", m.screen)
+	}
+	if !strings.Contains(m.message, "relaunch WinPath as administrator") {
+		t.Errorf("Expected elevation hint, got %q", m.message)
+	}
+
+	model.optimizerScope = "both"
+	m = pressKey(t, model, "a")
+	if m.screen != ScreenOptimizerPreview {
+		t.Errorf("Expected both to be refused without admin, got screen %d", m.screen)
+	}
+	if m.message != systemNeedsAdminMessage {
+		t.Errorf("Expected elevation hint for both, got %q", m.message)
+	}
+
+	// A confirm opened without admin rights refuses to write and says why
+	model.screen = ScreenOptimizerConfirm
+	if !strings.Contains(model.View(), "System PATH cannot be written") {
+		t.Error("Expected the confirm to explain that admin rights are needed")
+	}
+	m, cmd := model.handleOptimizerConfirmKey("y")
+	if cmd != nil || m.screen != ScreenOptimizerPreview || m.message != systemNeedsAdminMessage {
+		t.Errorf("Expected the confirm to refuse without admin, got screen %d message %q", m.screen, m.message)
+	}
+	model.screen = ScreenOptimizerPreview
+
+	model.isAdmin = true
+	model.optimizerScope = "system"
+	m = pressKey(t, model, "a")
+	if m.screen != ScreenOptimizerConfirm {
+		t.Errorf("Expected admin to reach the confirm, got screen %d", m.screen)
+	}
+	if strings.Contains(m.View(), "System PATH cannot be written") {
+		t.Error("No admin warning expected as admin")
+	}
+}
+
+func TestModel_RelaunchAsAdmin(t *testing.T) {
+	called := 0
+	var result error
+	oldRelaunch := relaunchAsAdmin
+	relaunchAsAdmin = func() error {
+		called++
+		return result
+	}
+	defer func() { relaunchAsAdmin = oldRelaunch }()
+
+	model := New()
+	model.screen = ScreenOptimizerPreview
+	model.analysis = &path.AnalysisResult{}
+	model.isAdmin = false
+	model.optimizerScope = "system"
+
+	m, cmd := model.handleOptimizerKey("e")
+	if cmd == nil {
+		t.Fatal("Expected a relaunch command")
+	}
+	msg := cmd()
+	if called != 1 {
+		t.Fatalf("Expected one relaunch attempt, got %d", called)
+	}
+	updated, quit := m.Update(msg)
+	if quit == nil {
+		t.Fatal("Expected WinPath to quit once the elevated copy started")
+	}
+	if _, ok := quit().(tea.QuitMsg); !ok {
+		t.Error("Expected tea.Quit after a successful relaunch")
+	}
+
+	result = path.ErrElevationCancelled
+	updated, quit = updated.(Model).Update(relaunchMsg{err: relaunchAsAdmin()})
+	m = updated.(Model)
+	if quit != nil || m.message != "Elevation cancelled, still running without admin rights" {
+		t.Errorf("Expected a cancelled UAC prompt to keep running, got %q", m.message)
+	}
+
+	updated, _ = m.Update(relaunchMsg{err: fmt.Errorf("relaunch failed: boom")})
+	m = updated.(Model)
+	if m.err == nil || !strings.Contains(m.message, "Could not relaunch as admin") {
+		t.Errorf("Expected relaunch failure to be reported, got %q", m.message)
+	}
+
+	model.isAdmin = true
+	if _, cmd := model.handleOptimizerKey("e"); cmd != nil {
+		t.Error("No relaunch expected when already elevated")
+	}
+}
+
+func TestModel_OptimizerDone_RelaunchOnlyWhenSkipped(t *testing.T) {
+	model := New()
+	model.screen = ScreenOptimizerDone
+	model.analysis = &path.AnalysisResult{}
+	model.isAdmin = false
+
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}}); cmd != nil {
+		t.Error("E should do nothing when no scope was skipped")
+	}
+	model.appliedScopes = []string{"User"}
+	model.skippedScopes = []string{"System"}
+	if !strings.Contains(model.View(), "Relaunch as admin") {
+		t.Error("Expected relaunch hint on the done screen")
+	}
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}}); cmd == nil {
+		t.Error("Expected E to relaunch after System was skipped")
+	}
+}
+
+func TestModel_Loading_CancelHungCommand(t *testing.T) {
+	withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetBlocking("LocalMachine.OpenSubKey")
+	})
+
+	model := New()
+	model.screen = ScreenOptimizer
+	model, cmd := model.startAnalysis()
+	if model.screen != ScreenLoading {
+		t.Fatalf("Expected loading screen, got %d", model.screen)
+	}
+	if !strings.Contains(model.View(), "Cancel") {
+		t.Error("Expected a cancel hint on the loading screen")
+	}
+
+	// The first command of the batch is the analysis, the second the spinner tick
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) == 0 {
+		t.Fatal("Expected a batched loading command")
+	}
+	result := make(chan tea.Msg, 1)
+	go func() { result <- batch[0]() }()
+
+	select {
+	case <-result:
+		t.Fatal("Analysis finished although PowerShell was hung")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	updated, quit := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m := updated.(Model)
+	if quit != nil {
+		t.Error("Esc while loading should not quit")
+	}
+	if m.screen != ScreenOptimizer || m.err == nil || m.message != "Cancelled: Analyzing PATH" {
+		t.Errorf("Expected to return to the optimizer with an error, got screen %d message %q", m.screen, m.message)
+	}
+
+	var stale tea.Msg
+	select {
+	case stale = <-result:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Cancelling did not unblock the hung command")
+	}
+	updated, _ = m.Update(stale)
+	m = updated.(Model)
+	if m.screen != ScreenOptimizer || m.analysis != nil {
+		t.Error("The cancelled analysis result should be dropped")
+	}
+}
+
+func TestModel_Loading_WritesCannotBeCancelled(t *testing.T) {
+	model := New()
+	model.screen = ScreenOptimizerConfirm
+	model, _ = model.startWriting(TaskAnalyze, "Applying optimization", func() tea.Msg { return nil })
+	id := model.operationID
+
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyEsc}, {Type: tea.KeyCtrlC}} {
+		updated, cmd := model.Update(key)
+		model = updated.(Model)
+		if cmd != nil || model.screen != ScreenLoading || model.operationID != id {
+			t.Fatalf("%s should not cancel or quit a write in progress", key)
+		}
+	}
+	if strings.Contains(model.View(), "Cancel") {
+		t.Error("Expected no cancel hint while writing")
+	}
+
+	updated, _ := model.Update(operationMsg{id: id, msg: applyCompleteMsg{written: []string{"User"}}})
+	model = updated.(Model)
+	if model.screen != ScreenOptimizerDone || model.loadingWrite {
+		t.Errorf("Expected the write's result to be reported, got screen %d", model.screen)
+	}
+}
+
+func TestModel_Loading_CtrlCCancelsInsteadOfQuitting(t *testing.T) {
+	model := New()
+	model.screen = ScreenJunctions
+	model, _ = model.startLoading(TaskJunctions, "Loading junctions", func() tea.Msg { return nil })
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	m := updated.(Model)
+	if cmd != nil {
+		t.Error("Ctrl+C while loading should cancel, not quit")
+	}
+	if m.screen != ScreenJunctions {
+		t.Errorf("Expected to return to the junctions screen, got %d", m.screen)
+	}
+
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC}); cmd == nil {
+		t.Error("Ctrl+C outside loading should still quit")
+	}
+}
+
+func TestModel_Loading_CompletedOperationIsDelivered(t *testing.T) {
+	model := New()
+	model.screen = ScreenOptimizer
+	model, cmd := model.startLoading(TaskAnalyze, "Analyzing PATH", func() tea.Msg {
+		return analysisCompleteMsg{}
+	})
+	msg := cmd().(tea.BatchMsg)[0]()
+	updated, _ := model.Update(msg)
+	m := updated.(Model)
+	if m.screen != ScreenOptimizerPreview || m.analysis == nil {
+		t.Errorf("Expected the analysis to be shown, got screen %d", m.screen)
+	}
+	if m.cancelOperation != nil {
+		t.Error("Expected the finished operation to be released")
+	}
+}
+
+func TestModel_Loading_TimeoutReturnsWithError(t *testing.T) {
+	withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetError("CurrentUser.OpenSubKey", fmt.Errorf("PowerShell did not respond within 30s: %w", context.DeadlineExceeded))
+	})
+
+	tests := []struct {
+		name  string
+		from  Screen
+		start func(Model) (Model, tea.Cmd)
+		want  string
+	}{
+		{"analysis", ScreenOptimizerPreview, Model.startAnalysis, "Analysis failed"},
+		{"suggestions", ScreenJunctions, func(m Model) (Model, tea.Cmd) {
+			return m.startLoading(TaskSuggestions, "Refreshing suggestions", loadSuggestionsCmd())
+		}, "Loading suggestions failed"},
+		{"auto-fix", ScreenMenu, func(m Model) (Model, tea.Cmd) {
+			return m.startLoading(TaskAutoFix, "Checking recommended fixes", autoFixPlanCmd())
+		}, "Checking recommended fixes failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := New()
+			model.screen = tt.from
+			model, cmd := tt.start(model)
+			updated, _ := model.Update(cmd().(tea.BatchMsg)[0]())
+			m := updated.(Model)
+			if m.screen != tt.from || m.err == nil {
+				t.Fatalf("Expected to return to screen %d with an error, got %d", tt.from, m.screen)
+			}
+			if !strings.Contains(m.message, tt.want) || !strings.Contains(m.message, "did not respond") {
+				t.Errorf("Expected the timeout in the message, got %q", m.message)
+			}
+			if m.analysis != nil || m.suggestions != nil || m.autoFixAnalysis != nil {
+				t.Error("A failed read should not produce a result")
+			}
+		})
+	}
+}
+
+func TestModel_OptimizerConfirm_ForceRequiresFailedBackup(t *testing.T) {
+	model := New()
+	model.screen = ScreenOptimizerConfirm
+	model.analysis = &path.AnalysisResult{}
+	model.optimizerScope = "user"
+
+	m, cmd := model.handleOptimizerConfirmKey("!")
+	if cmd != nil || m.screen != ScreenOptimizerConfirm {
+		t.Error("'!' should do nothing unless the last backup failed")
+	}
+
+	model.backupFailed = true
+	m, cmd = model.handleOptimizerConfirmKey("!")
+	if cmd == nil || m.screen != ScreenLoading {
+		t.Error("'!' should apply without backup after a failed backup")
+	}
+}
+
+// ============================================================================
+// Junction Name Validation Tests
+// ============================================================================
+
+func TestModel_JunctionCreate_RejectsInvalidName(t *testing.T) {
+	mock := withMock(t, nil)
+	before := len(mock.Calls)
+
+	model := New()
+	model.screen = ScreenJunctionCreate
+	model.junctionName = "bad:name"
+
+	m := model.handleJunctionCreateEnter()
+	if m.junctionInputMode != 0 {
+		t.Error("Should stay on the name field when the name is invalid")
+	}
+	if m.err == nil || !strings.Contains(m.View(), "invalid character") {
+		t.Errorf("Expected friendly invalid-name message, got %q", m.message)
+	}
+
+	// Invalid name is caught even after moving to the target field
+	m.junctionInputMode = 1
+	m.junctionName = "waytoolongjunctionname"
+	m.junctionTarget = `C:\Windows`
+	m = m.handleJunctionCreateEnter()
+	if m.screen != ScreenJunctionCreate || !strings.Contains(m.message, "too long") {
+		t.Errorf("Expected too-long message, got %q", m.message)
+	}
+	if n := countCalls(mock.Calls[before:], "mklink"); n != 0 {
+		t.Errorf("Expected no mklink calls, got %d", n)
+	}
+
+	// A valid name advances to the target field
+	model.junctionName = "tools"
+	m = model.handleJunctionCreateEnter()
+	if m.junctionInputMode != 1 || m.err != nil {
+		t.Error("Valid name should advance to target")
+	}
+}
+
+// ============================================================================
+// Batch Junction Creation Tests
+// ============================================================================
+
+func TestCreateAllJunctionsCmd_CreatesEverySuggestion(t *testing.T) {
+	original := path.LoadConfig()
+	defer func() { _ = path.SaveConfig(original) }()
+	config := original
+	config.JunctionFolder = filepath.Join(t.TempDir(), "junctions")
+	if err := path.SaveConfig(config); err != nil {
+		t.Fatalf("SaveConfig error: %v", err)
+	}
+
+	targets := t.TempDir()
+	var suggestions []path.JunctionSuggestion
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		dir := filepath.Join(targets, name)
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatalf("Mkdir error: %v", err)
+		}
+		suggestions = append(suggestions, path.JunctionSuggestion{SuggestedName: name, OriginalPath: dir})
+	}
+	// One suggestion points at a missing target and should fail
+	suggestions = append(suggestions, path.JunctionSuggestion{SuggestedName: "missing", OriginalPath: filepath.Join(targets, "nope")})
+
+	mock := withMock(t, nil)
+	before := len(mock.Calls)
+
+	msg := createAllJunctionsCmd(suggestions)().(junctionsBatchCreatedMsg)
+	if n := countCalls(mock.Calls[before:], "mklink /J"); n != 3 {
+		t.Errorf("Expected 3 mklink calls, got %d", n)
+	}
+	if msg.created != 3 || msg.total != 4 || len(msg.errs) != 1 {
+		t.Errorf("Expected 3 of 4 created with 1 error, got %+v", msg)
+	}
+
+	model := New()
+	model.screen = ScreenLoading
+	updated, cmd := model.Update(msg)
+	m := updated.(Model)
+	if m.message != "Created 3 of 4 (1 failed)" {
+		t.Errorf("Unexpected summary message: %q", m.message)
+	}
+	if m.err == nil {
+		t.Error("Expected partial failure to be reported as an error")
+	}
+	if cmd == nil || m.loadingTask != TaskSuggestions {
+		t.Error("Expected suggestions to be refreshed")
+	}
+}
+
+func TestModel_JunctionSuggestions_CreateAllKey(t *testing.T) {
+	model := New()
+	model.screen = ScreenJunctionSuggestions
+
+	m, cmd := model.handleJunctionSuggestionsKey("A")
+	if cmd != nil || m.screen != ScreenJunctionSuggestions {
+		t.Error("'A' should do nothing without suggestions")
+	}
+
+	model.suggestions = []path.JunctionSuggestion{{SuggestedName: "a"}, {SuggestedName: "b"}}
+	m, cmd = model.handleJunctionSuggestionsKey("A")
+	if cmd == nil || m.screen != ScreenLoading {
+		t.Error("'A' should start creating all suggestions")
+	}
+}
+
+// ============================================================================
+// Nested Entry Tests
+// ============================================================================
+
+func TestModel_Summary_ShowsNestedEntries(t *testing.T) {
+	model := New()
+	model.screen = ScreenOptimizerPreview
+	model.analysis = &path.AnalysisResult{
+		NestedEntries: []path.NestedEntry{{Parent: `C:\App`, Child: `C:\App\bin`}},
+	}
+
+	view := model.renderSummary()
+	if !strings.Contains(view, "Nested Entries") || !strings.Contains(view, `C:\App contains C:\App\bin`) {
+		t.Errorf("Expected nested entry in summary, got:\n%s", view)
+	}
+}
+
+func TestModel_Summary_ShowsUserVarsInSystem(t *testing.T) {
+	model := New()
+	model.screen = ScreenOptimizerPreview
+	model.analysis = &path.AnalysisResult{
+		UserVarsInSystem: []path.UserVarEntry{{Entry: `%USERPROFILE%\bin`, Variable: "USERPROFILE"}},
+	}
+
+	view := model.renderSummary()
+	if !strings.Contains(view, "Per-User Variables in System PATH") || !strings.Contains(view, `%USERPROFILE%\bin`) {
+		t.Errorf("Expected per-user variable finding in summary, got:\n%s", view)
+	}
+}
+
+func TestModel_Summary_ShowsMisdirectedVars(t *testing.T) {
+	model := New()
+	model.screen = ScreenOptimizerPreview
+	model.analysis = &path.AnalysisResult{
+		MisdirectedVars: []path.MisdirectedVar{{Name: "TOOLS_HOME", Entries: []string{`C:\Tools\bin`, `C:\Tools\sbin`}}},
+	}
+
+	view := model.renderSummary()
+	if !strings.Contains(view, "Possibly Misdirected PATH Additions") || !strings.Contains(view, "%TOOLS_HOME%") {
+		t.Errorf("Expected misdirected variable finding in summary, got:\n%s", view)
+	}
+}
+
+// ============================================================================
+// Copy Optimized PATH Tests
+// ============================================================================
+
+func TestModel_Optimizer_CopyOptimizedPath(t *testing.T) {
+	clip, cleanup := path.SetDefaultClipboard()
+	defer cleanup()
+
+	model := New()
+	model.screen = ScreenOptimizerPreview
+	model.analysis = &path.AnalysisResult{}
+	model.analysis.User.Optimized.Raw = `C:\Users\Test\bin`
+	model.analysis.System.Optimized.Raw = `C:\Windows;C:\Windows\System32`
+
+	tests := []struct {
+		scope string
+		want  string
+	}{
+		{"user", `C:\Users\Test\bin`},
+		{"system", `C:\Windows;C:\Windows\System32`},
+		{"both", `C:\Users\Test\bin`},
+	}
+	for _, tt := range tests {
+		model.optimizerScope = tt.scope
+		m := pressKey(t, model, "c")
+		if clip.Last() != tt.want {
+			t.Errorf("scope %s: expected %q copied, got %q", tt.scope, tt.want, clip.Last())
+		}
+		if !strings.Contains(m.message, "Copied optimized") {
+			t.Errorf("scope %s: expected confirmation, got %q", tt.scope, m.message)
+		}
+	}
+}
+
+func TestModel_Optimizer_YankOptimizedPath(t *testing.T) {
+	clip, cleanup := path.SetDefaultClipboard()
+	defer cleanup()
+
+	model := New()
+	model.analysis = &path.AnalysisResult{}
+	model.analysis.User.Optimized.Raw = `C:\Users\Test\bin`
+	model.analysis.System.Optimized.Raw = `C:\Windows;C:\Windows\System32`
+
+	tests := []struct {
+		scope string
+		want  string
+	}{
+		{"user", `C:\Users\Test\bin`},
+		{"system", `C:\Windows;C:\Windows\System32`},
+		{"both", "System PATH:\n" + `C:\Windows;C:\Windows\System32` + "\n\nUser PATH:\n" + `C:\Users\Test\bin`},
+	}
+	for _, screen := range []Screen{ScreenOptimizerPreview, ScreenOptimizerDone} {
+		for _, tt := range tests {
+			clip.Writes = nil
+			model.screen = screen
+			model.optimizerScope = tt.scope
+			m := pressKey(t, model, "y")
+			if clip.Last() != tt.want {
+				t.Errorf("screen %d scope %s: expected %q copied, got %q", screen, tt.scope, tt.want, clip.Last())
+			}
+			if !strings.HasPrefix(m.message, "Yanked full optimized") {
+				t.Errorf("screen %d scope %s: expected yank confirmation, got %q", screen, tt.scope, m.message)
+			}
+		}
+	}
+}
+
+func TestModel_Optimizer_YankFailure(t *testing.T) {
+	clip, cleanup := path.SetDefaultClipboard()
+	defer cleanup()
+	clip.Err = fmt.Errorf("clipboard busy")
+
+	model := New()
+	model.screen = ScreenOptimizerDone
+	model.optimizerScope = "user"
+	model.analysis = &path.AnalysisResult{}
+
+	m := pressKey(t, model, "y")
+	if m.err == nil || m.message != "Copy failed: clipboard busy" {
+		t.Errorf("Expected copy failure to be reported, got %q", m.message)
+	}
+	if !strings.Contains(m.View(), "Copy failed") {
+		t.Error("Expected failure message on the done screen")
+	}
+}
+
+// ============================================================================
+// Junction PATH Rewrite Tests
+// ============================================================================
+
+func TestModel_JunctionCreated_RewritesPath(t *testing.T) {
+	mock := withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\Windows;C:\Program Files\Long App\bin`)
+	})
+
+	model := New()
+	model.screen = ScreenLoading
+	updated, _ := model.Update(junctionCreatedMsg{
+		success:      true,
+		name:         "app",
+		target:       `C:\Program Files\Long App\bin`,
+		junctionPath: `C:\l\app`,
+		rewrite:      true,
+	})
+	m := updated.(Model)
+	if m.screen != ScreenJunctionRewriteConfirm {
+		t.Fatalf("Expected rewrite confirm, got screen %d", m.screen)
+	}
+	if len(m.junctionRewriteScopes) != 1 || m.junctionRewriteScopes[0] != "User" {
+		t.Errorf("Expected User scope, got %v", m.junctionRewriteScopes)
+	}
+	if !strings.Contains(m.View(), `C:\l\app`) {
+		t.Error("Expected junction path in confirm view")
+	}
+
+	before := len(mock.Calls)
+	m, cmd := m.handleJunctionRewriteKey("y")
+	if cmd == nil || m.screen != ScreenLoading {
+		t.Error("Expected suggestions refresh after rewrite")
+	}
+	var setCall string
+	for _, call := range mock.Calls[before:] {
+		if strings.Contains(call, "SetEnvironmentVariable('Path'") {
+			setCall = call
+		}
+	}
+	if !strings.Contains(setCall, `C:\Windows;C:\l\app`) {
+		t.Errorf("Expected PATH rewritten to junction, got %q", setCall)
+	}
+}
+
+func TestModel_JunctionCreated_NoRewriteWithoutFlag(t *testing.T) {
+	model := New()
+	model.screen = ScreenLoading
+	updated, _ := model.Update(junctionCreatedMsg{success: true, name: "app", target: `C:\Windows`})
+	if updated.(Model).screen == ScreenJunctionRewriteConfirm {
+		t.Error("Should not offer rewrite unless requested")
+	}
+}
+
+func TestModel_JunctionRewrite_Decline(t *testing.T) {
+	mock := withMock(t, nil)
+	before := len(mock.Calls)
+
+	model := New()
+	model.screen = ScreenJunctionRewriteConfirm
+	model.junctionRewriteOld = `C:\Program Files\Long App`
+	model.junctionRewriteNew = `C:\l\app`
+	model.junctionRewriteScopes = []string{"User"}
+
+	m, _ := model.handleJunctionRewriteKey("n")
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable('Path'"); n != 0 {
+		t.Errorf("Expected no PATH writes, got %d", n)
+	}
+	if m.junctionRewriteScopes != nil {
+		t.Error("Expected rewrite state to be cleared")
+	}
+}
+
+func TestModel_JunctionPrune(t *testing.T) {
+	mock := withMock(t, nil)
+
+	model := New()
+	model.screen = ScreenJunctions
+	model.junctions = []path.Junction{
+		{Name: "ok", Target: `C:\Windows`},
+		{Name: "gone", Target: `C:\Removed`, Broken: true},
+	}
+
+	m, _ := model.handleJunctionsKey("p")
+	if m.screen != ScreenJunctionPruneConfirm {
+		t.Fatalf("Expected prune confirm screen, got %v", m.screen)
+	}
+	if !strings.Contains(m.View(), "gone") {
+		t.Error("Confirm view should list the broken junction")
+	}
+
+	before := len(mock.Calls)
+	m, _ = m.handleJunctionPruneKey("y")
+	if m.screen != ScreenJunctions {
+		t.Errorf("Expected junctions screen, got %v", m.screen)
+	}
+	if n := countCalls(mock.Calls[before:], "rmdir"); n != 1 {
+		t.Errorf("Expected 1 rmdir call, got %d", n)
+	}
+	if !strings.Contains(m.message, "Pruned 1") {
+		t.Errorf("Unexpected message: %q", m.message)
+	}
+}
+
+func TestModel_JunctionPrune_NoneBroken(t *testing.T) {
+	model := New()
+	model.screen = ScreenJunctions
+	model.junctions = []path.Junction{{Name: "ok", Target: `C:\Windows`}}
+
+	m, _ := model.handleJunctionsKey("p")
+	if m.screen != ScreenJunctions {
+		t.Errorf("Expected to stay on junctions screen, got %v", m.screen)
+	}
+	if m.message != "No broken junctions" {
+		t.Errorf("Unexpected message: %q", m.message)
+	}
+}
+
+func TestModel_JunctionPrune_Decline(t *testing.T) {
+	mock := withMock(t, nil)
+	before := len(mock.Calls)
+
+	model := New()
+	model.screen = ScreenJunctionPruneConfirm
+	model.junctions = []path.Junction{{Name: "gone", Target: `C:\Removed`, Broken: true}}
+
+	m, _ := model.handleJunctionPruneKey("n")
+	if m.screen != ScreenJunctions {
+		t.Errorf("Expected junctions screen, got %v", m.screen)
+	}
+	if n := countCalls(mock.Calls[before:], "rmdir"); n != 0 {
+		t.Errorf("Expected no rmdir calls, got %d", n)
+	}
+}
+
+func TestModel_ViewJunctions_Broken(t *testing.T) {
+	model := New()
+	model.screen = ScreenJunctions
+	model.junctions = []path.Junction{{Name: "gone", Target: `C:\Removed`, Broken: true}}
+
+	view := model.viewJunctions()
+	if !strings.Contains(view, "!") || !strings.Contains(view, "Prune broken") {
+		t.Error("Broken junction should be marked and offer pruning")
+	}
+}
+
+func TestModel_HotPaths_UndoDelete(t *testing.T) {
+	original := path.LoadConfig()
+	defer func() { _ = path.SaveConfig(original) }()
+
+	model := New()
+	model.screen = ScreenHotPaths
+	model.config.HotPaths = []string{`C:\Path1`, `C:\Path2`, `C:\Path3`}
+	model.hotPathIndex = 1
+
+	m, _ := model.handleHotPathsKey("x")
+	if len(m.config.HotPaths) != 2 {
+		t.Fatalf("Expected 2 hot paths after delete, got %d", len(m.config.HotPaths))
+	}
+
+	m, _ = m.handleHotPathsKey("u")
+	want := []string{`C:\Path1`, `C:\Path2`, `C:\Path3`}
+	if strings.Join(m.config.HotPaths, ";") != strings.Join(want, ";") {
+		t.Errorf("Expected %v after undo, got %v", want, m.config.HotPaths)
+	}
+	if saved := path.LoadConfig().HotPaths; strings.Join(saved, ";") != strings.Join(want, ";") {
+		t.Errorf("Expected undo to be saved, got %v", saved)
+	}
+	if m.hotPathCanUndo {
+		t.Error("Undo should only be available once")
+	}
+}
+
+func TestModel_HotPaths_UndoMove(t *testing.T) {
+	original := path.LoadConfig()
+	defer func() { _ = path.SaveConfig(original) }()
+
+	model := New()
+	model.screen = ScreenHotPaths
+	model.config.HotPaths = []string{`C:\Path1`, `C:\Path2`}
+	model.hotPathIndex = 1
+
+	m, _ := model.handleHotPathsKey("K")
+	m, _ = m.handleHotPathsKey("u")
+	if m.config.HotPaths[0] != `C:\Path1` || m.config.HotPaths[1] != `C:\Path2` {
+		t.Errorf("Expected original order after undo, got %v", m.config.HotPaths)
+	}
+}
+
+func TestModel_HotPaths_UndoClearedOnLeave(t *testing.T) {
+	model := New()
+	model.screen = ScreenHotPaths
+	model.hotPathUndo = []string{`C:\Path1`}
+	model.hotPathCanUndo = true
+
+	m, _ := model.handleHotPathsKey("esc")
+	if m.hotPathCanUndo || m.hotPathUndo != nil {
+		t.Error("Undo should be discarded when leaving the screen")
+	}
+}
+
+func TestModel_JunctionRename_OpensInput(t *testing.T) {
+	model := New()
+	model.screen = ScreenJunctions
+	model.junctions = []path.Junction{{Name: "old", Target: `C:\Tools`}}
+
+	m, _ := model.handleJunctionsKey("n")
+	if m.screen != ScreenJunctionCreate {
+		t.Fatalf("Expected create screen, got %v", m.screen)
+	}
+	if m.junctionRenaming != "old" || m.junctionName != "old" {
+		t.Errorf("Expected rename of 'old', got renaming=%q name=%q", m.junctionRenaming, m.junctionName)
+	}
+	if !strings.Contains(m.viewJunctionCreate(), "Rename Junction") {
+		t.Error("Expected rename title")
+	}
+
+	m, _ = m.handleJunctionCreateKey("tab")
+	if m.junctionInputMode != 0 {
+		t.Error("Target should not be editable when renaming")
+	}
+}
+
+func TestModel_JunctionRename_InvalidName(t *testing.T) {
+	model := New()
+	model.screen = ScreenJunctionCreate
+	model.junctionRenaming = "old"
+	model.junctionName = "bad:name"
+
+	m, _ := model.handleJunctionCreateKey("enter")
+	if m.screen != ScreenJunctionCreate || m.err == nil {
+		t.Error("Invalid name should keep the rename input open with an error")
+	}
+}
+
+func TestModel_JunctionRename_Escape(t *testing.T) {
+	model := New()
+	model.screen = ScreenJunctionCreate
+	model.junctionRenaming = "old"
+
+	m, _ := model.handleJunctionCreateKey("esc")
+	if m.screen != ScreenJunctions || m.junctionRenaming != "" {
+		t.Error("Escape should cancel the rename")
+	}
+}
+
+func TestModel_Settings_JunctionThresholds(t *testing.T) {
+	original := path.LoadConfig()
+	defer func() { _ = path.SaveConfig(original) }()
+
+	model := New()
+	model.screen = ScreenSettings
+	model.settingsIndex = 4
+	savings := model.config.JunctionMinSavings
+
+	m, _ := model.handleSettingsKey("+")
+	if m.config.JunctionMinSavings != savings+1 {
+		t.Errorf("Expected min savings %d, got %d", savings+1, m.config.JunctionMinSavings)
+	}
+
+	m.settingsIndex = 5
+	length := m.config.JunctionMinPathLength
+	m, _ = m.handleSettingsKey("-")
+	if m.config.JunctionMinPathLength != length-5 {
+		t.Errorf("Expected min path length %d, got %d", length-5, m.config.JunctionMinPathLength)
+	}
+
+	saved := path.LoadConfig()
+	if saved.JunctionMinSavings != m.config.JunctionMinSavings || saved.JunctionMinPathLength != m.config.JunctionMinPathLength {
+		t.Error("Expected thresholds to be saved")
+	}
+	if !strings.Contains(m.viewSettings(), "Junction Min Savings") {
+		t.Error("Settings view should list the junction thresholds")
+	}
+}
+
+// ============================================================================
+// Backup Compare Tests
+// ============================================================================
+
+// writeTestBackup writes a backup file with the given entries
+func writeTestBackup(t *testing.T, filename string, system, user []string) {
+	t.Helper()
+	var b path.Backup
+	b.SystemPath.Entries = system
+	b.UserPath.Entries = user
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := path.EnsureBackupDir(); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(path.GetBackupDir(), filename)
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newBackupCompareModel(t *testing.T) Model {
+	t.Helper()
+	original := filepath.Dir(path.GetConfigPath())
+	path.SetConfigDir(t.TempDir())
+	t.Cleanup(func() { path.SetConfigDir(original) })
+
+	writeTestBackup(t, "path_20240101_120000_old.json", []string{`C:\Windows`, `C:\Removed`}, []string{`C:\Users\Test\bin`})
+	writeTestBackup(t, "path_20240102_120000_new.json", []string{`C:\Windows`, `C:\Go\bin`}, []string{`C:\Users\Test\bin`})
+
+	model := New()
+	model.screen = ScreenBackup
+	model.backups = path.ListBackups()
+	if len(model.backups) != 2 {
+		t.Fatalf("Expected 2 backups, got %d", len(model.backups))
+	}
+	return model
+}
+
+func TestModel_BackupCompare_Mark(t *testing.T) {
+	model := newBackupCompareModel(t)
+
+	m, _ := model.handleBackupKey("m")
+	if m.compareIndex != 0 {
+		t.Fatalf("Expected backup 0 marked, got %d", m.compareIndex)
+	}
+	if !strings.Contains(m.viewBackup(), "[compare]") {
+		t.Error("Marked backup should be shown in the list")
+	}
+}
+
+func TestModel_BackupCompare_ClearMark(t *testing.T) {
+	model := newBackupCompareModel(t)
+
+	m, _ := model.handleBackupKey("m")
+	m, _ = m.handleBackupKey("m")
+	if m.compareIndex != -1 {
+		t.Errorf("Pressing M on the marked backup should clear the mark, got %d", m.compareIndex)
+	}
+	if m.screen != ScreenBackup {
+		t.Errorf("Expected to stay on backup screen, got %v", m.screen)
+	}
+
+	m, _ = m.handleBackupKey("m")
+	m, _ = m.handleBackupKey("esc")
+	if m.compareIndex != -1 {
+		t.Error("Leaving the backup screen should clear the mark")
+	}
+}
+
+func TestModel_BackupCompare_Diff(t *testing.T) {
+	model := newBackupCompareModel(t)
+
+	// Mark the newest backup, then compare with the older one
+	m, _ := model.handleBackupKey("m")
+	m, _ = m.handleBackupKey("down")
+	m, _ = m.handleBackupKey("m")
+
+	if m.screen != ScreenBackupDiff || m.backupDiff == nil {
+		t.Fatalf("Expected diff screen, got %v", m.screen)
+	}
+	if m.compareIndex != -1 {
+		t.Error("Mark should be cleared once the diff opens")
+	}
+	if len(m.backupDiff.system.Added) != 1 || m.backupDiff.system.Added[0] != `C:\Go\bin` {
+		t.Errorf("Expected C:\\Go\\bin added (older to newer), got %+v", m.backupDiff.system)
+	}
+	if len(m.backupDiff.system.Removed) != 1 || m.backupDiff.system.Removed[0] != `C:\Removed` {
+		t.Errorf("Expected C:\\Removed removed, got %+v", m.backupDiff.system)
+	}
+	if !m.backupDiff.user.Empty() {
+		t.Errorf("Expected no user changes, got %+v", m.backupDiff.user)
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "+ C:\\Go\\bin") || !strings.Contains(view, "- C:\\Removed") {
+		t.Errorf("Diff view missing entries:\n%s", view)
+	}
+
+	m, _ = m.handleBackupDiffKey("esc")
+	if m.screen != ScreenBackup || m.backupDiff != nil {
+		t.Error("Esc should return to the backup list")
+	}
+}
+
+func TestModel_BackupPreview_ExportReg(t *testing.T) {
+	original := filepath.Dir(path.GetConfigPath())
+	path.SetConfigDir(t.TempDir())
+	t.Cleanup(func() { path.SetConfigDir(original) })
+
+	backup := &path.Backup{}
+	backup.UserPath.Entries = []string{`C:\Tools`}
+
+	model := New()
+	model.screen = ScreenBackupPreview
+	model.backupPreview = backup
+
+	m, _ := model.handleBackupPreviewKey("x")
+	if m.err != nil {
+		t.Fatalf("Export failed: %v", m.err)
+	}
+	file := strings.TrimPrefix(m.message, "Exported to ")
+	if filepath.Ext(file) != ".reg" {
+		t.Fatalf("Expected a .reg file, got message %q", m.message)
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Errorf("Expected exported file to exist: %v", err)
+	}
+	if !strings.Contains(m.viewBackupPreview(), "Exported to") {
+		t.Error("Preview should show the export result")
+	}
+}
+
+func TestModel_JunctionSuggestions_Projection(t *testing.T) {
+	model := New()
+	updated, _ := model.Update(suggestionsLoadedMsg{
+		suggestions: []path.JunctionSuggestion{
+			{OriginalPath: `C:\Program Files\Vendor Suite`, SuggestedName: "vs", SavedChars: 22},
+			{OriginalPath: `C:\Program Files\Other Suite`, SuggestedName: "os", SavedChars: 21},
+		},
+		projection: []int{200, 160, 130},
+	})
+	m := updated.(Model)
+
+	if !strings.Contains(m.viewJunctionSuggestions(), "Top 1 applied: ") || !strings.Contains(m.viewJunctionSuggestions(), "200 -> 160 chars") {
+		t.Errorf("Expected projection for the first suggestion:\n%s", m.viewJunctionSuggestions())
+	}
+
+	m, _ = m.handleJunctionSuggestionsKey("down")
+	view := m.viewJunctionSuggestions()
+	if !strings.Contains(view, "Top 2 applied: ") || !strings.Contains(view, "200 -> 130 chars") || !strings.Contains(view, "(-70)") {
+		t.Errorf("Expected projection to follow the selection:\n%s", view)
+	}
+}
+
+func TestModel_JunctionSuggestions_TotalSavings(t *testing.T) {
+	model := New()
+	model.screen = ScreenJunctionSuggestions
+	if strings.Contains(model.viewJunctionSuggestions(), "Total potential savings") {
+		t.Error("No total should be shown without suggestions")
+	}
+
+	model.suggestions = []path.JunctionSuggestion{
+		{OriginalPath: `C:\Program Files\Vendor Suite`, SuggestedName: "vs", SavedChars: 22},
+		{OriginalPath: `C:\Program Files\Other Suite`, SuggestedName: "os", SavedChars: 21},
+	}
+	want := fmt.Sprintf("Total potential savings: %d chars", path.CalculateJunctionSavings(model.suggestions))
+	if !strings.Contains(model.viewJunctionSuggestions(), want) {
+		t.Errorf("Expected %q:\n%s", want, model.viewJunctionSuggestions())
+	}
+}
+
+func TestModel_JunctionSuggestions_NoProjection(t *testing.T) {
+	model := New()
+	model.screen = ScreenJunctionSuggestions
+	model.suggestions = []path.JunctionSuggestion{{OriginalPath: `C:\Program Files\Test`, SuggestedName: "test", SavedChars: 20}}
+
+	if strings.Contains(model.viewJunctionSuggestions(), "applied:") {
+		t.Error("No projection should be shown without projected lengths")
+	}
+}
+
+func TestModel_JunctionSuggestions_PreviewAll(t *testing.T) {
+	mock := withMock(t, nil)
+	suggestions := []path.JunctionSuggestion{
+		{OriginalPath: `C:\Program Files\Vendor Suite`, SuggestedName: "vs", JunctionPath: `C:\l\vs`, SavedChars: 22},
+	}
+	model := New()
+	updated, _ := model.Update(suggestionsLoadedMsg{
+		suggestions: suggestions,
+		projection:  []int{60, 38},
+		preview:     path.PreviewJunctionRewrites(`C:\Windows;C:\Program Files\Vendor Suite\bin`, `C:\Tools`, suggestions),
+	})
+	m := updated.(Model)
+	if !strings.Contains(m.viewJunctionSuggestions(), "Preview all") {
+		t.Error("Expected the preview key on the suggestions screen")
+	}
+
+	before := len(mock.Calls)
+	m, _ = m.handleJunctionSuggestionsKey("p")
+	if m.screen != ScreenJunctionPreview {
+		t.Fatalf("Expected the preview screen, got %d", m.screen)
+	}
+	view := m.View()
+	for _, want := range []string{"60 -> 38 chars", "(-22)", `~ C:\l\vs\bin`, `<- C:\Program Files\Vendor Suite\bin`, `C:\Tools`} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Preview missing %q:\n%s", want, view)
+		}
+	}
+	if len(mock.Calls) != before {
+		t.Errorf("Preview should not call the shell, got %v", mock.Calls[before:])
+	}
+
+	m = m.handleJunctionPreviewKey("esc")
+	if m.screen != ScreenJunctionSuggestions {
+		t.Errorf("Esc should return to the suggestions, got %d", m.screen)
+	}
+}
+
+func TestModel_JunctionSuggestions_Filter(t *testing.T) {
+	model := New()
+	model.screen = ScreenJunctionSuggestions
+	model.suggestions = []path.JunctionSuggestion{
+		{OriginalPath: `C:\Program Files\Vendor Suite\bin`, SuggestedName: "vs", SavedChars: 25},
+		{OriginalPath: `D:\Tools\Long Named Toolchain\bin`, SuggestedName: "ltc", SavedChars: 22},
+		{OriginalPath: `C:\Program Files\Other Suite\bin`, SuggestedName: "os", SavedChars: 21},
+	}
+
+	m, _ := model.handleJunctionSuggestionsKey("/")
+	if !m.inTextInput() {
+		t.Fatal("'/' should start typing the filter")
+	}
+	for _, k := range strings.Split(`c:\program`, "") {
+		m, _ = m.handleJunctionSuggestionsKey(k)
+	}
+	m, _ = m.handleJunctionSuggestionsKey("enter")
+
+	visible := m.visibleSuggestions()
+	if len(visible) != 2 || visible[0].SuggestedName != "vs" || visible[1].SuggestedName != "os" {
+		t.Fatalf("Expected the two Program Files suggestions, got %+v", visible)
+	}
+	view := m.viewJunctionSuggestions()
+	if !strings.Contains(view, "(2 of 3)") || !strings.Contains(view, "Total potential savings: 46 chars") {
+		t.Errorf("Expected the filtered count and savings:\n%s", view)
+	}
+	if strings.Contains(view, "ltc") {
+		t.Error("Filtered out suggestion should not be listed")
+	}
+
+	m, _ = m.handleJunctionSuggestionsKey("j")
+	if m.junctionIndex != 1 {
+		t.Errorf("Expected to move within the filtered list, got %d", m.junctionIndex)
+	}
+	m, _ = m.handleJunctionSuggestionsKey("j")
+	if m.junctionIndex != 1 {
+		t.Error("Selection should stop at the last filtered suggestion")
+	}
+
+	m, _ = m.handleJunctionSuggestionsKey("esc")
+	if m.screen != ScreenJunctionSuggestions || m.suggestionFilter != "" {
+		t.Fatalf("Esc should clear the filter first, got screen %d filter %q", m.screen, m.suggestionFilter)
+	}
+	if len(m.visibleSuggestions()) != 3 || !strings.Contains(m.viewJunctionSuggestions(), "(3)") || !strings.Contains(m.viewJunctionSuggestions(), "Total potential savings: 68 chars") {
+		t.Error("Clearing should list every suggestion again")
+	}
+
+	m, _ = m.handleJunctionSuggestionsKey("/")
+	m, _ = m.handleJunctionSuggestionsKey("z")
+	if !strings.Contains(m.viewJunctionSuggestions(), "No suggestions match") {
+		t.Error("Expected a no-match note")
+	}
+	m, _ = m.handleJunctionSuggestionsKey("esc")
+	if m.suggestionTyping || m.suggestionFilter != "" {
+		t.Error("Esc while typing should clear the filter")
+	}
+}
+
+func TestModel_JunctionExcludes_Editor(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+
+	model := New()
+	model.screen = ScreenJunctionSuggestions
+	model.suggestions = []path.JunctionSuggestion{{OriginalPath: `C:\Windows\System32\WindowsPowerShell\v1.0`, SuggestedName: "v10", SavedChars: 30}}
+
+	m, _ := model.handleJunctionSuggestionsKey("e")
+	if m.screen != ScreenJunctionExcludes {
+		t.Fatalf("Expected the exclusions editor, got %d", m.screen)
+	}
+	if !strings.Contains(m.View(), "No folders excluded") {
+		t.Error("Expected the empty list note")
+	}
+
+	m, _ = m.handleJunctionExcludesKey("a")
+	if !m.inTextInput() {
+		t.Fatal("'a' should start typing a folder")
+	}
+	for _, k := range strings.Split(`C:\Windows`, "") {
+		m, _ = m.handleJunctionExcludesKey(k)
+	}
+	m, _ = m.handleJunctionExcludesKey("enter")
+	if saved := path.LoadConfig().JunctionExcludePrefixes; len(saved) != 1 || saved[0] != `C:\Windows` {
+		t.Fatalf("Expected the folder saved to the config, got %v", saved)
+	}
+
+	m, _ = m.handleJunctionExcludesKey("a")
+	for _, k := range strings.Split(`c:\windows\`, "") {
+		m, _ = m.handleJunctionExcludesKey(k)
+	}
+	m, _ = m.handleJunctionExcludesKey("enter")
+	if len(m.config.JunctionExcludePrefixes) != 1 || !strings.Contains(m.View(), "already excluded") {
+		t.Error("The same folder should not be added twice")
+	}
+
+	m, cmd := m.handleJunctionExcludesKey("esc")
+	if m.screen != ScreenLoading || cmd == nil {
+		t.Errorf("Leaving after a change should refresh the suggestions, got screen %d", m.screen)
+	}
+
+	m.screen = ScreenJunctionExcludes
+	m, _ = m.handleJunctionExcludesKey("x")
+	if len(path.LoadConfig().JunctionExcludePrefixes) != 0 {
+		t.Error("Expected the folder removed from the config")
+	}
+}
+
+func TestModel_EnvVars_ViewAndFilter(t *testing.T) {
+	t.Setenv("WINPATH_TEST_TOOLS", `C:\Tools\bin;D:\More\bin`)
+	t.Setenv("WINPATH_TEST_NAME", "plain value")
+
+	model := New()
+	model.screen = ScreenMenu
+	model.menuIndex = 9
+	m, _ := model.handleMenuKey("enter")
+	if m.screen != ScreenEnvVars {
+		t.Fatalf("Expected the environment variables screen, got %d", m.screen)
+	}
+	for i := 1; i < len(m.envVars); i++ {
+		if strings.ToLower(m.envVars[i-1].name) > strings.ToLower(m.envVars[i].name) {
+			t.Fatalf("Variables not sorted: %s before %s", m.envVars[i-1].name, m.envVars[i].name)
+		}
+	}
+
+	m = m.handleEnvVarsKey("/")
+	if !m.inTextInput() {
+		t.Fatal("'/' should start typing the filter")
+	}
+	for _, k := range strings.Split("winpath_test", "") {
+		m = m.handleEnvVarsKey(k)
+	}
+	m = m.handleEnvVarsKey("enter")
+
+	visible := m.visibleEnvVars()
+	if len(visible) != 2 || visible[0].name != "WINPATH_TEST_NAME" || visible[1].name != "WINPATH_TEST_TOOLS" {
+		t.Fatalf("Expected the two test variables, got %+v", visible)
+	}
+	if visible[0].pathLike || !visible[1].pathLike {
+		t.Error("Only the folder list should be marked as PATH-like")
+	}
+	view := m.viewEnvVars()
+	if !strings.Contains(view, fmt.Sprintf("(2 of %d)", len(m.envVars))) || !strings.Contains(view, "plain value") {
+		t.Errorf("Expected the filtered list:\n%s", view)
+	}
+
+	m = m.handleEnvVarsKey("j")
+	m = m.handleEnvVarsKey("enter")
+	view = m.viewEnvVars()
+	ownLine := 0
+	for _, line := range strings.Split(view, "\n") {
+		if strings.Contains(line, `D:\More\bin`) && !strings.Contains(line, ";") {
+			ownLine++
+		}
+	}
+	if ownLine != 1 {
+		t.Errorf("Expected the expanded folder list one entry per line:\n%s", view)
+	}
+
+	m = m.handleEnvVarsKey("esc")
+	if m.screen != ScreenEnvVars || m.envFilter != "" || len(m.visibleEnvVars()) != len(m.envVars) {
+		t.Error("Esc should clear the filter first")
+	}
+	m = m.handleEnvVarsKey("esc")
+	if m.screen != ScreenMenu {
+		t.Errorf("Esc without a filter should return to the menu, got %d", m.screen)
+	}
+}
+
+func newAdvisoryModel() Model {
+	model := New()
+	model.config.AdvisoryMode = true
+	model.width = 80
+	model.height = 24
+	return model
+}
+
+func TestModel_Advisory_OptimizerApplyDisabled(t *testing.T) {
+	model := newAdvisoryModel()
+	model.screen = ScreenOptimizerPreview
+	model.analysis = &path.AnalysisResult{}
+
+	result, _ := model.handleOptimizerKey("a")
+	if result.screen != ScreenOptimizerPreview {
+		t.Errorf("Expected to stay on the preview, got screen %d", result.screen)
+	}
+	if result.message != advisoryMessage {
+		t.Errorf("Expected advisory message, got %q", result.message)
+	}
+	if strings.Contains(result.viewOptimizer(), "Apply") {
+		t.Error("Optimizer footer should not offer Apply in advisory mode")
+	}
+}
+
+func TestModel_Advisory_OptimizerConfirmDisabled(t *testing.T) {
+	mock := withMock(t, nil)
+	before := len(mock.Calls)
+
+	model := newAdvisoryModel()
+	model.screen = ScreenOptimizerConfirm
+	model.analysis = &path.AnalysisResult{}
+
+	for _, key := range []string{"y", "t", "!"} {
+		result, cmd := model.handleOptimizerConfirmKey(key)
+		if cmd != nil {
+			t.Errorf("Key %q: expected no command in advisory mode", key)
+		}
+		if result.screen != ScreenOptimizerPreview {
+			t.Errorf("Key %q: expected ScreenOptimizerPreview, got %d", key, result.screen)
+		}
+	}
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable"); n != 0 {
+		t.Errorf("Expected no environment writes, got %d", n)
+	}
+}
+
+func TestModel_Advisory_PathExtApplyDisabled(t *testing.T) {
+	mock := withMock(t, nil)
+	before := len(mock.Calls)
+
+	model := newAdvisoryModel()
+	model.screen = ScreenPathExt
+	model.pathExtAnalysis = &path.PathExtAnalysis{Current: []string{".COM", ".EXE"}}
+	model.pathExtOpt = &path.PathExtOptimization{Changed: true, OptimizedString: ".EXE;.COM"}
+
+	for _, key := range []string{"a", "r"} {
+		result := pressKey(t, model, key)
+		if result.screen != ScreenPathExt {
+			t.Errorf("Key %q: expected to stay on ScreenPathExt, got %d", key, result.screen)
+		}
+	}
+
+	model.pathExtEditing = true
+	model.pathExtList = []string{".EXE", ".COM"}
+	if result := pressKey(t, model, "a"); result.screen != ScreenPathExt {
+		t.Errorf("Edit mode apply: expected to stay on ScreenPathExt, got %d", result.screen)
+	}
+
+	model.pathExtEditing = false
+	model.screen = ScreenPathExtConfirm
+	if result := pressKey(t, model, "y"); result.screen != ScreenPathExt {
+		t.Errorf("Confirm: expected ScreenPathExt, got %d", result.screen)
+	}
+	if n := countCalls(mock.Calls[before:], "PATHEXT"); n != 0 {
+		t.Errorf("Expected no PATHEXT writes, got %d", n)
+	}
+}
+
+func TestModel_Advisory_BackupRestoreDisabled(t *testing.T) {
+	model := newAdvisoryModel()
+	model.screen = ScreenBackup
+	model.backups = []path.BackupInfo{{Filename: "backup_20260101_120000_manual.json"}}
+
+	result, _ := model.handleBackupKey("r")
+	if result.screen != ScreenBackup {
+		t.Errorf("Expected to stay on ScreenBackup, got %d", result.screen)
+	}
+	if result.message != advisoryMessage {
+		t.Errorf("Expected advisory message, got %q", result.message)
+	}
+
+	model.screen = ScreenBackupConfirmRestore
+	result, _ = model.handleBackupConfirmKey("y")
+	if result.screen != ScreenBackup {
+		t.Errorf("Expected confirm to return to ScreenBackup, got %d", result.screen)
+	}
+
+	// Viewing backups stays available
+	result, _ = model.handleBackupKey("v")
+	if strings.Contains(result.viewBackup(), "Restore") {
+		t.Error("Backup list should not offer Restore in advisory mode")
+	}
+}
+
+func TestModel_Advisory_JunctionChangesDisabled(t *testing.T) {
+	mock := withMock(t, nil)
+	before := len(mock.Calls)
+
+	model := newAdvisoryModel()
+	model.screen = ScreenJunctions
+	model.junctions = []path.Junction{{Name: "old", Target: `C:\Missing`, Broken: true}}
+	for _, key := range []string{"3", "d", "n", "p"} {
+		result, _ := model.handleJunctionsKey(key)
+		if result.screen != ScreenJunctions || result.message != advisoryMessage {
+			t.Errorf("Key %q: expected advisory message on ScreenJunctions, got screen %d %q", key, result.screen, result.message)
+		}
+	}
+
+	model.screen = ScreenJunctionSuggestions
+	model.suggestions = []path.JunctionSuggestion{{SuggestedName: "tools", OriginalPath: `C:\Long\Tools`}}
+	for _, key := range []string{"c", "w", "a"} {
+		if _, cmd := model.handleJunctionSuggestionsKey(key); cmd != nil {
+			t.Errorf("Key %q: expected no command in advisory mode", key)
+		}
+	}
+
+	model.screen = ScreenJunctionPruneConfirm
+	if result, _ := model.handleJunctionPruneKey("y"); result.message != advisoryMessage {
+		t.Errorf("Prune: expected advisory message, got %q", result.message)
+	}
+
+	model.screen = ScreenJunctionCreate
+	model.junctionName, model.junctionTarget, model.junctionInputMode = "tools", `C:\Tools`, 1
+	if result := model.handleJunctionCreateEnter(); result.message != advisoryMessage {
+		t.Errorf("Create: expected advisory message, got %q", result.message)
+	}
+	if n := countCalls(mock.Calls[before:], "mklink") + countCalls(mock.Calls[before:], "rmdir"); n != 0 {
+		t.Errorf("Expected no junction changes, got %d", n)
+	}
+}
+
+func TestModel_Advisory_BannerShown(t *testing.T) {
+	model := newAdvisoryModel()
+	model.screen = ScreenMenu
+
+	view := model.View()
+	if !strings.Contains(view, "ADVISORY MODE") {
+		t.Error("Expected advisory banner on the menu")
+	}
+	if !strings.Contains(view, "[Advisory]") {
+		t.Error("Expected advisory marker in the menu title")
+	}
+
+	model.screen = ScreenOptimizerPreview
+	model.analysis = &path.AnalysisResult{}
+	if !strings.Contains(model.View(), "ADVISORY MODE") {
+		t.Error("Expected advisory banner on the optimizer")
+	}
+}
+
+func TestModel_Advisory_BannerHiddenByDefault(t *testing.T) {
+	model := New()
+	model.config.AdvisoryMode = false
+	model.screen = ScreenMenu
+	if strings.Contains(model.View(), "ADVISORY MODE") {
+		t.Error("Advisory banner should not be shown when advisory mode is off")
+	}
+}
+
+func TestModel_RenderSummary_CaseConflicts(t *testing.T) {
+	model := New()
+	model.analysis = &path.AnalysisResult{
+		User: path.OptimizeResult{
+			CaseConflicts: []path.CaseConflict{{Entry: `C:\proj\Bin`, Variant: `C:\proj\bin`, Directory: `C:\proj\`}},
+		},
+	}
+
+	summary := model.renderSummary()
+	if !strings.Contains(summary, "Case-Sensitive Directories") {
+		t.Error("Expected case-sensitive directories box in summary")
+	}
+	if !strings.Contains(summary, `C:\proj\bin`) {
+		t.Error("Expected the kept variant to be listed")
+	}
+}
+
+// ============================================================================
+// Path Editor Tests
+// ============================================================================
+
+func newPathEditorModel(t *testing.T) Model {
+	t.Helper()
+	withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\First;C:\Second;C:\Third`)
+	})
+	model := New()
+	model.screen = ScreenPathViewer
+	model.viewerScope = "User"
+	m := pressKey(t, model, "E")
+	if m.screen != ScreenPathEditor {
+		t.Fatalf("Expected ScreenPathEditor, got %d", m.screen)
+	}
+	return m
+}
+
+func TestModel_PathEditor_Open(t *testing.T) {
+	m := newPathEditorModel(t)
+	if m.editorScope != "User" || len(m.editorEntries) != 3 {
+		t.Errorf("Expected 3 User entries, got %s %v", m.editorScope, m.editorEntries)
+	}
+	if !strings.Contains(m.View(), "Edit User PATH") {
+		t.Error("Expected editor title in view")
+	}
+}
+
+func TestModel_PathEditor_SystemNeedsAdmin(t *testing.T) {
+	model := New()
+	model.isAdmin = false
+	model.screen = ScreenPathViewer
+	model.viewerScope = "System"
+
+	m, _ := model.handleViewerKey("E")
+	if m.screen != ScreenPathViewer {
+		t.Error("Non-admin should not be able to edit System PATH")
+	}
+	if !strings.Contains(m.message, "administrator") {
+		t.Errorf("Expected admin message, got %q", m.message)
+	}
+}
+
+func TestModel_PathEditor_Reorder(t *testing.T) {
+	m := newPathEditorModel(t)
+
+	m = pressKey(t, m, "J")
+	if got := strings.Join(m.editorEntries, ";"); got != `C:\Second;C:\First;C:\Third` {
+		t.Errorf("After move down: %s", got)
+	}
+	if m.editorIndex != 1 {
+		t.Errorf("Expected selection to follow entry, got %d", m.editorIndex)
+	}
+	m = pressKey(t, m, "K")
+	if got := strings.Join(m.editorEntries, ";"); got != `C:\First;C:\Second;C:\Third` {
+		t.Errorf("After move up: %s", got)
+	}
+	m = pressKey(t, m, "K")
+	if m.editorIndex != 0 {
+		t.Error("Moving the first entry up should do nothing")
+	}
+	if m.editorChanged() {
+		t.Error("Moving back should leave the PATH unchanged")
+	}
+}
+
+func TestModel_PathEditor_Remove(t *testing.T) {
+	m := newPathEditorModel(t)
+	m = pressKey(t, m, "G")
+	m = pressKey(t, m, "x")
+
+	if got := strings.Join(m.editorEntries, ";"); got != `C:\First;C:\Second` {
+		t.Errorf("After remove: %s", got)
+	}
+	if m.editorIndex != 1 {
+		t.Errorf("Expected selection clamped to last entry, got %d", m.editorIndex)
+	}
+	if len(m.editorOriginal) != 3 {
+		t.Error("Removing should not change the original entries")
+	}
+}
+
+func TestModel_PathEditor_Add(t *testing.T) {
+	m := newPathEditorModel(t)
+	m = pressKey(t, m, "j")
+	m = pressKey(t, m, "i")
+	if !m.inTextInput() {
+		t.Fatal("Expected text input mode")
+	}
+	for _, r := range `C:\New` {
+		m = pressKey(t, m, string(r))
+	}
+	m = pressKey(t, m, "enter")
+
+	if got := strings.Join(m.editorEntries, ";"); got != `C:\First;C:\New;C:\Second;C:\Third` {
+		t.Errorf("After add: %s", got)
+	}
+	if m.editorAdding {
+		t.Error("Expected input to close after a valid entry")
+	}
+}
+
+func TestModel_PathEditor_AddRejectsDuplicate(t *testing.T) {
+	m := newPathEditorModel(t)
+	m = pressKey(t, m, "i")
+	m.editorInput = `c:\second\`
+	m = pressKey(t, m, "enter")
+
+	if len(m.editorEntries) != 3 {
+		t.Errorf("Duplicate should not be added, got %v", m.editorEntries)
+	}
+	if m.err == nil || !m.editorAdding {
+		t.Error("Expected an error with the input still open")
+	}
+}
+
+func TestModel_PathEditor_ApplyBacksUpAndWrites(t *testing.T) {
+	m := newPathEditorModel(t)
+	mock := path.DefaultRunner.(*path.MockShellRunner)
+
+	m = pressKey(t, m, "a")
+	if m.screen != ScreenPathEditor || m.message != "No changes to apply" {
+		t.Error("Unchanged PATH should not be offered for apply")
+	}
+
+	m = pressKey(t, m, "x")
+	m = pressKey(t, m, "a")
+	if m.screen != ScreenPathEditorConfirm {
+		t.Fatalf("Expected confirm screen, got %d", m.screen)
+	}
+	before := len(mock.Calls)
+	m = pressKey(t, m, "y")
+
+	if m.screen != ScreenPathViewer {
+		t.Errorf("Expected return to viewer, got %d", m.screen)
+	}
+	if !strings.Contains(m.message, "_pre-edit.json") {
+		t.Errorf("Expected a pre-edit backup to be reported, got %q", m.message)
+	}
+	writes := 0
+	for _, c := range mock.Calls[before:] {
+		if strings.Contains(c, "SetEnvironmentVariable('Path', 'C:\\Second;C:\\Third', 'User')") {
+			writes++
+		}
+	}
+	if writes != 1 {
+		t.Errorf("Expected the edited User PATH to be written once, got %d", writes)
+	}
+}
+
+func TestModel_PathEditor_EscDiscards(t *testing.T) {
+	mock := path.DefaultRunner.(*path.MockShellRunner)
+	m := newPathEditorModel(t)
+	before := len(mock.Calls)
+
+	m = pressKey(t, m, "x")
+	m = pressKey(t, m, "esc")
+	if m.screen != ScreenPathViewer {
+		t.Errorf("Expected viewer after esc, got %d", m.screen)
+	}
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable"); n != 0 {
+		t.Errorf("Expected no writes, got %d", n)
+	}
+}
+
+func TestModel_Viewer_LoadEntriesPreviewsInEditor(t *testing.T) {
+	withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\First;C:\Second;C:\Third`)
+	})
+	file := filepath.Join(t.TempDir(), "entries.txt")
+	os.WriteFile(file, []byte("# User PATH\n\nC:\\Second\nC:\\New\n"), 0644)
+
+	model := New()
+	model.screen = ScreenPathViewer
+	model.viewerScope = "User"
+	m := pressKey(t, model, "L")
+	if !m.inTextInput() {
+		t.Fatal("Expected a file path prompt")
+	}
+	m.viewerImportInput = `"` + file + `"`
+	m = pressKey(t, m, "enter")
+
+	if m.screen != ScreenPathEditor {
+		t.Fatalf("Expected the loaded entries in the editor, got screen %d (%s)", m.screen, m.message)
+	}
+	if got := strings.Join(m.editorEntries, ";"); got != `C:\Second;C:\New` {
+		t.Errorf("Expected the file's entries, got %s", got)
+	}
+	if len(m.editorOriginal) != 3 || !m.editorChanged() {
+		t.Error("Expected the current PATH kept as the original for the diff")
+	}
+	if !strings.Contains(m.message, "Loaded 2 entries") {
+		t.Errorf("Expected a load message, got %q", m.message)
+	}
+
+	m = pressKey(t, m, "a")
+	view := m.View()
+	if m.screen != ScreenPathEditorConfirm || !strings.Contains(view, "Entries: 3 -> 2") || !strings.Contains(view, "Added: 1  Removed: 2") {
+		t.Errorf("Expected a preview of the replacement before applying, got:\n%s", view)
+	}
+}
+
+func TestModel_Viewer_LoadEntriesRejectsInvalidFile(t *testing.T) {
+	withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\First`)
+	})
+	file := filepath.Join(t.TempDir(), "entries.txt")
+	os.WriteFile(file, []byte("C:\\Tools\nrelative\\bin\n"), 0644)
+
+	model := New()
+	model.screen = ScreenPathViewer
+	model.viewerScope = "User"
+	m := pressKey(t, model, "L")
+	m.viewerImportInput = file
+	m = pressKey(t, m, "enter")
+
+	if m.screen != ScreenPathViewer || m.viewerImporting {
+		t.Errorf("Expected to stay on the viewer, got screen %d", m.screen)
+	}
+	if !strings.HasPrefix(m.message, "Load failed: line 2:") {
+		t.Errorf("Expected the invalid line to be reported, got %q", m.message)
+	}
+}
+
+func TestModel_Viewer_LoadEntriesProcessScopeReadOnly(t *testing.T) {
+	model := New()
+	model.screen = ScreenPathViewer
+	model.viewerScope = "Process"
+	m := pressKey(t, model, "L")
+	if m.viewerImporting || !strings.Contains(m.message, "read-only") {
+		t.Errorf("Expected Process scope to refuse loading, got %q", m.message)
+	}
+}
+
+func TestModel_OptimizerConfirm_ShowsRegistryKeys(t *testing.T) {
+	model := New()
+	model.isAdmin = true
+	model.analysis = &path.AnalysisResult{}
+	model.analysis.System.Optimized.Raw = `C:\Windows;C:\Tools`
+	model.analysis.User.Optimized.Raw = `C:\Bin`
+	model.screen = ScreenOptimizerConfirm
+
+	model.optimizerScope = "system"
+	view := model.View()
+	if !strings.Contains(view, path.SystemPathKey+`\Path`) || !strings.Contains(view, "(19 chars)") {
+		t.Errorf("Expected the HKLM key and new length on the System confirm, got:\n%s", view)
+	}
+	if strings.Contains(view, path.UserPathKey) {
+		t.Error("System scope should not list the User key")
+	}
+
+	model.optimizerScope = "both"
+	view = model.View()
+	if !strings.Contains(view, path.SystemPathKey) || !strings.Contains(view, path.UserPathKey+`\Path`) {
+		t.Error("Expected both registry keys when both scopes are applied")
+	}
+
+	model.isAdmin = false
+	if strings.Contains(model.View(), "HKLM:") {
+		t.Error("System is skipped without admin, so its key should not be listed")
+	}
+}
+
+func TestModel_MaintenanceReminder_SystemConfirm(t *testing.T) {
+	model := New()
+	model.isAdmin = true
+	model.config.MaintenanceReminder = "Reminder: only apply during approved windows"
+	model.analysis = &path.AnalysisResult{}
+
+	model.screen = ScreenOptimizerConfirm
+	model.optimizerScope = "system"
+	if !strings.Contains(model.View(), "only apply during approved windows") {
+		t.Error("Expected reminder on the System apply confirm")
+	}
+	model.optimizerScope = "both"
+	if !strings.Contains(model.View(), "only apply during approved windows") {
+		t.Error("Expected reminder when both scopes are applied")
+	}
+
+	model.screen = ScreenOptimizerConfirmReorder
+	if !strings.Contains(model.View(), "only apply during approved windows") {
+		t.Error("Expected reminder on the reorder confirm")
+	}
+
+	model.screen = ScreenPathExtConfirm
+	model.pathExtOpt = &path.PathExtOptimization{OptimizedString: ".EXE;.CMD"}
+	if !strings.Contains(model.View(), "only apply during approved windows") {
+		t.Error("Expected reminder on the System PATHEXT confirm")
+	}
+
+	model.screen = ScreenPathEditorConfirm
+	model.editorScope = "System"
+	if !strings.Contains(model.View(), "only apply during approved windows") {
+		t.Error("Expected reminder on the System PATH editor confirm")
+	}
+
+	model.screen = ScreenBackupConfirmRestore
+	model.backups = []path.BackupInfo{{Filename: "path_20250101_120000_manual.json"}}
+	if !strings.Contains(model.View(), "only apply during approved windows") {
+		t.Error("Expected reminder on the restore confirm")
+	}
+}
+
+func TestModel_MaintenanceReminder_NotShownForUserOrUnset(t *testing.T) {
+	model := New()
+	model.isAdmin = true
+	model.config.MaintenanceReminder = "Reminder: only apply during approved windows"
+	model.screen = ScreenOptimizerConfirm
+	model.optimizerScope = "user"
+	if strings.Contains(model.View(), "approved windows") {
+		t.Error("Reminder should not be shown when only User PATH is written")
+	}
+
+	model.optimizerScope = "system"
+	model.isAdmin = false
+	if strings.Contains(model.View(), "approved windows") {
+		t.Error("Reminder should not be shown when System PATH is skipped without admin")
+	}
+
+	model.isAdmin = true
+	model.config.MaintenanceReminder = "   "
+	if strings.Contains(model.View(), "Reminder") {
+		t.Error("Blank reminder should not be shown")
+	}
+}
+
+func TestModel_Settings_CanonicalizePaths(t *testing.T) {
+	original := path.LoadConfig()
+	defer func() { _ = path.SaveConfig(original) }()
+
+	model := New()
+	model.screen = ScreenSettings
+	model.settingsIndex = 6
+	before := model.config.CanonicalizePaths
+
+	m, _ := model.handleSettingsKey("enter")
+	if m.config.CanonicalizePaths == before {
+		t.Error("Expected canonical casing to toggle")
+	}
+	if path.LoadConfig().CanonicalizePaths != m.config.CanonicalizePaths {
+		t.Error("Expected the setting to be saved")
+	}
+	if !strings.Contains(m.viewSettings(), "Canonical Casing") {
+		t.Error("Expected the setting in the view")
+	}
+}
+
+func TestModel_RenderChanges_Canonical(t *testing.T) {
+	model := changesModel()
+	model.analysis.User.Changes = append(model.analysis.User.Changes,
+		path.PathChange{Type: "canonical", Original: `c:\PROGRA~1\git\CMD`, New: `C:\Program Files\Git\cmd`})
+
+	view := model.renderChanges()
+	if !strings.Contains(view, "[CASE]") || !strings.Contains(view, `C:\Program Files\Git\cmd`) {
+		t.Error("Expected canonical change to be rendered")
+	}
+}
+
+func TestModel_ConfirmExactValue(t *testing.T) {
+	raw := strings.Repeat(`C:\Tools\Very Long Directory Name\bin;`, 40) + `C:\End`
+	model := New()
+	model.isAdmin = false
+	model.screen = ScreenOptimizerConfirm
+	model.optimizerScope = "user"
+	model.analysis = &path.AnalysisResult{}
+	model.analysis.User.Optimized.Raw = raw
+
+	if strings.Contains(model.View(), `C:\End`) {
+		t.Fatal("Exact value should be collapsed by default")
+	}
+
+	m, _ := model.handleOptimizerConfirmKey("v")
+	if m.screen != ScreenOptimizerConfirm || !m.confirmShowValue {
+		t.Fatal("Expected V to expand the exact value on the confirm screen")
+	}
+	if !strings.Contains(m.View(), fmt.Sprintf("User PATH (%d chars)", len(raw))) {
+		t.Error("Expected the value length header")
+	}
+
+	// Scroll to the end and reassemble every wrapped line that was shown
+	var shown strings.Builder
+	for i := 0; i < 100; i++ {
+		for _, line := range m.exactValueLines()[m.confirmScroll:min(m.confirmScroll+listMaxVisible, len(m.exactValueLines()))] {
+			if !strings.Contains(m.View(), line) {
+				t.Fatalf("Line %q not rendered at scroll %d", line, m.confirmScroll)
+			}
+		}
+		m, _ = m.handleOptimizerConfirmKey("j")
+	}
+	for _, line := range m.exactValueLines()[1:] {
+		shown.WriteString(line)
+	}
+	if shown.String() != raw {
+		t.Error("Wrapped lines should reproduce the raw value byte-for-byte")
+	}
+	if !strings.Contains(m.View(), `C:\End`) {
+		t.Error("Expected the end of the value after scrolling down")
+	}
+
+	m, _ = m.handleOptimizerConfirmKey("n")
+	if m.confirmShowValue || m.screen != ScreenOptimizerPreview {
+		t.Error("Cancelling should collapse the preview and leave the confirm")
+	}
+}
+
+func TestModel_ConfirmExactValue_SkipsSystemWithoutAdmin(t *testing.T) {
+	model := New()
+	model.isAdmin = false
+	model.screen = ScreenOptimizerConfirm
+	model.optimizerScope = "both"
+	model.analysis = &path.AnalysisResult{}
+	model.analysis.User.Optimized.Raw = `C:\UserOnly`
+	model.analysis.System.Optimized.Raw = `C:\SystemOnly`
+
+	writes := model.pendingPathWrites()
+	if len(writes) != 1 || writes[0].scope != "User" {
+		t.Errorf("Expected only the User value without admin, got %+v", writes)
+	}
+}
+
+func TestModel_PathEditorConfirm_ExactValue(t *testing.T) {
+	model := New()
+	model.screen = ScreenPathEditorConfirm
+	model.editorScope = "User"
+	model.editorOriginal = []string{`C:\A`}
+	model.editorEntries = []string{`C:\B`, `C:\A`}
+
+	m, _ := model.handlePathEditorConfirmKey("v")
+	if !strings.Contains(m.View(), `C:\B;C:\A`) {
+		t.Error("Expected the edited PATH value on the editor confirm")
+	}
+}
+
+func TestModel_ScopesPersistAcrossRuns(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+
+	model := New()
+	if model.viewerScope != "User" || model.optimizerScope != "both" {
+		t.Fatalf("Expected defaults with an empty config, got %s / %s", model.viewerScope, model.optimizerScope)
+	}
+
+	model.screen = ScreenPathViewer
+	m, _ := model.handleViewerKey("s")
+	m.screen = ScreenOptimizerPreview
+	m, _ = m.handleOptimizerKey("s")
+	if m.viewerScope != "System" || m.optimizerScope != "system" {
+		t.Fatalf("Expected toggled scopes, got %s / %s", m.viewerScope, m.optimizerScope)
+	}
+
+	reloaded := New()
+	if reloaded.viewerScope != "System" {
+		t.Errorf("Expected viewer scope System after reload, got %s", reloaded.viewerScope)
+	}
+	if reloaded.optimizerScope != "system" {
+		t.Errorf("Expected optimizer scope system after reload, got %s", reloaded.optimizerScope)
+	}
+}
+
+func TestModel_ScopesFallBackOnInvalidConfig(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+
+	config := path.DefaultConfig()
+	config.LastViewerScope = "Machine"
+	config.LastOptimizerScope = "everything"
+	if err := path.SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+
+	model := New()
+	if model.viewerScope != "User" || model.optimizerScope != "both" {
+		t.Errorf("Expected defaults for unknown scopes, got %s / %s", model.viewerScope, model.optimizerScope)
+	}
+}
+
+func TestModel_Settings_CycleTheme(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+	defer ApplyTheme("default")
+
+	model := New()
+	model.screen = ScreenSettings
+	model.settingsIndex = 7
+
+	m, _ := model.handleSettingsKey("enter")
+	if m.config.Theme != "mono" {
+		t.Fatalf("Expected theme to cycle to mono, got %q", m.config.Theme)
+	}
+	if Cyan != "" || !SelectedStyle.GetReverse() {
+		t.Error("Expected mono styles to be applied immediately")
+	}
+	if path.LoadConfig().Theme != "mono" {
+		t.Error("Expected the theme to be saved")
+	}
+	if !strings.Contains(m.viewSettings(), "Theme: mono") {
+		t.Error("Expected the theme in the view")
+	}
+
+	m, _ = m.handleSettingsKey("enter")
+	m, _ = m.handleSettingsKey("enter")
+	if m.config.Theme != "default" || Cyan != "86" {
+		t.Errorf("Expected theme to wrap back to default, got %q", m.config.Theme)
+	}
+
+	// The saved theme is applied on the next run
+	m.config.Theme = "highcontrast"
+	_ = path.SaveConfig(m.config)
+	_ = New()
+	if Cyan != "14" {
+		t.Errorf("Expected saved theme to be applied at startup, got %q", Cyan)
+	}
+}
+
+func TestModel_RenderSummary_PathStoredAsString(t *testing.T) {
+	model := New()
+	model.analysis = &path.AnalysisResult{
+		System: path.OptimizeResult{StoredAsString: true},
+	}
+
+	summary := model.renderSummary()
+	if !strings.Contains(summary, "REG_SZ") {
+		t.Error("Expected REG_SZ warning in summary")
+	}
+	if !strings.Contains(summary, "System PATH will be converted to REG_EXPAND_SZ") {
+		t.Error("Expected System scope to be named in the warning")
+	}
+	if strings.Contains(summary, "User PATH will be converted") {
+		t.Error("User scope should not be flagged")
+	}
+
+	model.analysis = &path.AnalysisResult{}
+	if strings.Contains(model.renderSummary(), "REG_SZ") {
+		t.Error("Warning should not be shown when PATH is REG_EXPAND_SZ")
+	}
+}
+
+func TestModel_Junctions_CopyTable(t *testing.T) {
+	clip, cleanup := path.SetDefaultClipboard()
+	defer cleanup()
+
+	model := New()
+	model.screen = ScreenJunctions
+	model.junctions = []path.Junction{
+		{Name: "git", Target: `C:\Program Files\Git`},
+		{Name: "vscode-insiders", Target: `C:\Users\Test\AppData\Local\Programs\VS Code Insiders`},
+		{Name: "old", Target: `C:\Gone`, Broken: true},
+	}
+
+	m, _ := model.handleJunctionsKey("c")
+	copied := clip.Last()
+	for _, j := range model.junctions {
+		if !strings.Contains(copied, j.Name) || !strings.Contains(copied, j.Target) {
+			t.Errorf("Expected %s -> %s in copied table, got:\n%s", j.Name, j.Target, copied)
+		}
+	}
+	lines := strings.Split(strings.TrimSuffix(copied, "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected header, rule and 3 rows, got %d lines:\n%s", len(lines), copied)
+	}
+	if strings.Index(lines[2], `C:\`) != strings.Index(lines[3], `C:\`) {
+		t.Error("Expected targets to be aligned in one column")
+	}
+	if !strings.HasSuffix(lines[4], "(broken)") {
+		t.Errorf("Expected broken junction to be marked, got %q", lines[4])
+	}
+	if m.message != "Copied 3 junctions to clipboard" {
+		t.Errorf("Unexpected message %q", m.message)
+	}
+}
+
+func TestModel_Junctions_CopyTableEmpty(t *testing.T) {
+	clip, cleanup := path.SetDefaultClipboard()
+	defer cleanup()
+
+	model := New()
+	model.screen = ScreenJunctions
+	model.junctions = nil
+
+	m, _ := model.handleJunctionsKey("c")
+	if len(clip.Writes) != 0 {
+		t.Error("Nothing should be copied without junctions")
+	}
+	if m.message != "No junctions to copy" {
+		t.Errorf("Unexpected message %q", m.message)
+	}
+}
+
+func TestModel_Settings_EditJunctionFolder(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+
+	model := New()
+	model.screen = ScreenSettings
+	for i := 0; i < 20; i++ {
+		model = pressKey(t, model, "down")
+	}
+	if model.settingsIndex != 9 {
+		t.Fatalf("Expected down to stop on Junction Folder (9), got %d", model.settingsIndex)
+	}
+
+	m := pressKey(t, model, "enter")
+	if !m.settingsEditing || m.settingsInput != model.config.JunctionFolder {
+		t.Fatal("Expected Enter to open the folder editor prefilled with the current folder")
+	}
+	if !m.inTextInput() {
+		t.Error("Expected the folder editor to capture typed keys")
+	}
+
+	m.settingsInput = ""
+	for _, k := range []string{"D", ":", "\\", "j", "\\"} {
+		m = pressKey(t, m, k)
+	}
+	m = pressKey(t, m, "enter")
+	if m.settingsEditing {
+		t.Fatalf("Expected editor to close after a valid folder, message %q", m.message)
+	}
+	if m.config.JunctionFolder != `D:\j` {
+		t.Errorf("Expected trailing separator trimmed, got %q", m.config.JunctionFolder)
+	}
+	if path.GetJunctionFolder() != `D:\j` {
+		t.Errorf("Expected folder to be saved, got %q", path.GetJunctionFolder())
+	}
+	if !strings.Contains(m.viewSettings(), `Junction Folder: D:\j`) {
+		t.Error("Expected new folder in the settings view")
+	}
+}
+
+func TestModel_Settings_EditJunctionFolderRejectsInvalid(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+
+	model := New()
+	model.screen = ScreenSettings
+	model.settingsIndex = 9
+	original := model.config.JunctionFolder
+
+	m := pressKey(t, model, "enter")
+	m.settingsInput = "relative\\links"
+	m = pressKey(t, m, "enter")
+	if !m.settingsEditing {
+		t.Error("Expected editor to stay open on an invalid folder")
+	}
+	if !strings.Contains(m.viewSettings(), "absolute path") {
+		t.Error("Expected validation error in the view")
+	}
+	if path.GetJunctionFolder() != original {
+		t.Error("Invalid folder should not be saved")
+	}
+
+	m = pressKey(t, m, "esc")
+	if m.settingsEditing || m.screen != ScreenSettings {
+		t.Error("Expected Esc to cancel editing and stay on Settings")
+	}
+	if m.config.JunctionFolder != original {
+		t.Error("Cancel should keep the original folder")
+	}
+}
+
+func TestModel_Viewer_RefreshRereadsPath(t *testing.T) {
+	mock := withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\First;C:\Second;C:\Third`)
+	})
+
+	model := New()
+	model.screen = ScreenPathViewer
+	model.viewerScope = "User"
+	model = pressKey(t, model, "down")
+	model = pressKey(t, model, "down")
+	if model.viewerIndex != 2 {
+		t.Fatalf("Expected selection on the third entry, got %d", model.viewerIndex)
+	}
+
+	mock.SetResponse("CurrentUser.OpenSubKey", `C:\Changed`)
+	before := len(mock.Calls)
+	m := pressKey(t, model, "r")
+	if countCalls(mock.Calls[before:], "CurrentUser.OpenSubKey") == 0 {
+		t.Error("Expected refresh to re-read the User PATH")
+	}
+	if m.viewerIndex != 0 || m.scrollOffset != 0 {
+		t.Errorf("Expected selection and scroll reset, got index %d offset %d", m.viewerIndex, m.scrollOffset)
+	}
+	if m.message != "Reloaded User PATH (1 entries)" {
+		t.Errorf("Unexpected message %q", m.message)
+	}
+	view := m.viewPathViewer()
+	if !strings.Contains(view, `C:\Changed`) || strings.Contains(view, `C:\Third`) {
+		t.Errorf("Expected refreshed entries in the view, got:\n%s", view)
+	}
+}
+
+func TestModel_Viewer_ReadsPathOnceUntilRefresh(t *testing.T) {
+	mock := withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\First;C:\Second;C:\Third`)
+	})
+
+	model := New()
+	model.viewerScope = "User"
+	before := len(mock.Calls)
+	model, _ = model.handleMenuKey("2")
+	if n := countCalls(mock.Calls[before:], "CurrentUser.OpenSubKey"); n != 1 {
+		t.Fatalf("Expected one read when the viewer opens, got %d", n)
+	}
+
+	before = len(mock.Calls)
+	for _, key := range []string{"down", "down", "up"} {
+		model = pressKey(t, model, key)
+		_ = model.View()
+	}
+	if n := countCalls(mock.Calls[before:], "OpenSubKey"); n != 0 {
+		t.Errorf("Expected scrolling and rendering to use the cached PATH, got %d reads", n)
+	}
+}
+
+func TestModel_Viewer_RefreshFailure(t *testing.T) {
+	withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetError("CurrentUser.OpenSubKey", fmt.Errorf("access denied"))
+	})
+
+	model := New()
+	model.screen = ScreenPathViewer
+	model.viewerScope = "User"
+	m := pressKey(t, model, "r")
+	if m.message != "Refresh failed: access denied" {
+		t.Errorf("Unexpected message %q", m.message)
+	}
+}
+
+func TestModel_Settings_ReloadFromDisk(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+
+	model := New()
+	model.screen = ScreenSettings
+
+	cfg := path.LoadConfig()
+	cfg.MaxBackups = 42
+	if err := path.SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	m := pressKey(t, model, "r")
+	if m.config.MaxBackups != 42 {
+		t.Errorf("Expected reloaded MaxBackups 42, got %d", m.config.MaxBackups)
+	}
+	if !strings.Contains(m.message, "Settings reloaded") {
+		t.Errorf("Unexpected message %q", m.message)
+	}
+}
+
+func TestModel_Settings_ReloadKeepsUnsavedInput(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+
+	model := New()
+	model.screen = ScreenSettings
+	model.settingsIndex = 9
+	m := pressKey(t, model, "enter")
+	m.settingsInput = `D:\`
+	m = pressKey(t, m, "r")
+	if !m.settingsEditing || m.settingsInput != `D:\r` {
+		t.Errorf("Expected r to be typed into the folder editor, got %q", m.settingsInput)
+	}
+}
+
+func TestModel_Viewer_ScrollStopsAtEnd(t *testing.T) {
+	withMock(t, func(mock *path.MockShellRunner) {
+		entries := make([]string, viewerMaxVisible+5)
+		for i := range entries {
+			entries[i] = fmt.Sprintf(`C:\Tool%d`, i)
+		}
+		mock.SetResponse("CurrentUser.OpenSubKey", strings.Join(entries, ";"))
+	})
+
+	model := New()
+	model.screen = ScreenPathViewer
+	model.viewerScope = "User"
+
+	m := model
+	for i := 0; i < 100; i++ {
+		m, _ = m.handleViewerKey("down")
+	}
+	if m.scrollOffset != 5 {
+		t.Errorf("Expected offset to stop at 5, got %d", m.scrollOffset)
+	}
+	m, _ = m.handleViewerKey("up")
+	if m.viewerIndex != viewerMaxVisible+3 {
+		t.Errorf("Expected up to move the selection immediately, got index %d", m.viewerIndex)
+	}
+}
+
+func TestClampScroll(t *testing.T) {
+	tests := []struct {
+		offset, count, page, want int
+	}{
+		{5, 30, 12, 5},
+		{40, 30, 12, 18},
+		{-1, 30, 12, 0},
+		{3, 5, 12, 0},
+		{1, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := clampScroll(tt.offset, tt.count, tt.page); got != tt.want {
+			t.Errorf("clampScroll(%d, %d, %d) = %d, want %d", tt.offset, tt.count, tt.page, got, tt.want)
+		}
+	}
+}
+
+func TestModel_HotPaths_RejectsDuplicate(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+
+	model := New()
+	model.screen = ScreenHotPaths
+	model.config.HotPaths = nil
+
+	add := func(m Model, entry string) Model {
+		m.hotPathAdding = true
+		m.hotPathInput = entry
+		return m.handleHotPathsInputKey("enter")
+	}
+
+	m := add(model, `C:\Tools\bin`)
+	m = add(m, `c:\tools\BIN\`)
+	if len(m.config.HotPaths) != 1 || m.config.HotPaths[0] != `C:\Tools\bin` {
+		t.Errorf("Expected the path stored once, got %v", m.config.HotPaths)
+	}
+	if m.message != `C:\Tools\bin is already added` {
+		t.Errorf("Unexpected message %q", m.message)
+	}
+	if m.hotPathAdding || m.hotPathInput != "" {
+		t.Error("Expected input to close after a rejected duplicate")
+	}
+	if got := path.LoadConfig().HotPaths; len(got) != 1 {
+		t.Errorf("Expected one saved hot path, got %v", got)
+	}
+
+	m = add(m, `C:\Tools\sbin`)
+	if len(m.config.HotPaths) != 2 {
+		t.Errorf("Expected a different path to be added, got %v", m.config.HotPaths)
+	}
+}
+
+func protectedModel() Model {
+	model := New()
+	model.screen = ScreenOptimizerPreview
+	model.optimizerScope = "user"
+	model.isAdmin = false
+	model.config.AdvisoryMode = false
+	model.analysis = &path.AnalysisResult{}
+	model.analysis.User.Changes = []path.PathChange{
+		{Type: "dead", Original: `C:\Infra`},
+		{Type: "shortened", Original: `C:\Program Files\Agent`, New: `C:\PROGRA~1\Agent`},
+	}
+	model.analysis.User.ProtectedViolations = []path.ProtectedViolation{
+		{Entry: `C:\Infra`, Action: "removed"},
+		{Entry: `C:\Program Files\Agent`, Action: "modified", New: `C:\PROGRA~1\Agent`},
+	}
+	model.analysis.System.ProtectedViolations = []path.ProtectedViolation{{Entry: `C:\SysInfra`, Action: "removed"}}
+	return model
+}
+
+func TestModel_Protected_BlocksApplyPendingOverride(t *testing.T) {
+	mock := withMock(t, nil)
+	before := len(mock.Calls)
+
+	m := pressKey(t, protectedModel(), "a")
+	if m.screen != ScreenProtectedOverride {
+		t.Fatalf("Expected the protected override screen, got %d", m.screen)
+	}
+	view := m.View()
+	if !strings.Contains(view, `C:\Infra`) || !strings.Contains(view, "would be removed") {
+		t.Error("Expected the removed protected entry to be listed")
+	}
+	if strings.Contains(view, `C:\SysInfra`) {
+		t.Error("System violations should not block a User-only apply")
+	}
+
+	// One override is not enough
+	m = pressKey(t, m, " ")
+	m = pressKey(t, m, "enter")
+	if m.screen != ScreenProtectedOverride || !strings.Contains(m.message, "1 left") {
+		t.Fatalf("Expected apply to stay blocked with one entry left, message %q", m.message)
+	}
+
+	// Going straight to the confirm is blocked too
+	confirm := m
+	confirm.screen = ScreenOptimizerConfirm
+	confirm, cmd := confirm.handleOptimizerConfirmKey("y")
+	if cmd != nil || confirm.screen != ScreenProtectedOverride {
+		t.Error("Expected confirm to send unresolved protected entries back to the override screen")
+	}
+
+	m = pressKey(t, m, "j")
+	m = pressKey(t, m, " ")
+	if !strings.Contains(m.View(), "[x] [User] C:\\Program Files\\Agent") {
+		t.Error("Expected the override to be shown as checked")
+	}
+	m = pressKey(t, m, "enter")
+	if m.screen != ScreenOptimizerConfirm {
+		t.Fatalf("Expected the apply confirm once every entry is overridden, got %d", m.screen)
+	}
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable('Path'"); n != 0 {
+		t.Errorf("Nothing should be written before the confirm, got %d writes", n)
+	}
+
+	// A fresh analysis asks again
+	updated, _ := m.Update(analysisCompleteMsg{result: *m.analysis})
+	m = updated.(Model)
+	m = pressKey(t, m, "a")
+	if m.screen != ScreenProtectedOverride {
+		t.Error("Expected overrides to reset after a new analysis")
+	}
+}
+
+func TestModel_Protected_EscCancels(t *testing.T) {
+	m := pressKey(t, protectedModel(), "a")
+	m = pressKey(t, m, "esc")
+	if m.screen != ScreenOptimizerPreview {
+		t.Errorf("Expected Esc to return to the preview, got %d", m.screen)
+	}
+}
+
+func TestModel_Protected_SummaryAndNoViolations(t *testing.T) {
+	model := protectedModel()
+	if !strings.Contains(model.renderSummary(), "Protected Entries Affected") {
+		t.Error("Expected protected entries in the summary")
+	}
+
+	model.analysis.User.ProtectedViolations = nil
+	m := pressKey(t, model, "a")
+	if m.screen != ScreenOptimizerConfirm {
+		t.Errorf("Expected the normal confirm without violations, got %d", m.screen)
+	}
+}
+
+func TestModel_UndoLastApply_WritesBackPreApplyPath(t *testing.T) {
+	mock := withMock(t, nil)
+
+	model := New()
+	model.screen = ScreenLoading
+	model.isAdmin = true
+	model.optimizerScope = "both"
+	model.analysis = &path.AnalysisResult{}
+	model.analysis.User.Original.Raw = `C:\Before;C:\Before`
+	model.analysis.System.Original.Raw = `C:\Windows;C:\Windows`
+	backup := &path.BackupInfo{Filename: "path_20260101_120000_pre-optimize.json", Suffix: "pre-optimize"}
+	updated, _ := model.Update(applyCompleteMsg{backup: backup, written: []string{"User", "System"}})
+	m := updated.(Model)
+	if m.screen != ScreenOptimizerDone || !strings.Contains(m.View(), "Undo this apply") {
+		t.Fatal("Expected the done screen to offer undo")
+	}
+
+	before := len(mock.Calls)
+	m = pressKey(t, m, "u")
+	if m.screen != ScreenOptimizerUndoConfirm {
+		t.Fatalf("Expected undo confirm, got %d", m.screen)
+	}
+	if !strings.Contains(m.View(), "User and System PATH") {
+		t.Error("Expected the written scopes on the confirm")
+	}
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable"); n != 0 {
+		t.Fatal("Nothing should be written before confirming")
+	}
+
+	m, cmd := m.handleUndoConfirmKey("y")
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable"); n != 0 {
+		t.Fatal("The undo should run as a command, not inside Update")
+	}
+	m, _ = runWrite(t, m, cmd)
+	if n := countCalls(mock.Calls[before:], `SetEnvironmentVariable('Path', 'C:\Before;C:\Before', 'User')`); n != 1 {
+		t.Errorf("Expected the pre-apply User PATH written back once, got %d", n)
+	}
+	if n := countCalls(mock.Calls[before:], `SetEnvironmentVariable('Path', 'C:\Windows;C:\Windows', 'Machine')`); n != 1 {
+		t.Errorf("Expected the pre-apply System PATH written back once, got %d", n)
+	}
+	if m.screen != ScreenBackupDone || !strings.Contains(m.View(), "Optimization undone") {
+		t.Errorf("Expected undo result screen, got %d", m.screen)
+	}
+	if m.backupInfo != nil || m.analysis != nil || m.undoSnapshot != nil {
+		t.Error("Expected apply state to be cleared after undo")
+	}
+}
+
+func TestModel_UndoLastApply_OnlyTheLastOfCoalescedApplies(t *testing.T) {
+	mock := withMock(t, nil)
+	// Both applies share one coalesced backup holding the PATH before the first
+	shared := &path.BackupInfo{Filename: "path_20260101_120000_pre-optimize.json", Suffix: "pre-optimize"}
+
+	model := New()
+	model.screen = ScreenLoading
+	model.optimizerScope = "user"
+	model.analysis = &path.AnalysisResult{}
+	model.analysis.User.Original.Raw = `C:\First`
+	updated, _ := model.Update(applyCompleteMsg{backup: shared, written: []string{"User"}})
+	m := updated.(Model)
+
+	m.screen = ScreenLoading
+	m.analysis = &path.AnalysisResult{}
+	m.analysis.User.Original.Raw = `C:\Second`
+	updated, _ = m.Update(applyCompleteMsg{backup: shared, written: []string{"User"}})
+	m = updated.(Model)
+
+	before := len(mock.Calls)
+	m = pressKey(t, m, "u")
+	m, cmd := m.handleUndoConfirmKey("y")
+	_, _ = runWrite(t, m, cmd)
+	if n := countCalls(mock.Calls[before:], `'C:\Second', 'User'`); n != 1 {
+		t.Errorf("Expected undo to return to the PATH before the second apply, got calls %v", mock.Calls[before:])
+	}
+	if n := countCalls(mock.Calls[before:], `C:\First`); n != 0 {
+		t.Error("Undo should not roll back the earlier apply too")
+	}
+}
+
+func TestModel_UndoLastApply_CancelAndFailure(t *testing.T) {
+	withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetError("SetEnvironmentVariable", fmt.Errorf("access denied"))
+	})
+
+	model := New()
+	model.screen = ScreenOptimizerDone
+	model.backupInfo = &path.BackupInfo{Filename: "path_20260101_120000_pre-optimize.json"}
+	model.appliedScopes = []string{"User"}
+	model.undoSnapshot = map[string]string{"User": `C:\Before`}
+
+	m := pressKey(t, model, "u")
+	m = pressKey(t, m, "n")
+	if m.screen != ScreenOptimizerDone {
+		t.Errorf("Expected cancel to return to the done screen, got %d", m.screen)
+	}
+
+	m = pressKey(t, m, "u")
+	m, cmd := m.handleUndoConfirmKey("y")
+	m, _ = runWrite(t, m, cmd)
+	if m.screen != ScreenOptimizerDone || !strings.Contains(m.View(), "Undo failed: User: access denied") {
+		t.Errorf("Expected the failure on the done screen, got %d", m.screen)
+	}
+
+	// A forced apply has no backup, but its snapshot can still be written back
+	model.backupInfo = nil
+	m = pressKey(t, model, "u")
+	if m.screen != ScreenOptimizerUndoConfirm || strings.Contains(m.View(), "Backup ") {
+		t.Errorf("Expected undo to be offered from the snapshot alone, got screen %d", m.screen)
+	}
+
+	// Without a snapshot nothing was written, so there is nothing to undo
+	model.undoSnapshot = nil
+	m = pressKey(t, model, "u")
+	if m.screen != ScreenOptimizerDone || !strings.Contains(m.View(), "nothing to undo") {
+		t.Error("Expected undo to be refused without a snapshot")
+	}
+	if strings.Contains(m.View(), "Undo this apply") {
+		t.Error("Undo hint should be hidden without a snapshot")
+	}
+}
+
+func TestModel_AnalysisCache_ReusedUntilRefresh(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+	mock := withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\Tools;C:\Tools`)
+	})
+
+	model := New()
+	model.menuIndex = 0
+	m, cmd := model.handleMenuKey("enter")
+	if cmd == nil || m.screen != ScreenLoading {
+		t.Fatal("Expected the first visit to run an analysis")
+	}
+	msg := analyzeCmd()().(analysisCompleteMsg)
+	updated, _ := m.Update(msg)
+	m = updated.(Model)
+	if m.analysisCached || m.lastAnalyzedAt.IsZero() {
+		t.Error("Expected a fresh analysis with a timestamp")
+	}
+	if strings.Contains(m.View(), "[cached]") {
+		t.Error("Fresh analysis should not show the cached badge")
+	}
+	analyzedAt := m.lastAnalyzedAt
+	analysis := m.analysis
+
+	// Leaving and coming back checks the cache in the background and reuses the result
+	m = pressKey(t, m, "esc")
+	before := len(mock.Calls)
+	m, cmd = m.handleMenuKey("enter")
+	if cmd == nil || m.screen != ScreenLoading {
+		t.Fatal("Expected the cache check to run in the background")
+	}
+	if len(mock.Calls) != before {
+		t.Errorf("Expected no shell calls while handling the key, got %v", mock.Calls[before:])
+	}
+	msg = analyzeUnlessCachedCmd(m.cachedAnalysisKey)().(analysisCompleteMsg)
+	if !msg.cacheHit {
+		t.Fatal("Expected an unchanged PATH to hit the cache")
+	}
+	updated, _ = m.Update(msg)
+	m = updated.(Model)
+	if m.screen != ScreenOptimizerPreview || !m.analysisCached || m.analysis != analysis || !m.lastAnalyzedAt.Equal(analyzedAt) {
+		t.Error("Expected the cached analysis and its timestamp")
+	}
+	if !strings.Contains(m.View(), "[cached]") {
+		t.Error("Expected the cached badge")
+	}
+
+	// R forces a fresh analysis
+	m, cmd = m.handleOptimizerKey("r")
+	if cmd == nil || m.screen != ScreenLoading {
+		t.Fatal("Expected R to re-run the analysis")
+	}
+}
+
+func TestAnalyzeCmd_ReadsEachPathOnce(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+	mock := withMock(t, nil)
+
+	before := len(mock.Calls)
+	analyzeCmd()()
+	if n := countCalls(mock.Calls[before:], "LocalMachine.OpenSubKey"); n != 1 {
+		t.Errorf("Expected System PATH to be read once per analysis, got %d reads", n)
+	}
+	if n := countCalls(mock.Calls[before:], "CurrentUser.OpenSubKey"); n != 1 {
+		t.Errorf("Expected User PATH to be read once per analysis, got %d reads", n)
+	}
+}
+
+func TestModel_AnalysisCache_StaleCheckFeedsAnalysis(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+	mock := withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\Tools`)
+	})
+
+	model := New()
+	updated, _ := model.Update(analyzeCmd()().(analysisCompleteMsg))
+	m := pressKey(t, updated.(Model), "esc")
+	mock.SetResponse("CurrentUser.OpenSubKey", `C:\Tools;C:\Other`)
+
+	before := len(mock.Calls)
+	msg := analyzeUnlessCachedCmd(m.cachedAnalysisKey)().(analysisCompleteMsg)
+	if n := countCalls(mock.Calls[before:], "CurrentUser.OpenSubKey"); n != 1 {
+		t.Errorf("Expected the User PATH read once for the cache check and analysis, got %d", n)
+	}
+	if msg.cacheHit || len(msg.result.User.Original.Entries) != 2 {
+		t.Errorf("Expected a fresh analysis of the changed PATH, got %v", msg.result.User.Original.Entries)
+	}
+}
+
+func TestModel_Analysis_ReadErrorReturnsToMenu(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+	withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetError("CurrentUser.OpenSubKey", fmt.Errorf("operation timed out"))
+	})
+
+	model := New()
+	model.menuIndex = 0
+	m, _ := model.handleMenuKey("enter")
+	msg := analyzeUnlessCachedCmd("")().(analysisCompleteMsg)
+	if msg.err == nil {
+		t.Fatal("Expected the read error to be reported")
+	}
+	updated, _ := m.Update(msg)
+	m = updated.(Model)
+	if m.screen != ScreenMenu || m.analysis != nil || m.cachedAnalysis != nil {
+		t.Errorf("Expected to return to the menu without an analysis, got screen %d", m.screen)
+	}
+	if !strings.Contains(m.View(), "Analysis failed") || !strings.Contains(m.View(), "timed out") {
+		t.Error("Expected the read error on the menu")
+	}
+}
+
+func TestLoadSuggestionsCmd_ReadsEachPathOnce(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+	mock := withMock(t, nil)
+
+	before := len(mock.Calls)
+	loadSuggestionsCmd()()
+	if n := countCalls(mock.Calls[before:], "LocalMachine.OpenSubKey"); n != 1 {
+		t.Errorf("Expected System PATH to be read once, got %d reads", n)
+	}
+}
+
+func TestModel_AnalysisCache_InvalidatedByPathChange(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+	mock := withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\Tools`)
+	})
+
+	model := New()
+	updated, _ := model.Update(analyzeCmd()().(analysisCompleteMsg))
+	m := updated.(Model)
+
+	mock.SetResponse("CurrentUser.OpenSubKey", `C:\Tools;C:\Other`)
+	if msg := analyzeUnlessCachedCmd(m.cachedAnalysisKey)().(analysisCompleteMsg); msg.cacheHit {
+		t.Error("Expected a changed PATH to bypass the cache")
+	}
+
+	// So does a settings change the optimizer depends on
+	mock.SetResponse("CurrentUser.OpenSubKey", `C:\Tools`)
+	config := path.LoadConfig()
+	config.HotPaths = []string{`C:\Tools`}
+	_ = path.SaveConfig(config)
+	if msg := analyzeUnlessCachedCmd(m.cachedAnalysisKey)().(analysisCompleteMsg); msg.cacheHit {
+		t.Error("Expected a hot path change to bypass the cache")
+	}
+}
+
+func TestModel_Optimizer_PresetCyclesAndReanalyzes(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+	dead := `Z:\nowhere\winpath-dead`
+	withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\Tools;C:\Tools;`+dead)
+	})
+
+	model := New()
+	updated, _ := model.Update(analyzeCmd()().(analysisCompleteMsg))
+	m := updated.(Model)
+	if !strings.Contains(m.View(), "Preset: default") {
+		t.Error("Expected the default preset to be shown")
+	}
+
+	m, cmd := m.handleOptimizerKey("p")
+	if cmd == nil || m.screen != ScreenLoading {
+		t.Fatal("Expected P to re-run the analysis")
+	}
+	if got := path.LoadConfig().OptimizerPreset; got != "conservative" {
+		t.Fatalf("Expected the conservative preset to be saved, got %q", got)
+	}
+	updated, _ = m.Update(analyzeCmd()().(analysisCompleteMsg))
+	m = updated.(Model)
+	if got := strings.Join(m.analysis.User.Optimized.Entries, ";"); got != `C:\Tools;`+dead {
+		t.Errorf("Conservative preset should only remove duplicates, got %s", got)
+	}
+	if !strings.Contains(m.View(), "Preset: conservative") {
+		t.Error("Expected the conservative preset to be shown")
+	}
+
+	for _, want := range []string{"existing", "aggressive", "default"} {
+		m, _ = m.handleOptimizerKey("p")
+		if got := optimizerPreset(m.config); got != want {
+			t.Errorf("Expected preset %s, got %s", want, got)
+		}
+	}
+}
+
+// ============================================================================
+// Auto-fix Tests
+// ============================================================================
+
+func TestModel_AutoFix_WritesPathAndPathExtWithOneBackup(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+	// A User PATH with a duplicate and PATHEXT with .EXE behind .COM
+	dir := t.TempDir()
+	mock := withMock(t, func(mock *path.MockShellRunner) {
+		mock.Responses = map[string]string{
+			"CurrentUser.OpenSubKey":  dir + ";" + dir,
+			"LocalMachine.OpenSubKey": "",
+			"'PATHEXT'":               ".COM;.EXE",
+			"IsInRole":                "False",
+		}
+	})
+
+	model := New()
+	model.screen = ScreenMenu
+	model.menuIndex = 7
+	m, cmd := model.handleMenuKey("enter")
+	if cmd == nil || m.screen != ScreenLoading {
+		t.Fatal("Expected auto-fix to start checking")
+	}
+	updated, _ := m.Update(autoFixPlanCmd()())
+	m = updated.(Model)
+	if m.screen != ScreenAutoFixConfirm {
+		t.Fatalf("Expected the auto-fix confirmation, got screen %d", m.screen)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Apply Recommended Fixes?") || !strings.Contains(view, "1 duplicates") || !strings.Contains(view, ".EXE;.COM") {
+		t.Errorf("Expected the planned PATH and PATHEXT fixes, got:\n%s", view)
+	}
+
+	m, cmd = m.handleAutoFixConfirmKey("y")
+	if cmd == nil || m.screen != ScreenLoading {
+		t.Fatal("Expected a single confirmation to start applying")
+	}
+	before := len(mock.Calls)
+	updated, _ = m.Update(autoFixApplyCmd(m.autoFixAnalysis, m.autoFixPathExt, m.isAdmin)())
+	m = updated.(Model)
+	if m.screen != ScreenAutoFixDone || m.backupInfo == nil {
+		t.Fatalf("Expected the done screen with a backup, got screen %d, message %q", m.screen, m.message)
+	}
+
+	calls := mock.Calls[before:]
+	backupAt, pathAt, extAt := -1, -1, -1
+	for i, c := range calls {
+		switch {
+		case strings.Contains(c, "$env:COMPUTERNAME"):
+			if backupAt != -1 {
+				t.Error("Expected a single backup")
+			}
+			backupAt = i
+		case strings.Contains(c, "SetEnvironmentVariable('Path'"):
+			pathAt = i
+		case strings.Contains(c, "SetEnvironmentVariable('PATHEXT', '.EXE;.COM', 'User')"):
+			extAt = i
+		}
+	}
+	if backupAt == -1 || pathAt == -1 || extAt == -1 {
+		t.Fatalf("Expected a backup, a PATH write and a PATHEXT write, got backup=%d path=%d pathext=%d", backupAt, pathAt, extAt)
+	}
+	if backupAt > pathAt || backupAt > extAt {
+		t.Error("Expected the backup to be taken before any write")
+	}
+	if n := len(path.ListBackups()); n != 1 {
+		t.Errorf("Expected exactly one backup file, got %d", n)
+	}
+}
+
+func TestModel_AutoFix_NothingToFix(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+	mock := withMock(t, func(mock *path.MockShellRunner) {
+		mock.Responses = map[string]string{
+			"'PATHEXT'": ".EXE;.COM",
+			"IsInRole":  "False",
+		}
+	})
+
+	model := New()
+	updated, _ := model.Update(autoFixPlanCmd()())
+	m := updated.(Model)
+	if !strings.Contains(m.View(), "Nothing to fix") {
+		t.Errorf("Expected nothing to fix, got:\n%s", m.View())
+	}
+
+	before := len(mock.Calls)
+	m, cmd := m.handleAutoFixConfirmKey("y")
+	if cmd != nil || m.screen != ScreenMenu {
+		t.Error("Expected confirming with nothing to fix to return to the menu")
+	}
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable"); n != 0 {
+		t.Errorf("Expected no writes, got %d", n)
+	}
+}
+
+func TestModel_AutoFix_BlockedByProtectedEntries(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+	withMock(t, func(mock *path.MockShellRunner) {
+		mock.Responses = map[string]string{
+			"CurrentUser.OpenSubKey": `C:\Gone\Agent`,
+			"'PATHEXT'":              ".EXE",
+			"IsInRole":               "False",
+		}
+	})
+	config := path.DefaultConfig()
+	config.ProtectedEntries = []string{`C:\Gone\Agent`}
+	_ = path.SaveConfig(config)
+
+	model := New()
+	updated, _ := model.Update(autoFixPlanCmd()())
+	m := updated.(Model)
+	m, cmd := m.handleAutoFixConfirmKey("y")
+	if cmd != nil || m.screen != ScreenAutoFixConfirm {
+		t.Error("Expected protected entries to block auto-fix")
+	}
+	if !strings.Contains(m.View(), "Protected entries would change") {
+		t.Errorf("Expected the protected entry warning, got:\n%s", m.View())
+	}
+}
+
+// ============================================================================
+// External Change Tests
+// ============================================================================
+
+func TestModel_ExternalChanges_BannerAndAccept(t *testing.T) {
+	defer path.SetConfigDir(filepath.Dir(path.GetConfigPath()))
+	path.SetConfigDir(t.TempDir())
+
+	mock := withMock(t, func(mock *path.MockShellRunner) {
+		mock.SetResponse("LocalMachine.OpenSubKey", `C:\Windows`)
+		mock.SetResponse("CurrentUser.OpenSubKey", `C:\Tools`)
+	})
+	if _, err := path.CreateBackup("manual"); err != nil {
+		t.Fatalf("CreateBackup error: %v", err)
+	}
+	// Something else adds an entry after the backup
+	mock.SetResponse("CurrentUser.OpenSubKey", `C:\Tools;C:\Installer\bin`)
+
+	before := countCalls(mock.Calls, "OpenSubKey")
+	model := New()
+	if countCalls(mock.Calls, "OpenSubKey") != before {
+		t.Error("New should not read the registry itself")
+	}
+	cmd := model.Init()
+	if cmd == nil {
+		t.Fatal("Expected Init to check for external changes when a backup exists")
+	}
+	updated, _ := model.Update(cmd())
+	model = updated.(Model)
+	if model.externalChanges == nil || !strings.Contains(model.View(), "PATH changed since last backup (1 entries differ)") {
+		t.Fatalf("Expected the external change banner, got:\n%s", model.View())
+	}
+
+	model = pressKey(t, model, "d")
+	if model.screen != ScreenExternalChanges || !strings.Contains(model.View(), `+ C:\Installer\bin`) {
+		t.Fatalf("Expected the added entry to be listed, got:\n%s", model.View())
+	}
+
+	model = pressKey(t, model, "b")
+	if model.screen != ScreenMenu || model.externalChanges != nil {
+		t.Error("Expected accepting to return to the menu without the banner")
+	}
+	if changes, err := path.CheckExternalChanges(); err != nil || changes.Count() != 0 {
+		t.Errorf("Expected the new backup to match the live PATH, got %+v, %v", changes, err)
+	}
+}
+
+func TestModel_New_WithoutPowerShell(t *testing.T) {
+	withMock(t, func(m *path.MockShellRunner) {
+		m.SetError("", &exec.Error{Name: "powershell.exe", Err: exec.ErrNotFound})
+	})
+
+	m := New()
+	if m.shellErr == nil || m.isAdmin {
+		t.Fatalf("Expected the missing shell detected, got shellErr=%v isAdmin=%v", m.shellErr, m.isAdmin)
+	}
+	if m.viewerScope != "Process" {
+		t.Errorf("Expected the viewer to fall back to the Process PATH, got %s", m.viewerScope)
+	}
+	view := m.View()
+	if !strings.Contains(view, "PowerShell was not found") || !strings.Contains(view, "Read-only") {
+		t.Errorf("Expected the missing-PowerShell banner, got:\n%s", view)
+	}
+}
+
+func TestModel_ShortNames_AcceptSubset(t *testing.T) {
+	sysLong, sysShort := `C:\Program Files\Common Tools\bin`, `C:\PROGRA~1\COMMON~1\bin`
+	usrLong, usrShort := `C:\Users\Test\Long Folder Name\bin`, `C:\Users\Test\LONGFO~1\bin`
+	result := func(long, short string) path.OptimizeResult {
+		return path.OptimizeResult{
+			Original:  path.PathInfo{Raw: `C:\Windows;` + long, Entries: []string{`C:\Windows`, long}, Length: len(`C:\Windows;` + long)},
+			Optimized: path.PathInfo{Raw: `C:\Windows;` + short, Entries: []string{`C:\Windows`, short}},
+			Changes: []path.PathChange{
+				{Type: "duplicate", Original: `C:\Windows`},
+				{Type: "shortened", Original: long, New: short, Saved: len(long) - len(short)},
+			},
+			Metrics: path.OptimizeMetrics{PathsShortened: 1, TotalSaved: len(long) - len(short)},
+		}
+	}
+	model := New()
+	model.screen = ScreenOptimizerPreview
+	model.analysis = &path.AnalysisResult{System: result(sysLong, sysShort), User: result(usrLong, usrShort)}
+	model.cachedAnalysis = model.analysis
+
+	m := pressKey(t, model, "8")
+	if m.screen != ScreenShortNames {
+		t.Fatalf("Expected the 8.3 review screen, got %d", m.screen)
+	}
+	view := m.View()
+	for _, want := range []string{"can change", sysLong, "-> " + sysShort, usrLong, "-> " + usrShort} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in view:\n%s", want, view)
+		}
+	}
+
+	// Reject the User conversion only
+	m = pressKey(t, m, "j")
+	m = pressKey(t, m, " ")
+	if !strings.Contains(m.View(), fmt.Sprintf("save %d chars", len(sysLong)-len(sysShort))) {
+		t.Errorf("Expected only the accepted savings counted:\n%s", m.View())
+	}
+	m = pressKey(t, m, "enter")
+
+	if m.screen != ScreenOptimizerPreview {
+		t.Fatalf("Expected back on the preview, got %d", m.screen)
+	}
+	if got := m.analysis.System.Optimized.Raw; got != `C:\Windows;`+sysShort {
+		t.Errorf("Expected the accepted System conversion kept, got %q", got)
+	}
+	if got := m.analysis.User.Optimized.Raw; got != `C:\Windows;`+usrLong {
+		t.Errorf("Expected the rejected User entry kept long, got %q", got)
+	}
+	if n := len(m.shortNameChanges()); n != 1 {
+		t.Errorf("Expected one conversion left, got %d", n)
+	}
+	if m.cachedAnalysis.User.Optimized.Raw != `C:\Windows;`+usrShort {
+		t.Error("Expected the cached analysis left untouched")
+	}
+
+	// Esc discards choices, and R rejects everything
+	m = pressKey(t, m, "8")
+	m = pressKey(t, m, "R")
+	m = pressKey(t, m, "esc")
+	if m.analysis.System.Optimized.Raw != `C:\Windows;`+sysShort {
+		t.Error("Expected Esc to leave the PATH unchanged")
+	}
+	m = pressKey(t, m, "8")
+	m = pressKey(t, m, "R")
+	m = pressKey(t, m, "enter")
+	if m.analysis.System.Optimized.Raw != `C:\Windows;`+sysLong || len(m.shortNameChanges()) != 0 {
+		t.Errorf("Expected every conversion rejected, got %q", m.analysis.System.Optimized.Raw)
+	}
+	if m = pressKey(t, m, "8"); m.screen != ScreenOptimizerPreview || !strings.Contains(m.message, "No entries") {
+		t.Errorf("Expected nothing left to review, got screen %d message %q", m.screen, m.message)
+	}
+}
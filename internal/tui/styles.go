@@ -4,84 +4,145 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// Themes lists the selectable color themes in Settings order
+var Themes = []string{"default", "mono", "highcontrast"}
+
 var (
 	// Colors
-	Cyan    = lipgloss.Color("86")
-	Green   = lipgloss.Color("82")
-	Yellow  = lipgloss.Color("226")
-	Red     = lipgloss.Color("196")
-	Magenta = lipgloss.Color("213")
-	Gray    = lipgloss.Color("245")
-	DimGray = lipgloss.Color("239")
-	White   = lipgloss.Color("255")
+	Cyan    lipgloss.Color
+	Green   lipgloss.Color
+	Yellow  lipgloss.Color
+	Red     lipgloss.Color
+	Magenta lipgloss.Color
+	Gray    lipgloss.Color
+	DimGray lipgloss.Color
+	White   lipgloss.Color
 
 	// Text styles
-	TitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(Cyan)
-
-	SubtitleStyle = lipgloss.NewStyle().
-			Foreground(Gray)
-
-	SelectedStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(Cyan)
+	TitleStyle    lipgloss.Style
+	SubtitleStyle lipgloss.Style
+	SelectedStyle lipgloss.Style
+	NormalStyle   lipgloss.Style
+	DimStyle      lipgloss.Style
+	SuccessStyle  lipgloss.Style
+	WarningStyle  lipgloss.Style
+	ErrorStyle    lipgloss.Style
+	InfoStyle     lipgloss.Style
 
-	NormalStyle = lipgloss.NewStyle().
-			Foreground(White)
+	// Box styles
+	BoxStyle         lipgloss.Style
+	SelectedBoxStyle lipgloss.Style
+	WarningBoxStyle  lipgloss.Style
 
-	DimStyle = lipgloss.NewStyle().
-			Foreground(DimGray)
+	// Footer style
+	FooterStyle lipgloss.Style
 
-	SuccessStyle = lipgloss.NewStyle().
-			Foreground(Green)
+	// Key style
+	KeyStyle lipgloss.Style
 
-	WarningStyle = lipgloss.NewStyle().
-			Foreground(Yellow)
+	// Metric styles
+	MetricLabelStyle lipgloss.Style
+	MetricValueStyle lipgloss.Style
+	MetricSavedStyle lipgloss.Style
+)
 
-	ErrorStyle = lipgloss.NewStyle().
-			Foreground(Red)
+func init() {
+	ApplyTheme("default")
+}
 
-	InfoStyle = lipgloss.NewStyle().
-			Foreground(Magenta)
+// Theme is the full set of colors and styles for one theme
+type Theme struct {
+	Name string
 
-	// Box styles
-	BoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(Gray).
-			Padding(0, 1)
+	Cyan, Green, Yellow, Red, Magenta, Gray, DimGray, White lipgloss.Color
 
-	SelectedBoxStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(Cyan).
-				Padding(0, 1)
+	Title, Subtitle, Selected, Normal, Dim, Success, Warning, Error, Info lipgloss.Style
+	Box, SelectedBox, WarningBox, Footer, Key                             lipgloss.Style
+	MetricLabel, MetricValue, MetricSaved                                 lipgloss.Style
+}
 
-	WarningBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(Yellow).
-			Padding(0, 1)
+// ResolveTheme builds the styles for a theme name, falling back to "default"
+// "mono" uses no color at all and tells states apart with bold, underline and reverse
+func ResolveTheme(name string) Theme {
+	t := Theme{Name: name}
+	switch name {
+	case "mono":
+		// Empty colors render without any color codes
+	case "highcontrast":
+		t.Cyan, t.Green, t.Yellow, t.Red = "14", "10", "11", "9"
+		t.Magenta, t.Gray, t.DimGray, t.White = "13", "252", "250", "15"
+	default:
+		t.Name = "default"
+		t.Cyan, t.Green, t.Yellow, t.Red = "86", "82", "226", "196"
+		t.Magenta, t.Gray, t.DimGray, t.White = "213", "245", "239", "255"
+	}
 
-	// Footer style
-	FooterStyle = lipgloss.NewStyle().
-			Foreground(Gray).
-			MarginTop(1)
+	t.Title = lipgloss.NewStyle().Bold(true).Foreground(t.Cyan)
+	t.Subtitle = lipgloss.NewStyle().Foreground(t.Gray)
+	t.Selected = lipgloss.NewStyle().Bold(true).Foreground(t.Cyan)
+	t.Normal = lipgloss.NewStyle().Foreground(t.White)
+	t.Dim = lipgloss.NewStyle().Foreground(t.DimGray)
+	t.Success = lipgloss.NewStyle().Foreground(t.Green)
+	t.Warning = lipgloss.NewStyle().Foreground(t.Yellow)
+	t.Error = lipgloss.NewStyle().Foreground(t.Red)
+	t.Info = lipgloss.NewStyle().Foreground(t.Magenta)
+	t.Key = lipgloss.NewStyle().Bold(true).Foreground(t.Cyan)
+	t.MetricSaved = lipgloss.NewStyle().Foreground(t.Green)
+
+	switch t.Name {
+	case "mono":
+		t.Title = t.Title.Underline(true)
+		t.Selected = t.Selected.Reverse(true)
+		t.Dim = t.Dim.Faint(true)
+		t.Success = t.Success.Bold(true)
+		t.Warning = t.Warning.Underline(true)
+		t.Error = t.Error.Bold(true).Underline(true)
+		t.Info = t.Info.Italic(true)
+		t.MetricSaved = t.MetricSaved.Bold(true)
+	case "highcontrast":
+		t.Success = t.Success.Bold(true)
+		t.Warning = t.Warning.Bold(true)
+		t.Error = t.Error.Bold(true)
+	}
 
-	// Key style
-	KeyStyle = lipgloss.NewStyle().
-			Foreground(Cyan).
-			Bold(true)
+	t.Box = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(t.Gray).Padding(0, 1)
+	t.SelectedBox = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(t.Cyan).Padding(0, 1)
+	t.WarningBox = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(t.Yellow).Padding(0, 1)
+	t.Footer = lipgloss.NewStyle().Foreground(t.Gray).MarginTop(1)
+	t.MetricLabel = lipgloss.NewStyle().Foreground(t.Gray).Width(12)
+	t.MetricValue = lipgloss.NewStyle().Foreground(t.White)
+	return t
+}
 
-	// Metric styles
-	MetricLabelStyle = lipgloss.NewStyle().
-				Foreground(Gray).
-				Width(12)
+// ApplyTheme switches every package color and style to the named theme
+// and returns the name actually applied
+func ApplyTheme(name string) string {
+	t := ResolveTheme(name)
+	Cyan, Green, Yellow, Red = t.Cyan, t.Green, t.Yellow, t.Red
+	Magenta, Gray, DimGray, White = t.Magenta, t.Gray, t.DimGray, t.White
+
+	TitleStyle, SubtitleStyle, SelectedStyle, NormalStyle = t.Title, t.Subtitle, t.Selected, t.Normal
+	DimStyle, SuccessStyle, WarningStyle, ErrorStyle, InfoStyle = t.Dim, t.Success, t.Warning, t.Error, t.Info
+	BoxStyle, SelectedBoxStyle, WarningBoxStyle = t.Box, t.SelectedBox, t.WarningBox
+	FooterStyle, KeyStyle = t.Footer, t.Key
+	MetricLabelStyle, MetricValueStyle, MetricSavedStyle = t.MetricLabel, t.MetricValue, t.MetricSaved
+	return t.Name
+}
 
-	MetricValueStyle = lipgloss.NewStyle().
-				Foreground(White)
+// themeName returns the theme name that ResolveTheme would use for name
+func themeName(name string) string {
+	return ResolveTheme(name).Name
+}
 
-	MetricSavedStyle = lipgloss.NewStyle().
-				Foreground(Green)
-)
+// nextTheme returns the theme after name in Themes, wrapping around
+func nextTheme(name string) string {
+	for i, t := range Themes {
+		if t == name {
+			return Themes[(i+1)%len(Themes)]
+		}
+	}
+	return Themes[1%len(Themes)]
+}
 
 // RenderKey renders a keyboard shortcut
 func RenderKey(key, desc string) string {
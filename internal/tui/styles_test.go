@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -302,3 +303,68 @@ func BenchmarkRenderMetric(b *testing.B) {
 		RenderMetric("Length", 100, 80, " chars")
 	}
 }
+
+// themeSignature describes a theme's styles without relying on terminal color support
+func themeSignature(th Theme) string {
+	var b strings.Builder
+	for _, s := range []lipgloss.Style{
+		th.Title, th.Subtitle, th.Selected, th.Normal, th.Dim, th.Success, th.Warning, th.Error, th.Info,
+		th.Box, th.SelectedBox, th.WarningBox, th.Footer, th.Key, th.MetricLabel, th.MetricValue, th.MetricSaved,
+	} {
+		fmt.Fprintf(&b, "%v|%v|%v|%v|%v|%v|%v;", s.GetForeground(), s.GetBorderTopForeground(),
+			s.GetBold(), s.GetUnderline(), s.GetReverse(), s.GetFaint(), s.GetItalic())
+	}
+	return b.String()
+}
+
+func TestThemes_DistinctAndNonEmpty(t *testing.T) {
+	defer ApplyTheme("default")
+
+	seen := make(map[string]string)
+	for _, name := range Themes {
+		th := ResolveTheme(name)
+		if th.Name != name {
+			t.Errorf("ResolveTheme(%q).Name = %q", name, th.Name)
+		}
+		if ApplyTheme(name) != name {
+			t.Errorf("ApplyTheme(%q) applied a different theme", name)
+		}
+		for i, s := range []lipgloss.Style{TitleStyle, SelectedStyle, DimStyle, SuccessStyle, WarningStyle, ErrorStyle, BoxStyle, KeyStyle} {
+			if s.Render("test") == "" {
+				t.Errorf("Theme %q style %d rendered empty string", name, i)
+			}
+		}
+
+		sig := themeSignature(th)
+		if other, ok := seen[sig]; ok {
+			t.Errorf("Themes %q and %q have identical styles", other, name)
+		}
+		seen[sig] = name
+	}
+}
+
+func TestThemes_MonoUsesNoColor(t *testing.T) {
+	th := ResolveTheme("mono")
+	for _, c := range []lipgloss.Color{th.Cyan, th.Green, th.Yellow, th.Red, th.Magenta, th.Gray, th.DimGray, th.White} {
+		if c != "" {
+			t.Errorf("Expected mono theme to have no colors, got %q", c)
+		}
+	}
+	if !th.Selected.GetReverse() || !th.Error.GetUnderline() {
+		t.Error("Expected mono theme to mark states with text attributes")
+	}
+}
+
+func TestThemes_UnknownFallsBackToDefault(t *testing.T) {
+	defer ApplyTheme("default")
+
+	if got := ApplyTheme("neon"); got != "default" {
+		t.Errorf("Expected unknown theme to fall back to default, got %q", got)
+	}
+	if Cyan != "86" {
+		t.Errorf("Expected default colors after fallback, got %q", Cyan)
+	}
+	if nextTheme("default") != "mono" || nextTheme("highcontrast") != "default" {
+		t.Error("Expected nextTheme to cycle through Themes in order")
+	}
+}
@@ -1,18 +1,455 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"slices"
+	"strings"
 
+	"github.com/quantumJLBass/winpath/internal/path"
 	"github.com/quantumJLBass/winpath/internal/tui"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
+	config := path.LoadConfig()
+	path.SetShellTimeout(config.ShellTimeoutSeconds)
+	path.SetPowerShellExe(config.PowerShellExe)
+	if len(os.Args) > 1 {
+		os.Exit(runCLI(os.Args[1:], os.Stdout, os.Stderr))
+	}
+
 	p := tea.NewProgram(tui.New(), tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// cliOptions holds the parsed command-line flags
+type cliOptions struct {
+	analyze           bool
+	optimize          bool
+	apply             bool
+	scope             string
+	dryRun            bool
+	json              bool
+	summary           bool
+	backup            bool
+	yes               bool
+	compare           string
+	baseline          string
+	overrideProtected string
+	shadowed          bool
+	diagnose          bool
+}
+
+// parseCLI parses command-line flags into cliOptions
+func parseCLI(args []string, stderr io.Writer) (cliOptions, error) {
+	var opts cliOptions
+	fs := flag.NewFlagSet("winpath", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.BoolVar(&opts.analyze, "analyze", false, "analyze PATH and print the result")
+	fs.BoolVar(&opts.optimize, "optimize", false, "compute the optimized PATH")
+	fs.BoolVar(&opts.apply, "apply", false, "write the optimized PATH (requires --optimize)")
+	fs.StringVar(&opts.scope, "scope", "both", "scope to operate on: user, system, or both")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "show what would change without writing")
+	fs.BoolVar(&opts.json, "json", false, "print output as JSON")
+	fs.BoolVar(&opts.summary, "summary", false, "print changes grouped by action with counts and savings")
+	fs.BoolVar(&opts.backup, "backup", true, "create a backup before applying")
+	fs.BoolVar(&opts.yes, "yes", false, "do not ask for confirmation when applying")
+	fs.StringVar(&opts.compare, "compare", "", "compare a PATH file from another machine against the baseline")
+	fs.StringVar(&opts.baseline, "baseline", "", "PATH file to compare against (default: this machine's PATH)")
+	fs.StringVar(&opts.overrideProtected, "override-protected", "", "semicolon-separated protected entries the apply may remove or rewrite")
+	fs.BoolVar(&opts.shadowed, "shadowed", false, "also report commands found in more than one PATH directory (reads every directory)")
+	fs.BoolVar(&opts.diagnose, "diagnose", false, "write a redacted diagnostics report to attach to bug reports")
+
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected argument: %s", fs.Arg(0))
+	}
+
+	opts.scope = strings.ToLower(opts.scope)
+	switch opts.scope {
+	case "user", "system", "both":
+	default:
+		return opts, fmt.Errorf("invalid --scope %q (want user, system, or both)", opts.scope)
+	}
+	if opts.apply && !opts.optimize {
+		return opts, errors.New("--apply requires --optimize")
+	}
+	if opts.summary && opts.json {
+		return opts, errors.New("--summary cannot be combined with --json")
+	}
+	if opts.baseline != "" && opts.compare == "" {
+		return opts, errors.New("--baseline requires --compare")
+	}
+	if opts.diagnose {
+		if opts.apply {
+			return opts, errors.New("--diagnose cannot be combined with --apply")
+		}
+		return opts, nil
+	}
+	if opts.compare != "" {
+		if opts.apply {
+			return opts, errors.New("--compare cannot be combined with --apply")
+		}
+		return opts, nil
+	}
+	if !opts.analyze && !opts.optimize {
+		return opts, errors.New("nothing to do: pass --analyze or --optimize")
+	}
+	return opts, nil
+}
+
+// runCLI runs winpath without the TUI and returns the process exit code
+func runCLI(args []string, stdout, stderr io.Writer) int {
+	opts, err := parseCLI(args, stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	if opts.diagnose {
+		return runDiagnose(stdout, stderr)
+	}
+	if opts.compare != "" {
+		return runCompare(opts, stdout, stderr)
+	}
+	if opts.apply && path.LoadConfig().AdvisoryMode {
+		fmt.Fprintln(stderr, "Error: advisory mode is enabled in the config; --apply is disabled")
+		return 2
+	}
+
+	if err := path.CheckPowerShell(); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	analyzeOpts := path.DefaultOptions()
+	analyzeOpts.DetectShadowedTools = opts.shadowed || path.LoadConfig().DetectShadowedTools
+	analysis, err := path.AnalyzeAll(analyzeOpts)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if opts.json {
+		if err := writeJSON(stdout, analysis, opts.scope); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+	} else if opts.summary {
+		writeSummary(stdout, analysis, opts.scope)
+	} else {
+		writeText(stdout, analysis, opts.scope)
+	}
+
+	if !opts.apply {
+		return 0
+	}
+	if opts.dryRun {
+		fmt.Fprintln(stderr, "Dry run: no changes written")
+		return 0
+	}
+	if !opts.yes {
+		fmt.Fprintln(stderr, "Error: refusing to apply without --yes in non-interactive mode")
+		return 1
+	}
+
+	if err := applyCLI(&analysis, opts, path.IsAdmin(), stderr); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runDiagnose writes the diagnostics report and prints where it went
+func runDiagnose(stdout, stderr io.Writer) int {
+	saved, err := path.SaveDiagnosticsReport()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Diagnostics report written to %s\n", saved)
+	return 0
+}
+
+// runCompare diffs an imported PATH file against a baseline file or the live PATH
+// It only reads; nothing on this machine is changed
+func runCompare(opts cliOptions, stdout, stderr io.Writer) int {
+	imported, err := path.ImportPathFromFile(opts.compare)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	baselineLabel := "this machine"
+	var baseline []string
+	if opts.baseline != "" {
+		baselineLabel = opts.baseline
+		if baseline, err = path.ImportPathFromFile(opts.baseline); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+	} else {
+		baseline = path.LivePathEntries()
+	}
+
+	comparison := path.ComparePaths(baseline, imported)
+	if opts.json {
+		data, err := json.MarshalIndent(comparison, "", "  ")
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Fprintln(stdout, string(data))
+		return 0
+	}
+	writeCompareText(stdout, comparison, opts.compare, baselineLabel)
+	return 0
+}
+
+// writeCompareText prints the diff and suggestions for an imported PATH
+func writeCompareText(w io.Writer, c path.PathComparison, importedLabel, baselineLabel string) {
+	fmt.Fprintf(w, "Comparing %s against %s\n", importedLabel, baselineLabel)
+	fmt.Fprintf(w, "  Only in %s: %d  Missing from it: %d\n", importedLabel, len(c.Diff.Added), len(c.Diff.Removed))
+	for _, e := range c.Diff.Added {
+		fmt.Fprintf(w, "  + %s\n", e)
+	}
+	for _, e := range c.Diff.Removed {
+		fmt.Fprintf(w, "  - %s\n", e)
+	}
+	writeScopeText(w, "Imported", c.Optimized)
+	for _, n := range c.NestedEntries {
+		fmt.Fprintf(w, "Nested entry: %s contains %s\n", n.Parent, n.Child)
+	}
+}
+
+// applyCLI writes the optimized PATH for the requested scope
+func applyCLI(analysis *path.AnalysisResult, opts cliOptions, isAdmin bool, stderr io.Writer) error {
+	if opts.scope == "system" && !isAdmin {
+		return errors.New("modifying System PATH requires administrator privileges")
+	}
+
+	if blocked := blockedProtected(analysis, opts, isAdmin); len(blocked) > 0 {
+		return fmt.Errorf("protected entries would be removed or rewritten, PATH unchanged: %s (pass --override-protected to allow)",
+			strings.Join(blocked, "; "))
+	}
+
+	// Decide every write before the first one so a refusal never leaves a scope half applied
+	var writes []string
+	if opts.scope == "both" || opts.scope == "user" {
+		writes = append(writes, "User")
+	}
+	if opts.scope == "both" || opts.scope == "system" {
+		if isAdmin {
+			writes = append(writes, "System")
+		} else {
+			fmt.Fprintln(stderr, "System PATH skipped (needs admin)")
+		}
+	}
+	var changed []string
+	for _, scope := range writes {
+		r := scopeResult(analysis, scope)
+		if r.Optimized.Raw == r.Original.Raw {
+			fmt.Fprintf(stderr, "%s PATH unchanged, not written\n", scope)
+			continue
+		}
+		if strings.TrimSpace(r.Optimized.Raw) == "" && strings.TrimSpace(r.Original.Raw) != "" {
+			return fmt.Errorf("refusing to replace the %s PATH with an empty value, PATH unchanged", scope)
+		}
+		changed = append(changed, scope)
+	}
+	if len(changed) == 0 {
+		fmt.Fprintln(stderr, "Nothing to apply")
+		return nil
+	}
+
+	if opts.backup {
+		backup, err := path.CreateBackup("pre-optimize")
+		if err != nil {
+			return fmt.Errorf("backup failed, PATH unchanged: %w", err)
+		}
+		fmt.Fprintf(stderr, "Backup: %s\n", backup.Filename)
+	}
+
+	for _, scope := range changed {
+		if err := path.SetPath(scopeResult(analysis, scope).Optimized.Raw, scope); err != nil {
+			return fmt.Errorf("failed to write %s PATH: %w", scope, err)
+		}
+	}
+
+	path.BroadcastEnvChange()
+	fmt.Fprintln(stderr, "PATH optimization applied")
+	return nil
+}
+
+// scopeResult returns the optimization result for "System" or "User"
+func scopeResult(analysis *path.AnalysisResult, scope string) path.OptimizeResult {
+	if scope == "System" {
+		return analysis.System
+	}
+	return analysis.User
+}
+
+// blockedProtected returns the protected entries the apply would change that were not overridden
+func blockedProtected(analysis *path.AnalysisResult, opts cliOptions, isAdmin bool) []string {
+	overridden := make(map[string]bool)
+	for _, e := range path.ParsePath(opts.overrideProtected) {
+		overridden[path.NormalizePath(e)] = true
+	}
+
+	var violations []path.ProtectedViolation
+	if opts.scope == "both" || opts.scope == "user" {
+		violations = append(violations, analysis.User.ProtectedViolations...)
+	}
+	if isAdmin && (opts.scope == "both" || opts.scope == "system") {
+		violations = append(violations, analysis.System.ProtectedViolations...)
+	}
+
+	var blocked []string
+	for _, v := range violations {
+		if !overridden[path.NormalizePath(v.Entry)] {
+			blocked = append(blocked, v.Entry)
+		}
+	}
+	return blocked
+}
+
+// scopedAnalysis is the --json output, leaving out the scope --scope didn't ask for
+type scopedAnalysis struct {
+	path.AnalysisResult
+	System *path.OptimizeResult `json:"system,omitempty"`
+	User   *path.OptimizeResult `json:"user,omitempty"`
+}
+
+// writeJSON prints the analysis for the requested scope as indented JSON
+func writeJSON(w io.Writer, analysis path.AnalysisResult, scope string) error {
+	out := scopedAnalysis{AnalysisResult: analysis}
+	if scope == "both" || scope == "system" {
+		out.System = &analysis.System
+	} else {
+		out.UserVarsInSystem = nil
+	}
+	if scope == "both" || scope == "user" {
+		out.User = &analysis.User
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// writeText prints a short human-readable report for each scope
+func writeText(w io.Writer, analysis path.AnalysisResult, scope string) {
+	if scope == "both" || scope == "system" {
+		writeScopeText(w, "System", analysis.System)
+	}
+	if scope == "both" || scope == "user" {
+		writeScopeText(w, "User", analysis.User)
+	}
+	for _, eq := range analysis.JunctionEquivalents {
+		fmt.Fprintf(w, "Junction duplicate: %s = %s (junction %s)\n", eq.Entry, eq.Equivalent, eq.Junction)
+	}
+	for _, u := range analysis.UserVarsInSystem {
+		fmt.Fprintf(w, "Warning: System PATH entry %s uses per-user variable %%%s%%\n", u.Entry, u.Variable)
+	}
+	for _, n := range analysis.NestedEntries {
+		fmt.Fprintf(w, "Nested entry: %s contains %s\n", n.Parent, n.Child)
+	}
+	for _, v := range analysis.MisdirectedVars {
+		fmt.Fprintf(w, "Possibly misdirected PATH addition: %%%s%% = %s\n", v.Name, strings.Join(v.Entries, ";"))
+	}
+	for _, s := range analysis.ShadowedTools {
+		fmt.Fprintf(w, "Shadowed tool: %s runs %s, hiding %s\n", s.Tool, s.Winner, strings.Join(s.Shadowed, ", "))
+	}
+}
+
+// summaryOrder is the order action types are listed in --summary output
+var summaryOrder = []string{"duplicate", "dead", "malformed", "canonical", "shortened", "variable", "reordered"}
+
+// writeSummary prints the changes for each scope grouped by action type
+func writeSummary(w io.Writer, analysis path.AnalysisResult, scope string) {
+	total := 0
+	if scope == "both" || scope == "system" {
+		total += writeScopeSummary(w, "System", analysis.System)
+	}
+	if scope == "both" || scope == "user" {
+		total += writeScopeSummary(w, "User", analysis.User)
+	}
+	if scope == "both" {
+		fmt.Fprintf(w, "Estimated total savings: %d chars\n", total)
+	}
+}
+
+// writeScopeSummary prints one scope's summary block and returns the chars it saves
+func writeScopeSummary(w io.Writer, label string, r path.OptimizeResult) int {
+	counts := make(map[string]int)
+	saved := make(map[string]int)
+	types := append([]string{}, summaryOrder...)
+	for _, c := range r.Changes {
+		if counts[c.Type] == 0 && !slices.Contains(types, c.Type) {
+			types = append(types, c.Type)
+		}
+		counts[c.Type]++
+		saved[c.Type] += c.Saved
+	}
+
+	fmt.Fprintf(w, "%s PATH summary\n", label)
+	if len(r.Changes) == 0 {
+		fmt.Fprintln(w, "  No changes")
+	}
+	for _, t := range types {
+		if counts[t] == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "  %-10s %4d  %6d chars\n", t, counts[t], saved[t])
+	}
+	total := r.Original.Length - r.Optimized.Length
+	fmt.Fprintf(w, "  Estimated savings: %d chars (%.1f%%)\n", total, r.Metrics.PercentageSaved)
+	return total
+}
+
+func writeScopeText(w io.Writer, label string, r path.OptimizeResult) {
+	fmt.Fprintf(w, "%s PATH\n", label)
+	fmt.Fprintf(w, "  Entries: %d -> %d\n", r.Original.Count, r.Optimized.Count)
+	fmt.Fprintf(w, "  Length:  %d -> %d chars (%.1f%% saved)\n", r.Original.Length, r.Optimized.Length, r.Metrics.PercentageSaved)
+	fmt.Fprintf(w, "  Dup: %d  Dead: %d  Short: %d  Vars: %d\n",
+		r.Metrics.DuplicatesRemoved, r.Metrics.DeadPathsRemoved,
+		r.Metrics.PathsShortened, r.Metrics.VarsSubstituted)
+	fmt.Fprintf(w, "  Checked: %d  Unchecked (vars): %d\n",
+		r.Metrics.CheckedEntries, r.Metrics.UncheckedEntries)
+	for _, v := range r.ProtectedViolations {
+		if v.Action == "modified" {
+			fmt.Fprintf(w, "  Protected: %s would be rewritten to %s\n", v.Entry, v.New)
+		} else {
+			fmt.Fprintf(w, "  Protected: %s would be removed\n", v.Entry)
+		}
+	}
+	if r.StoredAsString {
+		fmt.Fprintf(w, "  Warning: %s PATH is REG_SZ, so %%VARS%% will not expand; applying converts it to REG_EXPAND_SZ\n", label)
+	}
+	for _, c := range r.CaseConflicts {
+		fmt.Fprintf(w, "  Case-sensitive: %s and %s kept (%s is case sensitive)\n", c.Entry, c.Variant, c.Directory)
+	}
+	for _, c := range r.Changes {
+		if c.New != "" {
+			fmt.Fprintf(w, "  [%s] %s -> %s\n", c.Type, c.Original, c.New)
+		} else {
+			fmt.Fprintf(w, "  [%s] %s\n", c.Type, c.Original)
+		}
+	}
+}
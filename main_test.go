@@ -1,9 +1,36 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/quantumJLBass/winpath/internal/path"
 )
 
+// TestMain sets up temp directory for config and mock shell runner
+func TestMain(m *testing.M) {
+	tempDir, err := os.MkdirTemp("", "syspath-main-test-*")
+	if err != nil {
+		os.Exit(1)
+	}
+	path.SetConfigDir(tempDir)
+
+	_, cleanup := path.SetDefaultTestRunner()
+
+	code := m.Run()
+
+	cleanup()
+	os.RemoveAll(tempDir)
+
+	os.Exit(code)
+}
+
 func TestMain_Imports(t *testing.T) {
 	// Verify main package compiles and imports work
 	// This is a smoke test to catch import errors
@@ -13,3 +40,462 @@ func TestMain_Imports(t *testing.T) {
 // Note: Testing the actual main() function is tricky because it starts the TUI.
 // For comprehensive testing, the logic should be extracted into testable functions.
 // The TUI model tests in internal/tui/model_test.go cover the core functionality.
+
+func getMock(t *testing.T) *path.MockShellRunner {
+	t.Helper()
+	mock, ok := path.DefaultRunner.(*path.MockShellRunner)
+	if !ok {
+		t.Fatal("Not running with mock runner")
+	}
+	return mock
+}
+
+func countCalls(calls []string, pattern string) int {
+	n := 0
+	for _, c := range calls {
+		if strings.Contains(c, pattern) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestParseCLI_Defaults(t *testing.T) {
+	opts, err := parseCLI([]string{"--analyze"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("parseCLI error: %v", err)
+	}
+	if opts.scope != "both" {
+		t.Errorf("Expected default scope 'both', got %s", opts.scope)
+	}
+	if !opts.backup {
+		t.Error("Expected backup to default to true")
+	}
+	if opts.yes || opts.dryRun || opts.json {
+		t.Error("Expected yes, dry-run and json to default to false")
+	}
+}
+
+func TestParseCLI_Errors(t *testing.T) {
+	tests := [][]string{
+		{"--analyze", "--scope", "everything"},
+		{"--apply"},
+		{"--json"},
+		{"--analyze", "extra"},
+		{"--bogus"},
+		{"--baseline", "a.txt"},
+		{"--compare", "a.txt", "--optimize", "--apply"},
+		{"--diagnose", "--optimize", "--apply"},
+	}
+	for _, args := range tests {
+		if _, err := parseCLI(args, &bytes.Buffer{}); err == nil {
+			t.Errorf("Expected error for args %v", args)
+		}
+	}
+}
+
+func TestRunCLI_AnalyzeJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"--analyze", "--scope", "user", "--json"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &decoded); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, stdout.String())
+	}
+	if _, ok := decoded["user"]; !ok {
+		t.Error("Expected user key in JSON output")
+	}
+	if _, ok := decoded["system"]; ok {
+		t.Error("Expected --scope user to leave out the system key")
+	}
+
+	stdout.Reset()
+	if code := runCLI([]string{"--analyze", "--json"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d", code)
+	}
+	decoded = nil
+	if err := json.Unmarshal(stdout.Bytes(), &decoded); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	for _, key := range []string{"system", "user"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("Expected %s key with the default scope", key)
+		}
+	}
+}
+
+func TestRunCLI_AnalyzeText(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"--analyze", "--scope", "system"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d", code)
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "System PATH") {
+		t.Errorf("Expected System PATH section, got: %s", out)
+	}
+	if strings.Contains(out, "User PATH") {
+		t.Error("User section should be omitted for --scope system")
+	}
+}
+
+func TestRunCLI_InvalidFlagsExitNonZero(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runCLI([]string{"--scope", "nope", "--analyze"}, &stdout, &stderr); code == 0 {
+		t.Error("Expected nonzero exit code for invalid scope")
+	}
+}
+
+func TestRunCLI_ApplyRequiresYes(t *testing.T) {
+	mock := getMock(t)
+	before := len(mock.Calls)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"--optimize", "--apply", "--scope", "user"}, &stdout, &stderr)
+	if code == 0 {
+		t.Error("Expected nonzero exit code without --yes")
+	}
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable('Path'"); n != 0 {
+		t.Errorf("Expected no PATH writes, got %d", n)
+	}
+}
+
+func TestRunCLI_DryRunDoesNotWrite(t *testing.T) {
+	mock := getMock(t)
+	before := len(mock.Calls)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"--optimize", "--apply", "--yes", "--dry-run"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d", code)
+	}
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable('Path'"); n != 0 {
+		t.Errorf("Expected no PATH writes in dry run, got %d", n)
+	}
+}
+
+func TestRunCLI_ApplyYesWritesUser(t *testing.T) {
+	mock := getMock(t)
+	original := mock.Responses["CurrentUser.OpenSubKey"]
+	mock.SetResponse("CurrentUser.OpenSubKey", `%USERPROFILE%\bin;%USERPROFILE%\bin`)
+	defer mock.SetResponse("CurrentUser.OpenSubKey", original)
+	before := len(mock.Calls)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"--optimize", "--apply", "--yes", "--scope", "user"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable('Path'"); n != 1 {
+		t.Errorf("Expected 1 PATH write, got %d", n)
+	}
+	if !strings.Contains(stderr.String(), "Backup:") {
+		t.Error("Expected a backup to be reported before applying")
+	}
+}
+
+func TestRunCLI_ApplyStopsWhenPathUnreadable(t *testing.T) {
+	mock := getMock(t)
+	mock.SetError("CurrentUser.OpenSubKey", errors.New("operation timed out"))
+	defer delete(mock.Errors, "CurrentUser.OpenSubKey")
+	before := len(mock.Calls)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"--optimize", "--apply", "--yes", "--scope", "user"}, &stdout, &stderr)
+	if code == 0 {
+		t.Error("Expected nonzero exit code when the PATH can't be read")
+	}
+	if !strings.Contains(stderr.String(), "failed to read User PATH") {
+		t.Errorf("Expected the read error, got: %s", stderr.String())
+	}
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable"); n != 0 {
+		t.Errorf("Expected no writes after a failed read, got %d", n)
+	}
+}
+
+func TestRunCLI_AdvisoryModeRefusesApply(t *testing.T) {
+	config := path.LoadConfig()
+	advisory := config
+	advisory.AdvisoryMode = true
+	if err := path.SaveConfig(advisory); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = path.SaveConfig(config) })
+
+	mock := getMock(t)
+	before := len(mock.Calls)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"--optimize", "--apply", "--yes", "--scope", "user"}, &stdout, &stderr)
+	if code == 0 {
+		t.Error("Expected nonzero exit code in advisory mode")
+	}
+	if !strings.Contains(stderr.String(), "advisory mode") {
+		t.Errorf("Expected advisory mode error, got %q", stderr.String())
+	}
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable('Path'"); n != 0 {
+		t.Errorf("Expected no PATH writes, got %d", n)
+	}
+
+	stdout.Reset()
+	if code := runCLI([]string{"--analyze"}, &stdout, &stderr); code != 0 {
+		t.Errorf("Expected analysis to still run in advisory mode, got exit code %d", code)
+	}
+}
+
+func TestRunCLI_ApplySystemWithoutAdminFails(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"--optimize", "--apply", "--yes", "--scope", "system"}, &stdout, &stderr)
+	if code == 0 {
+		t.Error("Expected nonzero exit code when writing System PATH without admin")
+	}
+}
+
+func writeTempPathFile(t *testing.T, name, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+	return file
+}
+
+func TestRunCLI_CompareTwoFiles(t *testing.T) {
+	mock := getMock(t)
+	before := len(mock.Calls)
+
+	baseline := writeTempPathFile(t, "baseline.txt", `C:\Windows;C:\Old`)
+	customer := writeTempPathFile(t, "customer.txt", "C:\\Windows\nC:\\App\nC:\\App")
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"--compare", customer, "--baseline", baseline}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "+ C:\\App") || !strings.Contains(out, "- C:\\Old") {
+		t.Errorf("Expected diff in output, got: %s", out)
+	}
+	if !strings.Contains(out, "[duplicate] C:\\App") {
+		t.Errorf("Expected optimization suggestions in output, got: %s", out)
+	}
+	if n := countCalls(mock.Calls[before:], "Registry"); n != 0 {
+		t.Errorf("Expected no registry access, got %d calls", n)
+	}
+}
+
+func TestRunCLI_CompareAgainstLivePathJSON(t *testing.T) {
+	mock := getMock(t)
+	before := len(mock.Calls)
+
+	customer := writeTempPathFile(t, "customer.txt", `C:\Windows;C:\App`)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"--compare", customer, "--json"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+
+	var decoded path.PathComparison
+	if err := json.Unmarshal(stdout.Bytes(), &decoded); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, stdout.String())
+	}
+	if len(decoded.Baseline) == 0 {
+		t.Error("Expected the live PATH as baseline")
+	}
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable"); n != 0 {
+		t.Errorf("Expected no PATH writes, got %d", n)
+	}
+}
+
+func TestRunCLI_CompareMissingFile(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"--compare", filepath.Join(t.TempDir(), "missing.txt")}, &stdout, &stderr)
+	if code == 0 {
+		t.Error("Expected nonzero exit code for missing file")
+	}
+}
+
+func TestRunCLI_DiagnoseWritesReport(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"--diagnose"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	saved := strings.TrimSpace(strings.TrimPrefix(stdout.String(), "Diagnostics report written to "))
+	data, err := os.ReadFile(saved)
+	if err != nil {
+		t.Fatalf("Expected the report at %q: %v", saved, err)
+	}
+	if !strings.Contains(string(data), "== "+path.DiagSectionConfig+" ==") {
+		t.Errorf("Expected a full report, got:\n%s", data)
+	}
+}
+
+func TestRunCLI_WithoutPowerShellExplains(t *testing.T) {
+	mock := getMock(t)
+	mock.SetError("", &exec.Error{Name: "powershell.exe", Err: exec.ErrNotFound})
+	defer delete(mock.Errors, "")
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"--analyze"}, &stdout, &stderr)
+	if code == 0 {
+		t.Error("Expected nonzero exit code without PowerShell")
+	}
+	if !strings.Contains(stderr.String(), "PowerShell was not found") {
+		t.Errorf("Expected the friendly message, got: %s", stderr.String())
+	}
+}
+
+func TestWriteScopeText_PathStoredAsString(t *testing.T) {
+	var out bytes.Buffer
+	writeScopeText(&out, "User", path.OptimizeResult{StoredAsString: true})
+	if !strings.Contains(out.String(), "User PATH is REG_SZ") {
+		t.Errorf("Expected REG_SZ warning, got: %s", out.String())
+	}
+
+	out.Reset()
+	writeScopeText(&out, "User", path.OptimizeResult{})
+	if strings.Contains(out.String(), "REG_SZ") {
+		t.Error("Warning should not be shown for REG_EXPAND_SZ")
+	}
+}
+
+func TestWriteSummary_GroupsByAction(t *testing.T) {
+	analysis := path.AnalysisResult{
+		System: path.OptimizeResult{
+			Original:  path.PathInfo{Length: 100},
+			Optimized: path.PathInfo{Length: 60},
+			Changes: []path.PathChange{
+				{Type: "duplicate", Original: `C:\a`, Saved: 5},
+				{Type: "dead", Original: `C:\gone`, Saved: 8},
+				{Type: "duplicate", Original: `C:\b`, Saved: 5},
+				{Type: "shortened", Original: `C:\Program Files\X`, New: `C:\PROGRA~1\X`, Saved: 6},
+				{Type: "duplicate", Original: `C:\c`, Saved: 16},
+			},
+			Metrics: path.OptimizeMetrics{PercentageSaved: 40},
+		},
+		User: path.OptimizeResult{
+			Original:  path.PathInfo{Length: 50},
+			Optimized: path.PathInfo{Length: 40},
+			Changes: []path.PathChange{
+				{Type: "variable", Original: `C:\Users\Test\bin`, New: `%USERPROFILE%\bin`, Saved: 10},
+				{Type: "reordered", Original: `C:\x`},
+			},
+			Metrics: path.OptimizeMetrics{PercentageSaved: 20},
+		},
+	}
+
+	var out bytes.Buffer
+	writeSummary(&out, analysis, "both")
+	text := out.String()
+
+	for _, want := range []string{
+		"System PATH summary",
+		"  duplicate     3      26 chars",
+		"  dead          1       8 chars",
+		"  shortened     1       6 chars",
+		"  Estimated savings: 40 chars (40.0%)",
+		"User PATH summary",
+		"  variable      1      10 chars",
+		"  reordered     1       0 chars",
+		"  Estimated savings: 10 chars (20.0%)",
+		"Estimated total savings: 50 chars",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in summary, got:\n%s", want, text)
+		}
+	}
+	if strings.Index(text, "duplicate") > strings.Index(text, "dead") {
+		t.Error("Expected action types in a fixed order")
+	}
+	if strings.Contains(text, "canonical") {
+		t.Error("Action types without changes should be omitted")
+	}
+
+	out.Reset()
+	writeSummary(&out, path.AnalysisResult{}, "user")
+	if !strings.Contains(out.String(), "No changes") || strings.Contains(out.String(), "System") {
+		t.Errorf("Expected an empty User block only, got:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "total") {
+		t.Error("Grand total should only be shown for both scopes")
+	}
+}
+
+func TestRunCLI_AnalyzeSummary(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"--analyze", "--summary", "--scope", "user"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d (%s)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "User PATH summary") {
+		t.Errorf("Expected summary output, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := runCLI([]string{"--analyze", "--summary", "--json"}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected --summary with --json to be rejected, got %d", code)
+	}
+}
+
+func TestApplyCLI_ProtectedEntriesBlockApply(t *testing.T) {
+	mock := getMock(t)
+	before := len(mock.Calls)
+
+	analysis := &path.AnalysisResult{}
+	analysis.User.Optimized.Raw = `C:\Tools`
+	analysis.User.ProtectedViolations = []path.ProtectedViolation{{Entry: `C:\Infra`, Action: "removed"}}
+	analysis.System.ProtectedViolations = []path.ProtectedViolation{{Entry: `C:\SysInfra`, Action: "removed"}}
+
+	var stderr bytes.Buffer
+	err := applyCLI(analysis, cliOptions{scope: "user", backup: false}, false, &stderr)
+	if err == nil || !strings.Contains(err.Error(), `C:\Infra`) {
+		t.Fatalf("Expected protected entry to block the apply, got %v", err)
+	}
+	if strings.Contains(err.Error(), `C:\SysInfra`) {
+		t.Error("System entries should not block a User apply")
+	}
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable('Path'"); n != 0 {
+		t.Errorf("Expected no PATH writes, got %d", n)
+	}
+
+	err = applyCLI(analysis, cliOptions{scope: "user", backup: false, overrideProtected: `c:\infra\`}, false, &stderr)
+	if err != nil {
+		t.Fatalf("Expected override to allow the apply, got %v", err)
+	}
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable('Path'"); n != 1 {
+		t.Errorf("Expected 1 PATH write after override, got %d", n)
+	}
+}
+
+func TestApplyCLI_SkipsUnchangedAndRefusesEmpty(t *testing.T) {
+	mock := getMock(t)
+	before := len(mock.Calls)
+
+	analysis := &path.AnalysisResult{}
+	analysis.User.Original.Raw = `C:\Tools`
+	analysis.User.Optimized.Raw = `C:\Tools`
+	var stderr bytes.Buffer
+	if err := applyCLI(analysis, cliOptions{scope: "user", backup: false}, false, &stderr); err != nil {
+		t.Fatalf("Expected an unchanged PATH to apply cleanly, got %v", err)
+	}
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable('Path'"); n != 0 {
+		t.Errorf("Expected an unchanged PATH not to be written, got %d writes", n)
+	}
+
+	analysis.User.Optimized.Raw = ""
+	analysis.System.Original.Raw = `C:\Windows;C:\Windows`
+	analysis.System.Optimized.Raw = `C:\Windows`
+	err := applyCLI(analysis, cliOptions{scope: "both", backup: false}, true, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "empty") {
+		t.Fatalf("Expected an empty User PATH to be refused, got %v", err)
+	}
+	if n := countCalls(mock.Calls[before:], "SetEnvironmentVariable"); n != 0 {
+		t.Errorf("Expected nothing written when one scope is refused, got %d writes", n)
+	}
+}